@@ -3,8 +3,6 @@ package quickbooks
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
-	"strconv"
 )
 
 const (
@@ -29,6 +27,8 @@ const (
 // Read-only fields (Id, SyncToken, MetaData, FullyQualifiedName, Classification,
 // CurrentBalance, CurrentBalanceWithSubAccounts) are populated by the service.
 type Account struct {
+	EntityMeta
+
 	ID                            string         `json:"Id,omitempty"`
 	SyncToken                     string         `json:",omitempty"`
 	MetaData                      *MetaData      `json:",omitempty"`
@@ -53,6 +53,21 @@ type Account struct {
 	AccountAlias    *string `json:",omitempty"`
 }
 
+// IsActive reports whether the account is active, treating a missing Active field as active.
+func (a *Account) IsActive() bool {
+	return boolValue(a.Active, true)
+}
+
+// CurrentBalanceFloat returns CurrentBalance as a float64, or 0 if it is not set.
+func (a *Account) CurrentBalanceFloat() float64 {
+	return numberFloat(a.CurrentBalance)
+}
+
+// CurrentBalanceWithSubAccountsFloat returns CurrentBalanceWithSubAccounts as a float64, or 0 if it is not set.
+func (a *Account) CurrentBalanceWithSubAccountsFloat() float64 {
+	return numberFloat(a.CurrentBalanceWithSubAccounts)
+}
+
 // AccountCreateInput contains the writable fields accepted when creating an Account.
 // Name and AccountType are required; all other fields are optional.
 type AccountCreateInput struct {
@@ -69,131 +84,114 @@ type AccountCreateInput struct {
 	TaxCodeRef      *ReferenceType `json:",omitempty"`
 	TxnLocationType *string        `json:",omitempty"`
 	AccountAlias    *string        `json:",omitempty"`
+	// Extra holds fields the struct does not model yet; see ExtraFields.
+	Extra ExtraFields `json:"-"`
 }
 
-// CreateAccount creates the given account within QuickBooks.
-func (c *Client) CreateAccount(input *AccountCreateInput) (*Account, error) {
-	var resp struct {
-		Account Account
-		Time    Date
-	}
+// ErrAccountTaxCodeRefUS is returned when an account is sent with a TaxCodeRef for a US company.
+// QuickBooks only accepts Account.TaxCodeRef in global (non-US) locales.
+var ErrAccountTaxCodeRefUS = errors.New("account TaxCodeRef is not supported for US companies")
 
-	if err := c.post("account", input, &resp, nil); err != nil {
-		return nil, err
+// checkAccountTaxCodeRef returns ErrAccountTaxCodeRefUS if taxCodeRef is set and the company is
+// in the US. The company country is only looked up when a TaxCodeRef is set.
+func (c *Client) checkAccountTaxCodeRef(taxCodeRef *ReferenceType) error {
+	if taxCodeRef == nil {
+		return nil
 	}
 
-	return &resp.Account, nil
-}
+	country, err := c.CompanyCountry()
+	if err != nil {
+		return err
+	}
 
-// FindAccounts gets the full list of Accounts in the QuickBooks account.
-func (c *Client) FindAccounts() ([]Account, error) {
-	var resp struct {
-		QueryResponse struct {
-			Accounts      []Account `json:"Account"`
-			MaxResults    int
-			StartPosition int
-			TotalCount    int
-		}
+	if country == "US" {
+		return ErrAccountTaxCodeRefUS
 	}
 
-	if err := c.query("SELECT COUNT(*) FROM Account", &resp); err != nil {
+	return nil
+}
+
+// CreateAccount creates the given account within QuickBooks.
+func (c *Client) CreateAccount(input *AccountCreateInput) (*Account, error) {
+	if err := c.checkAccountTaxCodeRef(input.TaxCodeRef); err != nil {
 		return nil, err
 	}
 
-	if resp.QueryResponse.TotalCount == 0 {
-		return nil, errors.New("no accounts could be found")
-	}
+	return postSingle[Account](c, "account", input, nil)
+}
 
-	accounts := make([]Account, 0, resp.QueryResponse.TotalCount)
+// UpsertAccountByName makes sure an account named input.Name exists under input.ParentRef (at
+// the top level when it is nil) with the fields set in input. It creates the account when none
+// matches, active or not, and otherwise sparse-updates the existing one, leaving fields that
+// input does not set alone. The bool reports whether the account was created.
+func (c *Client) UpsertAccountByName(input *AccountCreateInput) (*Account, bool, error) {
+	if input.Name == "" {
+		return nil, false, errors.New("missing account name")
+	}
 
-	for i := 0; i < resp.QueryResponse.TotalCount; i += queryPageSize {
-		query := "SELECT * FROM Account ORDERBY Id STARTPOSITION " + strconv.Itoa(i+1) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
+	if err := c.checkAccountTaxCodeRef(input.TaxCodeRef); err != nil {
+		return nil, false, err
+	}
 
-		if err := c.query(query, &resp); err != nil {
-			return nil, err
+	// Account names are only unique among siblings, so the parent has to match too.
+	find := func() (string, error) {
+		accounts, err := queryEntities[Account](c, "Account", "SELECT Id, ParentRef FROM Account WHERE "+
+			refEquals("Name", input.Name)+" AND Active IN (true, false)")
+		if err != nil {
+			return "", err
 		}
 
-		if resp.QueryResponse.Accounts == nil {
-			return nil, errors.New("no accounts could be found")
+		for _, account := range accounts {
+			if refValue(account.ParentRef) == refValue(input.ParentRef) {
+				return account.ID, nil
+			}
 		}
-
-		accounts = append(accounts, resp.QueryResponse.Accounts...)
+		return "", nil
 	}
 
-	return accounts, nil
+	return upsert[Account](c, "account", input, find, func() (*Account, error) { return c.CreateAccount(input) })
+}
+
+// FindAccounts gets the full list of Accounts in the QuickBooks account.
+func (c *Client) FindAccounts() ([]Account, error) {
+	return findAllPages[Account](c, "Account", "no accounts could be found")
+}
+
+// FindAccountsWithOptions returns the accounts matching opts, ordered by opts.OrderBy.
+// Pass nil for opts to list them all.
+func (c *Client) FindAccountsWithOptions(opts *ListOptions) ([]Account, error) {
+	return findAllWithOptions[Account](c, "Account", true, opts)
 }
 
 // FindAccountByID returns an account with a given Id.
 func (c *Client) FindAccountByID(id string) (*Account, error) {
-	var resp struct {
-		Account Account
-		Time    Date
-	}
-
-	if err := c.get("account/"+id, &resp, nil); err != nil {
-		return nil, err
-	}
+	return getSingle[Account](c, "account/"+id, nil)
+}
 
-	return &resp.Account, nil
+// FindAccountsByIDs returns the accounts with the given Ids, inactive ones included. Ids that match nothing are skipped.
+func (c *Client) FindAccountsByIDs(ids []string) ([]Account, error) {
+	return findByIDs[Account](c, "Account", true, ids)
 }
 
 // QueryAccounts accepts an SQL query and returns all accounts found using it
 func (c *Client) QueryAccounts(query string) ([]Account, error) {
-	var resp struct {
-		QueryResponse struct {
-			Accounts      []Account `json:"Account"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
+	accounts, err := queryEntities[Account](c, "Account", query)
+	if err != nil {
+		return accounts, err
 	}
 
-	if resp.QueryResponse.Accounts == nil {
+	if accounts == nil {
 		return nil, errors.New("could not find any accounts")
 	}
 
-	return resp.QueryResponse.Accounts, nil
+	return accounts, nil
 }
 
 // ListAccounts returns one page of Accounts ordered by Id.
 // Pass an empty pageToken to start from the beginning.
 // The returned nextPageToken is empty when there are no more results.
 func (c *Client) ListAccounts(pageToken string, pageSize int) (*ListResponse[Account], error) {
-	if pageSize <= 0 || pageSize > queryPageSize {
-		pageSize = queryPageSize
-	}
-
-	startPosition := 1
-	if pageToken != "" {
-		var err error
-		startPosition, err = strconv.Atoi(pageToken)
-		if err != nil {
-			return nil, fmt.Errorf("invalid page token: %v", err)
-		}
-	}
-
-	var resp struct {
-		QueryResponse struct {
-			Accounts      []Account `json:"Account"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	query := "SELECT * FROM Account ORDERBY Id STARTPOSITION " + strconv.Itoa(startPosition) + " MAXRESULTS " + strconv.Itoa(pageSize)
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
-	}
-
-	result := &ListResponse[Account]{Items: resp.QueryResponse.Accounts}
-	if len(result.Items) == pageSize {
-		result.NextPageToken = strconv.Itoa(startPosition + pageSize)
-	}
-
-	return result, nil
+	return listPage[Account](c, "Account", pageToken, pageSize)
 }
 
 // UpdateAccount updates the account
@@ -202,6 +200,10 @@ func (c *Client) UpdateAccount(account *Account) (*Account, error) {
 		return nil, errors.New("missing account id")
 	}
 
+	if err := c.checkAccountTaxCodeRef(account.TaxCodeRef); err != nil {
+		return nil, err
+	}
+
 	existingAccount, err := c.FindAccountByID(account.ID)
 	if err != nil {
 		return nil, err
@@ -217,14 +219,5 @@ func (c *Client) UpdateAccount(account *Account) (*Account, error) {
 		Sparse:  true,
 	}
 
-	var accountData struct {
-		Account Account
-		Time    Date
-	}
-
-	if err = c.post("account", payload, &accountData, nil); err != nil {
-		return nil, err
-	}
-
-	return &accountData.Account, err
+	return updateSingle[Account](c, "account", "account/"+account.ID, &account.SyncToken, payload)
 }
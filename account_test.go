@@ -2,6 +2,7 @@ package quickbooks
 
 import (
 	"encoding/json"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -25,7 +26,8 @@ func TestAccount(t *testing.T) {
 		"sparse": false,
 		"MetaData": {
 			"CreateTime": "2014-12-31T09:29:05-08:00",
-			"LastUpdatedTime": "2014-12-31T09:29:05-08:00"
+			"LastUpdatedTime": "2014-12-31T09:29:05-08:00",
+			"LastModifiedByRef": {"value": "9130354998027756"}
 		},
 		"AccountType": "Accounts Receivable",
 		"CurrentBalance": 0,
@@ -53,6 +55,8 @@ func TestAccount(t *testing.T) {
 	assert.Equal(t, json.Number("0"), r.Account.CurrentBalanceWithSubAccounts)
 	assert.Equal(t, "2014-12-31T09:29:05-08:00", r.Account.MetaData.CreateTime.String())
 	assert.Equal(t, "2014-12-31T09:29:05-08:00", r.Account.MetaData.LastUpdatedTime.String())
+	assert.Equal(t, "9130354998027756", r.Account.MetaData.LastModifiedByRef.Value)
+	assert.Nil(t, r.Account.MetaData.CreatedByRef)
 	assert.Equal(t, AccountsReceivableAccountType, r.Account.AccountType)
 	assert.Equal(t, json.Number("0"), r.Account.CurrentBalance)
 	assert.True(t, r.Account.Active != nil && *r.Account.Active == true)
@@ -60,3 +64,70 @@ func TestAccount(t *testing.T) {
 	assert.Equal(t, "94", r.Account.ID)
 	assert.False(t, r.Account.SubAccount)
 }
+
+func TestCreateAccountTaxCodeRefGuard(t *testing.T) {
+	country := "US"
+	requests := 0
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v3/company/test-realm/companyinfo/test-realm":
+			w.Write([]byte(`{"CompanyInfo":{"Id":"1","SyncToken":"4","Country":"` + country + `"}}`))
+		case "/v3/company/test-realm/account":
+			w.Write([]byte(`{"Account":{"Id":"94","Name":"Sales"}}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	})
+
+	input := &AccountCreateInput{
+		Name:        "Sales",
+		AccountType: IncomeAccountType,
+		TaxCodeRef:  &ReferenceType{NameValue: NameValue{Value: "5"}},
+	}
+
+	_, err := client.CreateAccount(input)
+	assert.ErrorIs(t, err, ErrAccountTaxCodeRefUS)
+
+	_, err = client.UpdateAccount(&Account{ID: "94", TaxCodeRef: input.TaxCodeRef})
+	assert.ErrorIs(t, err, ErrAccountTaxCodeRefUS)
+	assert.Equal(t, 1, requests, "company country should be cached")
+
+	input.TaxCodeRef = nil
+	_, err = client.CreateAccount(input)
+	require.NoError(t, err)
+}
+
+func TestAccountCurrentBalanceFloat(t *testing.T) {
+	account := Account{CurrentBalance: "-42.5", CurrentBalanceWithSubAccounts: "bogus"}
+	assert.Equal(t, -42.5, account.CurrentBalanceFloat())
+	assert.Equal(t, 0.0, account.CurrentBalanceWithSubAccountsFloat())
+	assert.True(t, account.IsActive())
+}
+
+func TestUpsertAccountByNameMatchesParent(t *testing.T) {
+	var posted map[string]any
+
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == "/v3/company/test-realm/query":
+			w.Write([]byte(`{"QueryResponse":{"Account":[{"Id":"7","ParentRef":{"value":"2"}}]}}`))
+		case r.Method == http.MethodPost:
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&posted))
+			w.Write([]byte(`{"Account":{"Id":"90","SyncToken":"0","Name":"Fuel"}}`))
+		default:
+			t.Errorf("unexpected %s to %s", r.Method, r.URL.Path)
+		}
+	})
+
+	// The only "Fuel" account is a sub-account of 2, so a top-level one is created.
+	account, created, err := client.UpsertAccountByName(&AccountCreateInput{Name: "Fuel", AccountType: ExpenseAccountType})
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, "90", account.ID)
+	assert.Equal(t, map[string]any{"Name": "Fuel", "AccountType": "Expense"}, posted)
+}
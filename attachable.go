@@ -10,7 +10,6 @@ import (
 	"net/http"
 	"net/textproto"
 	"net/url"
-	"strconv"
 )
 
 type ContentType string
@@ -39,6 +38,8 @@ const (
 // Read-only fields (Id, SyncToken, MetaData, FileAccessUri, ThumbnailFileAccessUri,
 // TempDownloadUri, ThumbnailTempDownloadUri, Size) are populated by the service.
 type Attachable struct {
+	EntityMeta
+
 	ID                       string          `json:"Id,omitempty"`
 	SyncToken                string          `json:",omitempty"`
 	MetaData                 *MetaData       `json:",omitempty"`
@@ -79,21 +80,14 @@ type AttachableCreateInput struct {
 	Long          *string         `json:",omitempty"`
 	Tag           *string         `json:",omitempty"`
 	Lat           *string         `json:",omitempty"`
+	// Extra holds fields the struct does not model yet; see ExtraFields.
+	Extra ExtraFields `json:"-"`
 }
 
 // CreateAttachable creates the given Attachable on the QuickBooks server,
 // returning the resulting Attachable object.
 func (c *Client) CreateAttachable(input *AttachableCreateInput) (*Attachable, error) {
-	var resp struct {
-		Attachable Attachable
-		Time       Date
-	}
-
-	if err := c.post("attachable", input, &resp, nil); err != nil {
-		return nil, err
-	}
-
-	return &resp.Attachable, nil
+	return postSingle[Attachable](c, "attachable", input, nil)
 }
 
 // DeleteAttachable deletes the attachable.
@@ -102,7 +96,7 @@ func (c *Client) DeleteAttachable(attachable *Attachable) error {
 		return errors.New("missing id/sync token")
 	}
 
-	return c.post("attachable", attachable, nil, map[string]string{"operation": "delete"})
+	return c.postRetryingStale("attachable", "attachable/"+attachable.ID, &attachable.SyncToken, attachable, nil, map[string]string{"operation": "delete"})
 }
 
 // DownloadAttachable downloads the attachable and returns its URL.
@@ -114,7 +108,7 @@ func (c *Client) DownloadAttachable(id string) (s string, e error) {
 	urlValues.Add("minorversion", c.minorVersion)
 	endpointURL.RawQuery = urlValues.Encode()
 
-	req, err := http.NewRequest("GET", endpointURL.String(), nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", endpointURL.String(), nil)
 	if err != nil {
 		return "", err
 	}
@@ -138,77 +132,40 @@ func (c *Client) DownloadAttachable(id string) (s string, e error) {
 	return string(downloadURL), err
 }
 
-// FindAttachables gets the full list of Attachables in the QuickBooks account.
+// FindAttachables gets the full list of Attachables in the QuickBooks account, including their
+// file metadata (FileName, ContentType, Size, FileAccessUri).
 func (c *Client) FindAttachables() ([]Attachable, error) {
-	var resp struct {
-		QueryResponse struct {
-			Attachables   []Attachable `json:"Attachable"`
-			MaxResults    int
-			StartPosition int
-			TotalCount    int
-		}
-	}
-
-	if err := c.query("SELECT COUNT(*) FROM Attachable", &resp); err != nil {
-		return nil, err
-	}
-
-	if resp.QueryResponse.TotalCount == 0 {
-		return nil, errors.New("no attachables could be found")
-	}
-
-	attachables := make([]Attachable, 0, resp.QueryResponse.TotalCount)
-
-	for i := 0; i < resp.QueryResponse.TotalCount; i += queryPageSize {
-		query := "SELECT * FROM Attachable ORDERBY Id STARTPOSITION " + strconv.Itoa(i+1) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
-
-		if err := c.query(query, &resp); err != nil {
-			return nil, err
-		}
-
-		if resp.QueryResponse.Attachables == nil {
-			return nil, errors.New("no attachables could be found")
-		}
-
-		attachables = append(attachables, resp.QueryResponse.Attachables...)
-	}
+	return findAllPages[Attachable](c, "Attachable", "no attachables could be found")
+}
 
-	return attachables, nil
+// FindAttachablesWithOptions returns the attachables matching opts, ordered by opts.OrderBy.
+// Pass nil for opts to list them all.
+func (c *Client) FindAttachablesWithOptions(opts *ListOptions) ([]Attachable, error) {
+	return findAllWithOptions[Attachable](c, "Attachable", false, opts)
 }
 
 // FindAttachableByID finds the attachable by the given id.
 func (c *Client) FindAttachableByID(id string) (*Attachable, error) {
-	var resp struct {
-		Attachable Attachable
-		Time       Date
-	}
-
-	if err := c.get("attachable/"+id, &resp, nil); err != nil {
-		return nil, err
-	}
+	return getSingle[Attachable](c, "attachable/"+id, nil)
+}
 
-	return &resp.Attachable, nil
+// FindAttachablesByIDs returns the attachables with the given Ids. Ids that match nothing are skipped.
+func (c *Client) FindAttachablesByIDs(ids []string) ([]Attachable, error) {
+	return findByIDs[Attachable](c, "Attachable", false, ids)
 }
 
 // QueryAttachables accepts an SQL query and returns all attachables found using it.
 func (c *Client) QueryAttachables(query string) ([]Attachable, error) {
-	var resp struct {
-		QueryResponse struct {
-			Attachables   []Attachable `json:"Attachable"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
+	attachables, err := queryEntities[Attachable](c, "Attachable", query)
+	if err != nil {
+		return attachables, err
 	}
 
-	if resp.QueryResponse.Attachables == nil {
+	if attachables == nil {
 		return nil, errors.New("could not find any attachables")
 	}
 
-	return resp.QueryResponse.Attachables, nil
+	return attachables, nil
 }
 
 // UpdateAttachable updates the attachable.
@@ -232,20 +189,61 @@ func (c *Client) UpdateAttachable(attachable *Attachable) (*Attachable, error) {
 		Sparse:     true,
 	}
 
-	var attachableData struct {
-		Attachable Attachable
-		Time       Date
+	return updateSingle[Attachable](c, "attachable", "attachable/"+attachable.ID, &attachable.SyncToken, payload)
+}
+
+// LinkAttachable adds the given references to an existing attachable, so an uploaded file
+// can be attached to more objects without uploading it again.
+// References the attachable already has are not duplicated.
+func (c *Client) LinkAttachable(attachableID string, refs []AttachableRef) (*Attachable, error) {
+	if attachableID == "" {
+		return nil, errors.New("missing attachable id")
 	}
 
-	if err = c.post("attachable", payload, &attachableData, nil); err != nil {
+	existingAttachable, err := c.FindAttachableByID(attachableID)
+	if err != nil {
 		return nil, err
 	}
 
-	return &attachableData.Attachable, err
+	// QuickBooks replaces AttachableRef as a whole, so send the existing references too.
+	attachableRefs := existingAttachable.AttachableRef
+	for _, ref := range refs {
+		if ref.EntityRef == nil || ref.EntityRef.Value == "" {
+			return nil, errors.New("attachable ref is missing its entity ref")
+		}
+
+		if !hasAttachableRef(attachableRefs, ref) {
+			attachableRefs = append(attachableRefs, ref)
+		}
+	}
+
+	payload := struct {
+		ID            string          `json:"Id"`
+		SyncToken     string          `json:",omitempty"`
+		AttachableRef []AttachableRef `json:",omitempty"`
+		Sparse        bool            `json:"sparse"`
+	}{
+		ID:            existingAttachable.ID,
+		SyncToken:     existingAttachable.SyncToken,
+		AttachableRef: attachableRefs,
+		Sparse:        true,
+	}
+
+	return postSingle[Attachable](c, "attachable", payload, nil)
+}
+
+func hasAttachableRef(refs []AttachableRef, ref AttachableRef) bool {
+	for _, r := range refs {
+		if r.EntityRef != nil && r.EntityRef.Type == ref.EntityRef.Type && r.EntityRef.Value == ref.EntityRef.Value {
+			return true
+		}
+	}
+	return false
 }
 
 // UploadAttachable uploads a file and links it to a QuickBooks entity.
-// FileName and ContentType must be set in the input.
+// FileName and ContentType must be set in the input. Set several AttachableRef entries
+// to attach the file to more than one entity (e.g. a bill and its payment) at once.
 func (c *Client) UploadAttachable(input *AttachableCreateInput, data io.Reader) (att *Attachable, e error) {
 	if input.FileName == nil || input.ContentType == nil {
 		return nil, errors.New("FileName and ContentType are required for upload")
@@ -298,7 +296,7 @@ func (c *Client) UploadAttachable(input *AttachableCreateInput, data io.Reader)
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", endpointURL.String(), &buffer)
+	req, err := http.NewRequestWithContext(c.context(), "POST", endpointURL.String(), &buffer)
 	if err != nil {
 		return nil, err
 	}
@@ -318,15 +316,16 @@ func (c *Client) UploadAttachable(input *AttachableCreateInput, data io.Reader)
 	}
 
 	var r struct {
-		AttachableResponse []struct {
-			Attachable Attachable
-		}
-		Time Date
+		AttachableResponse []json.RawMessage
 	}
 
 	if err = json.NewDecoder(resp.Body).Decode(&r); err != nil {
 		return nil, err
 	}
 
-	return &r.AttachableResponse[0].Attachable, nil
+	if len(r.AttachableResponse) == 0 {
+		return nil, errors.New("upload response has no attachable")
+	}
+
+	return decodeSingle[Attachable](r.AttachableResponse[0])
 }
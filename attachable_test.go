@@ -2,6 +2,8 @@ package quickbooks
 
 import (
 	"encoding/json"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -52,3 +54,88 @@ func TestAttachable(t *testing.T) {
 	assert.Equal(t, "2015-11-17T11:05:15-08:00", r.Attachable.MetaData.CreateTime.String())
 	assert.Equal(t, "2015-11-17T11:05:15-08:00", r.Attachable.MetaData.LastUpdatedTime.String())
 }
+
+func TestLinkAttachable(t *testing.T) {
+	const existing = `{"Attachable":{"Id":"5000000000000010341","SyncToken":"1","AttachableRef":[{"EntityRef":{"type":"Bill","value":"151"}}]},"time":"2015-07-24T10:48:27.082-07:00"}`
+
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Method {
+		case http.MethodGet:
+			assert.Equal(t, "/v3/company/test-realm/attachable/5000000000000010341", r.URL.Path)
+			w.Write([]byte(existing))
+		case http.MethodPost:
+			var payload struct {
+				ID            string `json:"Id"`
+				SyncToken     string
+				AttachableRef []AttachableRef
+				Sparse        bool `json:"sparse"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			assert.Equal(t, "1", payload.SyncToken)
+			assert.True(t, payload.Sparse)
+			require.Len(t, payload.AttachableRef, 2)
+			assert.Equal(t, "151", payload.AttachableRef[0].EntityRef.Value)
+			assert.Equal(t, "BillPayment", payload.AttachableRef[1].EntityRef.Type)
+
+			w.Write([]byte(`{"Attachable":{"Id":"5000000000000010341","SyncToken":"2"},"time":"2015-07-24T10:48:27.082-07:00"}`))
+		}
+	})
+
+	attachable, err := client.LinkAttachable("5000000000000010341", []AttachableRef{
+		{EntityRef: &ReferenceType{NameValue: NameValue{Value: "151"}, Type: "Bill"}},
+		{EntityRef: &ReferenceType{NameValue: NameValue{Value: "152"}, Type: "BillPayment"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "2", attachable.SyncToken)
+}
+
+func TestFindAttachablesFileMetadata(t *testing.T) {
+	file := map[string]any{
+		"Id":            "1",
+		"FileName":      "receipt.pdf",
+		"ContentType":   "application/pdf",
+		"Size":          52344,
+		"FileAccessUri": "/v3/company/test-realm/download/1",
+	}
+
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+
+		var body any
+		switch {
+		case strings.HasPrefix(query, "SELECT COUNT(*)"):
+			body = map[string]any{"QueryResponse": map[string]any{"totalCount": queryPageSize + 1}}
+		case strings.Contains(query, "STARTPOSITION 1 "):
+			page := make([]any, queryPageSize)
+			for i := range page {
+				page[i] = file
+			}
+			body = map[string]any{"QueryResponse": map[string]any{"Attachable": page}}
+		default:
+			body = map[string]any{"QueryResponse": map[string]any{"Attachable": []any{
+				map[string]any{"Id": "2", "Note": "Call the vendor"},
+			}}}
+		}
+
+		require.NoError(t, json.NewEncoder(w).Encode(body))
+	})
+
+	attachables, err := client.FindAttachables()
+	require.NoError(t, err)
+	require.Len(t, attachables, queryPageSize+1)
+
+	first := attachables[0]
+	assert.Equal(t, "receipt.pdf", *first.FileName)
+	assert.Equal(t, PDF, *first.ContentType)
+	assert.Equal(t, json.Number("52344"), first.Size)
+	assert.Equal(t, "/v3/company/test-realm/download/1", first.FileAccessURI)
+
+	note := attachables[queryPageSize]
+	assert.Equal(t, "2", note.ID)
+	assert.Nil(t, note.FileName)
+	assert.Nil(t, note.ContentType)
+	assert.Empty(t, note.Size)
+	assert.Empty(t, note.FileAccessURI)
+}
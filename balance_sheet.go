@@ -0,0 +1,279 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// BalanceSheetQueryParams holds the optional query parameters for the BalanceSheet report.
+type BalanceSheetQueryParams struct {
+	// Cash or Accrual
+	AccountingMethod *string
+	StartDate        *string
+	EndDate          *string
+	// Predefined date range, e.g. "This Month", "Last Fiscal Year". Ignored when StartDate/EndDate are set.
+	DateMacro *string
+	// Total, Month, Week, Days, Quarter, Year, Customers, Vendors, Classes, Departments, Employees, ProductsAndServices
+	SummarizeColumnBy *string
+	// Comma separated lists of ids to filter on.
+	Customer   *string
+	Vendor     *string
+	Item       *string
+	Class      *string
+	Department *string
+	// ascend or descend
+	SortOrder *string
+}
+
+func (p *BalanceSheetQueryParams) toMap() map[string]string {
+	m := map[string]string{}
+	if p.AccountingMethod != nil {
+		m["accounting_method"] = *p.AccountingMethod
+	}
+	if p.StartDate != nil {
+		m["start_date"] = *p.StartDate
+	}
+	if p.EndDate != nil {
+		m["end_date"] = *p.EndDate
+	}
+	if p.DateMacro != nil {
+		m["date_macro"] = *p.DateMacro
+	}
+	if p.SummarizeColumnBy != nil {
+		m["summarize_column_by"] = *p.SummarizeColumnBy
+	}
+	if p.Customer != nil {
+		m["customer"] = *p.Customer
+	}
+	if p.Vendor != nil {
+		m["vendor"] = *p.Vendor
+	}
+	if p.Item != nil {
+		m["item"] = *p.Item
+	}
+	if p.Class != nil {
+		m["class"] = *p.Class
+	}
+	if p.Department != nil {
+		m["department"] = *p.Department
+	}
+	if p.SortOrder != nil {
+		m["sort_order"] = *p.SortOrder
+	}
+	return m
+}
+
+// Groups of the main sections of a BalanceSheet report. Liabilities and Equity are nested in
+// TotalLiabilitiesAndEquity.
+const (
+	BalanceSheetTotalAssets               = "TotalAssets"
+	BalanceSheetLiabilities               = "Liabilities"
+	BalanceSheetEquity                    = "Equity"
+	BalanceSheetTotalLiabilitiesAndEquity = "TotalLiabilitiesAndEquity"
+)
+
+// BalanceSheetCells is a line of cells of a Balance Sheet: RowHeader names the line and
+// Values holds one value per column after it.
+type BalanceSheetCells struct {
+	RowHeader TrialBalanceRowHeader
+	Values    []TrialBalanceValueRow
+}
+
+// BalanceSheetRow is one node of a Balance Sheet.
+//
+// A section, such as Current Assets or an account with sub-accounts, has a Header naming
+// it, nested Rows, and a Summary holding its subtotals. A data row is a single account:
+// Data names it and holds its balances, and the other fields are empty.
+type BalanceSheetRow struct {
+	Group   string
+	Header  *BalanceSheetCells
+	Data    *BalanceSheetCells
+	Rows    []BalanceSheetRow
+	Summary *BalanceSheetCells
+}
+
+// IsSection reports whether the row is a section rather than an account.
+func (r *BalanceSheetRow) IsSection() bool {
+	return r.Data == nil
+}
+
+// BalanceSheet is the BalanceSheet report as a tree of sections and accounts. Unlike a
+// TrialBalance it nests to any depth, with subtotals at every level.
+type BalanceSheet struct {
+	Header  TrialBalanceHeader
+	Columns []TrialBalanceColumn
+	Rows    []BalanceSheetRow
+}
+
+// Section returns the section with the given group, e.g. BalanceSheetEquity, searching
+// nested sections too, or nil if the report has none.
+func (bs *BalanceSheet) Section(group string) *BalanceSheetRow {
+	var find func(rows []BalanceSheetRow) *BalanceSheetRow
+	find = func(rows []BalanceSheetRow) *BalanceSheetRow {
+		for i := range rows {
+			if rows[i].IsSection() && rows[i].Group == group {
+				return &rows[i]
+			}
+			if found := find(rows[i].Rows); found != nil {
+				return found
+			}
+		}
+		return nil
+	}
+	return find(bs.Rows)
+}
+
+// Total returns the subtotal of the section with the given group in the last column, or ""
+// if there is no such section.
+func (bs *BalanceSheet) Total(group string) json.Number {
+	section := bs.Section(group)
+	if section == nil || section.Summary == nil || len(section.Summary.Values) == 0 {
+		return ""
+	}
+	return glAmount(section.Summary.Values[len(section.Summary.Values)-1].Value)
+}
+
+// newBalanceSheet converts the generic report into a BalanceSheet.
+func newBalanceSheet(report *Report) *BalanceSheet {
+	bs := &BalanceSheet{Header: report.Header, Rows: balanceSheetRows(report.Rows)}
+	for _, col := range report.Columns {
+		bs.Columns = append(bs.Columns, TrialBalanceColumn{ColType: col.ColType, ColTitle: col.ColTitle})
+	}
+	return bs
+}
+
+func balanceSheetRows(rows []ReportRow) []BalanceSheetRow {
+	var out []BalanceSheetRow
+	for _, row := range rows {
+		r := BalanceSheetRow{Group: row.Group}
+		if row.IsSection() {
+			r.Header = balanceSheetCells(row.Header)
+			r.Rows = balanceSheetRows(row.Rows)
+			r.Summary = balanceSheetCells(row.Summary)
+		} else {
+			r.Data = balanceSheetCells(row.ColData)
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// balanceSheetCells returns the cells as a BalanceSheetCells, or nil if there are none.
+func balanceSheetCells(cells []ReportColData) *BalanceSheetCells {
+	if len(cells) == 0 {
+		return nil
+	}
+
+	line := &BalanceSheetCells{RowHeader: TrialBalanceRowHeader{ID: cells[0].ID, Value: cells[0].Value}}
+	for _, cell := range cells[1:] {
+		line.Values = append(line.Values, TrialBalanceValueRow{Value: cell.Value})
+	}
+	return line
+}
+
+// GetBalanceSheet fetches the BalanceSheet report. Pass nil for params to use the API
+// defaults. GetBalanceSheetSummary compares several periods instead.
+func (c *Client) GetBalanceSheet(params *BalanceSheetQueryParams) (*BalanceSheet, error) {
+	var queryParams map[string]string
+	if params != nil {
+		queryParams = params.toMap()
+	}
+
+	report, err := c.getReport("BalanceSheet", queryParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return newBalanceSheet(report), nil
+}
+
+// ReportPeriod selects one period of a comparative report, either by its end date or by a
+// date macro such as "Last Fiscal Year". Label is optional and defaults to the report's EndPeriod.
+type ReportPeriod struct {
+	Label     string
+	EndDate   *Date
+	DateMacro string
+}
+
+// ComparativeRow is one account of a comparative report.
+// Amounts holds one value per period, in period order; it is "" for periods in which the
+// account does not appear.
+type ComparativeRow struct {
+	AccountID string
+	Account   string
+	Amounts   []json.Number
+}
+
+// ComparativeBalanceSheet is a Balance Sheet with one amount column per period.
+type ComparativeBalanceSheet struct {
+	Periods []string
+	Rows    []ComparativeRow
+}
+
+// GetBalanceSheetSummary fetches a Balance Sheet for each of the given periods (one request per
+// period) and aligns the account rows across them. Rows are matched by the account Id of their
+// first column, or by name for rows without one (e.g. "Net Income"), and keep the order in which
+// they first appear. Only account rows are aligned; section subtotals are not included.
+//
+// params may be nil; its date fields and SummarizeColumnBy are overridden for every period.
+func (c *Client) GetBalanceSheetSummary(periods []ReportPeriod, params *BalanceSheetQueryParams) (*ComparativeBalanceSheet, error) {
+	if len(periods) == 0 {
+		return nil, errors.New("no periods given")
+	}
+
+	result := &ComparativeBalanceSheet{Periods: make([]string, len(periods))}
+	rowIndex := map[string]int{}
+
+	for i, period := range periods {
+		queryParams := map[string]string{}
+		if params != nil {
+			queryParams = params.toMap()
+		}
+		delete(queryParams, "start_date")
+		delete(queryParams, "end_date")
+		delete(queryParams, "date_macro")
+		queryParams["summarize_column_by"] = "Total"
+
+		switch {
+		case period.EndDate != nil:
+			queryParams["end_date"] = period.EndDate.Format(secondFormat)
+		case period.DateMacro != "":
+			queryParams["date_macro"] = period.DateMacro
+		default:
+			return nil, errors.New("period needs an end date or a date macro")
+		}
+
+		report, err := c.getReport("BalanceSheet", queryParams)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Periods[i] = period.Label
+		if result.Periods[i] == "" {
+			result.Periods[i] = report.Header.EndPeriod
+		}
+
+		for _, row := range report.DataRows() {
+			account := row.cell(0)
+			key := account.ID
+			if key == "" {
+				key = "name:" + account.Value
+			}
+
+			idx, ok := rowIndex[key]
+			if !ok {
+				idx = len(result.Rows)
+				rowIndex[key] = idx
+				result.Rows = append(result.Rows, ComparativeRow{
+					AccountID: account.ID,
+					Account:   account.Value,
+					Amounts:   make([]json.Number, len(periods)),
+				})
+			}
+
+			result.Rows[idx].Amounts[i] = json.Number(row.cell(len(row.ColData) - 1).Value)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,134 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBalanceSheetSummary(t *testing.T) {
+	responses := map[string]string{
+		"2015-12-31": `{
+  "Header": {"ReportName": "BalanceSheet", "EndPeriod": "2015-12-31"},
+  "Columns": {"Column": [{"ColType": "Account", "ColTitle": ""}, {"ColType": "Money", "ColTitle": "Total"}]},
+  "Rows": {"Row": [
+    {"type": "Section", "group": "TotalAssets",
+     "Header": {"ColData": [{"value": "ASSETS"}, {"value": ""}]},
+     "Rows": {"Row": [
+       {"type": "Data", "ColData": [{"value": "Checking", "id": "35"}, {"value": "1201.00"}]}
+     ]},
+     "Summary": {"ColData": [{"value": "TOTAL ASSETS"}, {"value": "1201.00"}]}},
+    {"type": "Data", "ColData": [{"value": "Net Income"}, {"value": "1201.00"}]}
+  ]}
+}`,
+		"2016-12-31": `{
+  "Header": {"ReportName": "BalanceSheet", "EndPeriod": "2016-12-31"},
+  "Columns": {"Column": [{"ColType": "Account", "ColTitle": ""}, {"ColType": "Money", "ColTitle": "Total"}]},
+  "Rows": {"Row": [
+    {"type": "Section", "group": "TotalAssets",
+     "Header": {"ColData": [{"value": "ASSETS"}, {"value": ""}]},
+     "Rows": {"Row": [
+       {"type": "Data", "ColData": [{"value": "Checking", "id": "35"}, {"value": "1500.00"}]},
+       {"type": "Data", "ColData": [{"value": "Savings", "id": "36"}, {"value": "800.00"}]}
+     ]},
+     "Summary": {"ColData": [{"value": "TOTAL ASSETS"}, {"value": "2300.00"}]}},
+    {"type": "Data", "ColData": [{"value": "Net Income"}, {"value": "1099.00"}]}
+  ]}
+}`,
+	}
+
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v3/company/test-realm/reports/BalanceSheet", r.URL.Path)
+		q := r.URL.Query()
+		assert.Equal(t, "Accrual", q.Get("accounting_method"))
+		assert.Equal(t, "Total", q.Get("summarize_column_by"))
+		assert.Empty(t, q.Get("start_date"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(responses[q.Get("end_date")]))
+	})
+
+	method := "Accrual"
+	summary, err := client.GetBalanceSheetSummary([]ReportPeriod{
+		{EndDate: &Date{time.Date(2015, 12, 31, 0, 0, 0, 0, time.UTC)}},
+		{Label: "FY2016", EndDate: &Date{time.Date(2016, 12, 31, 0, 0, 0, 0, time.UTC)}},
+	}, &BalanceSheetQueryParams{AccountingMethod: &method})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"2015-12-31", "FY2016"}, summary.Periods)
+	assert.Equal(t, []ComparativeRow{
+		{AccountID: "35", Account: "Checking", Amounts: []json.Number{"1201.00", "1500.00"}},
+		{Account: "Net Income", Amounts: []json.Number{"1201.00", "1099.00"}},
+		{AccountID: "36", Account: "Savings", Amounts: []json.Number{"", "800.00"}},
+	}, summary.Rows)
+}
+
+func TestGetBalanceSheet(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v3/company/test-realm/reports/BalanceSheet", r.URL.Path)
+		assert.Equal(t, "Accrual", r.URL.Query().Get("accounting_method"))
+		w.Write([]byte(`{
+  "Header": {"ReportName": "BalanceSheet", "EndPeriod": "2024-03-31", "Currency": "USD"},
+  "Columns": {"Column": [{"ColType": "Account", "ColTitle": ""}, {"ColType": "Money", "ColTitle": "Total"}]},
+  "Rows": {"Row": [
+    {"type": "Section", "group": "TotalAssets",
+     "Header": {"ColData": [{"value": "ASSETS"}, {"value": ""}]},
+     "Rows": {"Row": [
+       {"type": "Section", "group": "CurrentAssets",
+        "Header": {"ColData": [{"value": "Current Assets"}, {"value": ""}]},
+        "Rows": {"Row": [
+          {"type": "Section", "group": "BankAccounts",
+           "Header": {"ColData": [{"value": "Bank Accounts"}, {"value": ""}]},
+           "Rows": {"Row": [
+             {"type": "Data", "ColData": [{"value": "Checking", "id": "35"}, {"value": "1201.00"}]},
+             {"type": "Data", "ColData": [{"value": "Savings", "id": "36"}, {"value": "800.00"}]}
+           ]},
+           "Summary": {"ColData": [{"value": "Total Bank Accounts"}, {"value": "2001.00"}]}}
+        ]},
+        "Summary": {"ColData": [{"value": "Total Current Assets"}, {"value": "2001.00"}]}}
+     ]},
+     "Summary": {"ColData": [{"value": "TOTAL ASSETS"}, {"value": "2,001.00"}]}},
+    {"type": "Section", "group": "TotalLiabilitiesAndEquity",
+     "Header": {"ColData": [{"value": "LIABILITIES AND EQUITY"}, {"value": ""}]},
+     "Rows": {"Row": [
+       {"type": "Section", "group": "Equity",
+        "Header": {"ColData": [{"value": "Equity"}, {"value": ""}]},
+        "Rows": {"Row": [
+          {"type": "Data", "ColData": [{"value": "Net Income"}, {"value": "2001.00"}]}
+        ]},
+        "Summary": {"ColData": [{"value": "Total Equity"}, {"value": "2001.00"}]}}
+     ]},
+     "Summary": {"ColData": [{"value": "TOTAL LIABILITIES AND EQUITY"}, {"value": "2001.00"}]}}
+  ]}
+}`))
+	})
+
+	method := "Accrual"
+	bs, err := client.GetBalanceSheet(&BalanceSheetQueryParams{AccountingMethod: &method})
+	require.NoError(t, err)
+
+	assert.Equal(t, "2024-03-31", bs.Header.EndPeriod)
+	assert.Equal(t, []TrialBalanceColumn{{ColType: "Account"}, {ColType: "Money", ColTitle: "Total"}}, bs.Columns)
+	require.Len(t, bs.Rows, 2)
+
+	assets := bs.Rows[0]
+	assert.True(t, assets.IsSection())
+	assert.Equal(t, "ASSETS", assets.Header.RowHeader.Value)
+	assert.Equal(t, "TOTAL ASSETS", assets.Summary.RowHeader.Value)
+
+	bank := bs.Section("BankAccounts")
+	require.NotNil(t, bank)
+	require.Len(t, bank.Rows, 2)
+	checking := bank.Rows[0]
+	assert.False(t, checking.IsSection())
+	assert.Equal(t, &BalanceSheetCells{RowHeader: TrialBalanceRowHeader{ID: "35", Value: "Checking"}, Values: []TrialBalanceValueRow{{Value: "1201.00"}}}, checking.Data)
+	assert.Nil(t, checking.Header)
+
+	assert.Equal(t, json.Number("2001.00"), bs.Total(BalanceSheetTotalAssets))
+	assert.Equal(t, json.Number("2001.00"), bs.Total(BalanceSheetEquity))
+	assert.Equal(t, json.Number(""), bs.Total(BalanceSheetLiabilities))
+}
@@ -0,0 +1,283 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// batchMaxItems is the number of operations QuickBooks accepts in one batch request.
+const batchMaxItems = 30
+
+// Batch operations.
+const (
+	BatchCreate = "create"
+	BatchUpdate = "update"
+	BatchDelete = "delete"
+)
+
+// BatchItem is one operation of a batch request: either a write of Payload as Entity
+// (e.g. Entity "Invoice" with an *InvoiceCreateInput, Operation BatchCreate) or a Query.
+type BatchItem struct {
+	// BID identifies the item in the response. Items without one are numbered from "1".
+	BID       string
+	Operation string
+	Entity    string
+	Payload   any
+	Query     string
+	// OptionsData carries operation options such as "void".
+	OptionsData string
+}
+
+// MarshalJSON encodes the item the way the batch endpoint expects, with the payload under
+// the entity's name.
+func (item BatchItem) MarshalJSON() ([]byte, error) {
+	m := map[string]any{"bId": item.BID}
+	if item.Query != "" {
+		m["Query"] = item.Query
+		return json.Marshal(m)
+	}
+
+	m["operation"] = item.Operation
+	m[item.Entity] = item.Payload
+	if item.OptionsData != "" {
+		m["optionsData"] = item.OptionsData
+	}
+	return json.Marshal(m)
+}
+
+// BatchQueryResult holds the result of a query item.
+type BatchQueryResult struct {
+	EntityName string
+	// Entities holds pointers to the entity structs, e.g. *Invoice, or the raw JSON of each
+	// object when the entity is not known to this package.
+	Entities      []any
+	StartPosition int
+	MaxResults    int
+	TotalCount    int
+}
+
+// BatchItemResponse is the result of one BatchItem. Exactly one of Entity, QueryResult and
+// Err is set.
+type BatchItemResponse struct {
+	BID string
+	// EntityName and Entity hold the object a write returned. Entity is a pointer to the
+	// entity struct, e.g. *Invoice, or json.RawMessage when the entity is not known to this package.
+	EntityName  string
+	Entity      any
+	QueryResult *BatchQueryResult
+	// Err is the item's fault as a Failure, or a *StaleObjectError for stale SyncTokens,
+	// exactly as a single request would have returned it.
+	Err error
+}
+
+// UnmarshalJSON decodes a batch item response, whose object sits under a key named after
+// its entity, "QueryResponse" or "Fault".
+func (r *BatchItemResponse) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	*r = BatchItemResponse{}
+	for key, raw := range fields {
+		switch key {
+		case "bId":
+			if err := json.Unmarshal(raw, &r.BID); err != nil {
+				return err
+			}
+		case "Fault":
+			var failure Failure
+			if err := json.Unmarshal(raw, &failure.Fault); err != nil {
+				return fmt.Errorf("failed to unmarshal Fault: %v", err)
+			}
+			r.Err = asStaleObject(failure)
+		case "QueryResponse":
+			result, err := decodeBatchQueryResult(raw)
+			if err != nil {
+				return err
+			}
+			r.QueryResult = result
+		default:
+			entity, err := decodeBatchEntity(key, raw)
+			if err != nil {
+				return err
+			}
+			r.EntityName = key
+			r.Entity = entity
+		}
+	}
+
+	return nil
+}
+
+func decodeBatchEntity(name string, raw json.RawMessage) (any, error) {
+	finder, ok := entityFinders[name]
+	if !ok || finder.decode == nil {
+		return raw, nil
+	}
+
+	entity, err := finder.decode(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %v", name, err)
+	}
+	return entity, nil
+}
+
+func decodeBatchQueryResult(raw json.RawMessage) (*BatchQueryResult, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+
+	result := &BatchQueryResult{}
+	for key, value := range fields {
+		var err error
+		switch key {
+		case "startPosition":
+			err = json.Unmarshal(value, &result.StartPosition)
+		case "maxResults":
+			err = json.Unmarshal(value, &result.MaxResults)
+		case "totalCount":
+			err = json.Unmarshal(value, &result.TotalCount)
+		default:
+			var objects []json.RawMessage
+			if err = json.Unmarshal(value, &objects); err != nil {
+				break
+			}
+
+			result.EntityName = key
+			for _, object := range objects {
+				entity, err := decodeBatchEntity(key, object)
+				if err != nil {
+					return nil, err
+				}
+				result.Entities = append(result.Entities, entity)
+			}
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal QueryResponse.%s: %v", key, err)
+		}
+	}
+
+	return result, nil
+}
+
+// Batch sends up to 30 operations in one request. A failing item does not fail the batch:
+// its error is in the matching BatchItemResponse.Err. The responses are in the order of items,
+// matched by BID.
+func (c *Client) Batch(items []BatchItem) ([]BatchItemResponse, error) {
+	if len(items) == 0 {
+		return nil, errors.New("empty batch")
+	}
+
+	if len(items) > batchMaxItems {
+		return nil, fmt.Errorf("a batch holds at most %d items, got %d", batchMaxItems, len(items))
+	}
+
+	request := make([]BatchItem, len(items))
+	index := map[string]int{}
+	for i, item := range items {
+		if item.BID == "" {
+			item.BID = strconv.Itoa(i + 1)
+		}
+
+		if _, ok := index[item.BID]; ok {
+			return nil, fmt.Errorf("duplicate bId %q", item.BID)
+		}
+
+		if item.Query == "" && (item.Operation == "" || item.Entity == "") {
+			return nil, fmt.Errorf("item %s needs either a Query or an Operation and Entity", item.BID)
+		}
+
+		index[item.BID] = i
+		request[i] = item
+	}
+
+	payload := struct {
+		BatchItemRequest []BatchItem
+	}{request}
+
+	var resp struct {
+		BatchItemResponse []BatchItemResponse
+	}
+
+	if err := c.post("batch", payload, &resp, nil); err != nil {
+		return nil, err
+	}
+
+	responses := make([]BatchItemResponse, len(items))
+	seen := make([]bool, len(items))
+	for _, r := range resp.BatchItemResponse {
+		i, ok := index[r.BID]
+		if !ok {
+			return nil, fmt.Errorf("batch response for unknown bId %q", r.BID)
+		}
+		responses[i] = r
+		seen[i] = true
+	}
+
+	for i, ok := range seen {
+		if !ok {
+			return nil, fmt.Errorf("batch response is missing bId %q", request[i].BID)
+		}
+	}
+
+	return responses, nil
+}
+
+// BatchRequest collects batch operations, numbering their BIDs "1", "2", ... in the order
+// they are added. Each method returns the BID of the new item, to find its response by.
+// Client.SendBatch sends any number of items, batchMaxItems per request.
+type BatchRequest struct {
+	Items []BatchItem
+}
+
+func (b *BatchRequest) add(item BatchItem) string {
+	item.BID = strconv.Itoa(len(b.Items) + 1)
+	b.Items = append(b.Items, item)
+	return item.BID
+}
+
+// Create adds the creation of entity (e.g. "Invoice") from input, e.g. an *InvoiceCreateInput.
+func (b *BatchRequest) Create(entity string, input any) string {
+	return b.add(BatchItem{Operation: BatchCreate, Entity: entity, Payload: input})
+}
+
+// Update adds an update of object, a domain struct such as *Invoice with its Id and
+// SyncToken set. Wrap it with a "sparse": true field for a sparse update.
+func (b *BatchRequest) Update(entity string, object any) string {
+	return b.add(BatchItem{Operation: BatchUpdate, Entity: entity, Payload: object})
+}
+
+// Delete adds the deletion of object, which needs its Id and SyncToken.
+func (b *BatchRequest) Delete(entity string, object any) string {
+	return b.add(BatchItem{Operation: BatchDelete, Entity: entity, Payload: object})
+}
+
+// Query adds a query; its result comes back in BatchItemResponse.QueryResult.
+func (b *BatchRequest) Query(query string) string {
+	return b.add(BatchItem{Query: query})
+}
+
+// SendBatch sends the items of b in batches of up to 30 and returns the responses in the
+// order of b.Items. As with Batch, a failing item only sets its own Err. If a whole batch
+// request fails, the responses of the batches already sent are returned with the error.
+func (c *Client) SendBatch(b *BatchRequest) ([]BatchItemResponse, error) {
+	if len(b.Items) == 0 {
+		return nil, errors.New("empty batch")
+	}
+
+	responses := make([]BatchItemResponse, 0, len(b.Items))
+	for start := 0; start < len(b.Items); start += batchMaxItems {
+		chunk, err := c.Batch(b.Items[start:min(start+batchMaxItems, len(b.Items))])
+		if err != nil {
+			return responses, err
+		}
+		responses = append(responses, chunk...)
+	}
+
+	return responses, nil
+}
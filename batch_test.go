@@ -0,0 +1,117 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatch(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v3/company/test-realm/batch", r.URL.Path)
+
+		var body struct {
+			BatchItemRequest []map[string]json.RawMessage
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Len(t, body.BatchItemRequest, 4)
+		assert.JSONEq(t, `"create"`, string(body.BatchItemRequest[0]["operation"]))
+		assert.Contains(t, body.BatchItemRequest[0], "Vendor")
+		assert.JSONEq(t, `"2"`, string(body.BatchItemRequest[1]["bId"]))
+		assert.JSONEq(t, `"SELECT * FROM Customer MAXRESULTS 2"`, string(body.BatchItemRequest[2]["Query"]))
+
+		// QuickBooks does not promise to answer in request order.
+		w.Write([]byte(`{"BatchItemResponse": [
+  {"bId": "query", "QueryResponse": {"Customer": [{"Id": "1"}, {"Id": "2"}], "startPosition": 1, "maxResults": 2}},
+  {"bId": "2", "Fault": {"type": "ValidationFault", "Error": [{"Message": "Duplicate Name Exists Error", "Detail": "The name supplied already exists. : Another customer already uses this name.", "code": "6240"}]}},
+  {"bId": "vendor", "Vendor": {"Id": "56", "DisplayName": "Bob's Burger Joint"}},
+  {"bId": "4", "Fault": {"type": "ValidationFault", "Error": [{"Message": "Stale Object Error", "Detail": "Stale Object Error : You and root were working on this at the same time. root finished before you did, so your work was not saved. Current SyncToken 5", "code": "5010"}]}}
+], "time": "2024-01-01T00:00:00.000-08:00"}`))
+	})
+
+	responses, err := client.Batch([]BatchItem{
+		{BID: "vendor", Operation: BatchCreate, Entity: "Vendor", Payload: &VendorCreateInput{}},
+		{Operation: BatchCreate, Entity: "Customer", Payload: &CustomerCreateInput{}},
+		{BID: "query", Query: "SELECT * FROM Customer MAXRESULTS 2"},
+		{Operation: BatchUpdate, Entity: "Invoice", Payload: &Invoice{ID: "130", SyncToken: "4"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, responses, 4)
+
+	vendor, ok := responses[0].Entity.(*Vendor)
+	require.True(t, ok)
+	assert.Equal(t, "56", vendor.ID)
+	assert.NoError(t, responses[0].Err)
+
+	var failure Failure
+	require.ErrorAs(t, responses[1].Err, &failure)
+	assert.Equal(t, "6240", failure.Fault.Error[0].Code)
+	assert.Equal(t, "ValidationFault", failure.Fault.Type)
+	assert.Nil(t, responses[1].Entity)
+
+	result := responses[2].QueryResult
+	require.NotNil(t, result)
+	assert.Equal(t, "Customer", result.EntityName)
+	assert.Equal(t, 2, result.MaxResults)
+	require.Len(t, result.Entities, 2)
+	assert.Equal(t, "2", result.Entities[1].(*Customer).ID)
+
+	var staleErr *StaleObjectError
+	require.ErrorAs(t, responses[3].Err, &staleErr)
+	assert.Equal(t, "5", staleErr.CurrentSyncToken)
+}
+
+func TestBatchValidation(t *testing.T) {
+	client := &Client{}
+
+	_, err := client.Batch(nil)
+	assert.Error(t, err)
+
+	_, err = client.Batch(make([]BatchItem, 31))
+	assert.EqualError(t, err, "a batch holds at most 30 items, got 31")
+
+	_, err = client.Batch([]BatchItem{{BID: "a", Query: "SELECT * FROM Item"}, {BID: "a", Query: "SELECT * FROM Item"}})
+	assert.EqualError(t, err, `duplicate bId "a"`)
+
+	_, err = client.Batch([]BatchItem{{Entity: "Item"}})
+	assert.Error(t, err)
+}
+
+func TestSendBatch(t *testing.T) {
+	var sizes []int
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			BatchItemRequest []struct {
+				BID string `json:"bId"`
+			}
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		sizes = append(sizes, len(body.BatchItemRequest))
+
+		var resp struct {
+			BatchItemResponse []map[string]any
+		}
+		for _, item := range body.BatchItemRequest {
+			resp.BatchItemResponse = append(resp.BatchItemResponse, map[string]any{"bId": item.BID, "Customer": map[string]string{"Id": "c" + item.BID}})
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	})
+
+	var batch BatchRequest
+	for i := 0; i < 64; i++ {
+		batch.Create("Customer", &CustomerCreateInput{})
+	}
+	assert.Equal(t, "65", batch.Query("SELECT * FROM Customer"))
+
+	responses, err := client.SendBatch(&batch)
+	require.NoError(t, err)
+	assert.Equal(t, []int{30, 30, 5}, sizes)
+	require.Len(t, responses, 65)
+	assert.Equal(t, "c31", responses[30].Entity.(*Customer).ID)
+
+	_, err = client.SendBatch(&BatchRequest{})
+	assert.Error(t, err)
+}
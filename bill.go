@@ -4,13 +4,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"strconv"
+	"time"
 )
 
 // Bill represents a QuickBooks Bill object as returned by the API.
 // Read-only fields (Id, SyncToken, MetaData, TotalAmt, HomeBalance, Balance, RecurDataRef)
 // are populated by the service.
 type Bill struct {
+	EntityMeta
+
 	ID                      string         `json:"Id,omitempty"`
 	SyncToken               string         `json:",omitempty"`
 	MetaData                *MetaData      `json:",omitempty"`
@@ -29,7 +31,7 @@ type Bill struct {
 	IncludeInAnnualTPAR     *bool          `json:",omitempty"`
 	LinkedTxn               []LinkedTxn    `json:",omitempty"`
 	TxnTaxDetail            *TxnTaxDetail  `json:",omitempty"`
-	// GlobalTaxCalculation: TaxExcluded, TaxInclusive, NotApplicable
+	GlobalTaxCalculation    GlobalTaxCalculation `json:",omitempty"`
 	TotalAmt     json.Number    `json:",omitempty"`
 	HomeBalance  json.Number    `json:",omitempty"`
 	Balance      json.Number    `json:",omitempty"`
@@ -54,142 +56,155 @@ type BillCreateInput struct {
 	IncludeInAnnualTPAR     *bool          `json:",omitempty"`
 	LinkedTxn               []LinkedTxn    `json:",omitempty"`
 	TxnTaxDetail            *TxnTaxDetail  `json:",omitempty"`
-	// GlobalTaxCalculation: TaxExcluded, TaxInclusive, NotApplicable
+	GlobalTaxCalculation    GlobalTaxCalculation `json:",omitempty"`
+	// Extra holds fields the struct does not model yet; see ExtraFields.
+	Extra ExtraFields `json:"-"`
 }
 
 // CreateBill creates the given Bill on the QuickBooks server, returning
 // the resulting Bill object.
 func (c *Client) CreateBill(input *BillCreateInput) (*Bill, error) {
-	var resp struct {
-		Bill Bill
-		Time Date
-	}
-
-	if err := c.post("bill", input, &resp, nil); err != nil {
+	if err := input.validate(); err != nil {
 		return nil, err
 	}
 
-	return &resp.Bill, nil
+	return postSingle[Bill](c, "bill", input, nil)
 }
 
-// DeleteBill deletes the bill.
-func (c *Client) DeleteBill(bill *Bill) error {
-	if bill.ID == "" || bill.SyncToken == "" {
-		return errors.New("missing id/sync token")
+// BuildCreateBillPayload runs the same checks as CreateBill and returns the exact JSON body it
+// would POST, without sending anything.
+func (c *Client) BuildCreateBillPayload(input *BillCreateInput) ([]byte, error) {
+	if err := input.validate(); err != nil {
+		return nil, err
 	}
 
-	return c.post("bill", bill, nil, map[string]string{"operation": "delete"})
+	return json.Marshal(input)
 }
 
-// FindBills gets the full list of Bills in the QuickBooks account.
-func (c *Client) FindBills() ([]Bill, error) {
-	var resp struct {
-		QueryResponse struct {
-			Bills         []Bill `json:"Bill"`
-			MaxResults    int
-			StartPosition int
-			TotalCount    int
-		}
+func (input *BillCreateInput) validate() error {
+	if input.VendorRef.Value == "" {
+		return errors.New("missing vendor ref")
 	}
 
-	if err := c.query("SELECT COUNT(*) FROM Bill", &resp); err != nil {
-		return nil, err
+	if len(input.Line) == 0 {
+		return errors.New("a bill needs at least one line")
 	}
 
-	if resp.QueryResponse.TotalCount == 0 {
-		return nil, errors.New("no bills could be found")
+	if err := validateGlobalTaxCalculation(input.GlobalTaxCalculation); err != nil {
+		return err
 	}
 
-	bills := make([]Bill, 0, resp.QueryResponse.TotalCount)
+	return ValidateExpenseLines(input.Line)
+}
 
-	for i := 0; i < resp.QueryResponse.TotalCount; i += queryPageSize {
-		query := "SELECT * FROM Bill ORDERBY Id STARTPOSITION " + strconv.Itoa(i+1) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
+// DeleteBill deletes the bill.
+func (c *Client) DeleteBill(bill *Bill) error {
+	if bill.ID == "" || bill.SyncToken == "" {
+		return errors.New("missing id/sync token")
+	}
 
-		if err := c.query(query, &resp); err != nil {
-			return nil, err
-		}
+	return c.postRetryingStale("bill", "bill/"+bill.ID, &bill.SyncToken, bill, nil, map[string]string{"operation": "delete"})
+}
 
-		if resp.QueryResponse.Bills == nil {
-			return nil, errors.New("no bills could be found")
-		}
+// FindBills gets the full list of Bills in the QuickBooks account.
+func (c *Client) FindBills() ([]Bill, error) {
+	return findAllPages[Bill](c, "Bill", "no bills could be found")
+}
 
-		bills = append(bills, resp.QueryResponse.Bills...)
-	}
+// FindBillsWithOptions returns the bills matching opts, ordered by opts.OrderBy.
+// Pass nil for opts to list them all.
+func (c *Client) FindBillsWithOptions(opts *ListOptions) ([]Bill, error) {
+	return findAllWithOptions[Bill](c, "Bill", false, opts)
+}
 
-	return bills, nil
+// FindBillsByAmount returns the bills whose TotalAmt is between min and max inclusive, on top
+// of any filter in opts. Leave min or max empty for an open-ended range.
+func (c *Client) FindBillsByAmount(min, max json.Number, opts *ListOptions) ([]Bill, error) {
+	return findByAmount[Bill](c, "Bill", min, max, opts)
 }
 
 // FindBillByID finds the bill by the given id.
 func (c *Client) FindBillByID(id string) (*Bill, error) {
-	var resp struct {
-		Bill Bill
-		Time Date
-	}
-
-	if err := c.get("bill/"+id, &resp, nil); err != nil {
-		return nil, err
-	}
+	return getSingle[Bill](c, "bill/"+id, nil)
+}
 
-	return &resp.Bill, nil
+// FindBillsByIDs returns the bills with the given Ids. Ids that match nothing are skipped.
+func (c *Client) FindBillsByIDs(ids []string) ([]Bill, error) {
+	return findByIDs[Bill](c, "Bill", false, ids)
 }
 
 // QueryBills accepts an SQL query and returns all bills found using it.
 func (c *Client) QueryBills(query string) ([]Bill, error) {
-	var resp struct {
-		QueryResponse struct {
-			Bills         []Bill `json:"Bill"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
+	bills, err := queryEntities[Bill](c, "Bill", query)
+	if err != nil {
+		return bills, err
 	}
 
-	if resp.QueryResponse.Bills == nil {
+	if bills == nil {
 		return nil, errors.New("could not find any bills")
 	}
 
-	return resp.QueryResponse.Bills, nil
+	return bills, nil
 }
 
 // ListBills returns one page of Bills ordered by Id.
 // Pass an empty pageToken to start from the beginning.
 // The returned nextPageToken is empty when there are no more results.
 func (c *Client) ListBills(pageToken string, pageSize int) (*ListResponse[Bill], error) {
-	if pageSize <= 0 || pageSize > queryPageSize {
-		pageSize = queryPageSize
+	return listPage[Bill](c, "Bill", pageToken, pageSize)
+}
+
+// EarlyPaymentDiscount describes the early-payment discount a bill's term offers.
+type EarlyPaymentDiscount struct {
+	// Applies is true when the bill can still be paid with the discount.
+	Applies bool
+	// Deadline is the last day the discount can be taken.
+	Deadline Date
+	// Amount is the discount on the open balance and PayAmount what is left to pay after it,
+	// both rounded to cents. They are computed even when the deadline has passed.
+	Amount    json.Number
+	PayAmount json.Number
+}
+
+// EarlyPaymentDiscount works out whether paying the bill on asOf captures the discount of
+// term, the Term its SalesTermRef points at. The discount is taken off the open Balance,
+// or TotalAmt when the bill does not report one. It returns nil if the term has no discount.
+func (b *Bill) EarlyPaymentDiscount(term *Term, asOf time.Time) (*EarlyPaymentDiscount, error) {
+	if b.SalesTermRef == nil || b.SalesTermRef.Value != term.ID {
+		return nil, fmt.Errorf("term %s is not the sales term of bill %s", term.ID, b.ID)
+	}
+	if b.TxnDate == nil {
+		return nil, errors.New("bill has no TxnDate")
 	}
 
-	startPosition := 1
-	if pageToken != "" {
-		var err error
-		startPosition, err = strconv.Atoi(pageToken)
-		if err != nil {
-			return nil, fmt.Errorf("invalid page token: %v", err)
-		}
+	deadline, ok := term.DiscountDeadline(b.TxnDate.Time)
+	if !ok {
+		return nil, nil
 	}
 
-	var resp struct {
-		QueryResponse struct {
-			Bills         []Bill `json:"Bill"`
-			StartPosition int
-			MaxResults    int
-		}
+	base := b.Balance
+	if base == "" {
+		base = b.TotalAmt
 	}
 
-	query := "SELECT * FROM Bill ORDERBY Id STARTPOSITION " + strconv.Itoa(startPosition) + " MAXRESULTS " + strconv.Itoa(pageSize)
-	if err := c.query(query, &resp); err != nil {
+	payAmount, err := markedUpAmount(base, json.Number("-"+term.DiscountPercent.String()))
+	if err != nil {
 		return nil, err
 	}
-
-	result := &ListResponse[Bill]{Items: resp.QueryResponse.Bills}
-	if len(result.Items) == pageSize {
-		result.NextPageToken = strconv.Itoa(startPosition + pageSize)
+	amount, err := subtractAmounts(base, payAmount)
+	if err != nil {
+		return nil, err
 	}
 
-	return result, nil
+	y, m, d := asOf.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, deadline.Location())
+
+	return &EarlyPaymentDiscount{
+		Applies:   !day.After(deadline) && numberFloat(base) > 0,
+		Deadline:  Date{deadline},
+		Amount:    amount,
+		PayAmount: payAmount,
+	}, nil
 }
 
 // UpdateBill updates the bill.
@@ -213,14 +228,5 @@ func (c *Client) UpdateBill(bill *Bill) (*Bill, error) {
 		Sparse: true,
 	}
 
-	var billData struct {
-		Bill Bill
-		Time Date
-	}
-
-	if err = c.post("bill", payload, &billData, nil); err != nil {
-		return nil, err
-	}
-
-	return &billData.Bill, err
+	return updateSingle[Bill](c, "bill", "bill/"+bill.ID, &bill.SyncToken, payload)
 }
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"strconv"
 )
 
 // BillPaymentCheckPayment contains details for a check-based bill payment.
@@ -21,6 +20,8 @@ type BillPaymentCreditCardPayment struct {
 // BillPayment represents a QuickBooks BillPayment object as returned by the API.
 // Read-only fields (Id, SyncToken, MetaData) are populated by the service.
 type BillPayment struct {
+	EntityMeta
+
 	ID                string                        `json:"Id,omitempty"`
 	SyncToken         string                        `json:",omitempty"`
 	MetaData          *MetaData                     `json:",omitempty"`
@@ -55,141 +56,126 @@ type BillPaymentCreateInput struct {
 	CurrencyRef       *ReferenceType                `json:",omitempty"`
 	ExchangeRate      json.Number                   `json:",omitempty"`
 	DepartmentRef     *ReferenceType                `json:",omitempty"`
+	// Extra holds fields the struct does not model yet; see ExtraFields.
+	Extra ExtraFields `json:"-"`
 }
 
-// CreateBillPayment creates the given BillPayment on the QuickBooks server, returning
-// the resulting BillPayment object.
-func (c *Client) CreateBillPayment(input *BillPaymentCreateInput) (*BillPayment, error) {
-	var resp struct {
-		BillPayment BillPayment
-		Time        Date
-	}
-
-	if err := c.post("billpayment", input, &resp, nil); err != nil {
+// ApplyVendorCredit applies amount of the given vendor credit against the given bill without
+// moving any cash. QuickBooks models this as a zero-dollar BillPayment with one line linked to
+// the Bill and one line linked to the VendorCredit.
+//
+// QuickBooks still requires a bank account on the check payment, and posts the zero-dollar
+// payment to it. ApplyVendorCredit uses the company's only active Bank account, and returns
+// an error when there is more than one: pick the account with
+// ApplyVendorCreditWithBankAccount then.
+func (c *Client) ApplyVendorCredit(vendorCreditID, billID string, amount json.Number) (*BillPayment, error) {
+	banks, err := queryEntities[Account](c, "Account", "SELECT Id FROM Account WHERE AccountType = '"+BankAccountType+"' AND Active = true MAXRESULTS 2")
+	if err != nil {
 		return nil, err
 	}
 
-	return &resp.BillPayment, nil
+	switch len(banks) {
+	case 0:
+		return nil, errors.New("applying a vendor credit needs a Bank account for the zero-dollar bill payment")
+	case 1:
+		return c.ApplyVendorCreditWithBankAccount(vendorCreditID, billID, amount, ReferenceType{NameValue: NameValue{Value: banks[0].ID}})
+	default:
+		return nil, errors.New("the company has several active Bank accounts; choose one with ApplyVendorCreditWithBankAccount")
+	}
 }
 
-// DeleteBillPayment deletes the bill payment.
-func (c *Client) DeleteBillPayment(billPayment *BillPayment) error {
-	if billPayment.ID == "" || billPayment.SyncToken == "" {
-		return errors.New("missing id/sync token")
+// ApplyVendorCreditWithBankAccount is ApplyVendorCredit with the Bank account of the
+// zero-dollar bill payment given by bankAccountRef.
+func (c *Client) ApplyVendorCreditWithBankAccount(vendorCreditID, billID string, amount json.Number, bankAccountRef ReferenceType) (*BillPayment, error) {
+	if vendorCreditID == "" || billID == "" {
+		return nil, errors.New("missing vendor credit id/bill id")
 	}
 
-	return c.post("billpayment", billPayment, nil, map[string]string{"operation": "delete"})
-}
+	if amount == "" {
+		return nil, errors.New("missing amount")
+	}
 
-// FindBillPayments gets the full list of BillPayments in the QuickBooks account.
-func (c *Client) FindBillPayments() ([]BillPayment, error) {
-	var resp struct {
-		QueryResponse struct {
-			BillPayments  []BillPayment `json:"BillPayment"`
-			MaxResults    int
-			StartPosition int
-			TotalCount    int
-		}
+	if bankAccountRef.Value == "" {
+		return nil, errors.New("missing bank account ref")
 	}
 
-	if err := c.query("SELECT COUNT(*) FROM BillPayment", &resp); err != nil {
+	bill, err := c.FindBillByID(billID)
+	if err != nil {
 		return nil, err
 	}
 
-	if resp.QueryResponse.TotalCount == 0 {
-		return nil, errors.New("no bill payments could be found")
+	vendorCredit, err := c.FindVendorCreditByID(vendorCreditID)
+	if err != nil {
+		return nil, err
 	}
 
-	billPayments := make([]BillPayment, 0, resp.QueryResponse.TotalCount)
-
-	for i := 0; i < resp.QueryResponse.TotalCount; i += queryPageSize {
-		query := "SELECT * FROM BillPayment ORDERBY Id STARTPOSITION " + strconv.Itoa(i+1) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
+	if bill.VendorRef.Value != vendorCredit.VendorRef.Value {
+		return nil, fmt.Errorf("vendor credit %s belongs to vendor %s, but bill %s belongs to vendor %s",
+			vendorCreditID, vendorCredit.VendorRef.Value, billID, bill.VendorRef.Value)
+	}
 
-		if err := c.query(query, &resp); err != nil {
-			return nil, err
-		}
+	return c.CreateBillPayment(&BillPaymentCreateInput{
+		VendorRef:    bill.VendorRef,
+		PayType:      "Check",
+		CheckPayment: &BillPaymentCheckPayment{BankAccountRef: bankAccountRef},
+		TotalAmt:     "0",
+		Line: []PaymentLine{
+			{Amount: amount, LinkedTxn: []LinkedTxn{{TxnID: billID, TxnType: "Bill"}}},
+			{Amount: amount, LinkedTxn: []LinkedTxn{{TxnID: vendorCreditID, TxnType: "VendorCredit"}}},
+		},
+	})
+}
 
-		if resp.QueryResponse.BillPayments == nil {
-			return nil, errors.New("no bill payments could be found")
-		}
+// CreateBillPayment creates the given BillPayment on the QuickBooks server, returning
+// the resulting BillPayment object.
+func (c *Client) CreateBillPayment(input *BillPaymentCreateInput) (*BillPayment, error) {
+	return postSingle[BillPayment](c, "billpayment", input, nil)
+}
 
-		billPayments = append(billPayments, resp.QueryResponse.BillPayments...)
+// DeleteBillPayment deletes the bill payment.
+func (c *Client) DeleteBillPayment(billPayment *BillPayment) error {
+	if billPayment.ID == "" || billPayment.SyncToken == "" {
+		return errors.New("missing id/sync token")
 	}
 
-	return billPayments, nil
+	return c.postRetryingStale("billpayment", "billpayment/"+billPayment.ID, &billPayment.SyncToken, billPayment, nil, map[string]string{"operation": "delete"})
 }
 
-// FindBillPaymentByID finds the bill payment by the given id.
-func (c *Client) FindBillPaymentByID(id string) (*BillPayment, error) {
-	var resp struct {
-		BillPayment BillPayment
-		Time        Date
-	}
+// FindBillPayments gets the full list of BillPayments in the QuickBooks account.
+func (c *Client) FindBillPayments() ([]BillPayment, error) {
+	return findAllPages[BillPayment](c, "BillPayment", "no bill payments could be found")
+}
 
-	if err := c.get("billpayment/"+id, &resp, nil); err != nil {
-		return nil, err
-	}
+// FindBillPaymentsWithOptions returns the bill payments matching opts, ordered by opts.OrderBy.
+// Pass nil for opts to list them all.
+func (c *Client) FindBillPaymentsWithOptions(opts *ListOptions) ([]BillPayment, error) {
+	return findAllWithOptions[BillPayment](c, "BillPayment", false, opts)
+}
 
-	return &resp.BillPayment, nil
+// FindBillPaymentByID finds the bill payment by the given id.
+func (c *Client) FindBillPaymentByID(id string) (*BillPayment, error) {
+	return getSingle[BillPayment](c, "billpayment/"+id, nil)
 }
 
 // QueryBillPayments accepts an SQL query and returns all bill payments found using it.
 func (c *Client) QueryBillPayments(query string) ([]BillPayment, error) {
-	var resp struct {
-		QueryResponse struct {
-			BillPayments  []BillPayment `json:"BillPayment"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
+	billPayments, err := queryEntities[BillPayment](c, "BillPayment", query)
+	if err != nil {
+		return billPayments, err
 	}
 
-	if resp.QueryResponse.BillPayments == nil {
+	if billPayments == nil {
 		return nil, errors.New("could not find any bill payments")
 	}
 
-	return resp.QueryResponse.BillPayments, nil
+	return billPayments, nil
 }
 
 // ListBillPayments returns one page of BillPayments ordered by Id.
 // Pass an empty pageToken to start from the beginning.
 // The returned nextPageToken is empty when there are no more results.
 func (c *Client) ListBillPayments(pageToken string, pageSize int) (*ListResponse[BillPayment], error) {
-	if pageSize <= 0 || pageSize > queryPageSize {
-		pageSize = queryPageSize
-	}
-
-	startPosition := 1
-	if pageToken != "" {
-		var err error
-		startPosition, err = strconv.Atoi(pageToken)
-		if err != nil {
-			return nil, fmt.Errorf("invalid page token: %v", err)
-		}
-	}
-
-	var resp struct {
-		QueryResponse struct {
-			BillPayments  []BillPayment `json:"BillPayment"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	query := "SELECT * FROM BillPayment ORDERBY Id STARTPOSITION " + strconv.Itoa(startPosition) + " MAXRESULTS " + strconv.Itoa(pageSize)
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
-	}
-
-	result := &ListResponse[BillPayment]{Items: resp.QueryResponse.BillPayments}
-	if len(result.Items) == pageSize {
-		result.NextPageToken = strconv.Itoa(startPosition + pageSize)
-	}
-
-	return result, nil
+	return listPage[BillPayment](c, "BillPayment", pageToken, pageSize)
 }
 
 // UpdateBillPayment updates the bill payment.
@@ -213,14 +199,5 @@ func (c *Client) UpdateBillPayment(billPayment *BillPayment) (*BillPayment, erro
 		Sparse:      true,
 	}
 
-	var billPaymentData struct {
-		BillPayment BillPayment
-		Time        Date
-	}
-
-	if err = c.post("billpayment", payload, &billPaymentData, nil); err != nil {
-		return nil, err
-	}
-
-	return &billPaymentData.BillPayment, err
+	return updateSingle[BillPayment](c, "billpayment", "billpayment/"+billPayment.ID, &billPayment.SyncToken, payload)
 }
@@ -0,0 +1,83 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyVendorCredit(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v3/company/test-realm/bill/145":
+			w.Write([]byte(`{"Bill":{"Id":"145","SyncToken":"0","VendorRef":{"value":"56"},"Line":[]}}`))
+		case "/v3/company/test-realm/vendorcredit/88":
+			w.Write([]byte(`{"VendorCredit":{"Id":"88","SyncToken":"0","VendorRef":{"value":"56"}}}`))
+		case "/v3/company/test-realm/query":
+			assert.Contains(t, r.URL.Query().Get("query"), "AccountType = 'Bank'")
+			w.Write([]byte(`{"QueryResponse":{"Account":[{"Id":"35"}],"startPosition":1,"maxResults":1}}`))
+		case "/v3/company/test-realm/billpayment":
+			require.Equal(t, http.MethodPost, r.Method)
+
+			var body BillPaymentCreateInput
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+			assert.Equal(t, "56", body.VendorRef.Value)
+			assert.Equal(t, json.Number("0"), body.TotalAmt)
+			assert.Equal(t, "35", body.CheckPayment.BankAccountRef.Value)
+			require.Len(t, body.Line, 2)
+			assert.Equal(t, json.Number("40.00"), body.Line[0].Amount)
+			assert.Equal(t, LinkedTxn{TxnID: "145", TxnType: "Bill"}, body.Line[0].LinkedTxn[0])
+			assert.Equal(t, json.Number("40.00"), body.Line[1].Amount)
+			assert.Equal(t, LinkedTxn{TxnID: "88", TxnType: "VendorCredit"}, body.Line[1].LinkedTxn[0])
+
+			w.Write([]byte(`{"BillPayment":{"Id":"210","SyncToken":"0","TotalAmt":0}}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	billPayment, err := client.ApplyVendorCredit("88", "145", "40.00")
+	require.NoError(t, err)
+	assert.Equal(t, "210", billPayment.ID)
+}
+
+func TestApplyVendorCreditSeveralBankAccounts(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v3/company/test-realm/query":
+			w.Write([]byte(`{"QueryResponse":{"Account":[{"Id":"35"},{"Id":"36"}],"startPosition":1,"maxResults":2}}`))
+		case "/v3/company/test-realm/bill/145":
+			w.Write([]byte(`{"Bill":{"Id":"145","SyncToken":"0","VendorRef":{"value":"56"},"Line":[]}}`))
+		case "/v3/company/test-realm/vendorcredit/88":
+			w.Write([]byte(`{"VendorCredit":{"Id":"88","SyncToken":"0","VendorRef":{"value":"56"}}}`))
+		case "/v3/company/test-realm/billpayment":
+			var body BillPaymentCreateInput
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "36", body.CheckPayment.BankAccountRef.Value)
+
+			w.Write([]byte(`{"BillPayment":{"Id":"211","SyncToken":"0","TotalAmt":0}}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	_, err := client.ApplyVendorCredit("88", "145", "40.00")
+	assert.ErrorContains(t, err, "several active Bank accounts")
+
+	billPayment, err := client.ApplyVendorCreditWithBankAccount("88", "145", "40.00", ReferenceType{NameValue: NameValue{Value: "36"}})
+	require.NoError(t, err)
+	assert.Equal(t, "211", billPayment.ID)
+
+	_, err = client.ApplyVendorCreditWithBankAccount("88", "145", "40.00", ReferenceType{})
+	assert.EqualError(t, err, "missing bank account ref")
+}
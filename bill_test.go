@@ -2,9 +2,13 @@ package quickbooks
 
 import (
 	"encoding/json"
+	"io"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBill(t *testing.T) {
@@ -87,7 +91,7 @@ func TestBill(t *testing.T) {
 	assert.Equal(t, 103.55, totalAmt)
 	assert.Equal(t, "United States Dollar", r.Bill.CurrencyRef.Name)
 	assert.Equal(t, "USD", r.Bill.CurrencyRef.Value)
-	// LinkedTxn
+	assert.Equal(t, []LinkedTxn{{TxnID: "118", TxnType: "BillPaymentCheck"}}, r.Bill.LinkedTxn)
 	assert.Equal(t, "3", r.Bill.SalesTermRef.Value)
 	assert.Equal(t, "2014-12-06T00:00:00+00:00", r.Bill.DueDate.String())
 	assert.Equal(t, 1, len(r.Bill.Line))
@@ -97,3 +101,121 @@ func TestBill(t *testing.T) {
 	assert.Equal(t, "2014-11-06T15:37:25-08:00", r.Bill.MetaData.CreateTime.String())
 	assert.Equal(t, "2015-02-09T10:11:11-08:00", r.Bill.MetaData.LastUpdatedTime.String())
 }
+
+func TestCreateBillLinkedTxn(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/v3/company/test-realm/bill", r.URL.Path)
+
+		var input BillCreateInput
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+		assert.Equal(t, "46", input.VendorRef.Value)
+		require.Len(t, input.Line, 1)
+		assert.Equal(t, []LinkedTxn{{TxnID: "80", TxnType: "PurchaseOrder", TxnLineID: "1"}}, input.Line[0].LinkedTxn)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Bill":{"Id":"151","SyncToken":"0","VendorRef":{"value":"46"},"LinkedTxn":[{"TxnId":"80","TxnType":"PurchaseOrder"}],"Line":[{"Id":"1","Amount":100,"DetailType":"AccountBasedExpenseLineDetail","LinkedTxn":[{"TxnId":"80","TxnType":"PurchaseOrder","TxnLineId":"1"}]}]},"time":"2015-02-09T10:17:20.251-08:00"}`))
+	})
+
+	bill, err := client.CreateBill(&BillCreateInput{
+		VendorRef: ReferenceType{NameValue: NameValue{Value: "46"}},
+		Line: []Line{{
+			Amount:     "100",
+			DetailType: "AccountBasedExpenseLineDetail",
+			LinkedTxn:  []LinkedTxn{{TxnID: "80", TxnType: "PurchaseOrder", TxnLineID: "1"}},
+		}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "151", bill.ID)
+	assert.Equal(t, []LinkedTxn{{TxnID: "80", TxnType: "PurchaseOrder"}}, bill.LinkedTxn)
+	assert.Equal(t, "1", bill.Line[0].LinkedTxn[0].TxnLineID)
+}
+
+func TestCreateBillMissingVendor(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL.Path)
+	})
+
+	_, err := client.CreateBill(&BillCreateInput{Line: []Line{{Amount: "100"}}})
+	assert.Error(t, err)
+}
+
+func TestBuildCreateBillPayload(t *testing.T) {
+	input := &BillCreateInput{
+		VendorRef: ReferenceType{NameValue: NameValue{Value: "46"}},
+		Line:      []Line{{Amount: "100", DetailType: "AccountBasedExpenseLineDetail"}},
+	}
+
+	payload, err := (&Client{}).BuildCreateBillPayload(input)
+	require.NoError(t, err)
+
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Equal(t, string(payload), string(body))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Bill":{"Id":"151"}}`))
+	})
+
+	_, err = client.CreateBill(input)
+	require.NoError(t, err)
+
+	_, err = (&Client{}).BuildCreateBillPayload(&BillCreateInput{VendorRef: input.VendorRef})
+	assert.Error(t, err)
+}
+
+func TestBillableExpenseLine(t *testing.T) {
+	line := NewBillableExpenseLine("7", "125.00", "58", "200000000000000002")
+	detail := line.AccountBasedExpenseLineDetail
+	assert.Equal(t, AccountBasedExpenseLineDetailType, line.DetailType)
+	assert.Equal(t, "7", detail.AccountRef.Value)
+	assert.Equal(t, "58", detail.CustomerRef.Value)
+	assert.Equal(t, "200000000000000002", detail.ClassRef.Value)
+	assert.Equal(t, BillableStatusBillable, *detail.BillableStatus)
+	assert.Nil(t, NewBillableExpenseLine("7", "1", "58", "").AccountBasedExpenseLineDetail.ClassRef)
+
+	assert.NoError(t, ValidateExpenseLines([]Line{line}))
+
+	missingCustomer := NewBillableExpenseLine("7", "125.00", "", "")
+	_, err := (&Client{}).BuildCreateBillPayload(&BillCreateInput{
+		VendorRef: ReferenceType{NameValue: NameValue{Value: "56"}},
+		Line:      []Line{line, missingCustomer},
+	})
+	assert.EqualError(t, err, "line 1: a billable expense line needs a CustomerRef")
+
+	status := BillableStatusBillable
+	itemLine := Line{DetailType: ItemBasedExpenseLineDetailType, ItemBasedExpenseLineDetail: ItemBasedExpenseLineDetail{BillableStatus: &status}}
+	_, err = (&Client{}).CreatePurchase(&PurchaseCreateInput{Line: []Line{itemLine}})
+	assert.Error(t, err)
+}
+
+func TestBillEarlyPaymentDiscount(t *testing.T) {
+	days := func(n int) *int { return &n }
+	term := &Term{ID: "3", DueDays: days(30), DiscountDays: days(10), DiscountPercent: "2"}
+	bill := &Bill{
+		ID:           "42",
+		TxnDate:      &Date{time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		SalesTermRef: &ReferenceType{NameValue: NameValue{Value: "3"}},
+		TotalAmt:     "1500.00",
+		Balance:      "1234.56",
+	}
+
+	discount, err := bill.EarlyPaymentDiscount(term, time.Date(2024, 3, 11, 17, 30, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.True(t, discount.Applies)
+	assert.Equal(t, "2024-03-11", discount.Deadline.Format(secondFormat))
+	assert.Equal(t, json.Number("24.69"), discount.Amount)
+	assert.Equal(t, json.Number("1209.87"), discount.PayAmount)
+
+	discount, err = bill.EarlyPaymentDiscount(term, time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.False(t, discount.Applies)
+
+	discount, err = bill.EarlyPaymentDiscount(&Term{ID: "3", DueDays: days(30)}, time.Now())
+	require.NoError(t, err)
+	assert.Nil(t, discount)
+
+	_, err = bill.EarlyPaymentDiscount(&Term{ID: "4"}, time.Now())
+	assert.Error(t, err)
+}
@@ -3,7 +3,6 @@ package quickbooks
 import (
 	"encoding/json"
 	"errors"
-	"strconv"
 )
 
 // BudgetDetail holds a single budget line entry.
@@ -20,6 +19,8 @@ type BudgetDetail struct {
 // Budgets are read-only via the standard CRUD API.
 // Read-only fields (Id, SyncToken, MetaData) are populated by the service.
 type Budget struct {
+	EntityMeta
+
 	ID             string         `json:"Id,omitempty"`
 	SyncToken      string         `json:",omitempty"`
 	MetaData       *MetaData      `json:",omitempty"`
@@ -35,59 +36,25 @@ type Budget struct {
 
 // FindBudgets gets the full list of Budgets in the QuickBooks account.
 func (c *Client) FindBudgets() ([]Budget, error) {
-	var resp struct {
-		QueryResponse struct {
-			Budgets       []Budget `json:"Budget"`
-			MaxResults    int
-			StartPosition int
-			TotalCount    int
-		}
-	}
-
-	if err := c.query("SELECT COUNT(*) FROM Budget", &resp); err != nil {
-		return nil, err
-	}
-
-	if resp.QueryResponse.TotalCount == 0 {
-		return nil, errors.New("no budgets could be found")
-	}
-
-	budgets := make([]Budget, 0, resp.QueryResponse.TotalCount)
-
-	for i := 0; i < resp.QueryResponse.TotalCount; i += queryPageSize {
-		query := "SELECT * FROM Budget ORDERBY Id STARTPOSITION " + strconv.Itoa(i+1) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
-
-		if err := c.query(query, &resp); err != nil {
-			return nil, err
-		}
-
-		if resp.QueryResponse.Budgets == nil {
-			return nil, errors.New("no budgets could be found")
-		}
-
-		budgets = append(budgets, resp.QueryResponse.Budgets...)
-	}
+	return findAllPages[Budget](c, "Budget", "no budgets could be found")
+}
 
-	return budgets, nil
+// FindBudgetsWithOptions returns the budgets matching opts, ordered by opts.OrderBy.
+// Pass nil for opts to list them all.
+func (c *Client) FindBudgetsWithOptions(opts *ListOptions) ([]Budget, error) {
+	return findAllWithOptions[Budget](c, "Budget", true, opts)
 }
 
 // QueryBudgets accepts an SQL query and returns all budgets found using it.
 func (c *Client) QueryBudgets(query string) ([]Budget, error) {
-	var resp struct {
-		QueryResponse struct {
-			Budgets       []Budget `json:"Budget"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
+	budgets, err := queryEntities[Budget](c, "Budget", query)
+	if err != nil {
+		return budgets, err
 	}
 
-	if resp.QueryResponse.Budgets == nil {
+	if budgets == nil {
 		return nil, errors.New("could not find any budgets")
 	}
 
-	return resp.QueryResponse.Budgets, nil
+	return budgets, nil
 }
@@ -0,0 +1,142 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// BudgetVsActualsQueryParams are the query parameters of the BudgetVsActuals report, apart
+// from the budget itself.
+type BudgetVsActualsQueryParams struct {
+	// Cash or Accrual
+	AccountingMethod *string
+	StartDate        *string
+	EndDate          *string
+	// Predefined date range, e.g. "This Fiscal Year". Ignored when StartDate/EndDate are set.
+	DateMacro *string
+	// Total, Month, Quarter or Year. Whatever the period, Budget, Actual and Variance are read
+	// from the report's total columns.
+	SummarizeColumnBy *string
+	// Comma separated lists of ids to filter on.
+	Customer   *string
+	Class      *string
+	Department *string
+}
+
+func (p *BudgetVsActualsQueryParams) toMap() map[string]string {
+	m := map[string]string{}
+	if p.AccountingMethod != nil {
+		m["accounting_method"] = *p.AccountingMethod
+	}
+	if p.StartDate != nil {
+		m["start_date"] = *p.StartDate
+	}
+	if p.EndDate != nil {
+		m["end_date"] = *p.EndDate
+	}
+	if p.DateMacro != nil {
+		m["date_macro"] = *p.DateMacro
+	}
+	if p.SummarizeColumnBy != nil {
+		m["summarize_column_by"] = *p.SummarizeColumnBy
+	}
+	if p.Customer != nil {
+		m["customer"] = *p.Customer
+	}
+	if p.Class != nil {
+		m["class"] = *p.Class
+	}
+	if p.Department != nil {
+		m["department"] = *p.Department
+	}
+	return m
+}
+
+// BudgetVsActualsRow compares the budget of one account with what was actually posted to it.
+type BudgetVsActualsRow struct {
+	AccountName string
+	AccountID   string
+	Budget      json.Number
+	Actual      json.Number
+	// Variance is Actual minus Budget: positive when the account is over budget.
+	Variance json.Number
+}
+
+// BudgetVsActuals is the BudgetVsActuals report with one row per budgeted account. Section
+// subtotals, such as Total Income or Net Income, are left out and can be read from Report.
+type BudgetVsActuals struct {
+	Report *Report
+	Rows   []BudgetVsActualsRow
+}
+
+// lastColumn returns the index of the last column titled title, ignoring case, or -1. When
+// the report is summarized by period the columns repeat per period and the last set holds
+// the totals.
+func (rp *Report) lastColumn(title string) int {
+	for i := len(rp.Columns) - 1; i >= 0; i-- {
+		if strings.EqualFold(strings.TrimSpace(rp.Columns[i].ColTitle), title) {
+			return i
+		}
+	}
+	return -1
+}
+
+// newBudgetVsActuals reads the account rows of the report. The variance is taken from the
+// "over Budget" column when there is one and computed otherwise.
+func newBudgetVsActuals(report *Report) (*BudgetVsActuals, error) {
+	budgetCol, actualCol := report.lastColumn("Budget"), report.lastColumn("Actual")
+	if budgetCol < 0 || actualCol < 0 {
+		return nil, errors.New("the BudgetVsActuals report has no Budget or Actual column")
+	}
+	varianceCol := report.lastColumn("over Budget")
+
+	result := &BudgetVsActuals{Report: report}
+	for _, row := range report.DataRows() {
+		account := row.cell(0)
+		line := BudgetVsActualsRow{
+			AccountName: account.Value,
+			AccountID:   account.ID,
+			Budget:      glAmount(row.cell(budgetCol).Value),
+			Actual:      glAmount(row.cell(actualCol).Value),
+		}
+
+		if varianceCol >= 0 {
+			line.Variance = glAmount(row.cell(varianceCol).Value)
+		}
+		if line.Variance == "" {
+			variance, err := subtractAmounts(line.Actual, line.Budget)
+			if err != nil {
+				return nil, err
+			}
+			line.Variance = variance
+		}
+
+		result.Rows = append(result.Rows, line)
+	}
+
+	return result, nil
+}
+
+// GetBudgetVsActuals fetches the BudgetVsActuals report of the given budget: the budgeted
+// and actual amount of each account over the period, and the variance between them. The
+// Budget entity itself only holds the budgeted amounts. Pass nil for params to use the API
+// defaults.
+func (c *Client) GetBudgetVsActuals(budgetID string, params *BudgetVsActualsQueryParams) (*BudgetVsActuals, error) {
+	if budgetID == "" {
+		return nil, errors.New("missing budget id")
+	}
+
+	queryParams := map[string]string{}
+	if params != nil {
+		queryParams = params.toMap()
+	}
+	queryParams["budget"] = budgetID
+
+	report, err := c.getReport("BudgetVsActuals", queryParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return newBudgetVsActuals(report)
+}
@@ -0,0 +1,48 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBudgetVsActuals(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v3/company/test-realm/reports/BudgetVsActuals", r.URL.Path)
+		assert.Equal(t, "4", r.URL.Query().Get("budget"))
+		assert.Equal(t, "2024-01-01", r.URL.Query().Get("start_date"))
+		w.Write([]byte(`{
+  "Header": {"ReportName": "BudgetVsActuals"},
+  "Columns": {"Column": [
+    {"ColTitle": "", "ColType": "Account"},
+    {"ColTitle": "Actual", "ColType": "Money"},
+    {"ColTitle": "Budget", "ColType": "Money"},
+    {"ColTitle": "over Budget", "ColType": "Money"},
+    {"ColTitle": "% of Budget", "ColType": "Money"}
+  ]},
+  "Rows": {"Row": [
+    {"type": "Section", "group": "Income",
+     "Header": {"ColData": [{"value": "Income"}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": ""}]},
+     "Rows": {"Row": [
+       {"type": "Data", "ColData": [{"value": "Services", "id": "1"}, {"value": "12,500.00"}, {"value": "10,000.00"}, {"value": "2,500.00"}, {"value": "125.00 %"}]},
+       {"type": "Data", "ColData": [{"value": "Sales", "id": "79"}, {"value": "800.00"}, {"value": "1,000.00"}, {"value": ""}, {"value": "80.00 %"}]}
+     ]},
+     "Summary": {"ColData": [{"value": "Total Income"}, {"value": "13,300.00"}, {"value": "11,000.00"}, {"value": "2,300.00"}, {"value": "120.91 %"}]}}
+  ]}
+}`))
+	})
+
+	start := "2024-01-01"
+	report, err := client.GetBudgetVsActuals("4", &BudgetVsActualsQueryParams{StartDate: &start})
+	require.NoError(t, err)
+	assert.Equal(t, []BudgetVsActualsRow{
+		{AccountName: "Services", AccountID: "1", Budget: "10000.00", Actual: "12500.00", Variance: "2500.00"},
+		{AccountName: "Sales", AccountID: "79", Budget: "1000.00", Actual: "800.00", Variance: json.Number("-200.00")},
+	}, report.Rows)
+
+	_, err = client.GetBudgetVsActuals("", nil)
+	assert.Error(t, err)
+}
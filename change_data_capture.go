@@ -3,6 +3,7 @@ package quickbooks
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"strings"
 	"time"
 )
@@ -111,3 +112,100 @@ func (c *Client) GetChangedEntities(entities []string, changedSince time.Time) (
 
 	return result, nil
 }
+
+// HydratedChanges holds the current version of the objects a CDC poll reported as changed,
+// grouped by entity like CDCResponse. Deleted objects are listed by Id in Deleted, keyed by
+// entity name.
+type HydratedChanges struct {
+	Accounts      []Account
+	Attachables   []Attachable
+	Bills         []Bill
+	Classes       []Class
+	CompanyInfo   *CompanyInfo
+	CreditMemos   []CreditMemo
+	Customers     []Customer
+	CustomerTypes []CustomerType
+	Deposits      []Deposit
+	Employees     []Employee
+	Estimates     []Estimate
+	Invoices      []Invoice
+	Items         []Item
+	Payments      []Payment
+	Vendors       []Vendor
+	Deleted       map[string][]string
+}
+
+// changedIDs returns the Ids of the changed (not deleted) objects and records the deleted ones.
+// Every entity struct has an ID field.
+func changedIDs[T any](changes []MaybeDeleted[T], entity string, deleted map[string][]string) []string {
+	var ids []string
+	for _, change := range changes {
+		switch {
+		case change.Deleted != nil:
+			deleted[entity] = append(deleted[entity], change.Deleted.ID)
+		case change.Entity != nil:
+			ids = append(ids, reflect.ValueOf(change.Entity).Elem().FieldByName("ID").String())
+		}
+	}
+	return ids
+}
+
+// hydrate fetches the current version of the changed objects of one entity, if there are any.
+func hydrate[T any](dest *[]T, changes []MaybeDeleted[T], entity string, deleted map[string][]string, find func([]string) ([]T, error)) error {
+	ids := changedIDs(changes, entity, deleted)
+	if len(ids) == 0 {
+		return nil
+	}
+
+	items, err := find(ids)
+	if err != nil {
+		return fmt.Errorf("failed to fetch changed %s objects: %w", entity, err)
+	}
+	*dest = items
+	return nil
+}
+
+// HydrateChanges fetches the full current objects behind a CDC response through the FindXByIDs
+// methods, which cost one query per 100 Ids. A CDC response can be hours old by the time it is
+// processed; hydrating re-reads every changed object so callers work with what is in
+// QuickBooks now. Objects deleted since the poll are missing from the result rather than an error.
+func (c *Client) HydrateChanges(cdc *CDCResponse) (*HydratedChanges, error) {
+	h := &HydratedChanges{Deleted: map[string][]string{}}
+	d := h.Deleted
+
+	steps := []func() error{
+		func() error { return hydrate(&h.Accounts, cdc.Accounts, "Account", d, c.FindAccountsByIDs) },
+		func() error { return hydrate(&h.Attachables, cdc.Attachables, "Attachable", d, c.FindAttachablesByIDs) },
+		func() error { return hydrate(&h.Bills, cdc.Bills, "Bill", d, c.FindBillsByIDs) },
+		func() error { return hydrate(&h.Classes, cdc.Classes, "Class", d, c.FindClassesByIDs) },
+		func() error { return hydrate(&h.CreditMemos, cdc.CreditMemos, "CreditMemo", d, c.FindCreditMemosByIDs) },
+		func() error { return hydrate(&h.Customers, cdc.Customers, "Customer", d, c.FindCustomersByIDs) },
+		func() error {
+			return hydrate(&h.CustomerTypes, cdc.CustomerTypes, "CustomerType", d, c.FindCustomerTypesByIDs)
+		},
+		func() error { return hydrate(&h.Deposits, cdc.Deposits, "Deposit", d, c.FindDepositsByIDs) },
+		func() error { return hydrate(&h.Employees, cdc.Employees, "Employee", d, c.FindEmployeesByIDs) },
+		func() error { return hydrate(&h.Estimates, cdc.Estimates, "Estimate", d, c.FindEstimatesByIDs) },
+		func() error { return hydrate(&h.Invoices, cdc.Invoices, "Invoice", d, c.FindInvoicesByIDs) },
+		func() error { return hydrate(&h.Items, cdc.Items, "Item", d, c.FindItemsByIDs) },
+		func() error { return hydrate(&h.Payments, cdc.Payments, "Payment", d, c.FindPaymentsByIDs) },
+		func() error { return hydrate(&h.Vendors, cdc.Vendors, "Vendor", d, c.FindVendorsByIDs) },
+	}
+
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return nil, err
+		}
+	}
+
+	// There is only one CompanyInfo, so it is read directly.
+	if len(changedIDs(cdc.CompanyInfos, "CompanyInfo", d)) > 0 {
+		info, err := c.FindCompanyInfo()
+		if err != nil {
+			return nil, err
+		}
+		h.CompanyInfo = info
+	}
+
+	return h, nil
+}
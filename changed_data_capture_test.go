@@ -3,6 +3,7 @@ package quickbooks
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -82,3 +83,44 @@ func TestGetChangedEntities(t *testing.T) {
 	assert.Empty(t, result.Bills)
 	assert.Empty(t, result.Vendors)
 }
+
+func TestHydrateChanges(t *testing.T) {
+	var queries []string
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		queries = append(queries, query)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(query, "FROM Estimate"):
+			w.Write([]byte(`{"QueryResponse":{"Estimate":[{"Id":"46","TotalAmt":75},{"Id":"48","TotalAmt":1005}]}}`))
+		case strings.Contains(query, "FROM Customer"):
+			w.Write([]byte(`{"QueryResponse":{"Customer":[{"Id":"13","DisplayName":"John Melton"}]}}`))
+		default:
+			t.Errorf("unexpected query %s", query)
+		}
+	})
+
+	cdc := &CDCResponse{
+		Estimates: []MaybeDeleted[Estimate]{
+			{Entity: &Estimate{ID: "48"}},
+			{Entity: &Estimate{ID: "46"}},
+			{Deleted: &DeletedEntity{ID: "99"}},
+		},
+		Customers: []MaybeDeleted[Customer]{
+			{Entity: &Customer{ID: "13"}},
+			{Entity: &Customer{ID: "13"}},
+		},
+	}
+
+	h, err := client.HydrateChanges(cdc)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"SELECT * FROM Customer WHERE Id IN ('13') AND Active IN (true, false) ORDERBY Id STARTPOSITION 1 MAXRESULTS 1000",
+		"SELECT * FROM Estimate WHERE Id IN ('48', '46') ORDERBY Id STARTPOSITION 1 MAXRESULTS 1000",
+	}, queries)
+	require.Len(t, h.Estimates, 2)
+	assert.Equal(t, json.Number("75"), h.Estimates[0].TotalAmt)
+	require.Len(t, h.Customers, 1)
+	assert.Equal(t, map[string][]string{"Estimate": {"99"}}, h.Deleted)
+	assert.Nil(t, h.Invoices)
+}
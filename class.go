@@ -2,13 +2,13 @@ package quickbooks
 
 import (
 	"errors"
-	"fmt"
-	"strconv"
 )
 
 // Class represents a QuickBooks Class object as returned by the API.
 // Read-only fields (Id, SyncToken, MetaData, FullyQualifiedName) are populated by the service.
 type Class struct {
+	EntityMeta
+
 	ID                 string         `json:"Id,omitempty"`
 	SyncToken          string         `json:",omitempty"`
 	MetaData           *MetaData      `json:",omitempty"`
@@ -19,6 +19,11 @@ type Class struct {
 	Active             *bool          `json:",omitempty"`
 }
 
+// IsActive reports whether the class is active, treating a missing Active field as active.
+func (c *Class) IsActive() bool {
+	return boolValue(c.Active, true)
+}
+
 // ClassCreateInput contains the writable fields accepted when creating a Class.
 // Name is required; all other fields are optional.
 type ClassCreateInput struct {
@@ -26,21 +31,14 @@ type ClassCreateInput struct {
 	ParentRef *ReferenceType `json:",omitempty"`
 	SubClass  *bool          `json:",omitempty"`
 	Active    *bool          `json:",omitempty"`
+	// Extra holds fields the struct does not model yet; see ExtraFields.
+	Extra ExtraFields `json:"-"`
 }
 
 // CreateClass creates the given Class on the QuickBooks server, returning
 // the resulting Class object.
 func (c *Client) CreateClass(input *ClassCreateInput) (*Class, error) {
-	var resp struct {
-		Class Class
-		Time  Date
-	}
-
-	if err := c.post("class", input, &resp, nil); err != nil {
-		return nil, err
-	}
-
-	return &resp.Class, nil
+	return postSingle[Class](c, "class", input, nil)
 }
 
 // DeleteClass deletes the class.
@@ -49,118 +47,49 @@ func (c *Client) DeleteClass(class *Class) error {
 		return errors.New("missing id/sync token")
 	}
 
-	return c.post("class", class, nil, map[string]string{"operation": "delete"})
+	return c.postRetryingStale("class", "class/"+class.ID, &class.SyncToken, class, nil, map[string]string{"operation": "delete"})
 }
 
 // FindClasses gets the full list of Classes in the QuickBooks account.
 func (c *Client) FindClasses() ([]Class, error) {
-	var resp struct {
-		QueryResponse struct {
-			Classes       []Class `json:"Class"`
-			MaxResults    int
-			StartPosition int
-			TotalCount    int
-		}
-	}
-
-	if err := c.query("SELECT COUNT(*) FROM Class", &resp); err != nil {
-		return nil, err
-	}
-
-	if resp.QueryResponse.TotalCount == 0 {
-		return nil, errors.New("no classes could be found")
-	}
-
-	classes := make([]Class, 0, resp.QueryResponse.TotalCount)
-
-	for i := 0; i < resp.QueryResponse.TotalCount; i += queryPageSize {
-		query := "SELECT * FROM Class ORDERBY Id STARTPOSITION " + strconv.Itoa(i+1) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
-
-		if err := c.query(query, &resp); err != nil {
-			return nil, err
-		}
-
-		if resp.QueryResponse.Classes == nil {
-			return nil, errors.New("no classes could be found")
-		}
-
-		classes = append(classes, resp.QueryResponse.Classes...)
-	}
+	return findAllPages[Class](c, "Class", "no classes could be found")
+}
 
-	return classes, nil
+// FindClassesWithOptions returns the classes matching opts, ordered by opts.OrderBy.
+// Pass nil for opts to list them all.
+func (c *Client) FindClassesWithOptions(opts *ListOptions) ([]Class, error) {
+	return findAllWithOptions[Class](c, "Class", true, opts)
 }
 
 // FindClassByID returns a class with a given Id.
 func (c *Client) FindClassByID(id string) (*Class, error) {
-	var resp struct {
-		Class Class
-		Time  Date
-	}
-
-	if err := c.get("class/"+id, &resp, nil); err != nil {
-		return nil, err
-	}
+	return getSingle[Class](c, "class/"+id, nil)
+}
 
-	return &resp.Class, nil
+// FindClassesByIDs returns the classes with the given Ids, inactive ones included. Ids that match nothing are skipped.
+func (c *Client) FindClassesByIDs(ids []string) ([]Class, error) {
+	return findByIDs[Class](c, "Class", true, ids)
 }
 
 // QueryClasses accepts an SQL query and returns all classes found using it.
 func (c *Client) QueryClasses(query string) ([]Class, error) {
-	var resp struct {
-		QueryResponse struct {
-			Classes       []Class `json:"Class"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
+	classes, err := queryEntities[Class](c, "Class", query)
+	if err != nil {
+		return classes, err
 	}
 
-	if resp.QueryResponse.Classes == nil {
+	if classes == nil {
 		return nil, errors.New("could not find any classes")
 	}
 
-	return resp.QueryResponse.Classes, nil
+	return classes, nil
 }
 
 // ListClasses returns one page of Classes ordered by Id.
 // Pass an empty pageToken to start from the beginning.
 // The returned nextPageToken is empty when there are no more results.
 func (c *Client) ListClasses(pageToken string, pageSize int) (*ListResponse[Class], error) {
-	if pageSize <= 0 || pageSize > queryPageSize {
-		pageSize = queryPageSize
-	}
-
-	startPosition := 1
-	if pageToken != "" {
-		var err error
-		startPosition, err = strconv.Atoi(pageToken)
-		if err != nil {
-			return nil, fmt.Errorf("invalid page token: %v", err)
-		}
-	}
-
-	var resp struct {
-		QueryResponse struct {
-			Classes       []Class `json:"Class"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	query := "SELECT * FROM Class ORDERBY Id STARTPOSITION " + strconv.Itoa(startPosition) + " MAXRESULTS " + strconv.Itoa(pageSize)
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
-	}
-
-	result := &ListResponse[Class]{Items: resp.QueryResponse.Classes}
-	if len(result.Items) == pageSize {
-		result.NextPageToken = strconv.Itoa(startPosition + pageSize)
-	}
-
-	return result, nil
+	return listPage[Class](c, "Class", pageToken, pageSize)
 }
 
 // UpdateClass updates the class.
@@ -184,14 +113,5 @@ func (c *Client) UpdateClass(class *Class) (*Class, error) {
 		Sparse: true,
 	}
 
-	var classData struct {
-		Class Class
-		Time  Date
-	}
-
-	if err = c.post("class", payload, &classData, nil); err != nil {
-		return nil, err
-	}
-
-	return &classData.Class, err
+	return updateSingle[Class](c, "class", "class/"+class.ID, &class.SyncToken, payload)
 }
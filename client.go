@@ -3,11 +3,15 @@ package quickbooks
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -15,8 +19,38 @@ import (
 type Client struct {
 	// Get this from oauth2.NewClient().
 	Client *http.Client
+	// RetryOnStale makes the Update, Delete and Void methods retry once when QuickBooks rejects
+	// the write because the object changed after it was read (fault 5010). The retry sends the
+	// same sparse changes with the current SyncToken, so the caller's values win for the fields
+	// they set; a delete or void simply goes ahead on the newer version.
+	// UpdateInvoiceIfUnchanged never retries.
+	RetryOnStale bool
+	// MaxConcurrentPages caps the number of pages the FindX methods, such as FindInvoices and
+	// FindVendors, fetch in parallel once their COUNT query has told them how many there are.
+	// 0 means DefaultMaxConcurrentPages and 1 fetches one page at a time. QuickBooks throttles
+	// a company making more than 10 requests at once, so stay well below that, counting the
+	// other requests the application makes.
+	//
+	// It is also the ceiling for the FindXWithOptions methods: a ListOptions.Concurrency above
+	// it is lowered to it, so this field alone bounds the fan-out of the client.
+	MaxConcurrentPages int
+	// DocNumbers decides what the Create methods of the sales forms do with a DocNumber while
+	// custom transaction numbers are turned off in the company preferences. The default,
+	// DocNumberSend, sends it anyway.
+	DocNumbers DocNumberPolicy
+	// MaxRetries is the number of times a failed request is sent again; 0 disables retries.
+	// Throttled requests wait for the Retry-After delay QuickBooks asks for, other failures
+	// back off exponentially. Any request is retried after a 429 or a connection error, but
+	// only GETs, which include queries and reports, after a 5xx, because a failed POST may
+	// still have been applied.
+	MaxRetries int
+	// AllowProduction lets PurgeEntity run against the production endpoint. Leave it unset
+	// unless you really mean to wipe a live company.
+	AllowProduction bool
 	// Set to ProductionEndpoint or SandboxEndpoint.
 	endpoint *url.URL
+	// Set to PaymentsProductionEndpoint or PaymentsSandboxEndpoint.
+	paymentsEndpoint *url.URL
 	// The set of quickbooks APIs
 	discoveryAPI *DiscoveryAPI
 	// The client Id
@@ -27,8 +61,18 @@ type Client struct {
 	minorVersion string
 	// The account Id you're connecting to.
 	realm string
-	// Flag set if the limit of 500req/s has been hit (source: https://developer.intuit.com/app/developer/qbo/docs/learn/rest-api-features#limits-and-throttles)
-	throttled bool
+	// Tracks which realms have hit the limit of 500req/s (source: https://developer.intuit.com/app/developer/qbo/docs/learn/rest-api-features#limits-and-throttles).
+	// Shared by the clients returned from ForRealm.
+	limiter *realmLimiter
+	// Names found by ResolveRef, keyed by "<entity type>/<id>".
+	refNames sync.Map
+	// Country of the company, looked up once by CompanyCountry.
+	countryMu sync.Mutex
+	country   string
+	// ctx is the context of every request, set by WithContext; nil means context.Background().
+	ctx context.Context
+	// cacheOwner is the client whose refNames and country caches a WithContext copy uses.
+	cacheOwner *Client
 }
 
 // NewClient initializes a new QuickBooks client for interacting with their Online API
@@ -42,7 +86,7 @@ func NewClient(clientID string, clientSecret string, realm string, isProduction
 		clientSecret: clientSecret,
 		minorVersion: minorVersion,
 		realm:        realm,
-		throttled:    false,
+		limiter:      newRealmLimiter(),
 	}
 
 	if isProduction {
@@ -51,6 +95,11 @@ func NewClient(clientID string, clientSecret string, realm string, isProduction
 			return nil, fmt.Errorf("failed to parse API endpoint: %v", err)
 		}
 
+		client.paymentsEndpoint, err = url.Parse(PaymentsProductionEndpoint.String() + "/")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse payments endpoint: %v", err)
+		}
+
 		client.discoveryAPI, err = CallDiscoveryAPI(DiscoveryProductionEndpoint)
 		if err != nil {
 			return nil, fmt.Errorf("failed to obtain discovery endpoint: %v", err)
@@ -61,6 +110,11 @@ func NewClient(clientID string, clientSecret string, realm string, isProduction
 			return nil, fmt.Errorf("failed to parse API endpoint: %v", err)
 		}
 
+		client.paymentsEndpoint, err = url.Parse(PaymentsSandboxEndpoint.String() + "/")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse payments endpoint: %v", err)
+		}
+
 		client.discoveryAPI, err = CallDiscoveryAPI(DiscoverySandboxEndpoint)
 		if err != nil {
 			return nil, fmt.Errorf("failed to obtain discovery endpoint: %v", err)
@@ -74,6 +128,92 @@ func NewClient(clientID string, clientSecret string, realm string, isProduction
 	return &client, nil
 }
 
+// ForRealm returns a client for another company (realm) that shares this client's HTTP
+// transport, configuration and rate limiter, so one configured client can serve many
+// companies. Throttling is tracked per realm: a company hitting its limit does not block
+// requests to the others. Per-company caches, such as CompanyCountry, start empty.
+//
+// The token of the underlying HTTP client must be authorized for the given realm.
+func (c *Client) ForRealm(realm string) (*Client, error) {
+	if realm == "" {
+		return nil, errors.New("missing realm")
+	}
+
+	endpoint := *c.endpoint
+	if !strings.HasSuffix(endpoint.Path, "/v3/company/"+c.realm+"/") {
+		return nil, fmt.Errorf("unexpected API endpoint %q", c.endpoint.String())
+	}
+	endpoint.Path = strings.TrimSuffix(endpoint.Path, c.realm+"/") + realm + "/"
+
+	return &Client{
+		Client:             c.Client,
+		RetryOnStale:       c.RetryOnStale,
+		MaxConcurrentPages: c.MaxConcurrentPages,
+		MaxRetries:         c.MaxRetries,
+		DocNumbers:         c.DocNumbers,
+		AllowProduction:    c.AllowProduction,
+		endpoint:           &endpoint,
+		paymentsEndpoint:   c.paymentsEndpoint,
+		discoveryAPI:       c.discoveryAPI,
+		clientID:           c.clientID,
+		clientSecret:       c.clientSecret,
+		minorVersion:       c.minorVersion,
+		realm:              realm,
+		limiter:            c.limiter,
+		ctx:                c.ctx,
+	}, nil
+}
+
+// WithContext returns a copy of the client whose requests are made with ctx, so canceling
+// ctx or reaching its deadline aborts the request in flight and makes the method return the
+// context's error. Every method of the copy honors ctx, including the ones that page through
+// many requests, such as FindInvoices. The copy shares the caches of c and is cheap: create
+// one per operation, e.g.
+//
+//	invoices, err := client.WithContext(ctx).FindInvoicesWithOptions(opts)
+//
+// Methods called on c itself keep using context.Background().
+func (c *Client) WithContext(ctx context.Context) *Client {
+	if ctx == nil {
+		panic("nil context")
+	}
+
+	return &Client{
+		Client:             c.Client,
+		RetryOnStale:       c.RetryOnStale,
+		MaxConcurrentPages: c.MaxConcurrentPages,
+		MaxRetries:         c.MaxRetries,
+		DocNumbers:         c.DocNumbers,
+		AllowProduction:    c.AllowProduction,
+		endpoint:           c.endpoint,
+		paymentsEndpoint:   c.paymentsEndpoint,
+		discoveryAPI:       c.discoveryAPI,
+		clientID:           c.clientID,
+		clientSecret:       c.clientSecret,
+		minorVersion:       c.minorVersion,
+		realm:              c.realm,
+		limiter:            c.limiter,
+		ctx:                ctx,
+		cacheOwner:         c.caches(),
+	}
+}
+
+// context returns the context requests are made with.
+func (c *Client) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// caches returns the client holding the per-company caches of c.
+func (c *Client) caches() *Client {
+	if c.cacheOwner != nil {
+		return c.cacheOwner
+	}
+	return c
+}
+
 // FindAuthorizationURL compiles the authorization url from the discovery api's auth endpoint.
 //
 // Example: qbClient.FindAuthorizationURL("com.intuit.quickbooks.accounting", "security_token", "https://developer.intuit.com/v2/OAuth2Playground/RedirectUrl")
@@ -98,12 +238,16 @@ func (c *Client) FindAuthorizationURL(scope string, state string, redirectURI st
 	return authorizationURL.String(), nil
 }
 
-func (c *Client) req(method string, endpoint string, payloadData any, responseObject any, queryParameters map[string]string) (e error) {
-	// TODO: possibly just wait until c.throttled is false, and continue the request?
-	if c.throttled {
-		return errors.New("waiting for rate limit")
-	}
+func (c *Client) req(method string, endpoint string, payloadData any, responseObject any, queryParameters map[string]string) error {
+	_, err := c.do(method, endpoint, payloadData, responseObject, queryParameters, nil)
+	return err
+}
 
+// do performs the request with the given extra headers and returns the response headers,
+// retrying it up to MaxRetries times. A 304 Not Modified response is reported as
+// ErrNotModified. The body is decoded as JSON into responseObject, unless responseObject
+// is a *[]byte, which receives it as is.
+func (c *Client) do(method string, endpoint string, payloadData any, responseObject any, queryParameters map[string]string, headers map[string]string) (http.Header, error) {
 	endpointURL := *c.endpoint
 	endpointURL.Path += endpoint
 	urlValues := url.Values{}
@@ -115,7 +259,6 @@ func (c *Client) req(method string, endpoint string, payloadData any, responseOb
 	}
 
 	urlValues.Set("minorversion", c.minorVersion)
-	urlValues.Encode()
 	endpointURL.RawQuery = urlValues.Encode()
 
 	var err error
@@ -124,21 +267,47 @@ func (c *Client) req(method string, endpoint string, payloadData any, responseOb
 	if payloadData != nil {
 		marshalledJson, err = json.Marshal(payloadData)
 		if err != nil {
-			return fmt.Errorf("failed to marshal payload: %v", err)
+			return nil, fmt.Errorf("failed to marshal payload: %v", err)
 		}
 	}
 
-	req, err := http.NewRequest(method, endpointURL.String(), bytes.NewBuffer(marshalledJson))
+	for attempt := 0; ; attempt++ {
+		h, err := c.doOnce(method, endpointURL.String(), marshalledJson, responseObject, headers, attempt)
+		if err == nil || errors.Is(err, ErrNotModified) {
+			return h, err
+		}
+
+		delay, retry := c.retryDelay(method, err, attempt)
+		if !retry {
+			return h, err
+		}
+		if err = c.sleep(delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// doOnce sends the request to endpointURL once. attempt counts the retries already made.
+func (c *Client) doOnce(method string, endpointURL string, payload []byte, responseObject any, headers map[string]string, attempt int) (h http.Header, e error) {
+	if c.limiter.throttled(c.realm) {
+		return nil, ErrThrottled
+	}
+
+	req, err := http.NewRequestWithContext(c.context(), method, endpointURL, bytes.NewReader(payload))
 	if err != nil {
-		return fmt.Errorf("failed to create request: %v", err)
+		return nil, fmt.Errorf("failed to create request: %v", err)
 	}
 
 	req.Header.Add("Accept", "application/json")
 	req.Header.Add("Content-Type", "application/json")
 
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
 	resp, err := c.Client.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to make request: %v", err)
+		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
 
 	defer func() {
@@ -147,37 +316,145 @@ func (c *Client) req(method string, endpoint string, payloadData any, responseOb
 		}
 	}()
 
+	c.limiter.observe(c.realm, resp.Header, time.Now())
+
 	switch resp.StatusCode {
 	case http.StatusOK:
 		break
+	case http.StatusNotModified:
+		return resp.Header, ErrNotModified
 	case http.StatusTooManyRequests:
-		c.throttled = true
-		go func(c *Client) {
-			time.Sleep(1 * time.Minute)
-			c.throttled = false
-		}(c)
+		// Without a Retry-After header, hold the company back for a minute, or for the next
+		// backoff delay when the request is about to be retried.
+		fallback := time.Minute
+		if attempt < c.MaxRetries {
+			fallback = backoff(attempt)
+		}
+		c.limiter.throttle(c.realm, retryAfter(resp.Header, time.Now(), fallback))
+		return nil, parseFailure(resp)
 	default:
-		return parseFailure(resp)
+		return nil, parseFailure(resp)
 	}
 
 	if responseObject != nil {
-		if err = json.NewDecoder(resp.Body).Decode(&responseObject); err != nil {
-			return fmt.Errorf("failed to unmarshal response into object: %v", err)
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %v", err)
+		}
+
+		if raw, ok := responseObject.(*[]byte); ok {
+			*raw = body
+			return resp.Header, nil
+		}
+
+		if err = checkJSONBody(resp, body); err != nil {
+			return nil, err
+		}
+
+		if err = json.Unmarshal(body, &responseObject); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal response into object: %v", err)
 		}
 	}
 
-	return nil
+	return resp.Header, nil
 }
 
 func (c *Client) get(endpoint string, responseObject interface{}, queryParameters map[string]string) error {
 	return c.req("GET", endpoint, nil, responseObject, queryParameters)
 }
 
+// getPDF fetches the PDF rendering of the transaction at endpoint, e.g. "invoice/130/pdf",
+// and returns the document unparsed.
+func (c *Client) getPDF(endpoint string) ([]byte, error) {
+	var body []byte
+	h, err := c.do("GET", endpoint, nil, &body, nil, map[string]string{"Accept": string(PDF)})
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.HasPrefix(body, []byte("%PDF")) {
+		return nil, &UnexpectedResponseError{StatusCode: http.StatusOK, ContentType: h.Get("Content-Type"), Body: snippet(body)}
+	}
+
+	return body, nil
+}
+
 func (c *Client) post(endpoint string, payloadData interface{}, responseObject interface{}, queryParameters map[string]string) error {
 	return c.req("POST", endpoint, payloadData, responseObject, queryParameters)
 }
 
-// query makes the specified QBO `query` and unmarshals the result into `responseObject`
+// postUpdate posts the sparse update payload, whose SyncToken field syncToken points to.
+func (c *Client) postUpdate(endpoint string, fetchEndpoint string, syncToken *string, payloadData interface{}, responseObject interface{}) error {
+	return c.postRetryingStale(endpoint, fetchEndpoint, syncToken, payloadData, responseObject, nil)
+}
+
+// postRetryingStale posts payloadData, whose SyncToken field syncToken points to. Updates,
+// deletes and voids go through it. With RetryOnStale set, a stale object fault makes it read
+// the current SyncToken (from the fault or by fetching fetchEndpoint), store it in *syncToken
+// and post once more.
+func (c *Client) postRetryingStale(endpoint string, fetchEndpoint string, syncToken *string, payloadData interface{}, responseObject interface{}, queryParameters map[string]string) error {
+	err := c.post(endpoint, payloadData, responseObject, queryParameters)
+	if err == nil || !c.RetryOnStale {
+		return err
+	}
+
+	var staleErr *StaleObjectError
+	if !errors.As(asStaleObject(err), &staleErr) {
+		return err
+	}
+
+	current := staleErr.CurrentSyncToken
+	if current == "" || current == *syncToken {
+		if current, err = c.currentSyncToken(fetchEndpoint); err != nil {
+			return err
+		}
+	}
+
+	*syncToken = current
+	return c.post(endpoint, payloadData, responseObject, queryParameters)
+}
+
+// currentSyncToken fetches the object at endpoint and returns its SyncToken.
+func (c *Client) currentSyncToken(endpoint string) (string, error) {
+	var resp map[string]json.RawMessage
+	if err := c.get(endpoint, &resp, nil); err != nil {
+		return "", err
+	}
+
+	for key, raw := range resp {
+		if strings.EqualFold(key, "time") {
+			continue
+		}
+
+		var object struct {
+			SyncToken string
+		}
+		if err := json.Unmarshal(raw, &object); err == nil && object.SyncToken != "" {
+			return object.SyncToken, nil
+		}
+	}
+
+	return "", fmt.Errorf("no SyncToken in %s response", endpoint)
+}
+
+// query makes the specified QBO `query` and unmarshals the result into `responseObject`.
+//
+// QuickBooks can answer 200 with a Fault next to the (partial) QueryResponse. In that case
+// responseObject still receives whatever data came back and the fault is returned as a Failure.
 func (c *Client) query(query string, responseObject interface{}) error {
-	return c.get("query", responseObject, map[string]string{"query": query})
+	var body json.RawMessage
+	if err := c.get("query", &body, map[string]string{"query": query}); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, responseObject); err != nil {
+		return fmt.Errorf("failed to unmarshal response into object: %v", err)
+	}
+
+	var failure Failure
+	if err := json.Unmarshal(body, &failure); err == nil && len(failure.Fault.Error) > 0 {
+		return failure
+	}
+
+	return nil
 }
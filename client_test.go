@@ -0,0 +1,151 @@
+package quickbooks
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForRealm(t *testing.T) {
+	var paths []string
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if strings.Contains(r.URL.Path, "/busy-realm/") {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+		_, _ = w.Write([]byte(`{}`))
+	})
+
+	busy, err := client.ForRealm("busy-realm")
+	require.NoError(t, err)
+	other, err := client.ForRealm("other-realm")
+	require.NoError(t, err)
+
+	err = busy.get("preferences", nil, nil)
+	assert.True(t, IsThrottled(err))
+	assert.NotErrorIs(t, err, ErrThrottled)
+	assert.ErrorIs(t, busy.get("preferences", nil, nil), ErrThrottled)
+
+	require.NoError(t, other.get("preferences", nil, nil))
+	require.NoError(t, client.get("preferences", nil, nil))
+
+	assert.Equal(t, []string{
+		"/v3/company/busy-realm/preferences",
+		"/v3/company/other-realm/preferences",
+		"/v3/company/test-realm/preferences",
+	}, paths)
+
+	_, err = client.ForRealm("")
+	assert.Error(t, err)
+}
+
+func TestWithContext(t *testing.T) {
+	countryLookups := 0
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/v3/company/test-realm/companyinfo/"):
+			countryLookups++
+			w.Write([]byte(`{"CompanyInfo": {"Id": "1", "Country": "US"}}`))
+		case r.URL.Path == "/v3/company/test-realm/query":
+			<-r.Context().Done()
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.WithContext(ctx).FindInvoicesWithOptions(nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	// Copies share the caches of the client they were made from.
+	country, err := client.WithContext(context.Background()).CompanyCountry()
+	require.NoError(t, err)
+	assert.Equal(t, "US", country)
+	country, err = client.CompanyCountry()
+	require.NoError(t, err)
+	assert.Equal(t, "US", country)
+	assert.Equal(t, 1, countryLookups)
+}
+
+func TestRateLimit(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/company/test-realm/preferences":
+			w.Header().Set("X-RateLimit-Limit", "500")
+			w.Header().Set("X-RateLimit-Remaining", "42")
+			w.Header().Set("X-RateLimit-Reset", "30")
+		case "/v3/company/busy-realm/preferences":
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+		_, _ = w.Write([]byte(`{}`))
+	})
+
+	assert.False(t, client.RateLimit().Observed)
+
+	before := time.Now()
+	require.NoError(t, client.get("preferences", nil, nil))
+	status := client.RateLimit()
+	assert.True(t, status.Observed)
+	assert.Equal(t, 500, status.Limit)
+	assert.Equal(t, 42, status.Remaining)
+	assert.WithinDuration(t, before.Add(30*time.Second), status.Reset, 5*time.Second)
+
+	busy, err := client.ForRealm("busy-realm")
+	require.NoError(t, err)
+	assert.True(t, IsThrottled(busy.get("preferences", nil, nil)))
+
+	status = busy.RateLimit()
+	assert.Equal(t, -1, status.Limit)
+	assert.Equal(t, 0, status.Remaining)
+	assert.WithinDuration(t, before.Add(2*time.Minute), status.Reset, 5*time.Second)
+	assert.Equal(t, 42, client.RateLimit().Remaining)
+
+	assert.Equal(t, 90*time.Second, retryAfter(http.Header{"Retry-After": {"90"}}, before, time.Minute))
+	assert.Equal(t, time.Minute, retryAfter(http.Header{}, before, time.Minute))
+}
+
+func TestGetPDF(t *testing.T) {
+	document := []byte("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n1 0 obj\n")
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/pdf", r.Header.Get("Accept"))
+
+		switch r.URL.Path {
+		case "/v3/company/test-realm/salesreceipt/11/pdf", "/v3/company/test-realm/creditmemo/12/pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Write(document)
+		case "/v3/company/test-realm/invoice/13/pdf":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"Invoice": {"Id": "13"}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"Fault": {"Error": [{"Message": "Object Not Found", "code": "610"}], "type": "ValidationFault"}}`))
+		}
+	})
+
+	pdf, err := client.FindSalesReceiptPDF("11")
+	require.NoError(t, err)
+	assert.Equal(t, document, pdf)
+
+	pdf, err = client.FindCreditMemoPDF("12")
+	require.NoError(t, err)
+	assert.Equal(t, document, pdf)
+
+	var unexpected *UnexpectedResponseError
+	_, err = client.FindInvoicePDF("13")
+	require.ErrorAs(t, err, &unexpected)
+	assert.Equal(t, "application/json", unexpected.ContentType)
+
+	_, err = client.FindEstimatePDF("14")
+	assert.True(t, IsValidation(err))
+
+	_, err = client.FindRefundReceiptPDF("")
+	assert.EqualError(t, err, "missing refund receipt id")
+}
@@ -1,9 +1,10 @@
 package quickbooks
 
 type CompanyInfo struct {
+	EntityMeta
+
 	ID                        string `json:"Id"`
 	SyncToken                 string
-	Domain                    string   `json:"domain"`
 	LegalAddr                 *Address `json:",omitempty"`
 	SupportedLanguages        *string  `json:",omitempty"`
 	CompanyName               string   `json:",omitempty"`
@@ -24,16 +25,30 @@ type CompanyInfo struct {
 // FindCompanyInfo returns the QuickBooks CompanyInfo object. This is a good
 // test to check whether you're connected.
 func (c *Client) FindCompanyInfo() (*CompanyInfo, error) {
-	var resp struct {
-		CompanyInfo CompanyInfo
-		Time        Date
+	return getSingle[CompanyInfo](c, "companyinfo/"+c.realm, nil)
+}
+
+// CompanyCountry returns the country code of the company (e.g. "US", "GB", "AU") from its
+// CompanyInfo. The result is cached on the client, so only the first call makes a request.
+func (c *Client) CompanyCountry() (string, error) {
+	cache := c.caches()
+	cache.countryMu.Lock()
+	defer cache.countryMu.Unlock()
+
+	if cache.country != "" {
+		return cache.country, nil
 	}
 
-	if err := c.get("companyinfo/"+c.realm, &resp, nil); err != nil {
-		return nil, err
+	companyInfo, err := c.FindCompanyInfo()
+	if err != nil {
+		return "", err
 	}
 
-	return &resp.CompanyInfo, nil
+	if companyInfo.Country != nil {
+		cache.country = *companyInfo.Country
+	}
+
+	return cache.country, nil
 }
 
 // UpdateCompanyInfo updates the company info
@@ -54,14 +69,5 @@ func (c *Client) UpdateCompanyInfo(companyInfo *CompanyInfo) (*CompanyInfo, erro
 		Sparse:      true,
 	}
 
-	var companyInfoData struct {
-		CompanyInfo CompanyInfo
-		Time        Date
-	}
-
-	if err = c.post("companyInfo", payload, &companyInfoData, nil); err != nil {
-		return nil, err
-	}
-
-	return &companyInfoData.CompanyInfo, err
+	return updateSingle[CompanyInfo](c, "companyinfo", "companyinfo/"+c.realm, &companyInfo.SyncToken, payload)
 }
@@ -0,0 +1,29 @@
+package quickbooks
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateCompanyInfoPath(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			assert.Equal(t, "/v3/company/test-realm/companyinfo/test-realm", r.URL.Path)
+		case http.MethodPost:
+			assert.Equal(t, "/v3/company/test-realm/companyinfo", r.URL.Path)
+		default:
+			t.Errorf("unexpected %s request", r.Method)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"CompanyInfo":{"Id":"1","SyncToken":"4","CompanyName":"Larry's Bakery"},"time":"2015-07-24T10:48:27.082-07:00"}`))
+	})
+
+	info, err := client.UpdateCompanyInfo(&CompanyInfo{CompanyName: "Larry's Bakery"})
+	require.NoError(t, err)
+	assert.Equal(t, "Larry's Bakery", info.CompanyName)
+}
@@ -0,0 +1,72 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// ErrNotModified is returned by the conditional Find methods when the object has not
+// changed since the given CacheValidators were captured.
+var ErrNotModified = errors.New("not modified")
+
+// CacheValidators holds the HTTP caching validators QuickBooks returned with a response.
+// QuickBooks does not send them on every endpoint; when both are empty a conditional
+// request behaves like a plain one.
+type CacheValidators struct {
+	ETag         string
+	LastModified string
+}
+
+// getConditional fetches endpoint, sending If-None-Match/If-Modified-Since from the given
+// validators, and returns the validators of the new response.
+// On a 304 it returns the validators it was given together with ErrNotModified.
+func (c *Client) getConditional(endpoint string, responseObject any, validators CacheValidators) (CacheValidators, error) {
+	headers := map[string]string{}
+	if validators.ETag != "" {
+		headers["If-None-Match"] = validators.ETag
+	}
+	if validators.LastModified != "" {
+		headers["If-Modified-Since"] = validators.LastModified
+	}
+
+	h, err := c.do("GET", endpoint, nil, responseObject, nil, headers)
+	if errors.Is(err, ErrNotModified) {
+		return validators, err
+	}
+	if err != nil {
+		return CacheValidators{}, err
+	}
+
+	return CacheValidators{ETag: h.Get("ETag"), LastModified: h.Get("Last-Modified")}, nil
+}
+
+// getSingleConditional is getSingle for the conditional Find methods: it decodes the T
+// returned by getConditional, and returns nothing but the validators on ErrNotModified.
+func getSingleConditional[T any](c *Client, endpoint string, validators CacheValidators) (*T, CacheValidators, error) {
+	var body json.RawMessage
+	validators, err := c.getConditional(endpoint, &body, validators)
+	if err != nil {
+		return nil, validators, err
+	}
+
+	v, err := decodeSingle[T](body)
+	if err != nil {
+		return nil, CacheValidators{}, err
+	}
+
+	return v, validators, nil
+}
+
+// FindCustomerByIDIfModified returns the customer with the given Id unless it is unchanged
+// since validators were captured, in which case it returns ErrNotModified.
+// Pass the returned validators to the next call; pass zero validators on the first one.
+func (c *Client) FindCustomerByIDIfModified(id string, validators CacheValidators) (*Customer, CacheValidators, error) {
+	return getSingleConditional[Customer](c, "customer/"+id, validators)
+}
+
+// FindInvoiceByIDIfModified returns the invoice with the given Id unless it is unchanged
+// since validators were captured, in which case it returns ErrNotModified.
+// Pass the returned validators to the next call; pass zero validators on the first one.
+func (c *Client) FindInvoiceByIDIfModified(id string, validators CacheValidators) (*Invoice, CacheValidators, error) {
+	return getSingleConditional[Invoice](c, "invoice/"+id, validators)
+}
@@ -0,0 +1,34 @@
+package quickbooks
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindInvoiceByIDIfModified(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v3/company/test-realm/invoice/130", r.URL.Path)
+
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"Invoice":{"Id":"130","SyncToken":"0","CustomerRef":{"value":"1"},"Line":[]}}`))
+	})
+
+	invoice, validators, err := client.FindInvoiceByIDIfModified("130", CacheValidators{})
+	require.NoError(t, err)
+	assert.Equal(t, "130", invoice.ID)
+	assert.Equal(t, `"v1"`, validators.ETag)
+
+	invoice, validators, err = client.FindInvoiceByIDIfModified("130", validators)
+	assert.ErrorIs(t, err, ErrNotModified)
+	assert.Nil(t, invoice)
+	assert.Equal(t, `"v1"`, validators.ETag)
+}
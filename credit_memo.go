@@ -3,65 +3,93 @@ package quickbooks
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
-	"strconv"
 )
 
 // CreditMemo represents a QuickBooks CreditMemo object as returned by the API.
 // Read-only fields (Id, SyncToken, MetaData, TotalAmt, RemainingCredit, Balance)
 // are populated by the service.
 type CreditMemo struct {
-	ID           string        `json:"Id,omitempty"`
-	SyncToken    string        `json:",omitempty"`
-	MetaData     *MetaData     `json:",omitempty"`
-	DocNumber    *string       `json:",omitempty"`
-	TxnDate      *Date         `json:",omitempty"`
-	CustomerRef  ReferenceType `json:",omitempty"`
-	CustomerMemo *MemoRef      `json:",omitempty"`
-	ProjectRef   *ReferenceType `json:",omitempty"`
-	BillAddr     *Address      `json:",omitempty"`
-	ShipAddr     *Address      `json:",omitempty"`
-	EmailStatus  *string       `json:",omitempty"`
-	BillEmail    *EmailAddress `json:",omitempty"`
-	Line         []Line        `json:",omitempty"`
-	TxnTaxDetail *TxnTaxDetail `json:",omitempty"`
-	ApplyTaxAfterDiscount *bool `json:",omitempty"`
-	CustomField  []CustomField `json:",omitempty"`
-	TotalAmt     json.Number   `json:",omitempty"`
-	RemainingCredit json.Number `json:",omitempty"`
-	Balance      json.Number   `json:",omitempty"`
+	EntityMeta
+
+	ID                    string               `json:"Id,omitempty"`
+	SyncToken             string               `json:",omitempty"`
+	MetaData              *MetaData            `json:",omitempty"`
+	DocNumber             *string              `json:",omitempty"`
+	TxnDate               *Date                `json:",omitempty"`
+	CustomerRef           ReferenceType        `json:",omitempty"`
+	CustomerMemo          *MemoRef             `json:",omitempty"`
+	ProjectRef            *ReferenceType       `json:",omitempty"`
+	BillAddr              *Address             `json:",omitempty"`
+	ShipAddr              *Address             `json:",omitempty"`
+	EmailStatus           *string              `json:",omitempty"`
+	BillEmail             *EmailAddress        `json:",omitempty"`
+	Line                  []Line               `json:",omitempty"`
+	TxnTaxDetail          *TxnTaxDetail        `json:",omitempty"`
+	GlobalTaxCalculation  GlobalTaxCalculation `json:",omitempty"`
+	ApplyTaxAfterDiscount *bool                `json:",omitempty"`
+	CustomField           []CustomField        `json:",omitempty"`
+	CurrencyRef           *ReferenceType       `json:",omitempty"`
+	ExchangeRate          json.Number          `json:",omitempty"`
+	TotalAmt              json.Number          `json:",omitempty"`
+	RemainingCredit       json.Number          `json:",omitempty"`
+	Balance               json.Number          `json:",omitempty"`
+}
+
+// TransactionTotals returns the subtotal, discount, tax and total QuickBooks computed for the credit memo.
+func (m *CreditMemo) TransactionTotals() TransactionTotals {
+	return salesTotals(m.Line, m.TxnTaxDetail, m.TotalAmt)
 }
 
 // CreditMemoCreateInput contains the writable fields accepted when creating a CreditMemo.
 // CustomerRef and Line are required; all other fields are optional.
 type CreditMemoCreateInput struct {
-	CustomerRef  ReferenceType  `json:",omitempty"`
-	Line         []Line
-	DocNumber    *string        `json:",omitempty"`
-	TxnDate      *Date          `json:",omitempty"`
-	CustomerMemo *MemoRef       `json:",omitempty"`
-	ProjectRef   *ReferenceType `json:",omitempty"`
-	BillAddr     *Address       `json:",omitempty"`
-	ShipAddr     *Address       `json:",omitempty"`
-	EmailStatus  *string        `json:",omitempty"`
-	BillEmail    *EmailAddress  `json:",omitempty"`
-	TxnTaxDetail *TxnTaxDetail  `json:",omitempty"`
-	ApplyTaxAfterDiscount *bool `json:",omitempty"`
-	CustomField  []CustomField  `json:",omitempty"`
+	CustomerRef           ReferenceType `json:",omitempty"`
+	Line                  []Line
+	DocNumber             *string              `json:",omitempty"`
+	TxnDate               *Date                `json:",omitempty"`
+	CustomerMemo          *MemoRef             `json:",omitempty"`
+	ProjectRef            *ReferenceType       `json:",omitempty"`
+	BillAddr              *Address             `json:",omitempty"`
+	ShipAddr              *Address             `json:",omitempty"`
+	EmailStatus           *string              `json:",omitempty"`
+	BillEmail             *EmailAddress        `json:",omitempty"`
+	TxnTaxDetail          *TxnTaxDetail        `json:",omitempty"`
+	GlobalTaxCalculation  GlobalTaxCalculation `json:",omitempty"`
+	ApplyTaxAfterDiscount *bool                `json:",omitempty"`
+	CustomField           []CustomField        `json:",omitempty"`
+	CurrencyRef           *ReferenceType       `json:",omitempty"`
+	ExchangeRate          json.Number          `json:",omitempty"`
+	// Extra holds fields the struct does not model yet; see ExtraFields.
+	Extra ExtraFields `json:"-"`
 }
 
 // CreateCreditMemo creates the given CreditMemo within QuickBooks.
 func (c *Client) CreateCreditMemo(input *CreditMemoCreateInput) (*CreditMemo, error) {
-	var resp struct {
-		CreditMemo CreditMemo
-		Time       Date
+	if err := input.validate(); err != nil {
+		return nil, err
 	}
 
-	if err := c.post("creditmemo", input, &resp, nil); err != nil {
+	if err := c.applyDocNumberPolicy(&input.DocNumber); err != nil {
 		return nil, err
 	}
 
-	return &resp.CreditMemo, nil
+	if err := c.resolveCustomFields(input.CustomField); err != nil {
+		return nil, err
+	}
+
+	return postSingle[CreditMemo](c, "creditmemo", input, nil)
+}
+
+func (input *CreditMemoCreateInput) validate() error {
+	if err := validateGlobalTaxCalculation(input.GlobalTaxCalculation); err != nil {
+		return err
+	}
+
+	if err := ValidateSalesLines(input.Line); err != nil {
+		return err
+	}
+
+	return validateMemos(input.CustomerMemo, nil)
 }
 
 // DeleteCreditMemo deletes the given credit memo.
@@ -70,118 +98,70 @@ func (c *Client) DeleteCreditMemo(creditMemo *CreditMemo) error {
 		return errors.New("missing id/sync token")
 	}
 
-	return c.post("creditmemo", creditMemo, nil, map[string]string{"operation": "delete"})
+	return c.postRetryingStale("creditmemo", "creditmemo/"+creditMemo.ID, &creditMemo.SyncToken, creditMemo, nil, map[string]string{"operation": "delete"})
 }
 
 // FindCreditMemos retrieves the full list of credit memos from QuickBooks.
 func (c *Client) FindCreditMemos() ([]CreditMemo, error) {
-	var resp struct {
-		QueryResponse struct {
-			CreditMemos   []CreditMemo `json:"CreditMemo"`
-			MaxResults    int
-			StartPosition int
-			TotalCount    int
-		}
-	}
-
-	if err := c.query("SELECT COUNT(*) FROM CreditMemo", &resp); err != nil {
-		return nil, err
-	}
-
-	if resp.QueryResponse.TotalCount == 0 {
-		return nil, errors.New("no credit memos could be found")
-	}
-
-	creditMemos := make([]CreditMemo, 0, resp.QueryResponse.TotalCount)
-
-	for i := 0; i < resp.QueryResponse.TotalCount; i += queryPageSize {
-		query := "SELECT * FROM CreditMemo ORDERBY Id STARTPOSITION " + strconv.Itoa(i+1) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
-
-		if err := c.query(query, &resp); err != nil {
-			return nil, err
-		}
-
-		if resp.QueryResponse.CreditMemos == nil {
-			return nil, errors.New("no credit memos could be found")
-		}
-
-		creditMemos = append(creditMemos, resp.QueryResponse.CreditMemos...)
-	}
+	return findAllPages[CreditMemo](c, "CreditMemo", "no credit memos could be found")
+}
 
-	return creditMemos, nil
+// FindCreditMemosWithOptions returns the credit memos matching opts, ordered by opts.OrderBy.
+// Pass nil for opts to list them all.
+func (c *Client) FindCreditMemosWithOptions(opts *ListOptions) ([]CreditMemo, error) {
+	return findAllWithOptions[CreditMemo](c, "CreditMemo", false, opts)
 }
 
 // FindCreditMemoByID retrieves the given credit memo from QuickBooks.
 func (c *Client) FindCreditMemoByID(id string) (*CreditMemo, error) {
-	var resp struct {
-		CreditMemo CreditMemo
-		Time       Date
-	}
+	return getSingle[CreditMemo](c, "creditmemo/"+id, nil)
+}
 
-	if err := c.get("creditmemo/"+id, &resp, nil); err != nil {
-		return nil, err
+// FindCreditMemoPDF returns the credit memo with the given Id rendered as a PDF.
+func (c *Client) FindCreditMemoPDF(id string) ([]byte, error) {
+	if id == "" {
+		return nil, errors.New("missing credit memo id")
 	}
 
-	return &resp.CreditMemo, nil
+	return c.getPDF("creditmemo/" + id + "/pdf")
+}
+
+// FindCreditMemosByIDs returns the credit memos with the given Ids. Ids that match nothing are skipped.
+func (c *Client) FindCreditMemosByIDs(ids []string) ([]CreditMemo, error) {
+	return findByIDs[CreditMemo](c, "CreditMemo", false, ids)
 }
 
 // QueryCreditMemos accepts an SQL query and returns all credit memos found using it.
 func (c *Client) QueryCreditMemos(query string) ([]CreditMemo, error) {
-	var resp struct {
-		QueryResponse struct {
-			CreditMemos   []CreditMemo `json:"CreditMemo"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
+	creditMemos, err := queryEntities[CreditMemo](c, "CreditMemo", query)
+	if err != nil {
+		return creditMemos, err
 	}
 
-	if resp.QueryResponse.CreditMemos == nil {
+	if creditMemos == nil {
 		return nil, errors.New("could not find any credit memos")
 	}
 
-	return resp.QueryResponse.CreditMemos, nil
+	return creditMemos, nil
 }
 
 // ListCreditMemos returns one page of CreditMemos ordered by Id.
 // Pass an empty pageToken to start from the beginning.
 // The returned nextPageToken is empty when there are no more results.
 func (c *Client) ListCreditMemos(pageToken string, pageSize int) (*ListResponse[CreditMemo], error) {
-	if pageSize <= 0 || pageSize > queryPageSize {
-		pageSize = queryPageSize
-	}
-
-	startPosition := 1
-	if pageToken != "" {
-		var err error
-		startPosition, err = strconv.Atoi(pageToken)
-		if err != nil {
-			return nil, fmt.Errorf("invalid page token: %v", err)
-		}
-	}
-
-	var resp struct {
-		QueryResponse struct {
-			CreditMemos   []CreditMemo `json:"CreditMemo"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
+	return listPage[CreditMemo](c, "CreditMemo", pageToken, pageSize)
+}
 
-	query := "SELECT * FROM CreditMemo ORDERBY Id STARTPOSITION " + strconv.Itoa(startPosition) + " MAXRESULTS " + strconv.Itoa(pageSize)
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
-	}
+// SendCreditMemo emails the credit memo to its BillEmail, or to emailAddress if it is not
+// empty. See SendInvoice for how a send changes the credit memo.
+func (c *Client) SendCreditMemo(creditMemoId string, emailAddress string) error {
+	queryParameters := make(map[string]string)
 
-	result := &ListResponse[CreditMemo]{Items: resp.QueryResponse.CreditMemos}
-	if len(result.Items) == pageSize {
-		result.NextPageToken = strconv.Itoa(startPosition + pageSize)
+	if emailAddress != "" {
+		queryParameters["sendTo"] = emailAddress
 	}
 
-	return result, nil
+	return c.post("creditmemo/"+creditMemoId+"/send", nil, nil, queryParameters)
 }
 
 // UpdateCreditMemo updates the given credit memo.
@@ -205,14 +185,5 @@ func (c *Client) UpdateCreditMemo(creditMemo *CreditMemo) (*CreditMemo, error) {
 		Sparse:     true,
 	}
 
-	var creditMemoData struct {
-		CreditMemo CreditMemo
-		Time       Date
-	}
-
-	if err = c.post("creditmemo", payload, &creditMemoData, nil); err != nil {
-		return nil, err
-	}
-
-	return &creditMemoData.CreditMemo, err
+	return updateSingle[CreditMemo](c, "creditmemo", "creditmemo/"+creditMemo.ID, &creditMemo.SyncToken, payload)
 }
@@ -0,0 +1,147 @@
+package quickbooks
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CustomFieldPrefs is one group of custom field settings in SalesFormsPrefs. QuickBooks
+// describes each of the (up to three) sales form custom fields with a pair of settings:
+// SalesFormsPrefs.UseSalesCustomNameN tells whether field N is enabled and
+// SalesFormsPrefs.SalesCustomNameN holds its name.
+type CustomFieldPrefs struct {
+	CustomField []CustomFieldSetting `json:",omitempty"`
+}
+
+// CustomFieldSetting is a single custom field setting of the preferences.
+type CustomFieldSetting struct {
+	Name         string  `json:",omitempty"`
+	Type         string  `json:",omitempty"`
+	StringValue  *string `json:",omitempty"`
+	BooleanValue *bool   `json:",omitempty"`
+}
+
+// CustomFieldDefinition names an enabled custom field of the sales forms. DefinitionID is
+// what a CustomField on a transaction refers to; it is assigned per company, so the same
+// field can have a different DefinitionID in another company.
+type CustomFieldDefinition struct {
+	DefinitionID string
+	Name         string
+}
+
+const (
+	customFieldNamePrefix    = "SalesFormsPrefs.SalesCustomName"
+	customFieldEnabledPrefix = "SalesFormsPrefs.UseSalesCustomName"
+)
+
+// CustomFieldDefinitions returns the enabled custom fields of the company's sales forms,
+// ordered by DefinitionID.
+func (p *Preferences) CustomFieldDefinitions() []CustomFieldDefinition {
+	if p.SalesFormsPrefs == nil {
+		return nil
+	}
+
+	names := map[string]string{}
+	enabled := map[string]bool{}
+	for _, group := range p.SalesFormsPrefs.CustomField {
+		for _, setting := range group.CustomField {
+			switch {
+			case strings.HasPrefix(setting.Name, customFieldNamePrefix) && setting.StringValue != nil:
+				names[strings.TrimPrefix(setting.Name, customFieldNamePrefix)] = *setting.StringValue
+			case strings.HasPrefix(setting.Name, customFieldEnabledPrefix):
+				enabled[strings.TrimPrefix(setting.Name, customFieldEnabledPrefix)] = boolValue(setting.BooleanValue, false)
+			}
+		}
+	}
+
+	var definitions []CustomFieldDefinition
+	for _, id := range []string{"1", "2", "3"} {
+		if name := names[id]; enabled[id] && name != "" {
+			definitions = append(definitions, CustomFieldDefinition{DefinitionID: id, Name: name})
+		}
+	}
+	return definitions
+}
+
+// CustomFieldDefinitionID returns the DefinitionID of the enabled custom field called name.
+// Names are matched ignoring case and surrounding spaces, as QuickBooks does in its UI.
+func (p *Preferences) CustomFieldDefinitionID(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	for _, definition := range p.CustomFieldDefinitions() {
+		if strings.EqualFold(strings.TrimSpace(definition.Name), name) {
+			return definition.DefinitionID, nil
+		}
+	}
+
+	return "", fmt.Errorf("no enabled custom field is named %q", name)
+}
+
+// setCustomField sets the string custom field called name in fields, replacing an earlier
+// value set for the same name. The DefinitionID is left for resolveCustomFields to fill in.
+func setCustomField(fields *[]CustomField, name, value string) {
+	for i, field := range *fields {
+		if field.DefinitionID == "" && strings.EqualFold(field.Name, name) {
+			(*fields)[i].StringValue = value
+			return
+		}
+	}
+
+	*fields = append(*fields, CustomField{Name: name, Type: "StringType", StringValue: value})
+}
+
+// resolveCustomFields fills in the DefinitionID of the custom fields that were set by name,
+// looking the names up in the company preferences. It only fetches the preferences when
+// there is a field to resolve.
+func (c *Client) resolveCustomFields(fields []CustomField) error {
+	var preferences *Preferences
+	for i := range fields {
+		if fields[i].DefinitionID != "" {
+			continue
+		}
+		if fields[i].Name == "" {
+			return errors.New("custom field needs a DefinitionID or a Name")
+		}
+
+		if preferences == nil {
+			var err error
+			if preferences, err = c.FindPreferences(); err != nil {
+				return err
+			}
+		}
+
+		id, err := preferences.CustomFieldDefinitionID(fields[i].Name)
+		if err != nil {
+			return err
+		}
+		fields[i].DefinitionID = id
+	}
+
+	return nil
+}
+
+// SetCustomField sets the custom field called name, as shown on the sales form, to value.
+// Create resolves the name to the company's DefinitionID.
+func (input *InvoiceCreateInput) SetCustomField(name, value string) {
+	setCustomField(&input.CustomField, name, value)
+}
+
+// SetCustomField sets the custom field called name to value; see InvoiceCreateInput.SetCustomField.
+func (input *EstimateCreateInput) SetCustomField(name, value string) {
+	setCustomField(&input.CustomField, name, value)
+}
+
+// SetCustomField sets the custom field called name to value; see InvoiceCreateInput.SetCustomField.
+func (input *SalesReceiptCreateInput) SetCustomField(name, value string) {
+	setCustomField(&input.CustomField, name, value)
+}
+
+// SetCustomField sets the custom field called name to value; see InvoiceCreateInput.SetCustomField.
+func (input *CreditMemoCreateInput) SetCustomField(name, value string) {
+	setCustomField(&input.CustomField, name, value)
+}
+
+// SetCustomField sets the custom field called name to value; see InvoiceCreateInput.SetCustomField.
+func (input *RefundReceiptCreateInput) SetCustomField(name, value string) {
+	setCustomField(&input.CustomField, name, value)
+}
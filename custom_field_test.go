@@ -0,0 +1,77 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const customFieldPreferences = `{"Preferences": {"Id": "1", "SalesFormsPrefs": {"CustomField": [
+  {"CustomField": [
+    {"Name": "SalesFormsPrefs.UseSalesCustomName1", "Type": "BooleanType", "BooleanValue": true},
+    {"Name": "SalesFormsPrefs.UseSalesCustomName2", "Type": "BooleanType", "BooleanValue": true},
+    {"Name": "SalesFormsPrefs.UseSalesCustomName3", "Type": "BooleanType", "BooleanValue": false}
+  ]},
+  {"CustomField": [
+    {"Name": "SalesFormsPrefs.SalesCustomName1", "Type": "StringType", "StringValue": "Crew #"},
+    {"Name": "SalesFormsPrefs.SalesCustomName2", "Type": "StringType", "StringValue": "Sales Rep"},
+    {"Name": "SalesFormsPrefs.SalesCustomName3", "Type": "StringType", "StringValue": "Unused"}
+  ]}
+]}}}`
+
+func TestCustomFieldDefinitions(t *testing.T) {
+	var resp struct{ Preferences Preferences }
+	require.NoError(t, json.Unmarshal([]byte(customFieldPreferences), &resp))
+
+	assert.Equal(t, []CustomFieldDefinition{{DefinitionID: "1", Name: "Crew #"}, {DefinitionID: "2", Name: "Sales Rep"}},
+		resp.Preferences.CustomFieldDefinitions())
+
+	id, err := resp.Preferences.CustomFieldDefinitionID(" sales rep")
+	require.NoError(t, err)
+	assert.Equal(t, "2", id)
+
+	_, err = resp.Preferences.CustomFieldDefinitionID("Unused")
+	assert.Error(t, err)
+	assert.Empty(t, (&Preferences{}).CustomFieldDefinitions())
+}
+
+func TestCreateInvoiceCustomFieldByName(t *testing.T) {
+	var body struct{ CustomField []CustomField }
+	preferenceFetches := 0
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/company/test-realm/preferences":
+			preferenceFetches++
+			w.Write([]byte(customFieldPreferences))
+		case "/v3/company/test-realm/invoice":
+			b, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(b, &body))
+			w.Write([]byte(`{"Invoice": {"Id": "130"}}`))
+		}
+	})
+
+	input := &InvoiceCreateInput{
+		CustomerRef: ReferenceType{NameValue: NameValue{Value: "1"}},
+		Line:        []Line{{Amount: "10", DetailType: "SalesItemLineDetail", SalesItemLineDetail: SalesItemLineDetail{ItemRef: &ReferenceType{NameValue: NameValue{Value: "1"}}}}},
+	}
+	input.SetCustomField("Crew #", "7")
+	input.SetCustomField("Sales Rep", "Ann")
+	input.SetCustomField("crew #", "8")
+
+	_, err := client.CreateInvoice(input)
+	require.NoError(t, err)
+	assert.Equal(t, 1, preferenceFetches)
+	assert.Equal(t, []CustomField{
+		{DefinitionID: "1", Name: "Crew #", Type: "StringType", StringValue: "8"},
+		{DefinitionID: "2", Name: "Sales Rep", Type: "StringType", StringValue: "Ann"},
+	}, body.CustomField)
+
+	input.SetCustomField("Unknown", "x")
+	_, err = client.CreateInvoice(input)
+	assert.ErrorContains(t, err, "Unknown")
+}
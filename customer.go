@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 
 	"gopkg.in/guregu/null.v4"
@@ -14,6 +13,8 @@ import (
 // Read-only fields (Id, SyncToken, MetaData, FullyQualifiedName, Level,
 // Balance, OpenBalanceDate, BalanceWithJobs) are populated by the service.
 type Customer struct {
+	EntityMeta
+
 	ID                 string          `json:"Id,omitempty"`
 	SyncToken          string          `json:",omitempty"`
 	MetaData           *MetaData       `json:",omitempty"`
@@ -36,6 +37,11 @@ type Customer struct {
 	WebAddr            *WebSiteAddress `json:",omitempty"`
 	Taxable            *bool           `json:",omitempty"`
 	TaxExemptionReasonID *string       `json:"TaxExemptionReasonId,omitempty"`
+	// DefaultTaxCodeRef is the tax code applied to the customer's sales by default.
+	// Companies outside the US use it; QuickBooks requires it when Taxable is true there.
+	DefaultTaxCodeRef *ReferenceType `json:",omitempty"`
+	// ResaleNum is the resale certificate number of a customer buying for resale.
+	ResaleNum *string `json:",omitempty"`
 	BillAddr           *Address        `json:",omitempty"`
 	ShipAddr           *Address        `json:",omitempty"`
 	Notes              *string         `json:",omitempty"`
@@ -48,6 +54,26 @@ type Customer struct {
 	BalanceWithJobs    json.Number     `json:",omitempty"`
 }
 
+// IsActive reports whether the customer is active, treating a missing Active field as active.
+func (c *Customer) IsActive() bool {
+	return boolValue(c.Active, true)
+}
+
+// BalanceFloat returns Balance as a float64, or 0 if it is not set.
+func (c *Customer) BalanceFloat() float64 {
+	return numberFloat(c.Balance)
+}
+
+// IsTaxExempt reports whether sales to the customer should not be taxed: the customer is
+// marked as not taxable, or has a tax exemption reason. QuickBooks treats a customer without
+// a Taxable flag as taxable.
+func (c *Customer) IsTaxExempt() bool {
+	if c.Taxable != nil && !*c.Taxable {
+		return true
+	}
+	return c.TaxExemptionReasonID != nil && *c.TaxExemptionReasonID != ""
+}
+
 // CustomerCreateInput contains the writable fields accepted when creating a Customer.
 // At least one of GivenName, FamilyName, DisplayName, or CompanyName is required.
 type CustomerCreateInput struct {
@@ -69,12 +95,16 @@ type CustomerCreateInput struct {
 	WebAddr            *WebSiteAddress  `json:",omitempty"`
 	Taxable            *bool            `json:",omitempty"`
 	TaxExemptionReasonID *string        `json:"TaxExemptionReasonId,omitempty"`
+	DefaultTaxCodeRef  *ReferenceType   `json:",omitempty"`
+	ResaleNum          *string          `json:",omitempty"`
 	BillAddr           *Address         `json:",omitempty"`
 	ShipAddr           *Address         `json:",omitempty"`
 	Notes              *string          `json:",omitempty"`
 	Job                null.Bool        `json:",omitempty"`
 	BillWithParent     *bool            `json:",omitempty"`
 	ParentRef          *ReferenceType   `json:",omitempty"`
+	// Extra holds fields the struct does not model yet; see ExtraFields.
+	Extra ExtraFields `json:"-"`
 }
 
 // GetAddress prioritizes the ship address, but falls back on bill address
@@ -107,149 +137,93 @@ func (c *Customer) GetPrimaryEmail() string {
 // CreateCustomer creates the given Customer on the QuickBooks server,
 // returning the resulting Customer object.
 func (c *Client) CreateCustomer(input *CustomerCreateInput) (*Customer, error) {
-	var resp struct {
-		Customer Customer
-		Time     Date
-	}
-
-	if err := c.post("customer", input, &resp, nil); err != nil {
-		return nil, err
-	}
-
-	return &resp.Customer, nil
+	return postSingle[Customer](c, "customer", input, nil)
 }
 
 // FindCustomers gets the full list of Customers in the QuickBooks account.
 func (c *Client) FindCustomers() ([]Customer, error) {
-	var resp struct {
-		QueryResponse struct {
-			Customers     []Customer `json:"Customer"`
-			MaxResults    int
-			StartPosition int
-			TotalCount    int
-		}
-	}
+	return findAllPages[Customer](c, "Customer", "no customers could be found")
+}
 
-	if err := c.query("SELECT COUNT(*) FROM Customer", &resp); err != nil {
-		return nil, err
-	}
+// FindCustomersWithOptions returns the customers matching opts, ordered by opts.OrderBy.
+// Pass nil for opts to list them all.
+func (c *Client) FindCustomersWithOptions(opts *ListOptions) ([]Customer, error) {
+	return findAllWithOptions[Customer](c, "Customer", true, opts)
+}
 
-	if resp.QueryResponse.TotalCount == 0 {
-		return nil, errors.New("no customers could be found")
+// findCustomerAddresses returns copies of the customer's billing and shipping addresses, as
+// QuickBooks copies them onto a sales form that omits them. The copies carry no Id, so the
+// form gets its own addresses. Either may be nil.
+func (c *Client) findCustomerAddresses(customerID string) (billAddr, shipAddr *Address, err error) {
+	if customerID == "" {
+		return nil, nil, errors.New("missing customer id")
 	}
 
-	customers := make([]Customer, 0, resp.QueryResponse.TotalCount)
-
-	for i := 0; i < resp.QueryResponse.TotalCount; i += queryPageSize {
-		query := "SELECT * FROM Customer ORDERBY Id STARTPOSITION " + strconv.Itoa(i+1) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
-
-		if err := c.query(query, &resp); err != nil {
-			return nil, err
-		}
+	customer, err := c.FindCustomerByID(customerID)
+	if err != nil {
+		return nil, nil, err
+	}
 
-		if resp.QueryResponse.Customers == nil {
-			return nil, errors.New("no customers could be found")
-		}
+	if customer.BillAddr != nil {
+		addr := *customer.BillAddr
+		addr.ID = ""
+		billAddr = &addr
+	}
 
-		customers = append(customers, resp.QueryResponse.Customers...)
+	if customer.ShipAddr != nil {
+		addr := *customer.ShipAddr
+		addr.ID = ""
+		shipAddr = &addr
 	}
 
-	return customers, nil
+	return billAddr, shipAddr, nil
 }
 
 // FindCustomerByID returns a customer with a given Id.
 func (c *Client) FindCustomerByID(id string) (*Customer, error) {
-	var r struct {
-		Customer Customer
-		Time     Date
-	}
-
-	if err := c.get("customer/"+id, &r, nil); err != nil {
-		return nil, err
-	}
+	return getSingle[Customer](c, "customer/"+id, nil)
+}
 
-	return &r.Customer, nil
+// FindCustomersByIDs returns the customers with the given Ids, inactive ones included. Ids that match nothing are skipped.
+func (c *Client) FindCustomersByIDs(ids []string) ([]Customer, error) {
+	return findByIDs[Customer](c, "Customer", true, ids)
 }
 
 // FindCustomerByName gets a customer with a given name.
 func (c *Client) FindCustomerByName(name string) (*Customer, error) {
-	var resp struct {
-		QueryResponse struct {
-			Customer   []Customer
-			TotalCount int
-		}
-	}
-
 	query := "SELECT * FROM Customer WHERE DisplayName = '" + strings.Replace(name, "'", "''", -1) + "'"
 
-	if err := c.query(query, &resp); err != nil {
+	customers, err := queryEntities[Customer](c, "Customer", query)
+	if err != nil {
 		return nil, err
 	}
 
-	if len(resp.QueryResponse.Customer) == 0 {
+	if len(customers) == 0 {
 		return nil, errors.New("no customers could be found")
 	}
 
-	return &resp.QueryResponse.Customer[0], nil
+	return &customers[0], nil
 }
 
 // QueryCustomers accepts an SQL query and returns all customers found using it
 func (c *Client) QueryCustomers(query string) ([]Customer, error) {
-	var resp struct {
-		QueryResponse struct {
-			Customers     []Customer `json:"Customer"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
+	customers, err := queryEntities[Customer](c, "Customer", query)
+	if err != nil {
+		return customers, err
 	}
 
-	if resp.QueryResponse.Customers == nil {
+	if customers == nil {
 		return nil, errors.New("could not find any customers")
 	}
 
-	return resp.QueryResponse.Customers, nil
+	return customers, nil
 }
 
 // ListCustomers returns one page of Customers ordered by Id.
 // Pass an empty pageToken to start from the beginning.
 // The returned nextPageToken is empty when there are no more results.
 func (c *Client) ListCustomers(pageToken string, pageSize int) (*ListResponse[Customer], error) {
-	if pageSize <= 0 || pageSize > queryPageSize {
-		pageSize = queryPageSize
-	}
-
-	startPosition := 1
-	if pageToken != "" {
-		var err error
-		startPosition, err = strconv.Atoi(pageToken)
-		if err != nil {
-			return nil, fmt.Errorf("invalid page token: %v", err)
-		}
-	}
-
-	var resp struct {
-		QueryResponse struct {
-			Customers     []Customer `json:"Customer"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	query := "SELECT * FROM Customer ORDERBY Id STARTPOSITION " + strconv.Itoa(startPosition) + " MAXRESULTS " + strconv.Itoa(pageSize)
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
-	}
-
-	result := &ListResponse[Customer]{Items: resp.QueryResponse.Customers}
-	if len(result.Items) == pageSize {
-		result.NextPageToken = strconv.Itoa(startPosition + pageSize)
-	}
-
-	return result, nil
+	return listPage[Customer](c, "Customer", pageToken, pageSize)
 }
 
 // DeleteCustomer deletes the customer.
@@ -258,7 +232,7 @@ func (c *Client) DeleteCustomer(customer *Customer) error {
 		return errors.New("missing id/sync token")
 	}
 
-	return c.post("customer", customer, nil, map[string]string{"operation": "delete"})
+	return c.postRetryingStale("customer", "customer/"+customer.ID, &customer.SyncToken, customer, nil, map[string]string{"operation": "delete"})
 }
 
 // UpdateCustomer updates the given Customer on the QuickBooks server,
@@ -284,14 +258,5 @@ func (c *Client) UpdateCustomer(customer *Customer) (*Customer, error) {
 		Sparse:   true,
 	}
 
-	var customerData struct {
-		Customer Customer
-		Time     Date
-	}
-
-	if err = c.post("customer", payload, &customerData, nil); err != nil {
-		return nil, err
-	}
-
-	return &customerData.Customer, nil
+	return updateSingle[Customer](c, "customer", "customer/"+customer.ID, &customer.SyncToken, payload)
 }
@@ -0,0 +1,33 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomerTaxFields(t *testing.T) {
+	var customer Customer
+	require.NoError(t, json.Unmarshal([]byte(`{
+  "Id": "58",
+  "DisplayName": "Resale Co",
+  "Taxable": false,
+  "TaxExemptionReasonId": "4",
+  "ResaleNum": "RS-1029",
+  "DefaultTaxCodeRef": {"value": "2"}
+}`), &customer))
+
+	assert.Equal(t, "RS-1029", *customer.ResaleNum)
+	assert.Equal(t, "2", customer.DefaultTaxCodeRef.Value)
+	assert.True(t, customer.IsTaxExempt())
+
+	assert.False(t, (&Customer{}).IsTaxExempt())
+
+	taxable := true
+	assert.False(t, (&Customer{Taxable: &taxable}).IsTaxExempt())
+
+	reason := "1"
+	assert.True(t, (&Customer{Taxable: &taxable, TaxExemptionReasonID: &reason}).IsTaxExempt())
+}
@@ -7,6 +7,8 @@ import (
 // CustomerType represents a QuickBooks CustomerType object as returned by the API.
 // Read-only fields (Id, SyncToken, MetaData) are populated by the service.
 type CustomerType struct {
+	EntityMeta
+
 	ID        string    `json:"Id,omitempty"`
 	SyncToken string    `json:",omitempty"`
 	MetaData  *MetaData `json:",omitempty"`
@@ -14,37 +16,85 @@ type CustomerType struct {
 	Active    *bool     `json:",omitempty"`
 }
 
+// CustomerTypeCreateInput contains the writable fields accepted when creating a CustomerType.
+// Name is required; Active defaults to true.
+type CustomerTypeCreateInput struct {
+	Name   string `json:",omitempty"`
+	Active *bool  `json:",omitempty"`
+	// Extra holds fields the struct does not model yet; see ExtraFields.
+	Extra ExtraFields `json:"-"`
+}
+
+// CreateCustomerType creates the given CustomerType on the QuickBooks server, returning
+// the resulting CustomerType object.
+func (c *Client) CreateCustomerType(input *CustomerTypeCreateInput) (*CustomerType, error) {
+	return postSingle[CustomerType](c, "customertype", input, nil)
+}
+
+// DeleteCustomerType deletes the customer type.
+func (c *Client) DeleteCustomerType(customerType *CustomerType) error {
+	if customerType.ID == "" || customerType.SyncToken == "" {
+		return errors.New("missing id/sync token")
+	}
+
+	return c.postRetryingStale("customertype", "customertype/"+customerType.ID, &customerType.SyncToken, customerType, nil, map[string]string{"operation": "delete"})
+}
+
+// FindCustomerTypes gets the full list of CustomerTypes in the QuickBooks account.
+func (c *Client) FindCustomerTypes() ([]CustomerType, error) {
+	return findAllPages[CustomerType](c, "CustomerType", "no customer types could be found")
+}
+
+// FindCustomerTypesWithOptions returns the customer types matching opts, ordered by opts.OrderBy.
+// Pass nil for opts to list them all.
+func (c *Client) FindCustomerTypesWithOptions(opts *ListOptions) ([]CustomerType, error) {
+	return findAllWithOptions[CustomerType](c, "CustomerType", true, opts)
+}
+
 // FindCustomerTypeByID returns a customerType with a given Id.
 func (c *Client) FindCustomerTypeByID(id string) (*CustomerType, error) {
-	var r struct {
-		CustomerType CustomerType
-		Time         Date
+	return getSingle[CustomerType](c, "customertype/"+id, nil)
+}
+
+// FindCustomerTypesByIDs returns the customer types with the given Ids, inactive ones included. Ids that match nothing are skipped.
+func (c *Client) FindCustomerTypesByIDs(ids []string) ([]CustomerType, error) {
+	return findByIDs[CustomerType](c, "CustomerType", true, ids)
+}
+
+// QueryCustomerTypes accepts an SQL query and returns all customerTypes found using it
+func (c *Client) QueryCustomerTypes(query string) ([]CustomerType, error) {
+	customerTypes, err := queryEntities[CustomerType](c, "CustomerType", query)
+	if err != nil {
+		return customerTypes, err
 	}
 
-	if err := c.get("customertype/"+id, &r, nil); err != nil {
-		return nil, err
+	if customerTypes == nil {
+		return nil, errors.New("could not find any customerTypes")
 	}
 
-	return &r.CustomerType, nil
+	return customerTypes, nil
 }
 
-// QueryCustomerTypes accepts an SQL query and returns all customerTypes found using it
-func (c *Client) QueryCustomerTypes(query string) ([]CustomerType, error) {
-	var resp struct {
-		QueryResponse struct {
-			CustomerTypes []CustomerType `json:"CustomerType"`
-			StartPosition int
-			MaxResults    int
-		}
+// UpdateCustomerType updates the customer type.
+func (c *Client) UpdateCustomerType(customerType *CustomerType) (*CustomerType, error) {
+	if customerType.ID == "" {
+		return nil, errors.New("missing customer type id")
 	}
 
-	if err := c.query(query, &resp); err != nil {
+	existingCustomerType, err := c.FindCustomerTypeByID(customerType.ID)
+	if err != nil {
 		return nil, err
 	}
 
-	if resp.QueryResponse.CustomerTypes == nil {
-		return nil, errors.New("could not find any customerTypes")
+	customerType.SyncToken = existingCustomerType.SyncToken
+
+	payload := struct {
+		*CustomerType
+		Sparse bool `json:"sparse"`
+	}{
+		CustomerType: customerType,
+		Sparse:       true,
 	}
 
-	return resp.QueryResponse.CustomerTypes, nil
+	return updateSingle[CustomerType](c, "customertype", "customertype/"+customerType.ID, &customerType.SyncToken, payload)
 }
@@ -0,0 +1,61 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomerTypeCRUD(t *testing.T) {
+	var bodies []map[string]any
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v3/company/test-realm/query":
+			query := r.URL.Query().Get("query")
+			if query == "SELECT COUNT(*) FROM CustomerType" {
+				w.Write([]byte(`{"QueryResponse": {"totalCount": 2}}`))
+				return
+			}
+			assert.Equal(t, "SELECT * FROM CustomerType ORDERBY Id STARTPOSITION 1 MAXRESULTS 1000", query)
+			w.Write([]byte(`{"QueryResponse": {"CustomerType": [{"Id": "1", "Name": "Retail"}, {"Id": "2", "Name": "Wholesale"}]}}`))
+		case r.URL.Path == "/v3/company/test-realm/customertype/2":
+			w.Write([]byte(`{"CustomerType": {"Id": "2", "SyncToken": "3", "Name": "Wholesale"}}`))
+		case r.URL.Path == "/v3/company/test-realm/customertype" && r.Method == http.MethodPost:
+			b, _ := io.ReadAll(r.Body)
+			var body map[string]any
+			require.NoError(t, json.Unmarshal(b, &body))
+			body["operation"] = r.URL.Query().Get("operation")
+			bodies = append(bodies, body)
+			w.Write([]byte(`{"CustomerType": {"Id": "2", "SyncToken": "4", "Name": "Trade"}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	types, err := client.FindCustomerTypes()
+	require.NoError(t, err)
+	require.Len(t, types, 2)
+	assert.Equal(t, "Wholesale", types[1].Name)
+
+	inactive := false
+	created, err := client.CreateCustomerType(&CustomerTypeCreateInput{Name: "Trade", Active: &inactive})
+	require.NoError(t, err)
+	assert.Equal(t, "4", created.SyncToken)
+
+	updated, err := client.UpdateCustomerType(&CustomerType{ID: "2", Name: "Trade"})
+	require.NoError(t, err)
+	assert.Equal(t, "Trade", updated.Name)
+
+	require.NoError(t, client.DeleteCustomerType(&CustomerType{ID: "2", SyncToken: "4"}))
+	assert.Error(t, client.DeleteCustomerType(&CustomerType{ID: "2"}))
+
+	require.Len(t, bodies, 3)
+	assert.Equal(t, map[string]any{"Name": "Trade", "Active": false, "operation": ""}, bodies[0])
+	assert.Equal(t, "3", bodies[1]["SyncToken"])
+	assert.Equal(t, true, bodies[1]["sparse"])
+	assert.Equal(t, "delete", bodies[2]["operation"])
+}
@@ -1,6 +1,13 @@
 package quickbooks
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
 
 type CustomField struct {
 	DefinitionID string `json:"DefinitionId,omitempty"`
@@ -32,6 +39,36 @@ func (d Date) String() string {
 	return d.Format(format)
 }
 
+// boolValue returns *b, or def when b is nil. The IsActive accessors use it with def true:
+// QuickBooks leaves Active out of objects that have never been deactivated.
+func boolValue(b *bool, def bool) bool {
+	if b == nil {
+		return def
+	}
+	return *b
+}
+
+// numberFloat returns n as a float64, or 0 when n is empty or not a number. The Float
+// accessors are for display and rough comparisons; use the json.Number fields (and
+// sumAmounts) where exact decimal arithmetic matters.
+func numberFloat(n json.Number) float64 {
+	f, err := n.Float64()
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
+// voidedNote is the PrivateNote QuickBooks puts on a transaction when it voids it.
+const voidedNote = "Voided"
+
+// isVoided reports whether a transaction with the given PrivateNote and TotalAmt has been
+// voided. A voided transaction keeps its lines but its amounts are zeroed. This is a
+// heuristic: the API exposes no void status, and the note can be edited like any other.
+func isVoided(privateNote *string, totalAmt json.Number) bool {
+	return privateNote != nil && strings.HasPrefix(*privateNote, voidedNote) && numberFloat(totalAmt) == 0
+}
+
 // EmailAddress represents a QuickBooks email address.
 type EmailAddress struct {
 	Address *string `json:",omitempty"`
@@ -49,6 +86,10 @@ const (
 	ProductionEndpoint EndpointURL = "https://quickbooks.api.intuit.com"
 	// SandboxEndpoint is for testing.
 	SandboxEndpoint EndpointURL = "https://sandbox-quickbooks.api.intuit.com"
+	// PaymentsProductionEndpoint is the Payments API base for live apps.
+	PaymentsProductionEndpoint EndpointURL = "https://api.intuit.com/quickbooks/v4/payments"
+	// PaymentsSandboxEndpoint is the Payments API base for testing.
+	PaymentsSandboxEndpoint EndpointURL = "https://sandbox.api.intuit.com/quickbooks/v4/payments"
 
 	format        = "2006-01-02T15:04:05-07:00"
 	queryPageSize = 1000
@@ -71,16 +112,78 @@ type MemoRef struct {
 	Value string `json:"value,omitempty"`
 }
 
+const (
+	// maxCustomerMemoLength is the longest CustomerMemo QuickBooks accepts.
+	maxCustomerMemoLength = 1000
+	// maxPrivateNoteLength is the longest PrivateNote QuickBooks accepts.
+	maxPrivateNoteLength = 4000
+)
+
+// validateMemos checks the customer-facing memo and the internal note of a sales transaction
+// against the QuickBooks length limits.
+func validateMemos(customerMemo *MemoRef, privateNote *string) error {
+	if customerMemo != nil && len([]rune(customerMemo.Value)) > maxCustomerMemoLength {
+		return fmt.Errorf("CustomerMemo is longer than %d characters", maxCustomerMemoLength)
+	}
+
+	if privateNote != nil && len([]rune(*privateNote)) > maxPrivateNoteLength {
+		return fmt.Errorf("PrivateNote is longer than %d characters", maxPrivateNoteLength)
+	}
+
+	return nil
+}
+
 // MetaData is a timestamp of genesis and last change of a Quickbooks object
 type MetaData struct {
 	CreateTime      Date `json:",omitempty"`
 	LastUpdatedTime Date `json:",omitempty"`
+	// CreatedByRef and LastModifiedByRef name the user behind the change. QuickBooks only
+	// returns them for some entities and editions, so they are often nil.
+	CreatedByRef      *ReferenceType `json:",omitempty"`
+	LastModifiedByRef *ReferenceType `json:",omitempty"`
+}
+
+// Created returns when the object was created, or the zero time if m is nil.
+func (m *MetaData) Created() time.Time {
+	if m == nil {
+		return time.Time{}
+	}
+	return m.CreateTime.Time
+}
+
+// LastUpdated returns when the object was last changed, or the zero time if m is nil, as
+// for objects queried without their MetaData field.
+func (m *MetaData) LastUpdated() time.Time {
+	if m == nil {
+		return time.Time{}
+	}
+	return m.LastUpdatedTime.Time
+}
+
+// EntityMeta holds the top-level "domain" and "sparse" keys QuickBooks puts on every object it
+// returns. It is embedded in the domain structs, so the fields read as invoice.Domain and
+// invoice.Sparse. Sparse is true for objects whose query selected only some fields; see
+// ListOptions.Fields.
+type EntityMeta struct {
+	// Domain is the data source of the object, "QBO" for QuickBooks Online.
+	Domain string `json:"domain,omitempty"`
+	Sparse *bool  `json:"sparse,omitempty"`
+}
+
+// IsSparse reports whether the object only carries some of its fields.
+func (m EntityMeta) IsSparse() bool {
+	return boolValue(m.Sparse, false)
 }
 
 // Address represents a QuickBooks address.
+//
+// The lines are context-dependent. In a formatted address, where City, CountrySubDivisionCode
+// or PostalCode are set, Line1-Line5 are street lines only. In a free-form address only the
+// lines are set and they hold the whole address as printed, city and postal code included;
+// QuickBooks then parses them itself and may fill in the other fields in its response. On
+// non-US companies Line1-Line5 may also carry the recipient's name.
 type Address struct {
-	ID string `json:"Id,omitempty"`
-	// These lines are context-dependent! Read the QuickBooks API carefully.
+	ID      string  `json:"Id,omitempty"`
 	Line1   string  `json:",omitempty"`
 	Line2   *string `json:",omitempty"`
 	Line3   *string `json:",omitempty"`
@@ -95,6 +198,48 @@ type Address struct {
 	Long                   string `json:",omitempty"`
 }
 
+// NewFreeFormAddress returns a free-form address made of up to five lines, printed as given.
+func NewFreeFormAddress(lines ...string) (*Address, error) {
+	if len(lines) == 0 || len(lines) > 5 {
+		return nil, fmt.Errorf("a free-form address needs 1 to 5 lines, got %d", len(lines))
+	}
+
+	addr := &Address{Line1: lines[0]}
+	for i, target := range []**string{&addr.Line2, &addr.Line3, &addr.Line4, &addr.Line5} {
+		if i+1 < len(lines) {
+			line := lines[i+1]
+			*target = &line
+		}
+	}
+	return addr, nil
+}
+
+// lenientString decodes a JSON string, number or boolean into its string form, for fields
+// QuickBooks sends quoted in some responses and bare in others. null decodes to "".
+func lenientString(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil
+	}
+
+	if raw[0] == '"' {
+		var s string
+		err := json.Unmarshal(raw, &s)
+		return s, err
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(raw, &n); err == nil {
+		return n.String(), nil
+	}
+
+	var b bool
+	if err := json.Unmarshal(raw, &b); err != nil {
+		return "", fmt.Errorf("expected a string, number or boolean, got %s", raw)
+	}
+
+	return strconv.FormatBool(b), nil
+}
+
 type NameValue struct {
 	Value string `json:"value,omitempty"`
 	Name  string `json:"name,omitempty"`
@@ -106,6 +251,14 @@ type ReferenceType struct {
 	Type string `json:"type,omitempty"`
 }
 
+// refValue returns the Id ref points at, or "" when ref is nil.
+func refValue(ref *ReferenceType) string {
+	if ref == nil {
+		return ""
+	}
+	return ref.Value
+}
+
 // TelephoneNumber represents a QuickBooks phone number.
 type TelephoneNumber struct {
 	FreeFormNumber string `json:",omitempty"`
@@ -115,3 +268,42 @@ type TelephoneNumber struct {
 type WebSiteAddress struct {
 	URI string `json:",omitempty"`
 }
+
+// sumAmounts adds up monetary amounts without going through float64.
+// Empty amounts count as zero; the result keeps the largest number of decimal places among the inputs.
+func sumAmounts(amounts ...json.Number) (json.Number, error) {
+	sum := new(big.Rat)
+	scale := 0
+
+	for _, amount := range amounts {
+		if amount == "" {
+			continue
+		}
+
+		r, ok := new(big.Rat).SetString(amount.String())
+		if !ok {
+			return "", fmt.Errorf("invalid amount %q", amount)
+		}
+		sum.Add(sum, r)
+
+		if i := strings.IndexByte(amount.String(), '.'); i >= 0 && len(amount)-i-1 > scale {
+			scale = len(amount) - i - 1
+		}
+	}
+
+	return json.Number(sum.FloatString(scale)), nil
+}
+
+// subtractAmounts returns a - b without going through float64.
+func subtractAmounts(a, b json.Number) (json.Number, error) {
+	negated := "-" + b.String()
+	if strings.HasPrefix(b.String(), "-") {
+		negated = strings.TrimPrefix(b.String(), "-")
+	}
+
+	if b == "" {
+		negated = ""
+	}
+
+	return sumAmounts(a, json.Number(negated))
+}
@@ -0,0 +1,129 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// PrintStatus tells whether a transaction is queued for printing. The PrintStatus fields of the
+// entities stay *string; compare them with string(PrintStatusNeedToPrint) and friends.
+type PrintStatus string
+
+// Print statuses.
+const (
+	PrintStatusNotSet        PrintStatus = "NotSet"
+	PrintStatusNeedToPrint   PrintStatus = "NeedToPrint"
+	PrintStatusPrintComplete PrintStatus = "PrintComplete"
+)
+
+// IsValid reports whether s is a print status QuickBooks accepts.
+func (s PrintStatus) IsValid() bool {
+	switch s {
+	case PrintStatusNotSet, PrintStatusNeedToPrint, PrintStatusPrintComplete:
+		return true
+	}
+	return false
+}
+
+// EmailStatus tells whether a transaction is queued for emailing. Like PrintStatus, the entity
+// fields it describes are plain *string.
+type EmailStatus string
+
+// Email statuses.
+const (
+	EmailStatusNotSet     EmailStatus = "NotSet"
+	EmailStatusNeedToSend EmailStatus = "NeedToSend"
+	EmailStatusEmailSent  EmailStatus = "EmailSent"
+)
+
+// IsValid reports whether s is an email status QuickBooks accepts.
+func (s EmailStatus) IsValid() bool {
+	switch s {
+	case EmailStatusNotSet, EmailStatusNeedToSend, EmailStatusEmailSent:
+		return true
+	}
+	return false
+}
+
+// sparseFields is a sparse update payload that only carries the given fields.
+type sparseFields struct {
+	ID        string
+	SyncToken string
+	Fields    map[string]any
+}
+
+func (s *sparseFields) MarshalJSON() ([]byte, error) {
+	m := map[string]any{"Id": s.ID, "SyncToken": s.SyncToken, "sparse": true}
+	for key, value := range s.Fields {
+		m[key] = value
+	}
+	return json.Marshal(m)
+}
+
+// updateFields sparse-updates only the given fields of the entity with the given Id,
+// leaving every other field alone.
+func updateFields[T any](c *Client, endpoint string, id string, fields map[string]any) (*T, error) {
+	if id == "" {
+		return nil, errors.New("missing id")
+	}
+
+	syncToken, err := c.currentSyncToken(endpoint + "/" + id)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &sparseFields{ID: id, SyncToken: syncToken, Fields: fields}
+	return updateSingle[T](c, endpoint, endpoint+"/"+id, &payload.SyncToken, payload)
+}
+
+// SetInvoicePrintStatus sets the print status of the invoice without changing anything else.
+func (c *Client) SetInvoicePrintStatus(invoiceID string, status PrintStatus) (*Invoice, error) {
+	if !status.IsValid() {
+		return nil, errors.New("invalid print status " + string(status))
+	}
+	return updateFields[Invoice](c, "invoice", invoiceID, map[string]any{"PrintStatus": status})
+}
+
+// SetInvoiceEmailStatus sets the email status of the invoice without changing anything else.
+func (c *Client) SetInvoiceEmailStatus(invoiceID string, status EmailStatus) (*Invoice, error) {
+	if !status.IsValid() {
+		return nil, errors.New("invalid email status " + string(status))
+	}
+	return updateFields[Invoice](c, "invoice", invoiceID, map[string]any{"EmailStatus": status})
+}
+
+// MarkInvoiceNeedToPrint queues the invoice for printing.
+func (c *Client) MarkInvoiceNeedToPrint(invoiceID string) (*Invoice, error) {
+	return c.SetInvoicePrintStatus(invoiceID, PrintStatusNeedToPrint)
+}
+
+// MarkInvoiceNeedToSend queues the invoice for emailing.
+func (c *Client) MarkInvoiceNeedToSend(invoiceID string) (*Invoice, error) {
+	return c.SetInvoiceEmailStatus(invoiceID, EmailStatusNeedToSend)
+}
+
+// SetEstimatePrintStatus sets the print status of the estimate without changing anything else.
+func (c *Client) SetEstimatePrintStatus(estimateID string, status PrintStatus) (*Estimate, error) {
+	if !status.IsValid() {
+		return nil, errors.New("invalid print status " + string(status))
+	}
+	return updateFields[Estimate](c, "estimate", estimateID, map[string]any{"PrintStatus": status})
+}
+
+// SetEstimateEmailStatus sets the email status of the estimate without changing anything else.
+func (c *Client) SetEstimateEmailStatus(estimateID string, status EmailStatus) (*Estimate, error) {
+	if !status.IsValid() {
+		return nil, errors.New("invalid email status " + string(status))
+	}
+	return updateFields[Estimate](c, "estimate", estimateID, map[string]any{"EmailStatus": status})
+}
+
+// MarkEstimateNeedToPrint queues the estimate for printing.
+func (c *Client) MarkEstimateNeedToPrint(estimateID string) (*Estimate, error) {
+	return c.SetEstimatePrintStatus(estimateID, PrintStatusNeedToPrint)
+}
+
+// MarkEstimateNeedToSend queues the estimate for emailing.
+func (c *Client) MarkEstimateNeedToSend(estimateID string) (*Estimate, error) {
+	return c.SetEstimateEmailStatus(estimateID, EmailStatusNeedToSend)
+}
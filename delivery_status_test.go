@@ -0,0 +1,35 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkInvoiceNeedToPrint(t *testing.T) {
+	var posted map[string]any
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			assert.Equal(t, "/v3/company/test-realm/invoice/130", r.URL.Path)
+			w.Write([]byte(`{"Invoice": {"Id": "130", "SyncToken": "3", "PrintStatus": "NotSet"}}`))
+			return
+		}
+
+		assert.Equal(t, "/v3/company/test-realm/invoice", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&posted))
+		w.Write([]byte(`{"Invoice": {"Id": "130", "SyncToken": "4", "PrintStatus": "NeedToPrint"}}`))
+	})
+
+	invoice, err := client.MarkInvoiceNeedToPrint("130")
+	require.NoError(t, err)
+	assert.Equal(t, string(PrintStatusNeedToPrint), *invoice.PrintStatus)
+	assert.Equal(t, map[string]any{"Id": "130", "SyncToken": "3", "sparse": true, "PrintStatus": "NeedToPrint"}, posted)
+
+	_, err = client.SetInvoiceEmailStatus("130", EmailStatus("Sent"))
+	assert.EqualError(t, err, "invalid email status Sent")
+	assert.True(t, EmailStatusEmailSent.IsValid())
+	assert.False(t, PrintStatus("").IsValid())
+}
@@ -4,160 +4,217 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"strconv"
+	"math/big"
 )
 
 // Deposit represents a QuickBooks Deposit object as returned by the API.
 // Read-only fields (Id, SyncToken, MetaData, TotalAmt) are populated by the service.
 type Deposit struct {
-	ID                  string        `json:"Id,omitempty"`
-	SyncToken           string        `json:",omitempty"`
-	MetaData            *MetaData     `json:",omitempty"`
-	DepositToAccountRef ReferenceType `json:",omitempty"`
-	TxnDate             *Date         `json:",omitempty"`
-	TotalAmt            json.Number   `json:",omitempty"`
-	Line                []PaymentLine `json:",omitempty"`
+	EntityMeta
+
+	ID                  string         `json:"Id,omitempty"`
+	SyncToken           string         `json:",omitempty"`
+	MetaData            *MetaData      `json:",omitempty"`
+	DepositToAccountRef ReferenceType  `json:",omitempty"`
+	TxnDate             *Date          `json:",omitempty"`
+	TotalAmt            json.Number    `json:",omitempty"`
+	CurrencyRef         *ReferenceType `json:",omitempty"`
+	ExchangeRate        json.Number    `json:",omitempty"`
+	Line                []DepositLine  `json:",omitempty"`
+}
+
+// DepositLineDetailType is the DetailType of a deposit line that posts straight to an account
+// instead of depositing an existing payment.
+const DepositLineDetailType = "DepositLineDetail"
+
+// DepositLine is a line of a deposit. It either deposits an existing transaction held in
+// Undeposited Funds, named by LinkedTxn, or is a direct line with a DepositLineDetail, such
+// as interest income or a cash sale recorded on the deposit itself.
+type DepositLine struct {
+	ID                string             `json:"Id,omitempty"`
+	Amount            json.Number        `json:",omitempty"`
+	Description       *string            `json:",omitempty"`
+	LinkedTxn         []LinkedTxn        `json:",omitempty"`
+	DetailType        string             `json:",omitempty"`
+	DepositLineDetail *DepositLineDetail `json:",omitempty"`
+}
+
+// DepositLineDetail is the detail of a direct deposit line. AccountRef is the account the line
+// is posted to; Entity optionally names the customer, vendor or employee it came from.
+type DepositLineDetail struct {
+	AccountRef       *ReferenceType `json:",omitempty"`
+	Entity           *ReferenceType `json:",omitempty"`
+	ClassRef         *ReferenceType `json:",omitempty"`
+	PaymentMethodRef *ReferenceType `json:",omitempty"`
+	CheckNum         *string        `json:",omitempty"`
+	TaxCodeRef       *ReferenceType `json:",omitempty"`
 }
 
 // DepositCreateInput contains the writable fields accepted when creating a Deposit.
 // DepositToAccountRef and Line are required; all other fields are optional.
 type DepositCreateInput struct {
 	DepositToAccountRef ReferenceType `json:",omitempty"`
-	Line                []PaymentLine
-	TxnDate             *Date `json:",omitempty"`
+	Line                []DepositLine
+	TxnDate             *Date          `json:",omitempty"`
+	CurrencyRef         *ReferenceType `json:",omitempty"`
+	ExchangeRate        json.Number    `json:",omitempty"`
+	// Extra holds fields the struct does not model yet; see ExtraFields.
+	Extra ExtraFields `json:"-"`
 }
 
-// CreateDeposit creates the given deposit within QuickBooks
-func (c *Client) CreateDeposit(input *DepositCreateInput) (*Deposit, error) {
-	var resp struct {
-		Deposit Deposit
-		Time    Date
+// AddLinkedPayment appends a line depositing the undeposited transaction txnID, usually a
+// Payment or SalesReceipt (txnType), for amount.
+func (input *DepositCreateInput) AddLinkedPayment(txnType, txnID string, amount json.Number) error {
+	if txnType == "" || txnID == "" {
+		return errors.New("missing linked transaction type or id")
 	}
-
-	if err := c.post("deposit", input, &resp, nil); err != nil {
-		return nil, err
+	if err := checkDepositAmount(amount); err != nil {
+		return err
 	}
 
-	return &resp.Deposit, nil
+	input.Line = append(input.Line, DepositLine{
+		Amount:    amount,
+		LinkedTxn: []LinkedTxn{{TxnID: txnID, TxnType: txnType, TxnLineID: "0"}},
+	})
+	return nil
 }
 
-func (c *Client) DeleteDeposit(deposit *Deposit) error {
-	if deposit.ID == "" || deposit.SyncToken == "" {
-		return errors.New("missing id/sync token")
+// AddDirectLine appends a line depositing amount straight from the account accountID, e.g.
+// an interest income account. description may be empty.
+func (input *DepositCreateInput) AddDirectLine(accountID string, amount json.Number, description string) error {
+	if accountID == "" {
+		return errors.New("missing account id")
+	}
+	if err := checkDepositAmount(amount); err != nil {
+		return err
 	}
 
-	return c.post("deposit", deposit, nil, map[string]string{"operation": "delete"})
+	line := DepositLine{
+		Amount:            amount,
+		DetailType:        DepositLineDetailType,
+		DepositLineDetail: &DepositLineDetail{AccountRef: &ReferenceType{NameValue: NameValue{Value: accountID}}},
+	}
+	if description != "" {
+		line.Description = &description
+	}
+
+	input.Line = append(input.Line, line)
+	return nil
 }
 
-// FindDeposits gets the full list of Deposits in the QuickBooks account.
-func (c *Client) FindDeposits() ([]Deposit, error) {
-	var resp struct {
-		QueryResponse struct {
-			Deposits      []Deposit `json:"Deposit"`
-			MaxResults    int
-			StartPosition int
-			TotalCount    int
-		}
+func checkDepositAmount(amount json.Number) error {
+	r, ok := new(big.Rat).SetString(amount.String())
+	if !ok || r.Sign() <= 0 {
+		return fmt.Errorf("invalid deposit line amount %q", amount)
 	}
+	return nil
+}
 
-	if err := c.query("SELECT COUNT(*) FROM Deposit", &resp); err != nil {
-		return nil, err
+// validate checks that every line is either a linked payment or a direct line with an
+// account, and that no transaction is deposited twice.
+func (input *DepositCreateInput) validate() error {
+	if input.DepositToAccountRef.Value == "" {
+		return errors.New("missing deposit to account ref")
 	}
 
-	if resp.QueryResponse.TotalCount == 0 {
-		return nil, errors.New("no deposits could be found")
+	if len(input.Line) == 0 {
+		return errors.New("a deposit needs at least one line")
 	}
 
-	deposits := make([]Deposit, 0, resp.QueryResponse.TotalCount)
-
-	for i := 0; i < resp.QueryResponse.TotalCount; i += queryPageSize {
-		query := "SELECT * FROM Deposit ORDERBY Id STARTPOSITION " + strconv.Itoa(i+1) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
-
-		if err := c.query(query, &resp); err != nil {
-			return nil, err
+	linked := map[LinkedTxn]bool{}
+	for i, line := range input.Line {
+		if err := checkDepositAmount(line.Amount); err != nil {
+			return fmt.Errorf("line %d: %w", i, err)
 		}
 
-		if resp.QueryResponse.Deposits == nil {
-			return nil, errors.New("no deposits could be found")
+		switch {
+		case len(line.LinkedTxn) > 0 && (line.DetailType != "" || line.DepositLineDetail != nil):
+			return fmt.Errorf("line %d: a linked payment line cannot also have a DepositLineDetail", i)
+		case len(line.LinkedTxn) > 0:
+			for _, txn := range line.LinkedTxn {
+				key := LinkedTxn{TxnID: txn.TxnID, TxnType: txn.TxnType}
+				if linked[key] {
+					return fmt.Errorf("line %d: %s %s is deposited twice", i, txn.TxnType, txn.TxnID)
+				}
+				linked[key] = true
+			}
+		case line.DetailType != DepositLineDetailType || line.DepositLineDetail == nil:
+			return fmt.Errorf("line %d: needs either a LinkedTxn or a DepositLineDetail", i)
+		case line.DepositLineDetail.AccountRef == nil || line.DepositLineDetail.AccountRef.Value == "":
+			return fmt.Errorf("line %d: a direct deposit line needs an AccountRef", i)
 		}
+	}
 
-		deposits = append(deposits, resp.QueryResponse.Deposits...)
+	return nil
+}
+
+// CreateDeposit creates the given deposit within QuickBooks
+func (c *Client) CreateDeposit(input *DepositCreateInput) (*Deposit, error) {
+	if err := input.validate(); err != nil {
+		return nil, err
 	}
 
-	return deposits, nil
+	return postSingle[Deposit](c, "deposit", input, nil)
 }
 
-// FindDepositByID returns a deposit with a given Id.
-func (c *Client) FindDepositByID(id string) (*Deposit, error) {
-	var resp struct {
-		Deposit Deposit
-		Time    Date
+func (c *Client) DeleteDeposit(deposit *Deposit) error {
+	if deposit.ID == "" || deposit.SyncToken == "" {
+		return errors.New("missing id/sync token")
 	}
 
-	if err := c.get("deposit/"+id, &resp, nil); err != nil {
-		return nil, err
+	return c.postRetryingStale("deposit", "deposit/"+deposit.ID, &deposit.SyncToken, deposit, nil, map[string]string{"operation": "delete"})
+}
+
+// FindDeposits gets the full list of Deposits in the QuickBooks account.
+func (c *Client) FindDeposits() ([]Deposit, error) {
+	return findAllPages[Deposit](c, "Deposit", "no deposits could be found")
+}
+
+// FindDepositsWithOptions returns the deposits matching opts, ordered by opts.OrderBy.
+// Pass nil for opts to list them all.
+func (c *Client) FindDepositsWithOptions(opts *ListOptions) ([]Deposit, error) {
+	return findAllWithOptions[Deposit](c, "Deposit", false, opts)
+}
+
+// QueryDepositsForAccount returns every deposit into the given bank account, paging through
+// all of them, ordered by Id.
+func (c *Client) QueryDepositsForAccount(accountID string) ([]Deposit, error) {
+	if accountID == "" {
+		return nil, errors.New("missing account id")
 	}
 
-	return &resp.Deposit, nil
+	return findAllWithOptions[Deposit](c, "Deposit", false, &ListOptions{Filter: refEquals("DepositToAccountRef", accountID)})
+}
+
+// FindDepositByID returns a deposit with a given Id.
+func (c *Client) FindDepositByID(id string) (*Deposit, error) {
+	return getSingle[Deposit](c, "deposit/"+id, nil)
+}
+
+// FindDepositsByIDs returns the deposits with the given Ids. Ids that match nothing are skipped.
+func (c *Client) FindDepositsByIDs(ids []string) ([]Deposit, error) {
+	return findByIDs[Deposit](c, "Deposit", false, ids)
 }
 
 // QueryDeposits accepts an SQL query and returns all deposits found using it
 func (c *Client) QueryDeposits(query string) ([]Deposit, error) {
-	var resp struct {
-		QueryResponse struct {
-			Deposits      []Deposit `json:"Deposit"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
+	deposits, err := queryEntities[Deposit](c, "Deposit", query)
+	if err != nil {
+		return deposits, err
 	}
 
-	if resp.QueryResponse.Deposits == nil {
+	if deposits == nil {
 		return nil, errors.New("could not find any deposits")
 	}
 
-	return resp.QueryResponse.Deposits, nil
+	return deposits, nil
 }
 
 // ListDeposits returns one page of Deposits ordered by Id.
 // Pass an empty pageToken to start from the beginning.
 // The returned nextPageToken is empty when there are no more results.
 func (c *Client) ListDeposits(pageToken string, pageSize int) (*ListResponse[Deposit], error) {
-	if pageSize <= 0 || pageSize > queryPageSize {
-		pageSize = queryPageSize
-	}
-
-	startPosition := 1
-	if pageToken != "" {
-		var err error
-		startPosition, err = strconv.Atoi(pageToken)
-		if err != nil {
-			return nil, fmt.Errorf("invalid page token: %v", err)
-		}
-	}
-
-	var resp struct {
-		QueryResponse struct {
-			Deposits      []Deposit `json:"Deposit"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	query := "SELECT * FROM Deposit ORDERBY Id STARTPOSITION " + strconv.Itoa(startPosition) + " MAXRESULTS " + strconv.Itoa(pageSize)
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
-	}
-
-	result := &ListResponse[Deposit]{Items: resp.QueryResponse.Deposits}
-	if len(result.Items) == pageSize {
-		result.NextPageToken = strconv.Itoa(startPosition + pageSize)
-	}
-
-	return result, nil
+	return listPage[Deposit](c, "Deposit", pageToken, pageSize)
 }
 
 // UpdateDeposit updates the deposit
@@ -181,14 +238,5 @@ func (c *Client) UpdateDeposit(deposit *Deposit) (*Deposit, error) {
 		Sparse:  true,
 	}
 
-	var depositData struct {
-		Deposit Deposit
-		Time    Date
-	}
-
-	if err = c.post("deposit", payload, &depositData, nil); err != nil {
-		return nil, err
-	}
-
-	return &depositData.Deposit, err
+	return updateSingle[Deposit](c, "deposit", "deposit/"+deposit.ID, &deposit.SyncToken, payload)
 }
@@ -0,0 +1,71 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateMixedDeposit(t *testing.T) {
+	var body map[string]any
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(b, &body))
+		w.Write([]byte(`{"Deposit": {"Id": "77", "TotalAmt": 162.5}}`))
+	})
+
+	input := &DepositCreateInput{DepositToAccountRef: ReferenceType{NameValue: NameValue{Value: "35"}}}
+	require.NoError(t, input.AddLinkedPayment("Payment", "101", "100.00"))
+	require.NoError(t, input.AddLinkedPayment("SalesReceipt", "102", "50.00"))
+	require.NoError(t, input.AddDirectLine("81", "12.50", "Interest"))
+
+	deposit, err := client.CreateDeposit(input)
+	require.NoError(t, err)
+	assert.Equal(t, "77", deposit.ID)
+
+	lines := body["Line"].([]any)
+	require.Len(t, lines, 3)
+	assert.Equal(t, map[string]any{
+		"Amount":    100.0,
+		"LinkedTxn": []any{map[string]any{"TxnId": "101", "TxnType": "Payment", "TxnLineId": "0"}},
+	}, lines[0])
+	assert.Equal(t, map[string]any{
+		"Amount":            12.5,
+		"Description":       "Interest",
+		"DetailType":        "DepositLineDetail",
+		"DepositLineDetail": map[string]any{"AccountRef": map[string]any{"value": "81"}},
+	}, lines[2])
+}
+
+func TestDepositCreateInputValidate(t *testing.T) {
+	account := ReferenceType{NameValue: NameValue{Value: "35"}}
+
+	input := &DepositCreateInput{DepositToAccountRef: account}
+	assert.Error(t, input.validate())
+	assert.Error(t, input.AddLinkedPayment("Payment", "", "10"))
+	assert.Error(t, input.AddDirectLine("81", "0", ""))
+	assert.Error(t, input.AddDirectLine("", "10", ""))
+
+	require.NoError(t, input.AddLinkedPayment("Payment", "101", "10"))
+	require.NoError(t, input.AddLinkedPayment("Payment", "101", "10"))
+	assert.ErrorContains(t, input.validate(), "deposited twice")
+
+	input = &DepositCreateInput{DepositToAccountRef: account, Line: []DepositLine{{Amount: "10"}}}
+	assert.Error(t, input.validate())
+
+	input = &DepositCreateInput{DepositToAccountRef: account, Line: []DepositLine{{
+		Amount:            "10",
+		LinkedTxn:         []LinkedTxn{{TxnID: "101", TxnType: "Payment"}},
+		DetailType:        DepositLineDetailType,
+		DepositLineDetail: &DepositLineDetail{},
+	}}}
+	assert.Error(t, input.validate())
+
+	input = &DepositCreateInput{Line: []DepositLine{{Amount: "10", LinkedTxn: []LinkedTxn{{TxnID: "101", TxnType: "Payment"}}}}}
+	assert.Error(t, input.validate())
+}
@@ -0,0 +1,107 @@
+package quickbooks
+
+import (
+	"reflect"
+	"time"
+)
+
+// FieldChange describes one field that differs between two versions of an object.
+type FieldChange struct {
+	// Path is the dotted path of the field, e.g. "CustomerRef.Value" or "BillAddr.City".
+	// Fields of embedded structs are reported without the embedded type's name.
+	Path string
+	// Old and New hold the field values; a nil pointer is reported as nil.
+	Old any
+	New any
+}
+
+// Diff reports which exported fields changed between two fetched versions of the same object,
+// e.g. the copy stored before a change data capture poll and the one it returned.
+//
+// Pointers are compared by the value they point to, and nested structs such as references and
+// addresses are compared field by field. Slices (like Line) and maps are compared as a whole and
+// reported at their own path. SyncToken and MetaData.LastUpdatedTime differ after any update,
+// so callers building audit logs usually skip them.
+func Diff[T any](old, new T) []FieldChange {
+	var changes []FieldChange
+	diffValues("", reflect.ValueOf(old), reflect.ValueOf(new), &changes)
+	return changes
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func diffValues(path string, old, new reflect.Value, changes *[]FieldChange) {
+	if !old.IsValid() || !new.IsValid() {
+		if old.IsValid() != new.IsValid() {
+			*changes = append(*changes, FieldChange{Path: path, Old: diffInterface(old), New: diffInterface(new)})
+		}
+		return
+	}
+
+	switch old.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if old.IsNil() || new.IsNil() {
+			if old.IsNil() != new.IsNil() {
+				*changes = append(*changes, FieldChange{Path: path, Old: diffInterface(old), New: diffInterface(new)})
+			}
+			return
+		}
+		diffValues(path, old.Elem(), new.Elem(), changes)
+		return
+	case reflect.Struct:
+		if old.Type() == timeType {
+			if !old.Interface().(time.Time).Equal(new.Interface().(time.Time)) {
+				*changes = append(*changes, FieldChange{Path: path, Old: old.Interface(), New: new.Interface()})
+			}
+			return
+		}
+
+		if hasUnexportedFields(old.Type()) {
+			break
+		}
+
+		for i := 0; i < old.NumField(); i++ {
+			field := old.Type().Field(i)
+			fieldPath := path
+			if !field.Anonymous {
+				fieldPath = joinPath(path, field.Name)
+			}
+			diffValues(fieldPath, old.Field(i), new.Field(i), changes)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(old.Interface(), new.Interface()) {
+		*changes = append(*changes, FieldChange{Path: path, Old: old.Interface(), New: new.Interface()})
+	}
+}
+
+// diffInterface returns the value held by v, dereferencing pointers, or nil for a nil/invalid value.
+func diffInterface(v reflect.Value) any {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Interface()
+}
+
+func hasUnexportedFields(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if !t.Field(i).IsExported() {
+			return true
+		}
+	}
+	return false
+}
+
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
@@ -0,0 +1,46 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	var old, new Customer
+	require.NoError(t, json.Unmarshal([]byte(`{
+  "Id": "1",
+  "SyncToken": "3",
+  "DisplayName": "Amy's Bird Sanctuary",
+  "Notes": "Note",
+  "CustomerTypeRef": {"value": "1"},
+  "BillAddr": {"City": "Bayshore", "PostalCode": "94326"},
+  "MetaData": {"CreateTime": "2014-09-11T16:48:43-07:00", "LastUpdatedTime": "2015-07-01T10:14:15-07:00"}
+}`), &old))
+	require.NoError(t, json.Unmarshal([]byte(`{
+  "Id": "1",
+  "SyncToken": "4",
+  "DisplayName": "Amy's Bird Sanctuary",
+  "CustomerTypeRef": {"value": "2"},
+  "BillAddr": {"City": "Bayshore", "PostalCode": "94327"},
+  "MetaData": {"CreateTime": "2014-09-11T16:48:43-07:00", "LastUpdatedTime": "2015-07-02T10:14:15-07:00"}
+}`), &new))
+
+	changes := Diff(old, new)
+
+	paths := make(map[string]FieldChange, len(changes))
+	for _, c := range changes {
+		paths[c.Path] = c
+	}
+
+	assert.Len(t, changes, 5)
+	assert.Equal(t, FieldChange{Path: "SyncToken", Old: "3", New: "4"}, paths["SyncToken"])
+	assert.Contains(t, paths, "MetaData.LastUpdatedTime")
+	assert.Equal(t, FieldChange{Path: "Notes", Old: "Note", New: nil}, paths["Notes"])
+	assert.Equal(t, FieldChange{Path: "CustomerTypeRef.Value", Old: "1", New: "2"}, paths["CustomerTypeRef.Value"])
+	assert.Equal(t, FieldChange{Path: "BillAddr.PostalCode", Old: "94326", New: "94327"}, paths["BillAddr.PostalCode"])
+
+	assert.Empty(t, Diff(&old, &old))
+}
@@ -2,13 +2,13 @@ package quickbooks
 
 import (
 	"errors"
-	"fmt"
-	"strconv"
 )
 
 // Employee represents a QuickBooks Employee object as returned by the API.
 // Read-only fields (Id, SyncToken, MetaData) are populated by the service.
 type Employee struct {
+	EntityMeta
+
 	ID               string    `json:"Id,omitempty"`
 	SyncToken        string    `json:",omitempty"`
 	MetaData         *MetaData `json:",omitempty"`
@@ -25,6 +25,11 @@ type Employee struct {
 	PrimaryPhone TelephoneNumber `json:"-"`
 }
 
+// IsActive reports whether the employee is active, treating a missing Active field as active.
+func (e *Employee) IsActive() bool {
+	return boolValue(e.Active, true)
+}
+
 // EmployeeCreateInput contains the writable fields accepted when creating an Employee.
 // At least one of GivenName, FamilyName, or DisplayName is required.
 type EmployeeCreateInput struct {
@@ -34,131 +39,55 @@ type EmployeeCreateInput struct {
 	PrintOnCheckName *string `json:",omitempty"`
 	Active           *bool   `json:",omitempty"`
 	BillableTime     *bool   `json:",omitempty"`
+	// Extra holds fields the struct does not model yet; see ExtraFields.
+	Extra ExtraFields `json:"-"`
 }
 
 // CreateEmployee creates the given employee within QuickBooks
 func (c *Client) CreateEmployee(input *EmployeeCreateInput) (*Employee, error) {
-	var resp struct {
-		Employee Employee
-		Time     Date
-	}
-
-	if err := c.post("employee", input, &resp, nil); err != nil {
-		return nil, err
-	}
-
-	return &resp.Employee, nil
+	return postSingle[Employee](c, "employee", input, nil)
 }
 
 // FindEmployees gets the full list of Employees in the QuickBooks account.
 func (c *Client) FindEmployees() ([]Employee, error) {
-	var resp struct {
-		QueryResponse struct {
-			Employees     []Employee `json:"Employee"`
-			MaxResults    int
-			StartPosition int
-			TotalCount    int
-		}
-	}
-
-	if err := c.query("SELECT COUNT(*) FROM Employee", &resp); err != nil {
-		return nil, err
-	}
-
-	if resp.QueryResponse.TotalCount == 0 {
-		return nil, errors.New("no employees could be found")
-	}
-
-	employees := make([]Employee, 0, resp.QueryResponse.TotalCount)
-
-	for i := 0; i < resp.QueryResponse.TotalCount; i += queryPageSize {
-		query := "SELECT * FROM Employee ORDERBY Id STARTPOSITION " + strconv.Itoa(i+1) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
-
-		if err := c.query(query, &resp); err != nil {
-			return nil, err
-		}
-
-		if resp.QueryResponse.Employees == nil {
-			return nil, errors.New("no employees could be found")
-		}
-
-		employees = append(employees, resp.QueryResponse.Employees...)
-	}
+	return findAllPages[Employee](c, "Employee", "no employees could be found")
+}
 
-	return employees, nil
+// FindEmployeesWithOptions returns the employees matching opts, ordered by opts.OrderBy.
+// Pass nil for opts to list them all.
+func (c *Client) FindEmployeesWithOptions(opts *ListOptions) ([]Employee, error) {
+	return findAllWithOptions[Employee](c, "Employee", true, opts)
 }
 
 // FindEmployeeByID returns an employee with a given Id.
 func (c *Client) FindEmployeeByID(id string) (*Employee, error) {
-	var resp struct {
-		Employee Employee
-		Time     Date
-	}
-
-	if err := c.get("employee/"+id, &resp, nil); err != nil {
-		return nil, err
-	}
+	return getSingle[Employee](c, "employee/"+id, nil)
+}
 
-	return &resp.Employee, nil
+// FindEmployeesByIDs returns the employees with the given Ids, inactive ones included. Ids that match nothing are skipped.
+func (c *Client) FindEmployeesByIDs(ids []string) ([]Employee, error) {
+	return findByIDs[Employee](c, "Employee", true, ids)
 }
 
 // QueryEmployees accepts an SQL query and returns all employees found using it
 func (c *Client) QueryEmployees(query string) ([]Employee, error) {
-	var resp struct {
-		QueryResponse struct {
-			Employees     []Employee `json:"Employee"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
+	employees, err := queryEntities[Employee](c, "Employee", query)
+	if err != nil {
+		return employees, err
 	}
 
-	if resp.QueryResponse.Employees == nil {
+	if employees == nil {
 		return nil, errors.New("could not find any employees")
 	}
 
-	return resp.QueryResponse.Employees, nil
+	return employees, nil
 }
 
 // ListEmployees returns one page of Employees ordered by Id.
 // Pass an empty pageToken to start from the beginning.
 // The returned nextPageToken is empty when there are no more results.
 func (c *Client) ListEmployees(pageToken string, pageSize int) (*ListResponse[Employee], error) {
-	if pageSize <= 0 || pageSize > queryPageSize {
-		pageSize = queryPageSize
-	}
-
-	startPosition := 1
-	if pageToken != "" {
-		var err error
-		startPosition, err = strconv.Atoi(pageToken)
-		if err != nil {
-			return nil, fmt.Errorf("invalid page token: %v", err)
-		}
-	}
-
-	var resp struct {
-		QueryResponse struct {
-			Employees     []Employee `json:"Employee"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	query := "SELECT * FROM Employee ORDERBY Id STARTPOSITION " + strconv.Itoa(startPosition) + " MAXRESULTS " + strconv.Itoa(pageSize)
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
-	}
-
-	result := &ListResponse[Employee]{Items: resp.QueryResponse.Employees}
-	if len(result.Items) == pageSize {
-		result.NextPageToken = strconv.Itoa(startPosition + pageSize)
-	}
-
-	return result, nil
+	return listPage[Employee](c, "Employee", pageToken, pageSize)
 }
 
 // DeleteEmployee deletes the employee.
@@ -167,7 +96,7 @@ func (c *Client) DeleteEmployee(employee *Employee) error {
 		return errors.New("missing id/sync token")
 	}
 
-	return c.post("employee", employee, nil, map[string]string{"operation": "delete"})
+	return c.postRetryingStale("employee", "employee/"+employee.ID, &employee.SyncToken, employee, nil, map[string]string{"operation": "delete"})
 }
 
 // UpdateEmployee updates the employee
@@ -191,14 +120,5 @@ func (c *Client) UpdateEmployee(employee *Employee) (*Employee, error) {
 		Sparse:   true,
 	}
 
-	var employeeData struct {
-		Employee Employee
-		Time     Date
-	}
-
-	if err = c.post("employee", payload, &employeeData, nil); err != nil {
-		return nil, err
-	}
-
-	return &employeeData.Employee, err
+	return updateSingle[Employee](c, "employee", "employee/"+employee.ID, &employee.SyncToken, payload)
 }
@@ -0,0 +1,214 @@
+package quickbooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+)
+
+// ExportEntity writes every object of the given entity (e.g. "Invoice", "Customer") to w as
+// NDJSON: one JSON object per line, ordered by Id. Objects are written as QuickBooks returns
+// them, including fields this package does not model, and only one page of queryPageSize
+// objects is held in memory at a time.
+func (c *Client) ExportEntity(entityName string, w io.Writer) error {
+	if entityName == "" {
+		return errors.New("missing entity name")
+	}
+
+	var line bytes.Buffer
+	return c.exportPages(context.Background(), entityName, 1, func(page []json.RawMessage, next int) error {
+		for _, object := range page {
+			line.Reset()
+			if err := json.Compact(&line, object); err != nil {
+				return err
+			}
+			line.WriteByte('\n')
+
+			if _, err := w.Write(line.Bytes()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// exportPages queries the objects of entityName ordered by Id, one page of queryPageSize at a
+// time from STARTPOSITION start, and passes each page to fn with the start position of the
+// page after it. ctx is checked before every page.
+func (c *Client) exportPages(ctx context.Context, entityName string, start int, fn func(page []json.RawMessage, next int) error) error {
+	for ; ; start += queryPageSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		query := "SELECT * FROM " + entityName + " ORDERBY Id STARTPOSITION " + strconv.Itoa(start) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
+
+		page, err := queryEntities[json.RawMessage](c, entityName, query)
+		if err != nil {
+			return err
+		}
+
+		if err = fn(page, start+queryPageSize); err != nil {
+			return err
+		}
+
+		if len(page) < queryPageSize {
+			return nil
+		}
+	}
+}
+
+// ExportCursorVersion is the Version of the cursors ExportCompany writes.
+const ExportCursorVersion = 1
+
+// ExportCursor records how far ExportCompany has got. Its JSON form is stable, so cursors
+// can be persisted and read back by later releases:
+//
+//	{"version":1,"entity":"Invoice","startPosition":3001,"complete":false}
+//
+// entity is the entity being exported and startPosition the STARTPOSITION of its next page,
+// counting from 1. The entities before entity in the export's list are done; complete is true
+// once every entity is.
+type ExportCursor struct {
+	Version       int    `json:"version"`
+	Entity        string `json:"entity"`
+	StartPosition int    `json:"startPosition"`
+	Complete      bool   `json:"complete"`
+}
+
+// ExportCursorStore persists the cursor of an export between runs.
+type ExportCursorStore interface {
+	// LoadCursor returns the saved cursor, or nil if there is none and the export starts over.
+	LoadCursor() (*ExportCursor, error)
+	// SaveCursor replaces the saved cursor.
+	SaveCursor(cursor *ExportCursor) error
+}
+
+// FileCursorStore is an ExportCursorStore keeping the cursor as JSON in the file at the given
+// path. The file is replaced atomically, so a crash while saving leaves the previous cursor.
+type FileCursorStore string
+
+// LoadCursor reads the cursor file; a missing file means no cursor.
+func (path FileCursorStore) LoadCursor() (*ExportCursor, error) {
+	b, err := os.ReadFile(string(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor ExportCursor
+	if err = json.Unmarshal(b, &cursor); err != nil {
+		return nil, fmt.Errorf("invalid export cursor in %s: %w", string(path), err)
+	}
+	return &cursor, nil
+}
+
+// SaveCursor writes the cursor to a temporary file next to path and renames it into place.
+func (path FileCursorStore) SaveCursor(cursor *ExportCursor) error {
+	b, err := json.Marshal(cursor)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(string(path)), filepath.Base(string(path))+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), string(path))
+}
+
+// ExportPageFunc receives one page of an export: raw objects of entity, ordered by Id, as
+// QuickBooks returns them. Returning an error stops the export.
+type ExportPageFunc func(entity string, objects []json.RawMessage) error
+
+// ExportCompany exports every object of the given entities, in order, passing each page to
+// fn. A nil or empty entities exports every entity FindAll can list, see FindableEntities.
+//
+// The position is saved to store after each page fn accepts, and a later call with the same
+// entities and store resumes from it, so an interrupted export does not start from zero.
+// Delivery is at least once: a page is passed to fn again if the export stopped after fn
+// returned but before the cursor was saved, so fn should tolerate repeats, e.g. by upserting
+// by Id. Once everything is exported the cursor is saved as complete and further calls return
+// right away; clear the store to export again.
+//
+// Canceling ctx aborts the request in flight and stops the export; the cursor keeps the
+// position of the last page fn accepted.
+func (c *Client) ExportCompany(ctx context.Context, entities []string, store ExportCursorStore, fn ExportPageFunc) error {
+	c = c.WithContext(ctx)
+
+	if len(entities) == 0 {
+		for _, name := range FindableEntities() {
+			if entityFinders[name].findAll != nil {
+				entities = append(entities, name)
+			}
+		}
+	}
+
+	cursor, err := store.LoadCursor()
+	if err != nil {
+		return err
+	}
+	if cursor == nil {
+		cursor = &ExportCursor{Version: ExportCursorVersion, Entity: entities[0], StartPosition: 1}
+	}
+	if cursor.Version != ExportCursorVersion {
+		return fmt.Errorf("unsupported export cursor version %d", cursor.Version)
+	}
+	if cursor.Complete {
+		return nil
+	}
+
+	first := slices.Index(entities, cursor.Entity)
+	if first < 0 {
+		return fmt.Errorf("export cursor entity %q is not one of the exported entities", cursor.Entity)
+	}
+	if cursor.StartPosition < 1 {
+		return fmt.Errorf("invalid export cursor start position %d", cursor.StartPosition)
+	}
+
+	for i := first; i < len(entities); i++ {
+		entity := entities[i]
+		start := 1
+		if i == first {
+			start = cursor.StartPosition
+		}
+
+		err = c.exportPages(ctx, entity, start, func(page []json.RawMessage, next int) error {
+			if len(page) > 0 {
+				if err := fn(entity, page); err != nil {
+					return err
+				}
+			}
+			return store.SaveCursor(&ExportCursor{Version: ExportCursorVersion, Entity: entity, StartPosition: next})
+		})
+		if err != nil {
+			return err
+		}
+
+		if i+1 < len(entities) {
+			if err = store.SaveCursor(&ExportCursor{Version: ExportCursorVersion, Entity: entities[i+1], StartPosition: 1}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return store.SaveCursor(&ExportCursor{Version: ExportCursorVersion, Entity: entities[len(entities)-1], Complete: true})
+}
@@ -0,0 +1,132 @@
+package quickbooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportEntity(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "SELECT * FROM Class ORDERBY Id STARTPOSITION 1 MAXRESULTS 1000", r.URL.Query().Get("query"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"QueryResponse":{"Class":[
+  {"Id": "1", "Name": "East",
+   "Unmodeled": {"kept": true}},
+  {"Id": "2", "Name": "West"}
+]}}`))
+	})
+
+	var buf bytes.Buffer
+	require.NoError(t, client.ExportEntity("Class", &buf))
+	assert.Equal(t, `{"Id":"1","Name":"East","Unmodeled":{"kept":true}}
+{"Id":"2","Name":"West"}
+`, buf.String())
+}
+
+type memoryCursorStore struct {
+	cursor *ExportCursor
+	saves  int
+}
+
+func (s *memoryCursorStore) LoadCursor() (*ExportCursor, error) { return s.cursor, nil }
+
+func (s *memoryCursorStore) SaveCursor(cursor *ExportCursor) error {
+	s.cursor = cursor
+	s.saves++
+	return nil
+}
+
+func TestExportCompanyResume(t *testing.T) {
+	failClassPage := true
+	var queries []string
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		queries = append(queries, query)
+
+		m := pagePattern.FindStringSubmatch(query)
+		require.NotNil(t, m, query)
+		start, _ := strconv.Atoi(m[1])
+
+		entity, total := "Class", 1500
+		if strings.Contains(query, "FROM Term") {
+			entity, total = "Term", 2
+		}
+		if entity == "Class" && start == 1001 && failClassPage {
+			failClassPage = false
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		var objects []string
+		for id := start; id < start+queryPageSize && id <= total; id++ {
+			objects = append(objects, `{"Id":"`+strconv.Itoa(id)+`"}`)
+		}
+		w.Write([]byte(`{"QueryResponse":{"` + entity + `":[` + strings.Join(objects, ",") + `]}}`))
+	})
+
+	store := &memoryCursorStore{}
+	seen := map[string]int{}
+	export := func(entity string, objects []json.RawMessage) error {
+		seen[entity] += len(objects)
+		return nil
+	}
+
+	err := client.ExportCompany(context.Background(), []string{"Class", "Term"}, store, export)
+	require.Error(t, err)
+	assert.Equal(t, &ExportCursor{Version: 1, Entity: "Class", StartPosition: 1001}, store.cursor)
+	assert.Equal(t, 1000, seen["Class"])
+
+	queries = nil
+	require.NoError(t, client.ExportCompany(context.Background(), []string{"Class", "Term"}, store, export))
+	assert.Equal(t, map[string]int{"Class": 1500, "Term": 2}, seen)
+	assert.Equal(t, "SELECT * FROM Class ORDERBY Id STARTPOSITION 1001 MAXRESULTS 1000", queries[0])
+	assert.True(t, store.cursor.Complete)
+
+	b, err := json.Marshal(store.cursor)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"version":1,"entity":"Term","startPosition":0,"complete":true}`, string(b))
+
+	queries = nil
+	require.NoError(t, client.ExportCompany(context.Background(), []string{"Class", "Term"}, store, export))
+	assert.Empty(t, queries)
+}
+
+func TestExportCompanyCanceled(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("no request expected")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	store := &memoryCursorStore{}
+	err := client.ExportCompany(ctx, []string{"Class"}, store, func(string, []json.RawMessage) error { return nil })
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, store.cursor)
+
+	store.cursor = &ExportCursor{Version: 2, Entity: "Class", StartPosition: 1}
+	assert.Error(t, client.ExportCompany(context.Background(), []string{"Class"}, store, nil))
+}
+
+func TestFileCursorStore(t *testing.T) {
+	store := FileCursorStore(filepath.Join(t.TempDir(), "cursor.json"))
+
+	cursor, err := store.LoadCursor()
+	require.NoError(t, err)
+	assert.Nil(t, cursor)
+
+	require.NoError(t, store.SaveCursor(&ExportCursor{Version: 1, Entity: "Invoice", StartPosition: 3001}))
+	cursor, err = store.LoadCursor()
+	require.NoError(t, err)
+	assert.Equal(t, &ExportCursor{Version: 1, Entity: "Invoice", StartPosition: 3001}, cursor)
+}
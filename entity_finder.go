@@ -0,0 +1,184 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// entityFinder holds the typed Find methods of one entity, adapted to untyped signatures.
+// Either can be nil when QuickBooks does not support the operation for the entity.
+// decode unmarshals one object of the entity into a pointer to its struct.
+type entityFinder struct {
+	findByID func(c *Client, id string) (any, error)
+	findAll  func(c *Client) ([]any, error)
+	decode   func(raw json.RawMessage) (any, error)
+}
+
+// entityFinders maps entity names, as used in queries, to their Find methods.
+var entityFinders = map[string]entityFinder{
+	"Account":         {findByID: byID((*Client).FindAccountByID), findAll: listAll((*Client).FindAccountsWithOptions), decode: decodeAs[Account]},
+	"Attachable":      {findByID: byID((*Client).FindAttachableByID), findAll: listAll((*Client).FindAttachablesWithOptions), decode: decodeAs[Attachable]},
+	"Bill":            {findByID: byID((*Client).FindBillByID), findAll: listAll((*Client).FindBillsWithOptions), decode: decodeAs[Bill]},
+	"BillPayment":     {findByID: byID((*Client).FindBillPaymentByID), findAll: listAll((*Client).FindBillPaymentsWithOptions), decode: decodeAs[BillPayment]},
+	"Budget":          {findAll: listAll((*Client).FindBudgetsWithOptions), decode: decodeAs[Budget]},
+	"Class":           {findByID: byID((*Client).FindClassByID), findAll: listAll((*Client).FindClassesWithOptions), decode: decodeAs[Class]},
+	"CreditMemo":      {findByID: byID((*Client).FindCreditMemoByID), findAll: listAll((*Client).FindCreditMemosWithOptions), decode: decodeAs[CreditMemo]},
+	"Customer":        {findByID: byID((*Client).FindCustomerByID), findAll: listAll((*Client).FindCustomersWithOptions), decode: decodeAs[Customer]},
+	"CustomerType":    {findByID: byID((*Client).FindCustomerTypeByID), findAll: listAll((*Client).FindCustomerTypesWithOptions), decode: decodeAs[CustomerType]},
+	"Department":      {findByID: byID((*Client).FindDepartmentByID), findAll: listAll((*Client).FindDepartmentsWithOptions), decode: decodeAs[Department]},
+	"Deposit":         {findByID: byID((*Client).FindDepositByID), findAll: listAll((*Client).FindDepositsWithOptions), decode: decodeAs[Deposit]},
+	"Employee":        {findByID: byID((*Client).FindEmployeeByID), findAll: listAll((*Client).FindEmployeesWithOptions), decode: decodeAs[Employee]},
+	"Estimate":        {findByID: byID((*Client).FindEstimateByID), findAll: listAll((*Client).FindEstimatesWithOptions), decode: decodeAs[Estimate]},
+	"Invoice":         {findByID: byID((*Client).FindInvoiceByID), findAll: listAll((*Client).FindInvoicesWithOptions), decode: decodeAs[Invoice]},
+	"Item":            {findByID: byID((*Client).FindItemByID), findAll: listAll((*Client).FindItemsWithOptions), decode: decodeAs[Item]},
+	"JournalEntry":    {findByID: byID((*Client).FindJournalEntryByID), findAll: listAll((*Client).FindJournalEntriesWithOptions), decode: decodeAs[JournalEntry]},
+	"Payment":         {findByID: byID((*Client).FindPaymentByID), findAll: listAll((*Client).FindPaymentsWithOptions), decode: decodeAs[Payment]},
+	"PaymentMethod":   {findByID: byID((*Client).FindPaymentMethodByID), findAll: listAll((*Client).FindPaymentMethodsWithOptions), decode: decodeAs[PaymentMethod]},
+	"Purchase":        {findByID: byID((*Client).FindPurchaseByID), findAll: listAll((*Client).FindPurchasesWithOptions), decode: decodeAs[Purchase]},
+	"PurchaseOrder":   {findByID: byID((*Client).FindPurchaseOrderByID), findAll: listAll((*Client).FindPurchaseOrdersWithOptions), decode: decodeAs[PurchaseOrder]},
+	"RefundReceipt":   {findByID: byID((*Client).FindRefundReceiptByID), findAll: listAll((*Client).FindRefundReceiptsWithOptions), decode: decodeAs[RefundReceipt]},
+	"ReimburseCharge": {findByID: byID((*Client).FindReimburseChargeByID), decode: decodeAs[ReimburseCharge]},
+	"SalesReceipt":    {findByID: byID((*Client).FindSalesReceiptByID), findAll: listAll((*Client).FindSalesReceiptsWithOptions), decode: decodeAs[SalesReceipt]},
+	"TaxAgency":       {findByID: byID((*Client).FindTaxAgencyByID), findAll: listAll((*Client).FindTaxAgenciesWithOptions), decode: decodeAs[TaxAgency]},
+	"TaxCode":         {findByID: byID((*Client).FindTaxCodeByID), findAll: listAll((*Client).FindTaxCodesWithOptions), decode: decodeAs[TaxCode]},
+	"TaxRate":         {findByID: byID((*Client).FindTaxRateByID), findAll: listAll((*Client).FindTaxRatesWithOptions), decode: decodeAs[TaxRate]},
+	"Term":            {findByID: byID((*Client).FindTermByID), findAll: listAll((*Client).FindTermsWithOptions), decode: decodeAs[Term]},
+	"TimeActivity":    {findByID: byID((*Client).FindTimeActivityByID), findAll: listAll((*Client).FindTimeActivitiesWithOptions), decode: decodeAs[TimeActivity]},
+	"Transfer":        {findByID: byID((*Client).FindTransferByID), findAll: listAll((*Client).FindTransfersWithOptions), decode: decodeAs[Transfer]},
+	"Vendor":          {findByID: byID((*Client).FindVendorByID), findAll: listAll((*Client).FindVendorsWithOptions), decode: decodeAs[Vendor]},
+	"VendorCredit":    {findByID: byID((*Client).FindVendorCreditByID), findAll: listAll((*Client).FindVendorCreditsWithOptions), decode: decodeAs[VendorCredit]},
+}
+
+func byID[T any](find func(*Client, string) (*T, error)) func(*Client, string) (any, error) {
+	return func(c *Client, id string) (any, error) {
+		return find(c, id)
+	}
+}
+
+func decodeAs[T any](raw json.RawMessage) (any, error) {
+	var v T
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func listAll[T any](find func(*Client, *ListOptions) ([]T, error)) func(*Client) ([]any, error) {
+	return func(c *Client) ([]any, error) {
+		// A Failure comes with the objects read before it; keep them, like the typed method.
+		items, err := partialItems(find(c, nil))
+		if items == nil {
+			return nil, err
+		}
+
+		all := make([]any, len(items))
+		for i := range items {
+			all[i] = &items[i]
+		}
+		return all, err
+	}
+}
+
+// lookupEntityFinder returns the finder of entityName, matched case-insensitively.
+func lookupEntityFinder(entityName string) (entityFinder, string, error) {
+	for name, finder := range entityFinders {
+		if strings.EqualFold(name, entityName) {
+			return finder, name, nil
+		}
+	}
+	return entityFinder{}, "", fmt.Errorf("unknown entity %q", entityName)
+}
+
+// FindableEntities returns the entity names FindByID and FindAll accept, sorted.
+func FindableEntities() []string {
+	names := make([]string, 0, len(entityFinders))
+	for name := range entityFinders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FindByID finds the object of the named entity (e.g. "Invoice", case-insensitive) with the
+// given Id by calling the matching typed method, e.g. FindInvoiceByID. The result is a pointer
+// to the entity struct, e.g. *Invoice. It is meant for tools that get entity names at run time.
+func (c *Client) FindByID(entityName string, id string) (any, error) {
+	finder, name, err := lookupEntityFinder(entityName)
+	if err != nil {
+		return nil, err
+	}
+
+	if finder.findByID == nil {
+		return nil, fmt.Errorf("%s cannot be read by id", name)
+	}
+
+	return finder.findByID(c, id)
+}
+
+// FindAll returns every object of the named entity (e.g. "Invoice", case-insensitive) by
+// calling the matching typed method, e.g. FindInvoicesWithOptions. Like the result of FindByID,
+// each element is a pointer to the entity struct, e.g. *Invoice, so both can go through the
+// same type switch. An entity with no objects returns an empty result, not an error. When a
+// page fails with a QBError, the objects read before it are returned along with the error.
+func (c *Client) FindAll(entityName string) ([]any, error) {
+	finder, name, err := lookupEntityFinder(entityName)
+	if err != nil {
+		return nil, err
+	}
+
+	if finder.findAll == nil {
+		return nil, fmt.Errorf("%s cannot be listed", name)
+	}
+
+	return finder.findAll(c)
+}
+
+// txnTypeEntities maps the TxnType spellings QuickBooks uses in LinkedTxn and in reports to
+// the entity holding that transaction, where the two differ. Checks, expenses and card
+// charges are all Purchase objects.
+var txnTypeEntities = map[string]string{
+	"Check":                 "Purchase",
+	"Cash":                  "Purchase",
+	"Expense":               "Purchase",
+	"CreditCardCharge":      "Purchase",
+	"CreditCardCredit":      "Purchase",
+	"BillPaymentCheck":      "BillPayment",
+	"BillPaymentCreditCard": "BillPayment",
+}
+
+// transactionEntities lists the entities ResolveTransaction accepts.
+var transactionEntities = []string{"Bill", "BillPayment", "CreditMemo", "Deposit", "Estimate", "Invoice",
+	"JournalEntry", "Payment", "Purchase", "PurchaseOrder", "RefundReceipt", "ReimburseCharge",
+	"SalesReceipt", "TimeActivity", "Transfer", "VendorCredit"}
+
+// ResolveTransaction fetches the transaction a TxnType/Id pair points at, as found in LinkedTxn
+// and in report rows, by calling the matching typed method, e.g. FindInvoiceByID for "Invoice"
+// or FindPurchaseByID for "Check". The result is a pointer to the entity struct, e.g. *Invoice,
+// as it is for FindByID and FindAll, so callers can follow payment → invoice → credit memo links
+// with one type switch over pointer cases.
+func (c *Client) ResolveTransaction(txnType string, id string) (any, error) {
+	if id == "" {
+		return nil, errors.New("missing transaction id")
+	}
+
+	entity := txnType
+	if e, ok := txnTypeEntities[txnType]; ok {
+		entity = e
+	}
+
+	i := slices.IndexFunc(transactionEntities, func(name string) bool { return strings.EqualFold(name, entity) })
+	if i < 0 {
+		return nil, fmt.Errorf("unsupported transaction type %q", txnType)
+	}
+
+	return entityFinders[transactionEntities[i]].findByID(c, id)
+}
+
+// ResolveLinkedTxn is ResolveTransaction for a LinkedTxn.
+func (c *Client) ResolveLinkedTxn(link LinkedTxn) (any, error) {
+	return c.ResolveTransaction(link.TxnType, link.TxnID)
+}
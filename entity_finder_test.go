@@ -0,0 +1,102 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindByEntityName(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/company/test-realm/purchaseorder/12":
+			w.Write([]byte(`{"PurchaseOrder": {"Id": "12", "DocNumber": "1005"}}`))
+		case "/v3/company/test-realm/query":
+			w.Write([]byte(`{"QueryResponse": {"Vendor": [{"Id": "1"}, {"Id": "2"}]}}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	})
+
+	found, err := client.FindByID("purchaseOrder", "12")
+	require.NoError(t, err)
+	order, ok := found.(*PurchaseOrder)
+	require.True(t, ok)
+	assert.Equal(t, "1005", *order.DocNumber)
+
+	all, err := client.FindAll("Vendor")
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, "2", all[1].(*Vendor).ID)
+
+	_, err = client.FindByID("Widget", "1")
+	assert.EqualError(t, err, `unknown entity "Widget"`)
+
+	_, err = client.FindByID("Budget", "1")
+	assert.EqualError(t, err, "Budget cannot be read by id")
+
+	assert.Contains(t, FindableEntities(), "JournalEntry")
+}
+
+func TestResolveTransaction(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/company/test-realm/invoice/130":
+			w.Write([]byte(`{"Invoice": {"Id": "130", "DocNumber": "1037"}}`))
+		case "/v3/company/test-realm/purchase/44":
+			w.Write([]byte(`{"Purchase": {"Id": "44", "PaymentType": "Check"}}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	})
+
+	found, err := client.ResolveLinkedTxn(LinkedTxn{TxnID: "130", TxnType: "Invoice"})
+	require.NoError(t, err)
+	invoice, ok := found.(*Invoice)
+	require.True(t, ok)
+	assert.Equal(t, "1037", *invoice.DocNumber)
+
+	found, err = client.ResolveTransaction("Check", "44")
+	require.NoError(t, err)
+	switch txn := found.(type) {
+	case *Purchase:
+		assert.Equal(t, "44", txn.ID)
+	default:
+		t.Errorf("unexpected %T", found)
+	}
+
+	_, err = client.ResolveTransaction("Customer", "1")
+	assert.EqualError(t, err, `unsupported transaction type "Customer"`)
+
+	_, err = client.ResolveTransaction("Invoice", "")
+	assert.Error(t, err)
+}
+
+func TestFindAllPartialFailure(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Query().Get("query"), "STARTPOSITION 1 ") {
+			vendors := make([]map[string]string, queryPageSize)
+			for i := range vendors {
+				vendors[i] = map[string]string{"Id": strconv.Itoa(i + 1)}
+			}
+			body, err := json.Marshal(map[string]any{"QueryResponse": map[string]any{"Vendor": vendors}})
+			require.NoError(t, err)
+			w.Write(body)
+			return
+		}
+
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"Fault":{"Error":[{"Message":"Invalid query","code":"4000"}],"type":"ValidationFault"}}`))
+	})
+
+	all, err := client.FindAll("Vendor")
+	var failure QBError
+	require.ErrorAs(t, err, &failure)
+	require.Len(t, all, queryPageSize)
+	assert.Equal(t, "1000", all[queryPageSize-1].(*Vendor).ID)
+}
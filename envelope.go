@@ -0,0 +1,115 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// QuickBooks wraps single objects as {"<Entity>": {...}, "time": "..."} and query results as
+// {"QueryResponse": {"<Entity>": [...], "startPosition": 1, ...}, "time": "..."}, where
+// <Entity> is the entity name, e.g. "Invoice" or "PurchaseOrder". The helpers below decode
+// those envelopes so methods don't need to declare them.
+//
+// An object QuickBooks returns with "sparse": true only carries some of its fields, the rest
+// decoding to zero values. Sending such an object back in a read-modify-write clobbers every
+// field that is not omitted when empty (lines, references), so updateSingle never hands one
+// out: it refetches the full object instead.
+
+// entityName returns the envelope key of T, which is the name of the Go type.
+func entityName[T any]() string {
+	return reflect.TypeOf((*T)(nil)).Elem().Name()
+}
+
+// decodeSingle decodes the object of a single-object envelope.
+func decodeSingle[T any](body json.RawMessage) (*T, error) {
+	v, _, err := decodeSingleSparse[T](body)
+	return v, err
+}
+
+// decodeSingleSparse is decodeSingle that also reports whether QuickBooks flagged the
+// object as sparse.
+func decodeSingleSparse[T any](body json.RawMessage) (*T, bool, error) {
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal response into object: %v", err)
+	}
+
+	key := entityName[T]()
+	raw, ok := envelope[key]
+	if !ok {
+		return nil, false, fmt.Errorf("response has no %s", key)
+	}
+
+	var v T
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal %s: %v", key, err)
+	}
+
+	var flags struct {
+		Sparse bool `json:"sparse"`
+	}
+	_ = json.Unmarshal(raw, &flags)
+
+	return &v, flags.Sparse, nil
+}
+
+// decodeList decodes the objects stored under key in a query response envelope.
+// It returns nil when the query matched nothing, as QuickBooks then omits the key.
+func decodeList[T any](body json.RawMessage, key string) ([]T, error) {
+	var envelope struct {
+		QueryResponse map[string]json.RawMessage
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response into object: %v", err)
+	}
+
+	raw, ok := envelope.QueryResponse[key]
+	if !ok {
+		return nil, nil
+	}
+
+	var items []T
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %v", key, err)
+	}
+
+	return items, nil
+}
+
+// getSingle GETs endpoint and decodes the T it returns.
+func getSingle[T any](c *Client, endpoint string, queryParameters map[string]string) (*T, error) {
+	var body json.RawMessage
+	if err := c.get(endpoint, &body, queryParameters); err != nil {
+		return nil, err
+	}
+
+	return decodeSingle[T](body)
+}
+
+// postSingle POSTs payloadData to endpoint and decodes the T it returns.
+func postSingle[T any](c *Client, endpoint string, payloadData any, queryParameters map[string]string) (*T, error) {
+	var body json.RawMessage
+	if err := c.post(endpoint, payloadData, &body, queryParameters); err != nil {
+		return nil, err
+	}
+
+	return decodeSingle[T](body)
+}
+
+// updateSingle posts a sparse update through postUpdate and decodes the T it returns. When
+// QuickBooks answers with a sparse object, the full object is fetched from fetchEndpoint so
+// the result is always safe to modify and update again.
+func updateSingle[T any](c *Client, endpoint string, fetchEndpoint string, syncToken *string, payloadData any) (*T, error) {
+	var body json.RawMessage
+	if err := c.postUpdate(endpoint, fetchEndpoint, syncToken, payloadData, &body); err != nil {
+		return nil, err
+	}
+
+	v, sparse, err := decodeSingleSparse[T](body)
+	if err != nil || !sparse {
+		return v, err
+	}
+
+	return getSingle[T](c, fetchEndpoint, nil)
+}
@@ -0,0 +1,76 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeEnvelopes(t *testing.T) {
+	order, err := decodeSingle[PurchaseOrder](json.RawMessage(`{"PurchaseOrder": {"Id": "7", "SyncToken": "2"}, "time": "2024-01-01T00:00:00.000-08:00"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "7", order.ID)
+	assert.Equal(t, "2", order.SyncToken)
+
+	_, err = decodeSingle[PurchaseOrder](json.RawMessage(`{"Invoice": {"Id": "7"}}`))
+	assert.EqualError(t, err, "response has no PurchaseOrder")
+
+	vendors, err := decodeList[Vendor](json.RawMessage(`{"QueryResponse": {"Vendor": [{"Id": "1"}, {"Id": "2"}], "startPosition": 1, "maxResults": 2}}`), "Vendor")
+	require.NoError(t, err)
+	require.Len(t, vendors, 2)
+	assert.Equal(t, "2", vendors[1].ID)
+
+	vendors, err = decodeList[Vendor](json.RawMessage(`{"QueryResponse": {}}`), "Vendor")
+	require.NoError(t, err)
+	assert.Nil(t, vendors)
+}
+
+func TestUpdateRefetchesSparseResponse(t *testing.T) {
+	var gets int
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			assert.Equal(t, "/v3/company/test-realm/invoice/130", r.URL.Path)
+			gets++
+			w.Write([]byte(`{"Invoice": {"Id": "130", "SyncToken": "4", "CustomerRef": {"value": "1"}, "Line": [{"Amount": 10, "DetailType": "SalesItemLineDetail"}]}}`))
+			return
+		}
+
+		w.Write([]byte(`{"Invoice": {"Id": "130", "SyncToken": "4", "sparse": true, "PrivateNote": "updated"}}`))
+	})
+
+	invoice, err := client.UpdateInvoice(&Invoice{ID: "130", SyncToken: "3"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, gets, "the sparse response should be refetched")
+	assert.Equal(t, "1", invoice.CustomerRef.Value)
+	assert.Len(t, invoice.Line, 1)
+
+	_, sparse, err := decodeSingleSparse[Invoice](json.RawMessage(`{"Invoice": {"Id": "130"}}`))
+	require.NoError(t, err)
+	assert.False(t, sparse)
+}
+
+func TestEntityMeta(t *testing.T) {
+	invoice, err := decodeSingle[Invoice](json.RawMessage(`{"Invoice":{"Id":"130","domain":"QBO","sparse":true},"time":"2015-07-24T10:48:27.082-07:00"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "QBO", invoice.Domain)
+	assert.True(t, invoice.IsSparse())
+
+	var customer Customer
+	require.NoError(t, json.Unmarshal([]byte(`{"Id":"1","domain":"QBO","sparse":false}`), &customer))
+	assert.False(t, customer.IsSparse())
+
+	// The sparse flag of an update payload takes precedence over the one read into the object.
+	b, err := json.Marshal(struct {
+		*Customer
+		Sparse bool `json:"sparse"`
+	}{Customer: &customer, Sparse: true})
+	require.NoError(t, err)
+
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal(b, &payload))
+	assert.Equal(t, true, payload["sparse"])
+	assert.Equal(t, "QBO", payload["domain"])
+}
@@ -9,9 +9,68 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
+	"slices"
 	"strconv"
+	"strings"
 )
 
+// staleObjectCode is the fault code QuickBooks returns when an update carries an outdated SyncToken.
+const staleObjectCode = "5010"
+
+// ErrStaleObject matches (via errors.Is) the errors returned when QuickBooks rejects a
+// write because the object was modified since the given SyncToken.
+var ErrStaleObject = errors.New("stale object: the object was modified since it was read")
+
+// StaleObjectError is returned when QuickBooks rejects a write with a stale object fault.
+type StaleObjectError struct {
+	// CurrentSyncToken is the SyncToken the object has on the server, when the fault mentions it.
+	CurrentSyncToken string
+	Failure          Failure
+}
+
+// Error implements the error interface.
+func (e *StaleObjectError) Error() string {
+	if e.CurrentSyncToken != "" {
+		return ErrStaleObject.Error() + " (current SyncToken " + e.CurrentSyncToken + ")"
+	}
+	return ErrStaleObject.Error()
+}
+
+// Is makes errors.Is(err, ErrStaleObject) true for a StaleObjectError.
+func (e *StaleObjectError) Is(target error) bool {
+	return target == ErrStaleObject
+}
+
+// Unwrap returns the underlying fault.
+func (e *StaleObjectError) Unwrap() error {
+	return e.Failure
+}
+
+var syncTokenPattern = regexp.MustCompile(`(?i)sync\s*token\D{0,20}(\d+)`)
+
+// asStaleObject converts a stale object fault into a *StaleObjectError and returns any other error unchanged.
+func asStaleObject(err error) error {
+	var failure Failure
+	if !errors.As(err, &failure) {
+		return err
+	}
+
+	for _, fe := range failure.Fault.Error {
+		if fe.Code != staleObjectCode {
+			continue
+		}
+
+		staleErr := &StaleObjectError{Failure: failure}
+		if m := syncTokenPattern.FindStringSubmatch(fe.Detail + " " + fe.Message); m != nil {
+			staleErr.CurrentSyncToken = m[1]
+		}
+		return staleErr
+	}
+
+	return err
+}
+
 // Failure is the outermost struct that holds an error response.
 type Failure struct {
 	Fault struct {
@@ -24,6 +83,81 @@ type Failure struct {
 		Type string `json:"type"`
 	}
 	Time Date `json:"time"`
+	// StatusCode is the HTTP status of the response, or 0 for a fault that came with a 200,
+	// such as a batch item fault.
+	StatusCode int `json:"-"`
+}
+
+// QBError is the error the Client methods return when QuickBooks answers with a Fault. Match
+// it with errors.As, or use IsValidation, IsThrottled and IsAuthError to branch on the kind
+// of fault. It is another name for Failure.
+//
+// QuickBooks can answer a query with a 200 that carries a Fault next to the rows it could
+// return. The QueryX, FindX and FindXWithOptions methods then return those rows together with
+// the QBError, so check the error before treating the result as complete.
+type QBError = Failure
+
+// ErrThrottled is returned without making a request while the company is throttled after
+// QuickBooks answered 429 Too Many Requests.
+var ErrThrottled = errors.New("waiting for rate limit")
+
+// Fault codes used by IsThrottled and IsAuthError. QuickBooks writes some of them with
+// leading zeros.
+var (
+	throttleFaultCodes = []string{"3001", "003001"}
+	authFaultCodes     = []string{"3100", "3200", "003100", "003200"}
+)
+
+// hasCode reports whether any error of the fault has one of the codes.
+func (f Failure) hasCode(codes []string) bool {
+	for _, fe := range f.Fault.Error {
+		if slices.Contains(codes, fe.Code) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidation reports whether err is a validation fault: QuickBooks rejected the request
+// because of its content, e.g. a duplicate name (6240), a missing required field or a stale
+// SyncToken. Sending the same request again fails the same way.
+func IsValidation(err error) bool {
+	var failure Failure
+	return errors.As(err, &failure) && strings.EqualFold(failure.Fault.Type, "ValidationFault")
+}
+
+// IsThrottled reports whether err means the company hit the QuickBooks rate limit, either
+// as a throttle fault or a 429 response, or as ErrThrottled while waiting for the limit to
+// reset. The request can be retried later.
+func IsThrottled(err error) bool {
+	if errors.Is(err, ErrThrottled) {
+		return true
+	}
+
+	if responseStatus(err) == http.StatusTooManyRequests {
+		return true
+	}
+
+	var failure Failure
+	return errors.As(err, &failure) && failure.hasCode(throttleFaultCodes)
+}
+
+// IsAuthError reports whether err is an authentication or authorization failure, typically
+// an expired or revoked access token, or a token without access to the company. Refreshing
+// the token or reconnecting the company is needed before retrying.
+func IsAuthError(err error) bool {
+	switch responseStatus(err) {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return true
+	}
+
+	var failure Failure
+	if !errors.As(err, &failure) {
+		return false
+	}
+
+	faultType := strings.ToLower(failure.Fault.Type)
+	return strings.HasPrefix(faultType, "authentication") || strings.HasPrefix(faultType, "authorization") || failure.hasCode(authFaultCodes)
 }
 
 // Error implements the error interface.
@@ -36,6 +170,45 @@ func (f Failure) Error() string {
 	return string(text)
 }
 
+// UnexpectedResponseError is returned when a response body is not the JSON QuickBooks
+// normally sends, typically an HTML error page or an empty body from a gateway or proxy
+// answering a 502 or 503 in its place.
+type UnexpectedResponseError struct {
+	StatusCode  int
+	ContentType string
+	// Body holds the start of the response body.
+	Body string
+}
+
+// Error implements the error interface.
+func (e *UnexpectedResponseError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("unexpected empty response with status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("unexpected %s response with status %d: %s", e.ContentType, e.StatusCode, e.Body)
+}
+
+const responseSnippetLength = 256
+
+// checkJSONBody returns an *UnexpectedResponseError when body is not a JSON document.
+func checkJSONBody(resp *http.Response, body []byte) error {
+	contentType := resp.Header.Get("Content-Type")
+	if json.Valid(body) && !strings.Contains(contentType, "html") {
+		return nil
+	}
+
+	return &UnexpectedResponseError{StatusCode: resp.StatusCode, ContentType: contentType, Body: snippet(body)}
+}
+
+// snippet returns the start of body for an UnexpectedResponseError, with whitespace collapsed.
+func snippet(body []byte) string {
+	text := strings.Join(strings.Fields(string(body)), " ")
+	if len(text) > responseSnippetLength {
+		text = text[:responseSnippetLength] + "..."
+	}
+	return text
+}
+
 // parseFailure takes a response reader and tries to parse a Failure.
 func parseFailure(resp *http.Response) error {
 	msg, err := io.ReadAll(resp.Body)
@@ -43,11 +216,16 @@ func parseFailure(resp *http.Response) error {
 		return errors.New("When reading response body:" + err.Error())
 	}
 
+	if err = checkJSONBody(resp, msg); err != nil {
+		return err
+	}
+
 	var errStruct Failure
 
 	if err = json.Unmarshal(msg, &errStruct); err != nil {
 		return errors.New(strconv.Itoa(resp.StatusCode) + " " + string(msg))
 	}
+	errStruct.StatusCode = resp.StatusCode
 
 	return errStruct
 }
@@ -0,0 +1,244 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateInvoiceIfUnchangedStale(t *testing.T) {
+	posts := 0
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		posts++
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/v3/company/test-realm/invoice", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"Fault":{"Error":[{"Message":"Stale Object Error","Detail":"Stale Object Error : You and Jane were working on this at the same time. Jane finished before you did, so your work was not saved. Current SyncToken: 4","code":"5010","element":""}],"type":"ValidationFault"},"time":"2015-07-24T10:48:27.082-07:00"}`))
+	})
+
+	client.RetryOnStale = true
+	_, err := client.UpdateInvoiceIfUnchanged(&Invoice{ID: "130"}, "2")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrStaleObject)
+	assert.Equal(t, 1, posts)
+
+	var staleErr *StaleObjectError
+	require.True(t, errors.As(err, &staleErr))
+	assert.Equal(t, "4", staleErr.CurrentSyncToken)
+	assert.Equal(t, "5010", staleErr.Failure.Fault.Error[0].Code)
+}
+
+func TestUpdateInvoiceIfUnchangedSparseResponse(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v3/company/test-realm/invoice":
+			w.Write([]byte(`{"Invoice":{"Id":"130","SyncToken":"3","sparse":true}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/v3/company/test-realm/invoice/130":
+			w.Write([]byte(`{"Invoice":{"Id":"130","SyncToken":"3","TotalAmt":150,"Line":[{"Amount":150,"DetailType":"SalesItemLineDetail"}]}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	invoice, err := client.UpdateInvoiceIfUnchanged(&Invoice{ID: "130"}, "2")
+	require.NoError(t, err)
+	assert.Equal(t, "3", invoice.SyncToken)
+	assert.Len(t, invoice.Line, 1)
+}
+
+func TestQueryEmbeddedFault(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"QueryResponse":{"Customer":[{"Id":"1","DisplayName":"Amy's Bird Sanctuary"}],"startPosition":1,"maxResults":1},"Fault":{"Error":[{"Message":"Partial failure","Detail":"Some results could not be returned","code":"10000"}],"type":"ValidationFault"},"time":"2015-07-24T10:48:27.082-07:00"}`))
+	})
+
+	var resp struct {
+		QueryResponse struct {
+			Customers []Customer `json:"Customer"`
+		}
+	}
+
+	err := client.query("SELECT * FROM Customer", &resp)
+
+	var failure Failure
+	require.True(t, errors.As(err, &failure))
+	assert.Equal(t, "10000", failure.Fault.Error[0].Code)
+	require.Len(t, resp.QueryResponse.Customers, 1)
+	assert.Equal(t, "1", resp.QueryResponse.Customers[0].ID)
+}
+
+func TestQueryPartialFault(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("query") == "SELECT COUNT(*) FROM Customer" {
+			w.Write([]byte(`{"QueryResponse": {"totalCount": 2}}`))
+			return
+		}
+		w.Write([]byte(`{"QueryResponse":{"Customer":[{"Id":"1"},{"Id":"2"}]},"Fault":{"Error":[{"Message":"Partial failure","code":"10000"}],"type":"ValidationFault"}}`))
+	})
+
+	var failure QBError
+	queried, err := client.QueryCustomers("SELECT * FROM Customer")
+	require.ErrorAs(t, err, &failure)
+	assert.Len(t, queried, 2)
+
+	found, err := client.FindCustomers()
+	require.ErrorAs(t, err, &failure)
+	assert.Len(t, found, 2)
+
+	listed, err := client.FindCustomersWithOptions(&ListOptions{Concurrency: 2})
+	require.ErrorAs(t, err, &failure)
+	assert.Len(t, listed, 2)
+
+	var ids []string
+	it := Iterate[Customer](client, "SELECT * FROM Customer")
+	for it.Next() {
+		ids = append(ids, it.Value().ID)
+	}
+	assert.Equal(t, []string{"1", "2"}, ids)
+	assert.ErrorAs(t, it.Err(), &failure)
+}
+
+func TestUpdateCustomerRetryOnStale(t *testing.T) {
+	var posted []string
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			token := "3"
+			if len(posted) > 0 {
+				token = "4"
+			}
+			_, _ = w.Write([]byte(`{"Customer": {"Id": "1", "SyncToken": "` + token + `"}}`))
+			return
+		}
+
+		var body struct{ SyncToken string }
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		posted = append(posted, body.SyncToken)
+
+		if body.SyncToken == "3" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"Fault": {"Error": [{"Message": "Stale Object Error", "Detail": "Stale Object Error : You and someone else were editing this at the same time", "code": "5010"}], "type": "ValidationFault"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"Customer": {"Id": "1", "SyncToken": "5"}}`))
+	})
+
+	_, err := client.UpdateCustomer(&Customer{ID: "1"})
+	assert.ErrorIs(t, asStaleObject(err), ErrStaleObject)
+	assert.Equal(t, []string{"3"}, posted)
+
+	posted = nil
+	client.RetryOnStale = true
+	customer, err := client.UpdateCustomer(&Customer{ID: "1"})
+	require.NoError(t, err)
+	assert.Equal(t, "5", customer.SyncToken)
+	assert.Equal(t, []string{"3", "4"}, posted)
+}
+
+func TestDeleteAndVoidRetryOnStale(t *testing.T) {
+	var posted []string
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`{"Invoice": {"Id": "130", "SyncToken": "4"}}`))
+			return
+		}
+
+		var body struct{ SyncToken string }
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		posted = append(posted, r.URL.Query().Get("operation")+" "+body.SyncToken)
+
+		if len(posted)%2 == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"Fault": {"Error": [{"Message": "Stale Object Error", "Detail": "Stale Object Error : Current SyncToken 6", "code": "5010"}], "type": "ValidationFault"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"Invoice": {"Id": "130", "SyncToken": "7"}}`))
+	})
+
+	err := client.DeleteInvoice(&Invoice{ID: "130", SyncToken: "3"})
+	assert.ErrorIs(t, asStaleObject(err), ErrStaleObject)
+	assert.Equal(t, []string{"delete 3"}, posted)
+
+	posted = nil
+	client.RetryOnStale = true
+	invoice := &Invoice{ID: "130", SyncToken: "3"}
+	require.NoError(t, client.DeleteInvoice(invoice))
+	assert.Equal(t, "6", invoice.SyncToken)
+
+	require.NoError(t, client.VoidInvoice(&Invoice{ID: "130"}))
+	assert.Equal(t, []string{"delete 3", "delete 6", "void 4", "void 6"}, posted)
+}
+
+func TestNonJSONResponses(t *testing.T) {
+	status, contentType, body := http.StatusBadGateway, "text/html", "<html>\n  <body>502 Bad Gateway</body>\n</html>"
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	})
+
+	_, err := client.FindInvoiceByID("130")
+	var unexpected *UnexpectedResponseError
+	require.ErrorAs(t, err, &unexpected)
+	assert.Equal(t, http.StatusBadGateway, unexpected.StatusCode)
+	assert.Equal(t, "<html> <body>502 Bad Gateway</body> </html>", unexpected.Body)
+	assert.EqualError(t, err, "unexpected text/html response with status 502: <html> <body>502 Bad Gateway</body> </html>")
+
+	status, contentType, body = http.StatusServiceUnavailable, "", ""
+	_, err = client.FindInvoiceByID("130")
+	assert.EqualError(t, err, "unexpected empty response with status 503")
+
+	// A 200 with an HTML page (e.g. a captive proxy) is not decoded either.
+	status, contentType, body = http.StatusOK, "text/html; charset=utf-8", "<html>maintenance</html>"
+	_, err = client.FindInvoiceByID("130")
+	require.ErrorAs(t, err, &unexpected)
+	assert.Equal(t, http.StatusOK, unexpected.StatusCode)
+}
+
+func TestFaultKinds(t *testing.T) {
+	responses := map[string]struct {
+		status int
+		body   string
+	}{
+		"duplicate": {http.StatusBadRequest, `{"Fault": {"Error": [{"Message": "Duplicate Name Exists Error", "code": "6240"}], "type": "ValidationFault"}}`},
+		"expired":   {http.StatusUnauthorized, `{"Fault": {"Error": [{"Message": "message=AuthenticationFailed; errorCode=003200; statusCode=401", "code": "3200"}], "type": "AUTHENTICATION"}}`},
+		"forbidden": {http.StatusForbidden, `{"Fault": {"Error": [{"Message": "message=ApplicationAuthorizationFailed; errorCode=003100; statusCode=403", "code": "003100"}], "type": "SERVICE"}}`},
+		"throttled": {http.StatusBadRequest, `{"Fault": {"Error": [{"Message": "message=ThrottleExceeded; errorCode=003001; statusCode=429", "code": "3001"}], "type": "SERVICE"}}`},
+	}
+
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		resp := responses[r.URL.Path[len("/v3/company/test-realm/"):]]
+		w.WriteHeader(resp.status)
+		w.Write([]byte(resp.body))
+	})
+
+	err := client.get("duplicate", nil, nil)
+	var qbErr QBError
+	require.ErrorAs(t, err, &qbErr)
+	assert.Equal(t, http.StatusBadRequest, qbErr.StatusCode)
+	assert.Equal(t, "6240", qbErr.Fault.Error[0].Code)
+	assert.True(t, IsValidation(err))
+	assert.False(t, IsThrottled(err))
+	assert.False(t, IsAuthError(err))
+
+	for _, path := range []string{"expired", "forbidden"} {
+		err = client.get(path, nil, nil)
+		assert.True(t, IsAuthError(err), path)
+		assert.False(t, IsValidation(err), path)
+	}
+
+	err = client.get("throttled", nil, nil)
+	assert.True(t, IsThrottled(err))
+	assert.False(t, IsValidation(err))
+
+	assert.True(t, IsThrottled(ErrThrottled))
+	assert.True(t, IsValidation(&StaleObjectError{Failure: qbErr}))
+	assert.False(t, IsAuthError(errors.New("failed to make request")))
+}
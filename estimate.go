@@ -3,149 +3,205 @@ package quickbooks
 import (
 	"encoding/json"
 	"errors"
-	"strconv"
 )
 
 // Estimate represents a QuickBooks Estimate object as returned by the API.
 // Read-only fields (Id, SyncToken, MetaData, TotalAmt) are populated by the service.
 type Estimate struct {
+	EntityMeta
+
 	ID           string        `json:"Id,omitempty"`
 	SyncToken    string        `json:",omitempty"`
 	MetaData     *MetaData     `json:",omitempty"`
 	DocNumber    *string       `json:",omitempty"`
 	TxnDate      *Date         `json:",omitempty"`
 	TxnStatus    *string       `json:",omitempty"`
+	PrivateNote  *string       `json:",omitempty"`
 	CustomerRef  ReferenceType `json:",omitempty"`
 	CustomerMemo *MemoRef      `json:",omitempty"`
 	BillAddr     *Address      `json:",omitempty"`
 	ShipAddr     *Address      `json:",omitempty"`
-	PrintStatus  *string       `json:",omitempty"`
-	EmailStatus  *string       `json:",omitempty"`
-	BillEmail    *EmailAddress `json:",omitempty"`
-	Line         []Line        `json:",omitempty"`
-	TxnTaxDetail *TxnTaxDetail `json:",omitempty"`
-	ApplyTaxAfterDiscount *bool `json:",omitempty"`
-	CustomField  []CustomField `json:",omitempty"`
-	TotalAmt     json.Number   `json:",omitempty"`
+	// ShipFromAddr is where the goods ship from; Automated Sales Tax uses it for origin-based rates.
+	ShipFromAddr *Address `json:",omitempty"`
+	// FreeFormAddress is true when ShipAddr is stored exactly as entered rather than as a
+	// formatted address. See Address.
+	FreeFormAddress       *bool                `json:",omitempty"`
+	PrintStatus           *string              `json:",omitempty"`
+	EmailStatus           *string              `json:",omitempty"`
+	BillEmail             *EmailAddress        `json:",omitempty"`
+	Line                  []Line               `json:",omitempty"`
+	TxnTaxDetail          *TxnTaxDetail        `json:",omitempty"`
+	GlobalTaxCalculation  GlobalTaxCalculation `json:",omitempty"`
+	ApplyTaxAfterDiscount *bool                `json:",omitempty"`
+	CustomField           []CustomField        `json:",omitempty"`
+	CurrencyRef           *ReferenceType       `json:",omitempty"`
+	ExchangeRate          json.Number          `json:",omitempty"`
+	TotalAmt              json.Number          `json:",omitempty"`
+}
+
+// TransactionTotals returns the subtotal, discount, tax and total QuickBooks computed for the estimate.
+func (e *Estimate) TransactionTotals() TransactionTotals {
+	return salesTotals(e.Line, e.TxnTaxDetail, e.TotalAmt)
 }
 
 // EstimateCreateInput contains the writable fields accepted when creating an Estimate.
 // CustomerRef and Line are required; all other fields are optional.
 type EstimateCreateInput struct {
-	CustomerRef  ReferenceType  `json:",omitempty"`
-	Line         []Line
-	DocNumber    *string        `json:",omitempty"`
-	TxnDate      *Date          `json:",omitempty"`
-	TxnStatus    *string        `json:",omitempty"`
-	CustomerMemo *MemoRef       `json:",omitempty"`
-	BillAddr     *Address       `json:",omitempty"`
-	ShipAddr     *Address       `json:",omitempty"`
-	PrintStatus  *string        `json:",omitempty"`
-	EmailStatus  *string        `json:",omitempty"`
-	BillEmail    *EmailAddress  `json:",omitempty"`
-	TxnTaxDetail *TxnTaxDetail  `json:",omitempty"`
-	ApplyTaxAfterDiscount *bool `json:",omitempty"`
-	CustomField  []CustomField  `json:",omitempty"`
+	CustomerRef ReferenceType `json:",omitempty"`
+	Line        []Line
+	DocNumber   *string `json:",omitempty"`
+	TxnDate     *Date   `json:",omitempty"`
+	TxnStatus   *string `json:",omitempty"`
+	// PrivateNote is internal only; CustomerMemo is printed on the estimate.
+	PrivateNote           *string              `json:",omitempty"`
+	CustomerMemo          *MemoRef             `json:",omitempty"`
+	BillAddr              *Address             `json:",omitempty"`
+	ShipAddr              *Address             `json:",omitempty"`
+	ShipFromAddr          *Address             `json:",omitempty"`
+	FreeFormAddress       *bool                `json:",omitempty"`
+	PrintStatus           *string              `json:",omitempty"`
+	EmailStatus           *string              `json:",omitempty"`
+	BillEmail             *EmailAddress        `json:",omitempty"`
+	TxnTaxDetail          *TxnTaxDetail        `json:",omitempty"`
+	GlobalTaxCalculation  GlobalTaxCalculation `json:",omitempty"`
+	ApplyTaxAfterDiscount *bool                `json:",omitempty"`
+	CustomField           []CustomField        `json:",omitempty"`
+	CurrencyRef           *ReferenceType       `json:",omitempty"`
+	ExchangeRate          json.Number          `json:",omitempty"`
+	// Extra holds fields the struct does not model yet; see ExtraFields.
+	Extra ExtraFields `json:"-"`
+}
+
+// SetCustomerFacingMemo sets the memo printed on the estimate and shown to the customer.
+func (input *EstimateCreateInput) SetCustomerFacingMemo(s string) {
+	input.CustomerMemo = &MemoRef{Value: s}
+}
+
+// PopulateAddressesFromCustomer fills BillAddr and ShipAddr from the given customer when they
+// are not set, the way QuickBooks does when it saves the estimate, so a preview matches the saved document.
+func (input *EstimateCreateInput) PopulateAddressesFromCustomer(c *Client, customerID string) error {
+	billAddr, shipAddr, err := c.findCustomerAddresses(customerID)
+	if err != nil {
+		return err
+	}
+
+	if input.BillAddr == nil {
+		input.BillAddr = billAddr
+	}
+
+	if input.ShipAddr == nil {
+		input.ShipAddr = shipAddr
+	}
+
+	return nil
+}
+
+// SetInternalNote sets the private note, which is only visible inside QuickBooks.
+func (input *EstimateCreateInput) SetInternalNote(s string) {
+	input.PrivateNote = &s
 }
 
 // CreateEstimate creates the given Estimate on the QuickBooks server, returning
 // the resulting Estimate object.
 func (c *Client) CreateEstimate(input *EstimateCreateInput) (*Estimate, error) {
-	var resp struct {
-		Estimate Estimate
-		Time     Date
-	}
-
-	if err := c.post("estimate", input, &resp, nil); err != nil {
+	if err := c.prepareEstimate(input); err != nil {
 		return nil, err
 	}
 
-	return &resp.Estimate, nil
+	return postSingle[Estimate](c, "estimate", input, nil)
 }
 
-// DeleteEstimate deletes the estimate
-func (c *Client) DeleteEstimate(estimate *Estimate) error {
-	if estimate.ID == "" || estimate.SyncToken == "" {
-		return errors.New("missing id/sync token")
+// BuildCreateEstimatePayload returns the exact JSON body CreateEstimate would POST for input,
+// without sending it. See BuildCreateInvoicePayload: the same preferences lookups apply.
+func (c *Client) BuildCreateEstimatePayload(input *EstimateCreateInput) ([]byte, error) {
+	if err := c.prepareEstimate(input); err != nil {
+		return nil, err
 	}
 
-	return c.post("estimate", estimate, nil, map[string]string{"operation": "delete"})
+	return json.Marshal(input)
 }
 
-// FindEstimates gets the full list of Estimates in the QuickBooks account.
-func (c *Client) FindEstimates() ([]Estimate, error) {
-	var resp struct {
-		QueryResponse struct {
-			Estimates     []Estimate `json:"Estimate"`
-			MaxResults    int
-			StartPosition int
-			TotalCount    int
-		}
+// prepareEstimate validates input and applies the client settings to it before it is sent.
+func (c *Client) prepareEstimate(input *EstimateCreateInput) error {
+	if err := input.validate(); err != nil {
+		return err
 	}
 
-	if err := c.query("SELECT COUNT(*) FROM Estimate", &resp); err != nil {
-		return nil, err
+	if err := c.applyDocNumberPolicy(&input.DocNumber); err != nil {
+		return err
 	}
 
-	if resp.QueryResponse.TotalCount == 0 {
-		return nil, errors.New("no estimates could be found")
+	return c.resolveCustomFields(input.CustomField)
+}
+
+func (input *EstimateCreateInput) validate() error {
+	if err := validateGlobalTaxCalculation(input.GlobalTaxCalculation); err != nil {
+		return err
 	}
 
-	estimates := make([]Estimate, 0, resp.QueryResponse.TotalCount)
+	if err := ValidateSalesLines(input.Line); err != nil {
+		return err
+	}
 
-	for i := 0; i < resp.QueryResponse.TotalCount; i += queryPageSize {
-		query := "SELECT * FROM Estimate ORDERBY Id STARTPOSITION " + strconv.Itoa(i+1) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
+	return validateMemos(input.CustomerMemo, input.PrivateNote)
+}
 
-		if err := c.query(query, &resp); err != nil {
-			return nil, err
-		}
+// DeleteEstimate deletes the estimate
+func (c *Client) DeleteEstimate(estimate *Estimate) error {
+	if estimate.ID == "" || estimate.SyncToken == "" {
+		return errors.New("missing id/sync token")
+	}
 
-		if resp.QueryResponse.Estimates == nil {
-			return nil, errors.New("no estimates could be found")
-		}
+	return c.postRetryingStale("estimate", "estimate/"+estimate.ID, &estimate.SyncToken, estimate, nil, map[string]string{"operation": "delete"})
+}
 
-		estimates = append(estimates, resp.QueryResponse.Estimates...)
-	}
+// FindEstimates gets the full list of Estimates in the QuickBooks account.
+func (c *Client) FindEstimates() ([]Estimate, error) {
+	return findAllPages[Estimate](c, "Estimate", "no estimates could be found")
+}
 
-	return estimates, nil
+// FindEstimatesWithOptions returns the estimates matching opts, ordered by opts.OrderBy.
+// Pass nil for opts to list them all.
+func (c *Client) FindEstimatesWithOptions(opts *ListOptions) ([]Estimate, error) {
+	return findAllWithOptions[Estimate](c, "Estimate", false, opts)
 }
 
 // FindEstimateByID finds the estimate by the given id
 func (c *Client) FindEstimateByID(id string) (*Estimate, error) {
-	var resp struct {
-		Estimate Estimate
-		Time     Date
-	}
+	return getSingle[Estimate](c, "estimate/"+id, nil)
+}
 
-	if err := c.get("estimate/"+id, &resp, nil); err != nil {
-		return nil, err
+// FindEstimatePDF returns the estimate with the given Id rendered as a PDF.
+func (c *Client) FindEstimatePDF(id string) ([]byte, error) {
+	if id == "" {
+		return nil, errors.New("missing estimate id")
 	}
 
-	return &resp.Estimate, nil
+	return c.getPDF("estimate/" + id + "/pdf")
+}
+
+// FindEstimatesByIDs returns the estimates with the given Ids. Ids that match nothing are skipped.
+func (c *Client) FindEstimatesByIDs(ids []string) ([]Estimate, error) {
+	return findByIDs[Estimate](c, "Estimate", false, ids)
 }
 
 // QueryEstimates accepts an SQL query and returns all estimates found using it
 func (c *Client) QueryEstimates(query string) ([]Estimate, error) {
-	var resp struct {
-		QueryResponse struct {
-			Estimates     []Estimate `json:"Estimate"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
+	estimates, err := queryEntities[Estimate](c, "Estimate", query)
+	if err != nil {
+		return estimates, err
 	}
 
-	if resp.QueryResponse.Estimates == nil {
+	if estimates == nil {
 		return nil, errors.New("could not find any estimates")
 	}
 
-	return resp.QueryResponse.Estimates, nil
+	return estimates, nil
 }
 
-// SendEstimate sends the estimate to the Estimate.BillEmail if emailAddress is left empty
+// SendEstimate emails the estimate to its BillEmail, or to emailAddress if it is not empty.
+// It stores the address and marks the estimate sent, and copies only go to the stored
+// BillEmailCc and BillEmailBcc, as described at SendInvoice.
 func (c *Client) SendEstimate(estimateId string, emailAddress string) error {
 	queryParameters := make(map[string]string)
 
@@ -177,16 +233,7 @@ func (c *Client) UpdateEstimate(estimate *Estimate) (*Estimate, error) {
 		Sparse:   true,
 	}
 
-	var estimateData struct {
-		Estimate Estimate
-		Time     Date
-	}
-
-	if err = c.post("estimate", payload, &estimateData, nil); err != nil {
-		return nil, err
-	}
-
-	return &estimateData.Estimate, err
+	return updateSingle[Estimate](c, "estimate", "estimate/"+estimate.ID, &estimate.SyncToken, payload)
 }
 
 func (c *Client) VoidEstimate(estimate *Estimate) error {
@@ -201,5 +248,5 @@ func (c *Client) VoidEstimate(estimate *Estimate) error {
 
 	estimate.SyncToken = existingEstimate.SyncToken
 
-	return c.post("estimate", estimate, nil, map[string]string{"operation": "void"})
+	return c.postRetryingStale("estimate", "estimate/"+estimate.ID, &estimate.SyncToken, estimate, nil, map[string]string{"operation": "void"})
 }
@@ -8,6 +8,8 @@ import (
 // ExchangeRate represents a QuickBooks ExchangeRate object as returned by the API.
 // ExchangeRates are read-only.
 type ExchangeRate struct {
+	EntityMeta
+
 	SourceCurrencyCode string      `json:",omitempty"`
 	TargetCurrencyCode *string     `json:",omitempty"`
 	Rate               json.Number `json:",omitempty"`
@@ -17,11 +19,6 @@ type ExchangeRate struct {
 // FindExchangeRate returns the exchange rate for the given source currency code as of the given date.
 // asOfDate should be formatted as "YYYY-MM-DD".
 func (c *Client) FindExchangeRate(sourceCurrencyCode string, asOfDate string) (*ExchangeRate, error) {
-	var resp struct {
-		ExchangeRate ExchangeRate
-		Time         Date
-	}
-
 	params := map[string]string{
 		"sourcecurrencycode": sourceCurrencyCode,
 	}
@@ -29,35 +26,29 @@ func (c *Client) FindExchangeRate(sourceCurrencyCode string, asOfDate string) (*
 		params["asofdate"] = asOfDate
 	}
 
-	if err := c.get("exchangerate", &resp, params); err != nil {
+	exchangeRate, err := getSingle[ExchangeRate](c, "exchangerate", params)
+	if err != nil {
 		return nil, err
 	}
 
-	if resp.ExchangeRate.SourceCurrencyCode == "" {
+	if exchangeRate.SourceCurrencyCode == "" {
 		return nil, errors.New("exchange rate not found")
 	}
 
-	return &resp.ExchangeRate, nil
+	return exchangeRate, nil
 }
 
 // QueryExchangeRates accepts an SQL query and returns all exchange rates found using it.
 // Example: "SELECT * FROM ExchangeRate WHERE SourceCurrencyCode IN ('USD', 'EUR') AND AsOfDate = '2024-01-01'"
 func (c *Client) QueryExchangeRates(query string) ([]ExchangeRate, error) {
-	var resp struct {
-		QueryResponse struct {
-			ExchangeRates []ExchangeRate `json:"ExchangeRate"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
+	exchangeRates, err := queryEntities[ExchangeRate](c, "ExchangeRate", query)
+	if err != nil {
+		return exchangeRates, err
 	}
 
-	if resp.QueryResponse.ExchangeRates == nil {
+	if exchangeRates == nil {
 		return nil, errors.New("could not find any exchange rates")
 	}
 
-	return resp.QueryResponse.ExchangeRates, nil
+	return exchangeRates, nil
 }
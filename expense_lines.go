@@ -0,0 +1,66 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Billable statuses of expense lines.
+const (
+	BillableStatusBillable      = "Billable"
+	BillableStatusNotBillable   = "NotBillable"
+	BillableStatusHasBeenBilled = "HasBeenBilled"
+)
+
+// Line detail types of expense forms (bills, purchases, purchase orders, vendor credits).
+const (
+	AccountBasedExpenseLineDetailType = "AccountBasedExpenseLineDetail"
+	ItemBasedExpenseLineDetailType    = "ItemBasedExpenseLineDetail"
+)
+
+// NewBillableExpenseLine returns an account-based expense line that is billable to the given
+// customer, so it can later be added to one of their invoices. classID is optional.
+func NewBillableExpenseLine(accountID string, amount json.Number, customerID string, classID string) Line {
+	status := BillableStatusBillable
+	detail := AccountBasedExpenseLineDetail{
+		AccountRef:     ReferenceType{NameValue: NameValue{Value: accountID}},
+		CustomerRef:    &ReferenceType{NameValue: NameValue{Value: customerID}},
+		BillableStatus: &status,
+	}
+
+	if classID != "" {
+		detail.ClassRef = &ReferenceType{NameValue: NameValue{Value: classID}}
+	}
+
+	return Line{
+		Amount:                        amount,
+		DetailType:                    AccountBasedExpenseLineDetailType,
+		AccountBasedExpenseLineDetail: detail,
+	}
+}
+
+// ValidateExpenseLines checks that every expense line marked Billable names the customer it
+// is billable to. QuickBooks otherwise accepts the line but drops the billable status.
+func ValidateExpenseLines(lines []Line) error {
+	for i, line := range lines {
+		var status *string
+		var customerRef *ReferenceType
+
+		switch line.DetailType {
+		case AccountBasedExpenseLineDetailType:
+			status = line.AccountBasedExpenseLineDetail.BillableStatus
+			customerRef = line.AccountBasedExpenseLineDetail.CustomerRef
+		case ItemBasedExpenseLineDetailType:
+			status = line.ItemBasedExpenseLineDetail.BillableStatus
+			customerRef = line.ItemBasedExpenseLineDetail.CustomerRef
+		default:
+			continue
+		}
+
+		if status != nil && *status == BillableStatusBillable && (customerRef == nil || customerRef.Value == "") {
+			return fmt.Errorf("line %d: a billable expense line needs a CustomerRef", i)
+		}
+	}
+
+	return nil
+}
@@ -19,10 +19,15 @@ func NewTestClient(t *testing.T, handler http.HandlerFunc) (*Client, *httptest.S
 	endpoint, err := url.Parse(server.URL + "/v3/company/test-realm/")
 	require.NoError(t, err)
 
+	paymentsEndpoint, err := url.Parse(server.URL + "/quickbooks/v4/payments/")
+	require.NoError(t, err)
+
 	return &Client{
-		Client:       server.Client(),
-		endpoint:     endpoint,
-		realm:        "test-realm",
-		minorVersion: "65",
+		Client:           server.Client(),
+		endpoint:         endpoint,
+		paymentsEndpoint: paymentsEndpoint,
+		realm:            "test-realm",
+		minorVersion:     "65",
+		limiter:          newRealmLimiter(),
 	}, server
 }
@@ -0,0 +1,216 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ExtraFields holds raw JSON fields to send alongside the typed fields of a create input,
+// for fields Intuit has added (often behind a newer minor version) before this package
+// models them:
+//
+//	input.Extra = ExtraFields{"ProjectRef": json.RawMessage(`{"value": "42"}`)}
+//
+// A key that names one of the input's typed fields is rejected when marshalling, even if that
+// field is empty, so an extra field can never silently override a typed one.
+type ExtraFields map[string]json.RawMessage
+
+// marshalWithExtra marshals v and merges extra into the resulting JSON object.
+func marshalWithExtra(v any, extra ExtraFields) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return b, err
+	}
+
+	typed := jsonFieldNames(reflect.TypeOf(v))
+
+	var m map[string]json.RawMessage
+	if err = json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+
+	for key, value := range extra {
+		if typed[key] {
+			return nil, fmt.Errorf("extra field %s collides with a typed field", key)
+		}
+		if !json.Valid(value) {
+			return nil, fmt.Errorf("extra field %s is not valid JSON", key)
+		}
+		m[key] = value
+	}
+
+	return json.Marshal(m)
+}
+
+// jsonFieldNames returns the JSON keys of the exported fields of struct type t, including
+// those of embedded structs.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for key := range jsonFieldNames(embedded) {
+					names[key] = true
+				}
+				continue
+			}
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		names[name] = true
+	}
+	return names
+}
+
+func (input AccountCreateInput) MarshalJSON() ([]byte, error) {
+	type plain AccountCreateInput
+	return marshalWithExtra(plain(input), input.Extra)
+}
+
+func (input AttachableCreateInput) MarshalJSON() ([]byte, error) {
+	type plain AttachableCreateInput
+	return marshalWithExtra(plain(input), input.Extra)
+}
+
+func (input BillCreateInput) MarshalJSON() ([]byte, error) {
+	type plain BillCreateInput
+	return marshalWithExtra(plain(input), input.Extra)
+}
+
+func (input BillPaymentCreateInput) MarshalJSON() ([]byte, error) {
+	type plain BillPaymentCreateInput
+	return marshalWithExtra(plain(input), input.Extra)
+}
+
+func (input ClassCreateInput) MarshalJSON() ([]byte, error) {
+	type plain ClassCreateInput
+	return marshalWithExtra(plain(input), input.Extra)
+}
+
+func (input CreditMemoCreateInput) MarshalJSON() ([]byte, error) {
+	type plain CreditMemoCreateInput
+	return marshalWithExtra(plain(input), input.Extra)
+}
+
+func (input CustomerCreateInput) MarshalJSON() ([]byte, error) {
+	type plain CustomerCreateInput
+	return marshalWithExtra(plain(input), input.Extra)
+}
+
+func (input CustomerTypeCreateInput) MarshalJSON() ([]byte, error) {
+	type plain CustomerTypeCreateInput
+	return marshalWithExtra(plain(input), input.Extra)
+}
+
+func (input DepartmentCreateInput) MarshalJSON() ([]byte, error) {
+	type plain DepartmentCreateInput
+	return marshalWithExtra(plain(input), input.Extra)
+}
+
+func (input DepositCreateInput) MarshalJSON() ([]byte, error) {
+	type plain DepositCreateInput
+	return marshalWithExtra(plain(input), input.Extra)
+}
+
+func (input EmployeeCreateInput) MarshalJSON() ([]byte, error) {
+	type plain EmployeeCreateInput
+	return marshalWithExtra(plain(input), input.Extra)
+}
+
+func (input EstimateCreateInput) MarshalJSON() ([]byte, error) {
+	type plain EstimateCreateInput
+	return marshalWithExtra(plain(input), input.Extra)
+}
+
+func (input InvoiceCreateInput) MarshalJSON() ([]byte, error) {
+	type plain InvoiceCreateInput
+	return marshalWithExtra(plain(input), input.Extra)
+}
+
+func (input ItemCreateInput) MarshalJSON() ([]byte, error) {
+	type plain ItemCreateInput
+	return marshalWithExtra(plain(input), input.Extra)
+}
+
+func (input JournalEntryCreateInput) MarshalJSON() ([]byte, error) {
+	type plain JournalEntryCreateInput
+	return marshalWithExtra(plain(input), input.Extra)
+}
+
+func (input PaymentCreateInput) MarshalJSON() ([]byte, error) {
+	type plain PaymentCreateInput
+	return marshalWithExtra(plain(input), input.Extra)
+}
+
+func (input PaymentMethodCreateInput) MarshalJSON() ([]byte, error) {
+	type plain PaymentMethodCreateInput
+	return marshalWithExtra(plain(input), input.Extra)
+}
+
+func (input PurchaseCreateInput) MarshalJSON() ([]byte, error) {
+	type plain PurchaseCreateInput
+	return marshalWithExtra(plain(input), input.Extra)
+}
+
+func (input PurchaseOrderCreateInput) MarshalJSON() ([]byte, error) {
+	type plain PurchaseOrderCreateInput
+	return marshalWithExtra(plain(input), input.Extra)
+}
+
+func (input RefundReceiptCreateInput) MarshalJSON() ([]byte, error) {
+	type plain RefundReceiptCreateInput
+	return marshalWithExtra(plain(input), input.Extra)
+}
+
+func (input SalesReceiptCreateInput) MarshalJSON() ([]byte, error) {
+	type plain SalesReceiptCreateInput
+	return marshalWithExtra(plain(input), input.Extra)
+}
+
+func (input TaxAgencyCreateInput) MarshalJSON() ([]byte, error) {
+	type plain TaxAgencyCreateInput
+	return marshalWithExtra(plain(input), input.Extra)
+}
+
+func (input TermCreateInput) MarshalJSON() ([]byte, error) {
+	type plain TermCreateInput
+	return marshalWithExtra(plain(input), input.Extra)
+}
+
+func (input TimeActivityCreateInput) MarshalJSON() ([]byte, error) {
+	type plain TimeActivityCreateInput
+	return marshalWithExtra(plain(input), input.Extra)
+}
+
+func (input TransferCreateInput) MarshalJSON() ([]byte, error) {
+	type plain TransferCreateInput
+	return marshalWithExtra(plain(input), input.Extra)
+}
+
+func (input VendorCreateInput) MarshalJSON() ([]byte, error) {
+	type plain VendorCreateInput
+	return marshalWithExtra(plain(input), input.Extra)
+}
+
+func (input VendorCreditCreateInput) MarshalJSON() ([]byte, error) {
+	type plain VendorCreditCreateInput
+	return marshalWithExtra(plain(input), input.Extra)
+}
@@ -0,0 +1,38 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateInputExtraFields(t *testing.T) {
+	input := &InvoiceCreateInput{
+		CustomerRef: ReferenceType{NameValue: NameValue{Value: "1"}},
+		Extra:       ExtraFields{"ProjectRef": json.RawMessage(`{"value": "42"}`)},
+	}
+
+	b, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	var m map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(b, &m))
+	assert.JSONEq(t, `{"value": "42"}`, string(m["ProjectRef"]))
+	assert.JSONEq(t, `{"value": "1"}`, string(m["CustomerRef"]))
+	assert.NotContains(t, m, "Extra")
+
+	// Empty typed fields still count as collisions.
+	input.Extra = ExtraFields{"PrivateNote": json.RawMessage(`"x"`)}
+	_, err = json.Marshal(input)
+	assert.ErrorContains(t, err, "extra field PrivateNote collides with a typed field")
+
+	input.Extra = ExtraFields{"Broken": json.RawMessage(`{`)}
+	_, err = json.Marshal(input)
+	assert.Error(t, err)
+
+	b, err = json.Marshal(ClassCreateInput{Name: "Retail"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"Name": "Retail"}`, string(b))
+}
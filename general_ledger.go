@@ -0,0 +1,311 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// GeneralLedgerQueryParams holds the optional query parameters for the GeneralLedger report.
+type GeneralLedgerQueryParams struct {
+	// Cash or Accrual
+	AccountingMethod *string
+	StartDate        *string
+	EndDate          *string
+	// Predefined date range, e.g. "This Month", "Last Fiscal Year". Ignored when StartDate/EndDate are set.
+	DateMacro *string
+	// Comma separated lists of ids to filter on.
+	Account    *string
+	Customer   *string
+	Vendor     *string
+	Class      *string
+	Department *string
+	// Comma separated list of column keys, e.g. "tx_date,txn_type,doc_num,subt_nat_amount,rbal_nat_amount".
+	// Other useful keys are "name", "memo", "split_acc", "account_name", "debt_amt" and "credit_amt".
+	Columns *string
+	// Column key to sort by, e.g. "tx_date".
+	SortBy *string
+	// ascend or descend
+	SortOrder *string
+}
+
+func (p *GeneralLedgerQueryParams) toMap() map[string]string {
+	m := map[string]string{}
+	if p.AccountingMethod != nil {
+		m["accounting_method"] = *p.AccountingMethod
+	}
+	if p.StartDate != nil {
+		m["start_date"] = *p.StartDate
+	}
+	if p.EndDate != nil {
+		m["end_date"] = *p.EndDate
+	}
+	if p.DateMacro != nil {
+		m["date_macro"] = *p.DateMacro
+	}
+	if p.Account != nil {
+		m["account"] = *p.Account
+	}
+	if p.Customer != nil {
+		m["customer"] = *p.Customer
+	}
+	if p.Vendor != nil {
+		m["vendor"] = *p.Vendor
+	}
+	if p.Class != nil {
+		m["class"] = *p.Class
+	}
+	if p.Department != nil {
+		m["department"] = *p.Department
+	}
+	if p.Columns != nil {
+		m["columns"] = *p.Columns
+	}
+	if p.SortBy != nil {
+		m["sort_by"] = *p.SortBy
+	}
+	if p.SortOrder != nil {
+		m["sort_order"] = *p.SortOrder
+	}
+	return m
+}
+
+// GLTransaction is one transaction line of an account in the General Ledger.
+// Fields whose column was not requested are left empty.
+type GLTransaction struct {
+	Date      string
+	TxnType   string
+	TxnID     string
+	DocNumber string
+	Name      string
+	Memo      string
+	// SplitAccount is the account on the other side of the transaction.
+	SplitAccount string
+	Amount       json.Number
+	// Debit and Credit are the debt_amt and credit_amt columns. When the report has them but
+	// no subt_nat_amount column, Amount is Debit minus Credit.
+	Debit  json.Number
+	Credit json.Number
+	// Balance is the running balance of the account after this transaction.
+	Balance json.Number
+}
+
+// GLAccount holds the General Ledger activity of one account. Sub-accounts have their own
+// GLAccount; their transactions are not included in the parent's.
+type GLAccount struct {
+	AccountID string
+	Account   string
+	// ParentAccountID is the Id of the account this sub-account is listed under, or "" for
+	// a top-level account.
+	ParentAccountID string
+	OpeningBalance  json.Number
+	Transactions    []GLTransaction
+	ClosingBalance  json.Number
+}
+
+// GLByAccount is the General Ledger keyed by account Id.
+type GLByAccount map[string]*GLAccount
+
+// GeneralLedger is the GeneralLedger report grouped by account. Accounts are in report
+// order, each sub-account right after the transactions of its parent.
+type GeneralLedger struct {
+	Header   ReportHeader
+	Columns  []ReportColumn
+	Accounts []*GLAccount
+}
+
+// Account returns the account with the given Id, or nil if it is not in the report.
+func (gl *GeneralLedger) Account(id string) *GLAccount {
+	for _, account := range gl.Accounts {
+		if account.AccountID == id {
+			return account
+		}
+	}
+	return nil
+}
+
+// glBeginningBalance is the label of the row QuickBooks puts first in each account section.
+const glBeginningBalance = "Beginning Balance"
+
+// NewGLByAccount reassembles a GeneralLedger report into per-account opening balances,
+// transactions and closing balances.
+//
+// The closing balance is the running balance (rbal_nat_amount) after the last row of the
+// account. When the report has no running balance column it is the opening balance plus the
+// account's transaction amounts.
+func NewGLByAccount(report *Report) (GLByAccount, error) {
+	ledger, err := newGeneralLedger(report)
+	if err != nil {
+		return nil, err
+	}
+
+	gl := GLByAccount{}
+	for _, account := range ledger.Accounts {
+		gl[account.AccountID] = account
+	}
+	return gl, nil
+}
+
+// glColumns are the column keys newGLAccount reads.
+var glColumns = []string{"tx_date", "txn_type", "doc_num", "name", "memo", "split_acc", "subt_nat_amount",
+	"debt_amt", "credit_amt", "rbal_nat_amount"}
+
+// newGeneralLedger walks the account sections of the report, which nest sub-accounts inside
+// their parent, and attaches each transaction row to the account section it is listed in.
+func newGeneralLedger(report *Report) (*GeneralLedger, error) {
+	cols := map[string]int{}
+	for _, key := range glColumns {
+		cols[key] = report.ColumnIndex(key)
+	}
+	if cols["subt_nat_amount"] < 0 && cols["rbal_nat_amount"] < 0 && cols["debt_amt"] < 0 && cols["credit_amt"] < 0 {
+		return nil, errors.New("general ledger report has neither an amount nor a balance column")
+	}
+
+	ledger := &GeneralLedger{Header: report.Header, Columns: report.Columns}
+
+	var walk func(rows []ReportRow, parentID string) error
+	walk = func(rows []ReportRow, parentID string) error {
+		for _, row := range rows {
+			if !row.IsSection() {
+				continue
+			}
+
+			inner := parentID
+			if len(row.Header) > 0 && row.Header[0].ID != "" {
+				account, err := newGLAccount(row, cols)
+				if err != nil {
+					return err
+				}
+				account.ParentAccountID = parentID
+				ledger.Accounts = append(ledger.Accounts, account)
+				inner = account.AccountID
+			}
+
+			if err := walk(row.Rows, inner); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := walk(report.Rows, ""); err != nil {
+		return nil, err
+	}
+
+	return ledger, nil
+}
+
+func newGLAccount(section ReportRow, cols map[string]int) (*GLAccount, error) {
+	account := &GLAccount{
+		AccountID:      section.Header[0].ID,
+		Account:        section.Header[0].Value,
+		OpeningBalance: "0",
+	}
+
+	var total []json.Number
+	closing := json.Number("")
+
+	for _, row := range section.Rows {
+		if row.IsSection() {
+			continue
+		}
+
+		balance := glAmount(row.cell(cols["rbal_nat_amount"]).Value)
+		if balance != "" {
+			closing = balance
+		}
+
+		if strings.EqualFold(row.cell(0).Value, glBeginningBalance) {
+			if balance != "" {
+				account.OpeningBalance = balance
+			} else if amount := glAmount(row.cell(cols["subt_nat_amount"]).Value); amount != "" {
+				account.OpeningBalance = amount
+			}
+			continue
+		}
+
+		txnType := row.cell(cols["txn_type"])
+		txn := GLTransaction{
+			Date:         row.cell(cols["tx_date"]).Value,
+			TxnType:      txnType.Value,
+			TxnID:        txnType.ID,
+			DocNumber:    row.cell(cols["doc_num"]).Value,
+			Name:         row.cell(cols["name"]).Value,
+			Memo:         row.cell(cols["memo"]).Value,
+			SplitAccount: row.cell(cols["split_acc"]).Value,
+			Amount:       glAmount(row.cell(cols["subt_nat_amount"]).Value),
+			Debit:        glAmount(row.cell(cols["debt_amt"]).Value),
+			Credit:       glAmount(row.cell(cols["credit_amt"]).Value),
+			Balance:      balance,
+		}
+		if cols["subt_nat_amount"] < 0 && (txn.Debit != "" || txn.Credit != "") {
+			var err error
+			if txn.Amount, err = subtractAmounts(zeroIfEmpty(txn.Debit), zeroIfEmpty(txn.Credit)); err != nil {
+				return nil, err
+			}
+		}
+		account.Transactions = append(account.Transactions, txn)
+		total = append(total, txn.Amount)
+	}
+
+	if closing != "" {
+		account.ClosingBalance = closing
+		return account, nil
+	}
+
+	closingBalance, err := sumAmounts(append(total, account.OpeningBalance)...)
+	if err != nil {
+		return nil, err
+	}
+	account.ClosingBalance = closingBalance
+
+	return account, nil
+}
+
+// glAmount returns the amount of a report cell, dropping the thousands separators
+// QuickBooks sometimes includes.
+func glAmount(value string) json.Number {
+	return json.Number(strings.ReplaceAll(strings.TrimSpace(value), ",", ""))
+}
+
+// zeroIfEmpty returns n, or "0" for an empty cell.
+func zeroIfEmpty(n json.Number) json.Number {
+	if n == "" {
+		return "0"
+	}
+	return n
+}
+
+// GetGeneralLedger fetches the GeneralLedger report with the transactions of each account.
+// Use params.Columns to choose the columns, e.g. "tx_date,txn_type,doc_num,debt_amt,credit_amt";
+// the transaction fields of the columns left out are empty. Pass nil for params to use the API
+// defaults.
+func (c *Client) GetGeneralLedger(params *GeneralLedgerQueryParams) (*GeneralLedger, error) {
+	var queryParams map[string]string
+	if params != nil {
+		queryParams = params.toMap()
+	}
+
+	report, err := c.getReport("GeneralLedger", queryParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return newGeneralLedger(report)
+}
+
+// GetGeneralLedgerByAccount fetches the GeneralLedger report and returns it keyed by account.
+// Use params.Account to restrict it to some accounts. Pass nil for params to use the API defaults.
+func (c *Client) GetGeneralLedgerByAccount(params *GeneralLedgerQueryParams) (GLByAccount, error) {
+	var queryParams map[string]string
+	if params != nil {
+		queryParams = params.toMap()
+	}
+
+	report, err := c.getReport("GeneralLedger", queryParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewGLByAccount(report)
+}
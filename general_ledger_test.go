@@ -0,0 +1,158 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const generalLedgerFixture = `{
+  "Header": {"ReportName": "GeneralLedger", "StartPeriod": "2024-01-01", "EndPeriod": "2024-01-31"},
+  "Columns": {"Column": [
+    {"ColType": "Date", "ColTitle": "Date", "MetaData": [{"Name": "ColKey", "Value": "tx_date"}]},
+    {"ColType": "String", "ColTitle": "Transaction Type", "MetaData": [{"Name": "ColKey", "Value": "txn_type"}]},
+    {"ColType": "String", "ColTitle": "Num", "MetaData": [{"Name": "ColKey", "Value": "doc_num"}]},
+    {"ColType": "String", "ColTitle": "Name", "MetaData": [{"Name": "ColKey", "Value": "name"}]},
+    {"ColType": "String", "ColTitle": "Split", "MetaData": [{"Name": "ColKey", "Value": "split_acc"}]},
+    {"ColType": "Money", "ColTitle": "Amount", "MetaData": [{"Name": "ColKey", "Value": "subt_nat_amount"}]},
+    {"ColType": "Money", "ColTitle": "Balance", "MetaData": [{"Name": "ColKey", "Value": "rbal_nat_amount"}]}
+  ]},
+  "Rows": {"Row": [
+    {"type": "Section",
+     "Header": {"ColData": [{"value": "Checking", "id": "35"}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": ""}]},
+     "Rows": {"Row": [
+       {"type": "Data", "ColData": [{"value": "Beginning Balance"}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": "1,000.00"}]},
+       {"type": "Data", "ColData": [{"value": "2024-01-05"}, {"value": "Payment", "id": "101"}, {"value": "1001"}, {"value": "Amy's Bird Sanctuary"}, {"value": "Undeposited Funds"}, {"value": "250.00"}, {"value": "1,250.00"}]},
+       {"type": "Data", "ColData": [{"value": "2024-01-20"}, {"value": "Expense", "id": "102"}, {"value": ""}, {"value": "Hicks Hardware"}, {"value": "Supplies"}, {"value": "-75.50"}, {"value": "1,174.50"}]},
+       {"type": "Section",
+        "Header": {"ColData": [{"value": "Checking Reserve", "id": "36"}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": ""}]},
+        "Rows": {"Row": [
+          {"type": "Data", "ColData": [{"value": "2024-01-31"}, {"value": "Transfer", "id": "103"}, {"value": ""}, {"value": ""}, {"value": "Savings"}, {"value": "500.00"}, {"value": "500.00"}]}
+        ]},
+        "Summary": {"ColData": [{"value": "Total for Checking Reserve"}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": "500.00"}, {"value": ""}]}}
+     ]},
+     "Summary": {"ColData": [{"value": "Total for Checking with sub-accounts"}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": "674.50"}, {"value": ""}]}},
+    {"type": "Section",
+     "Header": {"ColData": [{"value": "Savings", "id": "37"}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": ""}]},
+     "Rows": {"Row": [
+       {"type": "Data", "ColData": [{"value": "Beginning Balance"}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": "2000.00"}]}
+     ]},
+     "Summary": {"ColData": [{"value": "Total for Savings"}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": ""}]}}
+  ]}
+}`
+
+func TestGetGeneralLedgerByAccount(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v3/company/test-realm/reports/GeneralLedger", r.URL.Path)
+		assert.Equal(t, "35,37", r.URL.Query().Get("account"))
+		w.Write([]byte(generalLedgerFixture))
+	})
+
+	accounts := "35,37"
+	gl, err := client.GetGeneralLedgerByAccount(&GeneralLedgerQueryParams{Account: &accounts})
+	require.NoError(t, err)
+	require.Len(t, gl, 3)
+
+	checking := gl["35"]
+	assert.Equal(t, "Checking", checking.Account)
+	assert.Equal(t, json.Number("1000.00"), checking.OpeningBalance)
+	assert.Equal(t, json.Number("1174.50"), checking.ClosingBalance)
+	require.Len(t, checking.Transactions, 2)
+	assert.Equal(t, GLTransaction{
+		Date:         "2024-01-05",
+		TxnType:      "Payment",
+		TxnID:        "101",
+		DocNumber:    "1001",
+		Name:         "Amy's Bird Sanctuary",
+		SplitAccount: "Undeposited Funds",
+		Amount:       "250.00",
+		Balance:      "1250.00",
+	}, checking.Transactions[0])
+
+	reserve := gl["36"]
+	assert.Equal(t, json.Number("0"), reserve.OpeningBalance)
+	assert.Equal(t, json.Number("500.00"), reserve.ClosingBalance)
+	assert.Len(t, reserve.Transactions, 1)
+
+	savings := gl["37"]
+	assert.Equal(t, json.Number("2000.00"), savings.OpeningBalance)
+	assert.Equal(t, json.Number("2000.00"), savings.ClosingBalance)
+	assert.Empty(t, savings.Transactions)
+}
+
+func TestNewGLByAccountWithoutBalanceColumn(t *testing.T) {
+	var report Report
+	require.NoError(t, json.Unmarshal([]byte(`{
+  "Columns": {"Column": [
+    {"ColType": "Date", "MetaData": [{"Name": "ColKey", "Value": "tx_date"}]},
+    {"ColType": "Money", "MetaData": [{"Name": "ColKey", "Value": "subt_nat_amount"}]}
+  ]},
+  "Rows": {"Row": [
+    {"type": "Section",
+     "Header": {"ColData": [{"value": "Sales", "id": "79"}, {"value": ""}]},
+     "Rows": {"Row": [
+       {"type": "Data", "ColData": [{"value": "Beginning Balance"}, {"value": "100.00"}]},
+       {"type": "Data", "ColData": [{"value": "2024-01-02"}, {"value": "40.25"}]},
+       {"type": "Data", "ColData": [{"value": "2024-01-03"}, {"value": "9.75"}]}
+     ]}}
+  ]}
+}`), &report))
+
+	gl, err := NewGLByAccount(&report)
+	require.NoError(t, err)
+	assert.Equal(t, json.Number("100.00"), gl["79"].OpeningBalance)
+	assert.Equal(t, json.Number("150.00"), gl["79"].ClosingBalance)
+}
+
+func TestGetGeneralLedger(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v3/company/test-realm/reports/GeneralLedger", r.URL.Path)
+		if r.URL.Query().Get("columns") == "" {
+			w.Write([]byte(generalLedgerFixture))
+			return
+		}
+
+		assert.Equal(t, "tx_date,txn_type,debt_amt,credit_amt", r.URL.Query().Get("columns"))
+		w.Write([]byte(`{
+  "Header": {"ReportName": "GeneralLedger"},
+  "Columns": {"Column": [
+    {"ColType": "Date", "MetaData": [{"Name": "ColKey", "Value": "tx_date"}]},
+    {"ColType": "String", "MetaData": [{"Name": "ColKey", "Value": "txn_type"}]},
+    {"ColType": "Money", "MetaData": [{"Name": "ColKey", "Value": "debt_amt"}]},
+    {"ColType": "Money", "MetaData": [{"Name": "ColKey", "Value": "credit_amt"}]}
+  ]},
+  "Rows": {"Row": [
+    {"type": "Section",
+     "Header": {"ColData": [{"value": "Accounts Receivable (A/R)", "id": "84"}, {"value": ""}, {"value": ""}, {"value": ""}]},
+     "Rows": {"Row": [
+       {"type": "Data", "ColData": [{"value": "2024-01-03"}, {"value": "Invoice", "id": "130"}, {"value": "1,200.00"}, {"value": ""}]},
+       {"type": "Data", "ColData": [{"value": "2024-01-09"}, {"value": "Payment", "id": "131"}, {"value": ""}, {"value": "450.00"}]}
+     ]},
+     "Summary": {"ColData": [{"value": "Total for Accounts Receivable (A/R)"}, {"value": ""}, {"value": "1200.00"}, {"value": "450.00"}]}}
+  ]}
+}`))
+	})
+
+	gl, err := client.GetGeneralLedger(nil)
+	require.NoError(t, err)
+	require.Len(t, gl.Accounts, 3)
+	assert.Equal(t, []string{"35", "36", "37"}, []string{gl.Accounts[0].AccountID, gl.Accounts[1].AccountID, gl.Accounts[2].AccountID})
+	assert.Equal(t, "", gl.Accounts[0].ParentAccountID)
+	assert.Equal(t, "35", gl.Account("36").ParentAccountID)
+	assert.Equal(t, "Transfer", gl.Account("36").Transactions[0].TxnType)
+	assert.Nil(t, gl.Account("99"))
+
+	columns := "tx_date,txn_type,debt_amt,credit_amt"
+	gl, err = client.GetGeneralLedger(&GeneralLedgerQueryParams{Columns: &columns})
+	require.NoError(t, err)
+
+	ar := gl.Account("84")
+	require.NotNil(t, ar)
+	require.Len(t, ar.Transactions, 2)
+	assert.Equal(t, GLTransaction{Date: "2024-01-03", TxnType: "Invoice", TxnID: "130", Debit: "1200.00", Amount: "1200.00"}, ar.Transactions[0])
+	assert.Equal(t, json.Number("-450.00"), ar.Transactions[1].Amount)
+	assert.Equal(t, json.Number("750.00"), ar.ClosingBalance)
+}
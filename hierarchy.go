@@ -0,0 +1,75 @@
+package quickbooks
+
+// Accounts, classes and departments form trees through ParentRef: a sub-account points at its
+// parent account, and FullyQualifiedName spells out the path ("Utilities:Gas"). The Build*Tree
+// functions rebuild those trees from a flat list, such as the one FindAccounts returns.
+
+// TreeNode is one object of a name list hierarchy together with its direct children.
+type TreeNode[T any] struct {
+	Item     T
+	Children []*TreeNode[T]
+}
+
+// Walk calls fn for the node and then for each of its descendants, depth first, in order.
+// depth is 0 for the node itself.
+func (n *TreeNode[T]) Walk(fn func(node *TreeNode[T], depth int)) {
+	n.walk(fn, 0)
+}
+
+func (n *TreeNode[T]) walk(fn func(node *TreeNode[T], depth int), depth int) {
+	fn(n, depth)
+	for _, child := range n.Children {
+		child.walk(fn, depth+1)
+	}
+}
+
+// buildTree links items into trees and returns the roots. Children keep the order of items.
+// An item whose parent is not in items (e.g. an inactive parent that was filtered out) is
+// returned as a root rather than dropped.
+func buildTree[T any](items []T, id func(*T) string, parentRef func(*T) *ReferenceType) []*TreeNode[T] {
+	nodes := make([]*TreeNode[T], len(items))
+	byID := make(map[string]*TreeNode[T], len(items))
+	for i := range items {
+		nodes[i] = &TreeNode[T]{Item: items[i]}
+		byID[id(&items[i])] = nodes[i]
+	}
+
+	var roots []*TreeNode[T]
+	for i, node := range nodes {
+		var parent *TreeNode[T]
+		if ref := parentRef(&items[i]); ref != nil && ref.Value != id(&items[i]) {
+			parent = byID[ref.Value]
+		}
+
+		if parent != nil {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+
+	return roots
+}
+
+// BuildAccountTree arranges accounts into their sub-account hierarchy, e.g. for rendering a
+// chart of accounts, and returns the top-level accounts.
+func BuildAccountTree(accounts []Account) []*TreeNode[Account] {
+	return buildTree(accounts,
+		func(a *Account) string { return a.ID },
+		func(a *Account) *ReferenceType { return a.ParentRef })
+}
+
+// BuildClassTree arranges classes into their sub-class hierarchy and returns the top-level classes.
+func BuildClassTree(classes []Class) []*TreeNode[Class] {
+	return buildTree(classes,
+		func(c *Class) string { return c.ID },
+		func(c *Class) *ReferenceType { return c.ParentRef })
+}
+
+// BuildDepartmentTree arranges departments (locations) into their sub-department hierarchy and
+// returns the top-level departments.
+func BuildDepartmentTree(departments []Department) []*TreeNode[Department] {
+	return buildTree(departments,
+		func(d *Department) string { return d.ID },
+		func(d *Department) *ReferenceType { return d.ParentRef })
+}
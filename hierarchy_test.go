@@ -0,0 +1,38 @@
+package quickbooks
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildAccountTree(t *testing.T) {
+	ref := func(id string) *ReferenceType { return &ReferenceType{NameValue: NameValue{Value: id}} }
+	accounts := []Account{
+		{ID: "1", Name: "Utilities"},
+		{ID: "2", Name: "Gas", ParentRef: ref("1"), SubAccount: true},
+		{ID: "3", Name: "Electric", ParentRef: ref("1"), SubAccount: true},
+		{ID: "4", Name: "Peak", ParentRef: ref("3"), SubAccount: true},
+		{ID: "5", Name: "Checking"},
+		{ID: "6", Name: "Orphan", ParentRef: ref("99"), SubAccount: true},
+	}
+
+	roots := BuildAccountTree(accounts)
+	require.Len(t, roots, 3)
+	assert.Equal(t, "Utilities", roots[0].Item.Name)
+	assert.Equal(t, "Checking", roots[1].Item.Name)
+	assert.Equal(t, "Orphan", roots[2].Item.Name)
+
+	var walked []string
+	roots[0].Walk(func(node *TreeNode[Account], depth int) {
+		walked = append(walked, fmt.Sprintf("%s:%d", node.Item.Name, depth))
+	})
+	assert.Equal(t, []string{"Utilities:0", "Gas:1", "Electric:1", "Peak:2"}, walked)
+
+	classes := BuildClassTree([]Class{{ID: "10", Name: "Retail"}, {ID: "11", Name: "Online", ParentRef: ref("10")}})
+	require.Len(t, classes, 1)
+	require.Len(t, classes[0].Children, 1)
+	assert.Equal(t, "Online", classes[0].Children[0].Item.Name)
+}
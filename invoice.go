@@ -4,39 +4,48 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"strconv"
+	"math/big"
+	"strings"
 )
 
 // Invoice represents a QuickBooks Invoice object as returned by the API.
 // Read-only fields (Id, SyncToken, MetaData, TotalAmt, HomeAmtTotal, HomeBalance,
 // Balance, TxnSource, LinkedTxn) are populated by the service.
 type Invoice struct {
-	ID            string        `json:"Id,omitempty"`
-	SyncToken     string        `json:",omitempty"`
-	MetaData      *MetaData     `json:",omitempty"`
-	CustomField   []CustomField `json:",omitempty"`
-	DocNumber     *string       `json:",omitempty"`
-	TxnDate       *Date         `json:",omitempty"`
-	DepartmentRef *ReferenceType `json:",omitempty"`
-	PrivateNote   *string       `json:",omitempty"`
-	LinkedTxn     []LinkedTxn   `json:"LinkedTxn"`
-	Line          []Line
-	TxnTaxDetail  *TxnTaxDetail  `json:",omitempty"`
-	CustomerRef   ReferenceType
-	CustomerMemo  *MemoRef       `json:",omitempty"`
-	BillAddr      *Address       `json:",omitempty"`
-	ShipAddr      *Address       `json:",omitempty"`
-	ClassRef      *ReferenceType `json:",omitempty"`
-	SalesTermRef  *ReferenceType `json:",omitempty"`
-	DueDate       *Date          `json:",omitempty"`
-	ShipMethodRef *ReferenceType `json:",omitempty"`
-	ShipDate      *Date          `json:",omitempty"`
-	TrackingNum   *string        `json:",omitempty"`
-	TotalAmt      json.Number    `json:",omitempty"`
-	CurrencyRef   *ReferenceType `json:",omitempty"`
-	ExchangeRate  json.Number    `json:",omitempty"`
-	HomeAmtTotal  json.Number    `json:",omitempty"`
-	HomeBalance   json.Number    `json:",omitempty"`
+	EntityMeta
+
+	ID                   string         `json:"Id,omitempty"`
+	SyncToken            string         `json:",omitempty"`
+	MetaData             *MetaData      `json:",omitempty"`
+	CustomField          []CustomField  `json:",omitempty"`
+	DocNumber            *string        `json:",omitempty"`
+	TxnDate              *Date          `json:",omitempty"`
+	DepartmentRef        *ReferenceType `json:",omitempty"`
+	PrivateNote          *string        `json:",omitempty"`
+	LinkedTxn            []LinkedTxn    `json:"LinkedTxn"`
+	Line                 []Line
+	TxnTaxDetail         *TxnTaxDetail        `json:",omitempty"`
+	GlobalTaxCalculation GlobalTaxCalculation `json:",omitempty"`
+	CustomerRef          ReferenceType
+	CustomerMemo         *MemoRef `json:",omitempty"`
+	BillAddr             *Address `json:",omitempty"`
+	ShipAddr             *Address `json:",omitempty"`
+	// ShipFromAddr is where the goods ship from; Automated Sales Tax uses it for origin-based rates.
+	ShipFromAddr *Address `json:",omitempty"`
+	// FreeFormAddress is true when ShipAddr is stored exactly as entered rather than as a
+	// formatted address. See Address.
+	FreeFormAddress              *bool          `json:",omitempty"`
+	ClassRef                     *ReferenceType `json:",omitempty"`
+	SalesTermRef                 *ReferenceType `json:",omitempty"`
+	DueDate                      *Date          `json:",omitempty"`
+	ShipMethodRef                *ReferenceType `json:",omitempty"`
+	ShipDate                     *Date          `json:",omitempty"`
+	TrackingNum                  *string        `json:",omitempty"`
+	TotalAmt                     json.Number    `json:",omitempty"`
+	CurrencyRef                  *ReferenceType `json:",omitempty"`
+	ExchangeRate                 json.Number    `json:",omitempty"`
+	HomeAmtTotal                 json.Number    `json:",omitempty"`
+	HomeBalance                  json.Number    `json:",omitempty"`
 	ApplyTaxAfterDiscount        *bool          `json:",omitempty"`
 	PrintStatus                  *string        `json:",omitempty"`
 	EmailStatus                  *string        `json:",omitempty"`
@@ -46,10 +55,67 @@ type Invoice struct {
 	DeliveryInfo                 *DeliveryInfo  `json:",omitempty"`
 	Balance                      json.Number    `json:",omitempty"`
 	TxnSource                    *string        `json:",omitempty"`
+	AllowIPNPayment              *bool          `json:",omitempty"`
 	AllowOnlineCreditCardPayment *bool          `json:",omitempty"`
 	AllowOnlineACHPayment        *bool          `json:",omitempty"`
-	Deposit                      json.Number    `json:",omitempty"`
-	DepositToAccountRef          *ReferenceType `json:",omitempty"`
+	// AllowOnlinePayment is the company-wide online payment switch as it applied to the invoice.
+	// QuickBooks only returns it (and InvoiceLink) when asked; see FindInvoiceByIDWithInclude.
+	AllowOnlinePayment *bool `json:",omitempty"`
+	// Deposit is an amount paid upfront, deducted from the balance due.
+	Deposit             json.Number    `json:",omitempty"`
+	DepositToAccountRef *ReferenceType `json:",omitempty"`
+	// EInvoiceStatus is only set in locales with e-invoicing; see Invoice.EInvoiceTransmitted.
+	EInvoiceStatus *string `json:",omitempty"`
+	// InvoiceLink is the customer-facing link to the invoice, set when online delivery is enabled.
+	InvoiceLink *string `json:",omitempty"`
+}
+
+// TransactionTotals returns the subtotal, discount, tax and total QuickBooks computed for the invoice.
+func (i *Invoice) TransactionTotals() TransactionTotals {
+	return salesTotals(i.Line, i.TxnTaxDetail, i.TotalAmt)
+}
+
+// E-invoice statuses QuickBooks reports in Invoice.EInvoiceStatus.
+const (
+	EInvoiceStatusSent   = "Sent"
+	EInvoiceStatusViewed = "Viewed"
+	EInvoiceStatusPaid   = "Paid"
+)
+
+// IsVoided reports whether the invoice has been voided.
+//
+// QuickBooks has no void flag, so this is inferred from what voiding leaves behind: a zero
+// TotalAmt and a PrivateNote starting with "Voided". A zero-amount invoice whose note was
+// written that way by hand is reported as voided too, and a voided invoice whose note was
+// edited afterwards is not.
+func (i *Invoice) IsVoided() bool {
+	return isVoided(i.PrivateNote, i.TotalAmt)
+}
+
+// EInvoiceState returns the e-invoice status of the invoice, and false if QuickBooks
+// reports none (the company's locale has no e-invoicing or the invoice was never sent).
+func (i *Invoice) EInvoiceState() (string, bool) {
+	if i.EInvoiceStatus == nil || *i.EInvoiceStatus == "" {
+		return "", false
+	}
+
+	return *i.EInvoiceStatus, true
+}
+
+// EInvoiceTransmitted reports whether QuickBooks has transmitted the e-invoice.
+// Statuses other than Sent, Viewed and Paid are treated as not transmitted.
+func (i *Invoice) EInvoiceTransmitted() bool {
+	status, ok := i.EInvoiceState()
+	if !ok {
+		return false
+	}
+
+	switch status {
+	case EInvoiceStatusSent, EInvoiceStatusViewed, EInvoiceStatusPaid:
+		return true
+	}
+
+	return false
 }
 
 // InvoiceCreateInput contains the writable fields accepted when creating an Invoice.
@@ -60,30 +126,40 @@ type InvoiceCreateInput struct {
 	DocNumber     *string        `json:",omitempty"`
 	TxnDate       *Date          `json:",omitempty"`
 	DepartmentRef *ReferenceType `json:",omitempty"`
-	PrivateNote   *string        `json:",omitempty"`
-	TxnTaxDetail  *TxnTaxDetail  `json:",omitempty"`
-	CustomerMemo  *MemoRef       `json:",omitempty"`
-	BillAddr      *Address       `json:",omitempty"`
-	ShipAddr      *Address       `json:",omitempty"`
-	ClassRef      *ReferenceType `json:",omitempty"`
-	SalesTermRef  *ReferenceType `json:",omitempty"`
-	DueDate       *Date          `json:",omitempty"`
-	ShipMethodRef *ReferenceType `json:",omitempty"`
-	ShipDate      *Date          `json:",omitempty"`
-	TrackingNum   *string        `json:",omitempty"`
-	CurrencyRef   *ReferenceType `json:",omitempty"`
-	ExchangeRate  json.Number    `json:",omitempty"`
+	// PrivateNote is internal only; CustomerMemo is printed on the invoice.
+	PrivateNote          *string              `json:",omitempty"`
+	TxnTaxDetail         *TxnTaxDetail        `json:",omitempty"`
+	GlobalTaxCalculation GlobalTaxCalculation `json:",omitempty"`
+	CustomerMemo         *MemoRef             `json:",omitempty"`
+	BillAddr             *Address             `json:",omitempty"`
+	ShipAddr             *Address             `json:",omitempty"`
+	ShipFromAddr         *Address             `json:",omitempty"`
+	FreeFormAddress      *bool                `json:",omitempty"`
+	ClassRef             *ReferenceType       `json:",omitempty"`
+	SalesTermRef         *ReferenceType       `json:",omitempty"`
+	DueDate              *Date                `json:",omitempty"`
+	ShipMethodRef        *ReferenceType       `json:",omitempty"`
+	ShipDate             *Date                `json:",omitempty"`
+	TrackingNum          *string              `json:",omitempty"`
+	CurrencyRef          *ReferenceType       `json:",omitempty"`
+	ExchangeRate         json.Number          `json:",omitempty"`
+	// ApplyTaxAfterDiscount is ignored for Automated Sales Tax and non-US companies;
+	// see Preferences.CheckApplyTaxAfterDiscount.
 	ApplyTaxAfterDiscount        *bool         `json:",omitempty"`
 	PrintStatus                  *string       `json:",omitempty"`
 	EmailStatus                  *string       `json:",omitempty"`
 	BillEmail                    *EmailAddress `json:",omitempty"`
 	BillEmailCC                  *EmailAddress `json:"BillEmailCc,omitempty"`
 	BillEmailBCC                 *EmailAddress `json:"BillEmailBcc,omitempty"`
+	AllowIPNPayment              *bool         `json:",omitempty"`
 	AllowOnlineCreditCardPayment *bool         `json:",omitempty"`
 	AllowOnlineACHPayment        *bool         `json:",omitempty"`
-	Deposit                      json.Number   `json:",omitempty"`
-	DepositToAccountRef          *ReferenceType `json:",omitempty"`
-	CustomField                  []CustomField  `json:",omitempty"`
+	// Deposit is an amount paid upfront, deducted from the balance due; see SetDeposit.
+	Deposit             json.Number    `json:",omitempty"`
+	DepositToAccountRef *ReferenceType `json:",omitempty"`
+	CustomField         []CustomField  `json:",omitempty"`
+	// Extra holds fields the struct does not model yet; see ExtraFields.
+	Extra ExtraFields `json:"-"`
 }
 
 type DeliveryInfo struct {
@@ -94,6 +170,8 @@ type DeliveryInfo struct {
 type LinkedTxn struct {
 	TxnID   string `json:"TxnId"`
 	TxnType string `json:"TxnType"`
+	// TxnLineID is set when the link targets a single line, e.g. a purchase order line on a bill.
+	TxnLineID string `json:"TxnLineId,omitempty"`
 }
 
 type TxnTaxDetail struct {
@@ -110,6 +188,7 @@ type AccountBasedExpenseLineDetail struct {
 	TaxCodeRef     *ReferenceType `json:",omitempty"`
 	TaxAmount      json.Number    `json:",omitempty"`
 	BillableStatus *string        `json:",omitempty"`
+	MarkupInfo     *MarkupInfo    `json:",omitempty"`
 }
 
 // JournalEntryLineDetail holds the detail for a JournalEntry line.
@@ -140,6 +219,7 @@ type ItemBasedExpenseLineDetail struct {
 	CustomerRef     *ReferenceType `json:",omitempty"`
 	BillableStatus  *string        `json:",omitempty"`
 	TaxInclusiveAmt json.Number    `json:",omitempty"`
+	MarkupInfo      *MarkupInfo    `json:",omitempty"`
 }
 
 type Line struct {
@@ -154,14 +234,24 @@ type Line struct {
 	TaxLineDetail                 TaxLineDetail                `json:",omitempty"`
 	JournalEntryLineDetail        JournalEntryLineDetail       `json:",omitempty"`
 	ItemBasedExpenseLineDetail    ItemBasedExpenseLineDetail   `json:",omitempty"`
+	// LinkedTxn links the line to a line of another transaction, e.g. a bill line to a purchase order line.
+	LinkedTxn           []LinkedTxn          `json:",omitempty"`
+	SubTotalLineDetail  *SubTotalLineDetail  `json:",omitempty"`
+	ReimburseLineDetail *ReimburseLineDetail `json:",omitempty"`
 }
 
-// TaxLineDetail ...
+// TaxLineDetail is the detail of a TxnTaxDetail.TaxLine entry: the tax one rate contributes
+// to the transaction. The line's Amount holds the tax amount.
 type TaxLineDetail struct {
 	PercentBased     *bool       `json:",omitempty"`
 	NetAmountTaxable json.Number `json:",omitempty"`
 	TaxPercent       json.Number `json:",omitempty"`
 	TaxRateRef       ReferenceType
+	// TaxInclusiveAmount is the taxable amount including tax, for TaxInclusive transactions.
+	TaxInclusiveAmount json.Number `json:",omitempty"`
+	// OverrideDeltaAmount is the difference between the tax QuickBooks computed and the
+	// amount entered when the tax was overridden.
+	OverrideDeltaAmount json.Number `json:",omitempty"`
 }
 
 // SalesItemLineDetail ...
@@ -176,27 +266,191 @@ type SalesItemLineDetail struct {
 	TaxInclusiveAmt json.Number   `json:",omitempty"`
 	DiscountRate   json.Number    `json:",omitempty"`
 	DiscountAmt    json.Number    `json:",omitempty"`
+	MarkupInfo     *MarkupInfo    `json:",omitempty"`
 }
 
 // DiscountLineDetail ...
 type DiscountLineDetail struct {
-	PercentBased    *bool       `json:",omitempty"`
-	DiscountPercent json.Number `json:",omitempty"`
+	PercentBased       *bool          `json:",omitempty"`
+	DiscountPercent    json.Number    `json:",omitempty"`
+	DiscountAccountRef *ReferenceType `json:",omitempty"`
+}
+
+// SubTotalLineDetail ...
+type SubTotalLineDetail struct {
+	ItemRef *ReferenceType `json:",omitempty"`
+}
+
+// AmountPaid returns how much of the invoice has been paid (including any deposit and
+// applied credits), computed as TotalAmt - Balance.
+func (i *Invoice) AmountPaid() (json.Number, error) {
+	return subtractAmounts(i.TotalAmt, i.Balance)
+}
+
+// AppliedPayments returns the links to the payments applied to the invoice.
+// The amount of each payment applied to this invoice lives on the Payment; use
+// Client.FindAppliedPayments to fetch it.
+func (i *Invoice) AppliedPayments() []LinkedTxn {
+	var payments []LinkedTxn
+	for _, txn := range i.LinkedTxn {
+		if txn.TxnType == "Payment" {
+			payments = append(payments, txn)
+		}
+	}
+	return payments
+}
+
+// AppliedPayment is a payment applied to an invoice, with the part of it applied to that invoice.
+type AppliedPayment struct {
+	Payment *Payment
+	Amount  json.Number
+}
+
+// FindAppliedPayments fetches the payments applied to the given invoice, together with the
+// amount each of them applied to it.
+func (c *Client) FindAppliedPayments(invoice *Invoice) ([]AppliedPayment, error) {
+	var applied []AppliedPayment
+
+	for _, txn := range invoice.AppliedPayments() {
+		payment, err := c.FindPaymentByID(txn.TxnID)
+		if err != nil {
+			return nil, err
+		}
+
+		var amounts []json.Number
+		for _, line := range payment.Line {
+			for _, linked := range line.LinkedTxn {
+				if linked.TxnType == "Invoice" && linked.TxnID == invoice.ID {
+					amounts = append(amounts, line.Amount)
+				}
+			}
+		}
+
+		amount, err := sumAmounts(amounts...)
+		if err != nil {
+			return nil, err
+		}
+
+		applied = append(applied, AppliedPayment{Payment: payment, Amount: amount})
+	}
+
+	return applied, nil
+}
+
+// SetCustomerFacingMemo sets the memo printed on the invoice and shown to the customer.
+func (input *InvoiceCreateInput) SetCustomerFacingMemo(s string) {
+	input.CustomerMemo = &MemoRef{Value: s}
+}
+
+// PopulateAddressesFromCustomer fills BillAddr and ShipAddr from the given customer when they
+// are not set, the way QuickBooks does when it saves the invoice, so a preview matches the saved document.
+func (input *InvoiceCreateInput) PopulateAddressesFromCustomer(c *Client, customerID string) error {
+	billAddr, shipAddr, err := c.findCustomerAddresses(customerID)
+	if err != nil {
+		return err
+	}
+
+	if input.BillAddr == nil {
+		input.BillAddr = billAddr
+	}
+
+	if input.ShipAddr == nil {
+		input.ShipAddr = shipAddr
+	}
+
+	return nil
+}
+
+// SetFreeFormShipAddr sets a free-form shipping address of up to five lines. Since ShipAddr
+// is then set, PopulateAddressesFromCustomer leaves it alone, and FreeFormAddress tells
+// QuickBooks to keep the lines as entered instead of reformatting them.
+func (input *InvoiceCreateInput) SetFreeFormShipAddr(lines ...string) error {
+	addr, err := NewFreeFormAddress(lines...)
+	if err != nil {
+		return err
+	}
+
+	freeForm := true
+	input.ShipAddr = addr
+	input.FreeFormAddress = &freeForm
+	return nil
+}
+
+// SetInternalNote sets the private note, which is only visible inside QuickBooks.
+func (input *InvoiceCreateInput) SetInternalNote(s string) {
+	input.PrivateNote = &s
+}
+
+// SetDeposit records an upfront deposit against the invoice, deposited to the given account.
+// Pass an empty depositToAccountID to let QuickBooks use Undeposited Funds.
+func (input *InvoiceCreateInput) SetDeposit(amount json.Number, depositToAccountID string) error {
+	r, ok := new(big.Rat).SetString(amount.String())
+	if !ok || r.Sign() < 0 {
+		return fmt.Errorf("invalid deposit amount %q", amount)
+	}
+
+	input.Deposit = amount
+	input.DepositToAccountRef = nil
+	if depositToAccountID != "" {
+		input.DepositToAccountRef = &ReferenceType{NameValue: NameValue{Value: depositToAccountID}}
+	}
+
+	return nil
 }
 
 // CreateInvoice creates the given Invoice on the QuickBooks server, returning
 // the resulting Invoice object.
 func (c *Client) CreateInvoice(input *InvoiceCreateInput) (*Invoice, error) {
-	var resp struct {
-		Invoice Invoice
-		Time    Date
+	if err := c.prepareInvoice(input); err != nil {
+		return nil, err
 	}
 
-	if err := c.post("invoice", input, &resp, nil); err != nil {
+	return postSingle[Invoice](c, "invoice", input, nil)
+}
+
+// BuildCreateInvoicePayload prepares input the way CreateInvoice does, checks included, and
+// returns the exact JSON body it would POST, without sending it. Like CreateInvoice it may
+// read the company preferences, to apply DocNumbers or to resolve custom fields set by name,
+// and it updates input accordingly.
+func (c *Client) BuildCreateInvoicePayload(input *InvoiceCreateInput) ([]byte, error) {
+	if err := c.prepareInvoice(input); err != nil {
 		return nil, err
 	}
 
-	return &resp.Invoice, nil
+	return json.Marshal(input)
+}
+
+// prepareInvoice validates input and applies the client settings to it before it is sent.
+func (c *Client) prepareInvoice(input *InvoiceCreateInput) error {
+	if err := input.validate(); err != nil {
+		return err
+	}
+
+	if err := c.applyDocNumberPolicy(&input.DocNumber); err != nil {
+		return err
+	}
+
+	return c.resolveCustomFields(input.CustomField)
+}
+
+func (input *InvoiceCreateInput) validate() error {
+	if input.CustomerRef.Value == "" {
+		return errors.New("missing customer ref")
+	}
+
+	if len(input.Line) == 0 {
+		return errors.New("an invoice needs at least one line")
+	}
+
+	if err := validateGlobalTaxCalculation(input.GlobalTaxCalculation); err != nil {
+		return err
+	}
+
+	if err := ValidateSalesLines(input.Line); err != nil {
+		return err
+	}
+
+	return validateMemos(input.CustomerMemo, input.PrivateNote)
 }
 
 // DeleteInvoice deletes the invoice
@@ -213,121 +467,109 @@ func (c *Client) DeleteInvoice(invoice *Invoice) error {
 		return errors.New("missing id/sync token")
 	}
 
-	return c.post("invoice", invoice, nil, map[string]string{"operation": "delete"})
+	return c.postRetryingStale("invoice", "invoice/"+invoice.ID, &invoice.SyncToken, invoice, nil, map[string]string{"operation": "delete"})
 }
 
 // FindInvoices gets the full list of Invoices in the QuickBooks account.
 func (c *Client) FindInvoices() ([]Invoice, error) {
-	var resp struct {
-		QueryResponse struct {
-			Invoices      []Invoice `json:"Invoice"`
-			MaxResults    int
-			StartPosition int
-			TotalCount    int
-		}
-	}
+	return findAllPages[Invoice](c, "Invoice", "no invoices could be found")
+}
 
-	if err := c.query("SELECT COUNT(*) FROM Invoice", &resp); err != nil {
-		return nil, err
-	}
+// FindInvoicesWithOptions returns the invoices matching opts, ordered by opts.OrderBy.
+// Pass nil for opts to list them all. Set opts.Fields (e.g. Id, DocNumber, Balance, CustomerRef)
+// to skip the lines of large invoices; the returned invoices then have no Line data.
+func (c *Client) FindInvoicesWithOptions(opts *ListOptions) ([]Invoice, error) {
+	return findAllWithOptions[Invoice](c, "Invoice", false, opts)
+}
 
-	if resp.QueryResponse.TotalCount == 0 {
-		return nil, errors.New("no invoices could be found")
-	}
+// FindInvoicesByAmount returns the invoices whose TotalAmt is between min and max inclusive, on top
+// of any filter in opts. Leave min or max empty for an open-ended range.
+func (c *Client) FindInvoicesByAmount(min, max json.Number, opts *ListOptions) ([]Invoice, error) {
+	return findByAmount[Invoice](c, "Invoice", min, max, opts)
+}
+
+// FindInvoiceByID finds the invoice by the given id
+func (c *Client) FindInvoiceByID(id string) (*Invoice, error) {
+	return getSingle[Invoice](c, "invoice/"+id, nil)
+}
 
-	invoices := make([]Invoice, 0, resp.QueryResponse.TotalCount)
+// FindInvoicePDF returns the invoice with the given Id rendered as a PDF, the document
+// SendInvoice emails to the customer.
+func (c *Client) FindInvoicePDF(id string) ([]byte, error) {
+	if id == "" {
+		return nil, errors.New("missing invoice id")
+	}
 
-	for i := 0; i < resp.QueryResponse.TotalCount; i += queryPageSize {
-		query := "SELECT * FROM Invoice ORDERBY Id STARTPOSITION " + strconv.Itoa(i+1) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
+	return c.getPDF("invoice/" + id + "/pdf")
+}
 
-		if err := c.query(query, &resp); err != nil {
-			return nil, err
-		}
+// FindInvoicesByIDs returns the invoices with the given Ids. Ids that match nothing are skipped.
+func (c *Client) FindInvoicesByIDs(ids []string) ([]Invoice, error) {
+	return findByIDs[Invoice](c, "Invoice", false, ids)
+}
 
-		if resp.QueryResponse.Invoices == nil {
-			return nil, errors.New("no invoices could be found")
-		}
+// Values for the include parameter of FindInvoiceByIDWithInclude.
+const (
+	// InvoiceIncludeInvoiceLink returns InvoiceLink, the shareable link customers pay from.
+	// It needs minor version 36 or later.
+	InvoiceIncludeInvoiceLink = "invoiceLink"
+	// InvoiceIncludeAllowOnlinePayment returns the computed online payment flags.
+	InvoiceIncludeAllowOnlinePayment = "allowonlinepayment"
+)
 
-		invoices = append(invoices, resp.QueryResponse.Invoices...)
+// FindInvoiceByIDWithInclude finds the invoice by the given id, asking QuickBooks to also
+// return the computed fields named by include, e.g. InvoiceIncludeInvoiceLink.
+func (c *Client) FindInvoiceByIDWithInclude(id string, include ...string) (*Invoice, error) {
+	if len(include) == 0 {
+		return c.FindInvoiceByID(id)
 	}
-
-	return invoices, nil
+	return getSingle[Invoice](c, "invoice/"+id, map[string]string{"include": strings.Join(include, ",")})
 }
 
-// FindInvoiceByID finds the invoice by the given id
-func (c *Client) FindInvoiceByID(id string) (*Invoice, error) {
-	var resp struct {
-		Invoice Invoice
-		Time    Date
+// OnlinePaymentMethods returns the online payment methods the customer can pay the invoice
+// with: "CreditCard" and/or "ACH". It is empty when online payment is off for the invoice.
+func (i *Invoice) OnlinePaymentMethods() []string {
+	if i.AllowOnlinePayment != nil && !*i.AllowOnlinePayment {
+		return nil
 	}
 
-	if err := c.get("invoice/"+id, &resp, nil); err != nil {
-		return nil, err
+	var methods []string
+	if boolValue(i.AllowOnlineCreditCardPayment, false) {
+		methods = append(methods, "CreditCard")
 	}
-
-	return &resp.Invoice, nil
+	if boolValue(i.AllowOnlineACHPayment, false) {
+		methods = append(methods, "ACH")
+	}
+	return methods
 }
 
 // QueryInvoices accepts an SQL query and returns all invoices found using it
 func (c *Client) QueryInvoices(query string) ([]Invoice, error) {
-	var resp struct {
-		QueryResponse struct {
-			Invoices      []Invoice `json:"Invoice"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
+	invoices, err := queryEntities[Invoice](c, "Invoice", query)
+	if err != nil {
+		return invoices, err
 	}
 
-	if resp.QueryResponse.Invoices == nil {
+	if invoices == nil {
 		return nil, errors.New("could not find any invoices")
 	}
 
-	return resp.QueryResponse.Invoices, nil
+	return invoices, nil
 }
 
 // ListInvoices returns one page of Invoices ordered by Id.
 // Pass an empty pageToken to start from the beginning.
 // The returned nextPageToken is empty when there are no more results.
 func (c *Client) ListInvoices(pageToken string, pageSize int) (*ListResponse[Invoice], error) {
-	if pageSize <= 0 || pageSize > queryPageSize {
-		pageSize = queryPageSize
-	}
-
-	startPosition := 1
-	if pageToken != "" {
-		var err error
-		startPosition, err = strconv.Atoi(pageToken)
-		if err != nil {
-			return nil, fmt.Errorf("invalid page token: %v", err)
-		}
-	}
-
-	var resp struct {
-		QueryResponse struct {
-			Invoices      []Invoice `json:"Invoice"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	query := "SELECT * FROM Invoice ORDERBY Id STARTPOSITION " + strconv.Itoa(startPosition) + " MAXRESULTS " + strconv.Itoa(pageSize)
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
-	}
-
-	result := &ListResponse[Invoice]{Items: resp.QueryResponse.Invoices}
-	if len(result.Items) == pageSize {
-		result.NextPageToken = strconv.Itoa(startPosition + pageSize)
-	}
-
-	return result, nil
+	return listPage[Invoice](c, "Invoice", pageToken, pageSize)
 }
 
-// SendInvoice sends the invoice to the Invoice.BillEmail if emailAddress is left empty
+// SendInvoice emails the invoice to its BillEmail, or to emailAddress if it is not empty.
+// A send changes the invoice: QuickBooks stores emailAddress as the new BillEmail.Address
+// and updates EmailStatus and DeliveryInfo, so re-read the invoice before updating it.
+//
+// There is no CC/BCC override. The send endpoint only copies the BillEmailCc and
+// BillEmailBcc stored on the invoice; set those with UpdateInvoice before sending.
 func (c *Client) SendInvoice(invoiceId string, emailAddress string) error {
 	queryParameters := make(map[string]string)
 
@@ -359,29 +601,70 @@ func (c *Client) UpdateInvoice(invoice *Invoice) (*Invoice, error) {
 		Sparse:  true,
 	}
 
-	var invoiceData struct {
-		Invoice Invoice
-		Time    Date
+	return updateSingle[Invoice](c, "invoice", "invoice/"+invoice.ID, &invoice.SyncToken, payload)
+}
+
+// UpdateInvoiceIfUnchanged sparse-updates the invoice only if its SyncToken on the server is
+// still expectedSyncToken. Unlike UpdateInvoice it does not refetch the invoice first; if
+// someone else modified it in the meantime it returns an error matching ErrStaleObject
+// (a *StaleObjectError) instead of overwriting their changes.
+func (c *Client) UpdateInvoiceIfUnchanged(invoice *Invoice, expectedSyncToken string) (*Invoice, error) {
+	if invoice.ID == "" {
+		return nil, errors.New("missing invoice id")
 	}
 
-	if err = c.post("invoice", payload, &invoiceData, nil); err != nil {
-		return nil, err
+	if expectedSyncToken == "" {
+		return nil, errors.New("missing sync token")
+	}
+
+	invoice.SyncToken = expectedSyncToken
+
+	payload := struct {
+		*Invoice
+		Sparse bool `json:"sparse"`
+	}{
+		Invoice: invoice,
+		Sparse:  true,
 	}
 
-	return &invoiceData.Invoice, err
+	// The whole point is to fail on a stale SyncToken, so never let RetryOnStale retry it.
+	noRetry := c.WithContext(c.context())
+	noRetry.RetryOnStale = false
+
+	updated, err := updateSingle[Invoice](noRetry, "invoice", "invoice/"+invoice.ID, &invoice.SyncToken, payload)
+	if err != nil {
+		return nil, asStaleObject(err)
+	}
+
+	return updated, nil
 }
 
+// VoidInvoice voids the invoice. QuickBooks keeps a voided invoice with its lines but zeroes
+// its TotalAmt and Balance and sets its PrivateNote to "Voided". The request carries only the
+// Id and SyncToken of invoice. On success *invoice is replaced by the voided invoice QuickBooks
+// returned, so IsVoided reports true.
 func (c *Client) VoidInvoice(invoice *Invoice) error {
 	if invoice.ID == "" {
 		return errors.New("missing invoice id")
 	}
 
-	existingInvoice, err := c.FindInvoiceByID(invoice.ID)
+	syncToken, err := c.currentSyncToken("invoice/" + invoice.ID)
 	if err != nil {
 		return err
 	}
 
-	invoice.SyncToken = existingInvoice.SyncToken
+	payload := &sparseFields{ID: invoice.ID, SyncToken: syncToken}
+
+	var body json.RawMessage
+	if err := c.postRetryingStale("invoice", "invoice/"+invoice.ID, &payload.SyncToken, payload, &body, map[string]string{"operation": "void"}); err != nil {
+		return err
+	}
+
+	voided, err := decodeSingle[Invoice](body)
+	if err != nil {
+		return err
+	}
 
-	return c.post("invoice", invoice, nil, map[string]string{"operation": "void"})
+	*invoice = *voided
+	return nil
 }
@@ -0,0 +1,344 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvoice(t *testing.T) {
+	byteValue := json.RawMessage(`
+{
+  "Invoice": {
+    "TxnDate": "2014-09-19",
+    "domain": "QBO",
+    "PrintStatus": "NeedToPrint",
+    "EInvoiceStatus": "Viewed",
+    "TotalAmt": 362.07,
+    "Line": [
+      {
+        "LineNum": 1,
+        "Amount": 362.07,
+        "SalesItemLineDetail": {
+          "TaxCodeRef": {"value": "TAX"},
+          "ItemRef": {"name": "Services", "value": "1"}
+        },
+        "Id": "1",
+        "DetailType": "SalesItemLineDetail"
+      }
+    ],
+    "DueDate": "2014-10-19",
+    "ApplyTaxAfterDiscount": false,
+    "DocNumber": "1037",
+    "sparse": false,
+    "CustomerMemo": {"value": "Thank you for your business and have a great day!"},
+    "Deposit": 50,
+    "Balance": 112.07,
+    "CustomerRef": {"name": "Sonnenschein Family Store", "value": "24"},
+    "SyncToken": "0",
+    "LinkedTxn": [
+      {"TxnId": "100", "TxnType": "Estimate"},
+      {"TxnId": "155", "TxnType": "Payment"}
+    ],
+    "EmailStatus": "NotSet",
+    "Id": "130",
+    "MetaData": {
+      "CreateTime": "2014-09-19T13:16:17-07:00",
+      "LastUpdatedTime": "2014-09-19T13:16:17-07:00"
+    }
+  },
+  "time": "2015-07-24T10:48:27.082-07:00"
+}`)
+
+	var r struct {
+		Invoice Invoice
+		Time    Date
+	}
+	require.NoError(t, json.Unmarshal(byteValue, &r))
+
+	invoice := r.Invoice
+	assert.Equal(t, "130", invoice.ID)
+	assert.Equal(t, "24", invoice.CustomerRef.Value)
+	require.NotNil(t, invoice.DocNumber)
+	assert.Equal(t, "1037", *invoice.DocNumber)
+	assert.Equal(t, json.Number("362.07"), invoice.TotalAmt)
+	assert.Equal(t, json.Number("112.07"), invoice.Balance)
+	assert.Equal(t, json.Number("50"), invoice.Deposit)
+	require.Len(t, invoice.Line, 1)
+	assert.Equal(t, "1", invoice.Line[0].SalesItemLineDetail.ItemRef.Value)
+
+	paid, err := invoice.AmountPaid()
+	require.NoError(t, err)
+	assert.Equal(t, json.Number("250.00"), paid)
+
+	assert.Equal(t, []LinkedTxn{{TxnID: "155", TxnType: "Payment"}}, invoice.AppliedPayments())
+
+	status, ok := invoice.EInvoiceState()
+	assert.True(t, ok)
+	assert.Equal(t, EInvoiceStatusViewed, status)
+	assert.True(t, invoice.EInvoiceTransmitted())
+	assert.False(t, (&Invoice{}).EInvoiceTransmitted())
+}
+
+func TestInvoiceCreateInputValidate(t *testing.T) {
+	_, err := (&Client{}).BuildCreateInvoicePayload(&InvoiceCreateInput{Line: []Line{{Amount: "10", DetailType: SalesItemLineDetailType}}})
+	assert.EqualError(t, err, "missing customer ref")
+
+	_, err = (&Client{}).BuildCreateInvoicePayload(&InvoiceCreateInput{CustomerRef: ReferenceType{NameValue: NameValue{Value: "1"}}})
+	assert.EqualError(t, err, "an invoice needs at least one line")
+}
+
+func TestInvoiceCreateInputGlobalTaxCalculation(t *testing.T) {
+	valid := func() *InvoiceCreateInput {
+		return &InvoiceCreateInput{
+			CustomerRef: ReferenceType{NameValue: NameValue{Value: "1"}},
+			Line:        []Line{{Amount: "10", DetailType: SalesItemLineDetailType}},
+		}
+	}
+
+	input := valid()
+	input.GlobalTaxCalculation = TaxInclusive
+	payload, err := (&Client{}).BuildCreateInvoicePayload(input)
+	require.NoError(t, err)
+	assert.Contains(t, string(payload), `"GlobalTaxCalculation":"TaxInclusive"`)
+
+	payload, err = (&Client{}).BuildCreateInvoicePayload(valid())
+	require.NoError(t, err)
+	assert.NotContains(t, string(payload), "GlobalTaxCalculation")
+
+	assert.True(t, TaxNotApplicable.IsValid())
+	assert.False(t, GlobalTaxCalculation("TaxIncluded").IsValid())
+}
+
+func TestPopulateAddressesFromCustomer(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v3/company/test-realm/customer/24", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Customer":{"Id":"24","DisplayName":"Sonnenschein Family Store","BillAddr":{"Id":"95","Line1":"Russ Sonnenschein","City":"Middlefield","PostalCode":"94303"},"ShipAddr":{"Id":"96","Line1":"5647 Cypress Hill Ave.","City":"Middlefield"}},"time":"2015-07-24T10:48:27.082-07:00"}`))
+	})
+
+	shipAddr := &Address{Line1: "Dock 4"}
+	input := &InvoiceCreateInput{ShipAddr: shipAddr}
+	require.NoError(t, input.PopulateAddressesFromCustomer(client, "24"))
+
+	require.NotNil(t, input.BillAddr)
+	assert.Equal(t, "Russ Sonnenschein", input.BillAddr.Line1)
+	assert.Empty(t, input.BillAddr.ID)
+	assert.Same(t, shipAddr, input.ShipAddr)
+}
+
+func TestInvoiceCreateInputSetDeposit(t *testing.T) {
+	input := &InvoiceCreateInput{
+		CustomerRef: ReferenceType{NameValue: NameValue{Value: "1"}},
+		Line:        []Line{{Amount: "100", DetailType: SalesItemLineDetailType}},
+	}
+
+	require.NoError(t, input.SetDeposit("25.00", "35"))
+	payload, err := (&Client{}).BuildCreateInvoicePayload(input)
+	require.NoError(t, err)
+	assert.Contains(t, string(payload), `"Deposit":25.00,"DepositToAccountRef":{"value":"35"}`)
+
+	require.NoError(t, input.SetDeposit("10", ""))
+	assert.Nil(t, input.DepositToAccountRef)
+
+	assert.Error(t, input.SetDeposit("-5", ""))
+	assert.Error(t, input.SetDeposit("ten", ""))
+	assert.Equal(t, json.Number("10"), input.Deposit)
+
+	var invoice Invoice
+	require.NoError(t, json.Unmarshal([]byte(`{"Id": "1", "Deposit": 25, "AllowIPNPayment": false, "Balance": 75}`), &invoice))
+	assert.Equal(t, json.Number("25"), invoice.Deposit)
+	assert.False(t, *invoice.AllowIPNPayment)
+}
+
+func TestSendOverridesRecipient(t *testing.T) {
+	var requests []*http.Request
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		requests = append(requests, r)
+		w.Write([]byte(`{}`))
+	})
+
+	require.NoError(t, client.SendInvoice("130", ""))
+	require.NoError(t, client.SendInvoice("130", "billing+ap@example.com"))
+	require.NoError(t, client.SendSalesReceipt("11", "front-desk@example.com"))
+	require.NoError(t, client.SendCreditMemo("12", ""))
+	require.NoError(t, client.SendRefundReceipt("13", "returns@example.com"))
+
+	require.Len(t, requests, 5)
+	assert.Equal(t, "/v3/company/test-realm/invoice/130/send", requests[0].URL.Path)
+	assert.False(t, requests[0].URL.Query().Has("sendTo"))
+	assert.Equal(t, "billing+ap@example.com", requests[1].URL.Query().Get("sendTo"))
+	assert.Equal(t, "/v3/company/test-realm/salesreceipt/11/send", requests[2].URL.Path)
+	assert.Equal(t, "front-desk@example.com", requests[2].URL.Query().Get("sendTo"))
+	assert.Equal(t, "/v3/company/test-realm/creditmemo/12/send", requests[3].URL.Path)
+	assert.False(t, requests[3].URL.Query().Has("sendTo"))
+	assert.Equal(t, "/v3/company/test-realm/refundreceipt/13/send", requests[4].URL.Path)
+	assert.Equal(t, "returns@example.com", requests[4].URL.Query().Get("sendTo"))
+}
+
+func TestInvoiceCreateInputSetFreeFormShipAddr(t *testing.T) {
+	input := &InvoiceCreateInput{}
+	require.NoError(t, input.SetFreeFormShipAddr("Loading dock B", "12 Harbour Rd", "Auckland 1010"))
+
+	b, err := json.Marshal(input)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"ShipAddr":{"Line1":"Loading dock B","Line2":"12 Harbour Rd","Line3":"Auckland 1010"}`)
+	assert.Contains(t, string(b), `"FreeFormAddress":true`)
+
+	assert.Error(t, input.SetFreeFormShipAddr())
+	_, err = NewFreeFormAddress("1", "2", "3", "4", "5", "6")
+	assert.Error(t, err)
+}
+
+func TestFindInvoiceByIDWithInclude(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v3/company/test-realm/invoice/130", r.URL.Path)
+		assert.Equal(t, "invoiceLink,allowonlinepayment", r.URL.Query().Get("include"))
+		w.Write([]byte(`{"Invoice": {"Id": "130", "InvoiceLink": "https://intuit.me/q/abc", "AllowOnlinePayment": true,
+  "AllowOnlineCreditCardPayment": true, "AllowOnlineACHPayment": false}}`))
+	})
+
+	invoice, err := client.FindInvoiceByIDWithInclude("130", InvoiceIncludeInvoiceLink, InvoiceIncludeAllowOnlinePayment)
+	require.NoError(t, err)
+	assert.Equal(t, "https://intuit.me/q/abc", *invoice.InvoiceLink)
+	assert.Equal(t, []string{"CreditCard"}, invoice.OnlinePaymentMethods())
+
+	disabled, enabled := false, true
+	assert.Nil(t, (&Invoice{AllowOnlinePayment: &disabled, AllowOnlineACHPayment: &enabled}).OnlinePaymentMethods())
+}
+
+func TestVoidInvoiceAndPayment(t *testing.T) {
+	var operations []string
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v3/company/test-realm/invoice/130":
+			w.Write([]byte(`{"Invoice": {"Id": "130", "SyncToken": "2", "TotalAmt": 150, "Balance": 150}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/v3/company/test-realm/payment/55":
+			w.Write([]byte(`{"Payment": {"Id": "55", "SyncToken": "0", "TotalAmt": 150}}`))
+		case r.URL.Path == "/v3/company/test-realm/invoice":
+			operations = append(operations, "invoice "+r.URL.Query().Get("operation"))
+			var body map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, map[string]any{"Id": "130", "SyncToken": "2", "sparse": true}, body)
+			w.Write([]byte(`{"Invoice": {"Id": "130", "SyncToken": "3", "TotalAmt": 0, "Balance": 0, "PrivateNote": "Voided",
+				"Line": [{"Amount": 0, "DetailType": "SalesItemLineDetail", "SalesItemLineDetail": {"ItemRef": {"value": "1"}}}]}}`))
+		case r.URL.Path == "/v3/company/test-realm/payment":
+			operations = append(operations, "payment "+r.URL.Query().Get("operation")+" "+r.URL.Query().Get("include"))
+			var body map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, map[string]any{"Id": "55", "SyncToken": "0", "sparse": true}, body)
+			w.Write([]byte(`{"Payment": {"Id": "55", "SyncToken": "1", "TotalAmt": 0, "PrivateNote": "Voided"}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	invoice := &Invoice{
+		ID:        "130",
+		TotalAmt:  "150",
+		LinkedTxn: []LinkedTxn{{TxnID: "55", TxnType: "Payment"}},
+		Line:      []Line{{Amount: "150", Description: "Consulting", DetailType: SalesItemLineDetailType}},
+	}
+	assert.False(t, invoice.IsVoided())
+	require.NoError(t, client.VoidInvoice(invoice))
+	assert.True(t, invoice.IsVoided())
+	assert.Equal(t, "3", invoice.SyncToken)
+	assert.Equal(t, json.Number("0"), invoice.TotalAmt)
+	assert.Equal(t, json.Number("0"), invoice.Balance)
+	assert.Nil(t, invoice.LinkedTxn)
+	require.Len(t, invoice.Line, 1)
+	assert.Empty(t, invoice.Line[0].Description)
+
+	payment := &Payment{ID: "55", Line: []PaymentLine{{Amount: "150", LinkedTxn: []LinkedTxn{{TxnID: "130", TxnType: "Invoice"}}}}}
+	require.NoError(t, client.VoidPayment(payment))
+	assert.True(t, payment.IsVoided())
+	assert.Equal(t, "1", payment.SyncToken)
+	assert.Nil(t, payment.Line)
+
+	assert.Equal(t, []string{"invoice void", "payment update void"}, operations)
+	assert.Error(t, client.VoidPayment(&Payment{}))
+}
+
+func TestFindAppliedPayments(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/company/test-realm/payment/200":
+			w.Write([]byte(`{"Payment":{"Id":"200","SyncToken":"0","TotalAmt":150,"Line":[
+				{"Amount":100,"LinkedTxn":[{"TxnId":"130","TxnType":"Invoice"}]},
+				{"Amount":50,"LinkedTxn":[{"TxnId":"131","TxnType":"Invoice"}]}
+			]}}`))
+		case "/v3/company/test-realm/payment/201":
+			w.Write([]byte(`{"Payment":{"Id":"201","SyncToken":"0","TotalAmt":20,"Line":[
+				{"Amount":20,"LinkedTxn":[{"TxnId":"130","TxnType":"Invoice"}]}
+			]}}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	invoice := &Invoice{
+		ID: "130",
+		LinkedTxn: []LinkedTxn{
+			{TxnID: "200", TxnType: "Payment"},
+			{TxnID: "9", TxnType: "Estimate"},
+			{TxnID: "73", TxnType: "CreditMemo"},
+			{TxnID: "201", TxnType: "Payment"},
+		},
+	}
+
+	applied, err := client.FindAppliedPayments(invoice)
+	require.NoError(t, err)
+	require.Len(t, applied, 2)
+	assert.Equal(t, "200", applied[0].Payment.ID)
+	assert.Equal(t, json.Number("100"), applied[0].Amount)
+	assert.Equal(t, "201", applied[1].Payment.ID)
+	assert.Equal(t, json.Number("20"), applied[1].Amount)
+}
+
+func TestBuildCreateInvoicePayloadMatchesRequest(t *testing.T) {
+	preferences := strings.Replace(customFieldPreferences, `"SalesFormsPrefs": {`, `"SalesFormsPrefs": {"CustomTxnNumbers": false, `, 1)
+
+	var posted []byte
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/company/test-realm/preferences":
+			w.Write([]byte(preferences))
+		case "/v3/company/test-realm/invoice":
+			var err error
+			posted, err = io.ReadAll(r.Body)
+			require.NoError(t, err)
+			w.Write([]byte(`{"Invoice": {"Id": "130"}}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	})
+	client.DocNumbers = DocNumberStrip
+
+	newInput := func() *InvoiceCreateInput {
+		docNumber := "INV-7"
+		input := &InvoiceCreateInput{
+			CustomerRef: ReferenceType{NameValue: NameValue{Value: "1"}},
+			DocNumber:   &docNumber,
+			Line:        []Line{{Amount: "10", DetailType: SalesItemLineDetailType}},
+		}
+		input.SetCustomField("Crew #", "7")
+		return input
+	}
+
+	payload, err := client.BuildCreateInvoicePayload(newInput())
+	require.NoError(t, err)
+	assert.NotContains(t, string(payload), "DocNumber")
+	assert.Contains(t, string(payload), `"DefinitionId":"1"`)
+
+	_, err = client.CreateInvoice(newInput())
+	require.NoError(t, err)
+	assert.Equal(t, string(payload), string(posted))
+}
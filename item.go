@@ -3,12 +3,14 @@ package quickbooks
 import (
 	"encoding/json"
 	"errors"
-	"strconv"
+	"strings"
 )
 
 // Item represents a QuickBooks Item object as returned by the API (a product or service).
 // Read-only fields (Id, SyncToken, MetaData, QtyOnHand) are populated by the service.
 type Item struct {
+	EntityMeta
+
 	ID          string      `json:"Id,omitempty"`
 	SyncToken   string      `json:",omitempty"`
 	MetaData    *MetaData   `json:",omitempty"`
@@ -32,6 +34,21 @@ type Item struct {
 	PurchaseTaxCodeRef  *ReferenceType `json:",omitempty"`
 }
 
+// IsActive reports whether the item is active, treating a missing Active field as active.
+func (i *Item) IsActive() bool {
+	return boolValue(i.Active, true)
+}
+
+// UnitPriceFloat returns UnitPrice as a float64, or 0 if it is not set.
+func (i *Item) UnitPriceFloat() float64 {
+	return numberFloat(i.UnitPrice)
+}
+
+// QtyOnHandFloat returns QtyOnHand as a float64, or 0 if it is not set.
+func (i *Item) QtyOnHandFloat() float64 {
+	return numberFloat(i.QtyOnHand)
+}
+
 // ItemCreateInput contains the writable fields accepted when creating an Item.
 // Name and Type are required; account refs are conditionally required based on Type.
 type ItemCreateInput struct {
@@ -50,96 +67,205 @@ type ItemCreateInput struct {
 	PurchaseCost        json.Number    `json:",omitempty"`
 	AssetAccountRef     *ReferenceType `json:",omitempty"`
 	TrackQtyOnHand      *bool          `json:",omitempty"`
-	SalesTaxCodeRef     *ReferenceType `json:",omitempty"`
-	PurchaseTaxCodeRef  *ReferenceType `json:",omitempty"`
+	// QtyOnHand and InvStartDate are required for inventory items.
+	QtyOnHand          json.Number    `json:",omitempty"`
+	InvStartDate       *Date          `json:",omitempty"`
+	SalesTaxCodeRef    *ReferenceType `json:",omitempty"`
+	PurchaseTaxCodeRef *ReferenceType `json:",omitempty"`
+	// Extra holds fields the struct does not model yet; see ExtraFields.
+	Extra ExtraFields `json:"-"`
 }
 
-// CreateItem creates the given Item on the QuickBooks server, returning
-// the resulting Item object.
-func (c *Client) CreateItem(input *ItemCreateInput) (*Item, error) {
-	var resp struct {
-		Item Item
-		Time Date
-	}
+// Item types.
+const (
+	InventoryItemType    = "Inventory"
+	ServiceItemType      = "Service"
+	NonInventoryItemType = "NonInventory"
+)
+
+// MissingItemAccountsError is returned by the typed item create helpers when account refs
+// required for the item type are not set.
+type MissingItemAccountsError struct {
+	Type    string
+	Missing []string
+}
+
+// Error implements the error interface.
+func (e *MissingItemAccountsError) Error() string {
+	return e.Type + " item is missing " + strings.Join(e.Missing, ", ")
+}
+
+// CreateInventoryItem creates an inventory item. IncomeAccountRef, ExpenseAccountRef (cost of
+// goods sold) and AssetAccountRef are required, as are QtyOnHand and InvStartDate.
+// Type and TrackQtyOnHand are set for the caller.
+func (c *Client) CreateInventoryItem(input *ItemCreateInput) (*Item, error) {
+	input.Type = InventoryItemType
+	trackQtyOnHand := true
+	input.TrackQtyOnHand = &trackQtyOnHand
 
-	if err := c.post("item", input, &resp, nil); err != nil {
+	if err := checkItemAccounts(input); err != nil {
 		return nil, err
 	}
 
-	return &resp.Item, nil
+	if input.QtyOnHand == "" || input.InvStartDate == nil {
+		return nil, errors.New("inventory item requires QtyOnHand and InvStartDate")
+	}
+
+	return c.CreateItem(input)
 }
 
-// FindItems gets the full list of Items in the QuickBooks account.
-func (c *Client) FindItems() ([]Item, error) {
-	var resp struct {
-		QueryResponse struct {
-			Items         []Item `json:"Item"`
-			MaxResults    int
-			StartPosition int
-			TotalCount    int
-		}
+// CreateServiceItem creates a service item. It needs an IncomeAccountRef to be sold, an
+// ExpenseAccountRef to be purchased, or both. Type is set for the caller.
+func (c *Client) CreateServiceItem(input *ItemCreateInput) (*Item, error) {
+	input.Type = ServiceItemType
+	if err := checkItemAccounts(input); err != nil {
+		return nil, err
 	}
+	return c.CreateItem(input)
+}
 
-	if err := c.query("SELECT COUNT(*) FROM Item", &resp); err != nil {
+// CreateNonInventoryItem creates a non-inventory item. It needs an IncomeAccountRef to be
+// sold, an ExpenseAccountRef to be purchased, or both. Type is set for the caller.
+func (c *Client) CreateNonInventoryItem(input *ItemCreateInput) (*Item, error) {
+	input.Type = NonInventoryItemType
+	if err := checkItemAccounts(input); err != nil {
 		return nil, err
 	}
+	return c.CreateItem(input)
+}
+
+// checkItemAccounts returns a *MissingItemAccountsError if input lacks account refs its Type requires.
+func checkItemAccounts(input *ItemCreateInput) error {
+	isSet := func(ref *ReferenceType) bool { return ref != nil && ref.Value != "" }
+
+	var missing []string
+	switch input.Type {
+	case InventoryItemType:
+		if !isSet(input.IncomeAccountRef) {
+			missing = append(missing, "IncomeAccountRef")
+		}
+		if !isSet(input.ExpenseAccountRef) {
+			missing = append(missing, "ExpenseAccountRef")
+		}
+		if !isSet(input.AssetAccountRef) {
+			missing = append(missing, "AssetAccountRef")
+		}
+	default:
+		if !isSet(input.IncomeAccountRef) && !isSet(input.ExpenseAccountRef) {
+			missing = append(missing, "IncomeAccountRef or ExpenseAccountRef")
+		}
+	}
 
-	if resp.QueryResponse.TotalCount == 0 {
-		return nil, errors.New("no items could be found")
+	if len(missing) > 0 {
+		return &MissingItemAccountsError{Type: input.Type, Missing: missing}
 	}
+	return nil
+}
 
-	items := make([]Item, 0, resp.QueryResponse.TotalCount)
+// defaultItemAccountSubTypes are the account sub-types ResolveDefaultItemAccounts picks from.
+var defaultItemAccountSubTypes = map[string]struct{ income, expense, asset string }{
+	InventoryItemType:    {"SalesOfProductIncome", "SuppliesMaterialsCogs", "Inventory"},
+	ServiceItemType:      {"ServiceFeeIncome", "", ""},
+	NonInventoryItemType: {"SalesOfProductIncome", "", ""},
+}
 
-	for i := 0; i < resp.QueryResponse.TotalCount; i += queryPageSize {
-		query := "SELECT * FROM Item ORDERBY Id STARTPOSITION " + strconv.Itoa(i+1) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
+// ResolveDefaultItemAccounts fills the account refs of input that its Type requires and that
+// are not set yet with the first active account of the matching sub-type (e.g. the company's
+// "Inventory Asset" account), similar to the defaults the QuickBooks UI proposes.
+// Refs for which no account exists are left empty; the create helpers then report them.
+func (c *Client) ResolveDefaultItemAccounts(input *ItemCreateInput) error {
+	subTypes, ok := defaultItemAccountSubTypes[input.Type]
+	if !ok {
+		return errors.New("unknown item type " + input.Type)
+	}
 
-		if err := c.query(query, &resp); err != nil {
-			return nil, err
+	resolve := func(ref **ReferenceType, subType string) error {
+		if subType == "" || (*ref != nil && (*ref).Value != "") {
+			return nil
 		}
 
-		if resp.QueryResponse.Items == nil {
-			return nil, errors.New("no items could be found")
+		accounts, err := c.FindAccountsWithOptions(&ListOptions{
+			Filter:     "AccountSubType = '" + subType + "'",
+			MaxResults: 1,
+		})
+		if err != nil {
+			return err
 		}
 
-		items = append(items, resp.QueryResponse.Items...)
+		if len(accounts) > 0 {
+			*ref = &ReferenceType{NameValue: NameValue{Value: accounts[0].ID, Name: accounts[0].Name}}
+		}
+		return nil
 	}
 
-	return items, nil
+	if err := resolve(&input.IncomeAccountRef, subTypes.income); err != nil {
+		return err
+	}
+	if err := resolve(&input.ExpenseAccountRef, subTypes.expense); err != nil {
+		return err
+	}
+	return resolve(&input.AssetAccountRef, subTypes.asset)
 }
 
-// FindItemByID returns an item with a given Id.
-func (c *Client) FindItemByID(id string) (*Item, error) {
-	var resp struct {
-		Item Item
-		Time Date
+// CreateItem creates the given Item on the QuickBooks server, returning
+// the resulting Item object.
+func (c *Client) CreateItem(input *ItemCreateInput) (*Item, error) {
+	return postSingle[Item](c, "item", input, nil)
+}
+
+// UpsertItemByName makes sure an item named input.Name exists with the fields set in input.
+// It creates the item when no item (active or not) has that name, and otherwise sparse-updates
+// the existing one, leaving fields that input does not set alone. The bool reports whether the
+// item was created. Importers syncing a catalog can call it repeatedly with the same input.
+func (c *Client) UpsertItemByName(input *ItemCreateInput) (*Item, bool, error) {
+	if input.Name == "" {
+		return nil, false, errors.New("missing item name")
 	}
 
-	if err := c.get("item/"+id, &resp, nil); err != nil {
-		return nil, err
+	find := func() (string, error) {
+		items, err := queryEntities[Item](c, "Item", "SELECT Id FROM Item WHERE "+refEquals("Name", input.Name)+" AND Active IN (true, false)")
+		if err != nil || len(items) == 0 {
+			return "", err
+		}
+		return items[0].ID, nil
 	}
 
-	return &resp.Item, nil
+	return upsert[Item](c, "item", input, find, func() (*Item, error) { return c.CreateItem(input) })
+}
+
+// FindItems gets the full list of Items in the QuickBooks account.
+func (c *Client) FindItems() ([]Item, error) {
+	return findAllPages[Item](c, "Item", "no items could be found")
+}
+
+// FindItemsWithOptions returns the items matching opts, ordered by opts.OrderBy.
+// Pass nil for opts to list them all.
+func (c *Client) FindItemsWithOptions(opts *ListOptions) ([]Item, error) {
+	return findAllWithOptions[Item](c, "Item", true, opts)
+}
+
+// FindItemByID returns an item with a given Id.
+func (c *Client) FindItemByID(id string) (*Item, error) {
+	return getSingle[Item](c, "item/"+id, nil)
+}
+
+// FindItemsByIDs returns the items with the given Ids, inactive ones included. Ids that match nothing are skipped.
+func (c *Client) FindItemsByIDs(ids []string) ([]Item, error) {
+	return findByIDs[Item](c, "Item", true, ids)
 }
 
 // QueryItems accepts an SQL query and returns all items found using it
 func (c *Client) QueryItems(query string) ([]Item, error) {
-	var resp struct {
-		QueryResponse struct {
-			Items         []Item `json:"Item"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
+	items, err := queryEntities[Item](c, "Item", query)
+	if err != nil {
+		return items, err
 	}
 
-	if resp.QueryResponse.Items == nil {
+	if items == nil {
 		return nil, errors.New("could not find any items")
 	}
 
-	return resp.QueryResponse.Items, nil
+	return items, nil
 }
 
 // DeleteItem deletes the item.
@@ -148,7 +274,7 @@ func (c *Client) DeleteItem(item *Item) error {
 		return errors.New("missing id/sync token")
 	}
 
-	return c.post("item", item, nil, map[string]string{"operation": "delete"})
+	return c.postRetryingStale("item", "item/"+item.ID, &item.SyncToken, item, nil, map[string]string{"operation": "delete"})
 }
 
 // UpdateItem updates the item
@@ -172,14 +298,5 @@ func (c *Client) UpdateItem(item *Item) (*Item, error) {
 		Sparse: true,
 	}
 
-	var itemData struct {
-		Item Item
-		Time Date
-	}
-
-	if err = c.post("item", payload, &itemData, nil); err != nil {
-		return nil, err
-	}
-
-	return &itemData.Item, err
+	return updateSingle[Item](c, "item", "item/"+item.ID, &item.SyncToken, payload)
 }
@@ -0,0 +1,109 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateInventoryItemMissingAccounts(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL.Path)
+	})
+
+	_, err := client.CreateInventoryItem(&ItemCreateInput{
+		Name:             "Garden Hose",
+		IncomeAccountRef: &ReferenceType{NameValue: NameValue{Value: "79"}},
+	})
+
+	var missingErr *MissingItemAccountsError
+	require.True(t, errors.As(err, &missingErr))
+	assert.Equal(t, InventoryItemType, missingErr.Type)
+	assert.Equal(t, []string{"ExpenseAccountRef", "AssetAccountRef"}, missingErr.Missing)
+	assert.EqualError(t, err, "Inventory item is missing ExpenseAccountRef, AssetAccountRef")
+
+	_, err = client.CreateServiceItem(&ItemCreateInput{Name: "Design"})
+	assert.EqualError(t, err, "Service item is missing IncomeAccountRef or ExpenseAccountRef")
+}
+
+func TestResolveDefaultItemAccounts(t *testing.T) {
+	accounts := map[string]string{
+		"SalesOfProductIncome":  `{"Id":"79","Name":"Sales of Product Income"}`,
+		"SuppliesMaterialsCogs": `{"Id":"80","Name":"Cost of Goods Sold"}`,
+		"Inventory":             `{"Id":"81","Name":"Inventory Asset"}`,
+	}
+
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+
+		w.Header().Set("Content-Type", "application/json")
+		for subType, account := range accounts {
+			if strings.Contains(query, "AccountSubType = '"+subType+"'") {
+				w.Write([]byte(`{"QueryResponse":{"Account":[` + account + `]}}`))
+				return
+			}
+		}
+		t.Errorf("unexpected query %s", query)
+	})
+
+	input := &ItemCreateInput{
+		Name:             "Garden Hose",
+		Type:             InventoryItemType,
+		IncomeAccountRef: &ReferenceType{NameValue: NameValue{Value: "1"}},
+	}
+	require.NoError(t, client.ResolveDefaultItemAccounts(input))
+
+	assert.Equal(t, "1", input.IncomeAccountRef.Value)
+	assert.Equal(t, "80", input.ExpenseAccountRef.Value)
+	assert.Equal(t, "Inventory Asset", input.AssetAccountRef.Name)
+}
+
+func TestUpsertItemByNameDuplicateRace(t *testing.T) {
+	queries := 0
+	var update map[string]any
+
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == "/v3/company/test-realm/query":
+			assert.Equal(t, "SELECT Id FROM Item WHERE Name = 'Bob''s Hose' AND Active IN (true, false)", r.URL.Query().Get("query"))
+			queries++
+			if queries == 1 {
+				w.Write([]byte(`{"QueryResponse":{}}`))
+				return
+			}
+			w.Write([]byte(`{"QueryResponse":{"Item":[{"Id":"5"}]}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/v3/company/test-realm/item/5":
+			w.Write([]byte(`{"Item":{"Id":"5","SyncToken":"3","Name":"Bob's Hose"}}`))
+		case r.Method == http.MethodPost && update == nil && queries == 1:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"Fault":{"Error":[{"Message":"Duplicate Name Exists Error","Detail":"The name supplied already exists.","code":"6240"}],"type":"ValidationFault"}}`))
+		case r.Method == http.MethodPost:
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&update))
+			w.Write([]byte(`{"Item":{"Id":"5","SyncToken":"4","Name":"Bob's Hose","UnitPrice":12.5}}`))
+		default:
+			t.Errorf("unexpected %s to %s", r.Method, r.URL.Path)
+		}
+	})
+
+	item, created, err := client.UpsertItemByName(&ItemCreateInput{
+		Name:      "Bob's Hose",
+		Type:      ServiceItemType,
+		UnitPrice: "12.5",
+	})
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, "4", item.SyncToken)
+	assert.Equal(t, 2, queries)
+
+	assert.Equal(t, map[string]any{
+		"Id": "5", "SyncToken": "3", "sparse": true,
+		"Name": "Bob's Hose", "Type": "Service", "UnitPrice": 12.5,
+	}, update)
+}
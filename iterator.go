@@ -0,0 +1,135 @@
+package quickbooks
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+)
+
+// baseQueryPattern splits a query into the entity, the WHERE clause (with its leading space)
+// and the ORDERBY clause.
+var baseQueryPattern = regexp.MustCompile(`(?is)^\s*SELECT\s+.+?\s+FROM\s+(\w+)((?:\s+WHERE\s+.+?)?)(?:\s+ORDERBY\s+(.+?))?\s*$`)
+
+// pagingClausePattern matches the clauses an Iterator adds itself.
+var pagingClausePattern = regexp.MustCompile(`(?i)\s(STARTPOSITION|MAXRESULTS)\s`)
+
+// Iterator walks the results of a query one object at a time, fetching a page of
+// queryPageSize objects whenever it runs out, so only one page is held in memory. Use it like
+// bufio.Scanner:
+//
+//	it := quickbooks.Iterate[quickbooks.Bill](client, "SELECT * FROM Bill WHERE Balance > '0'")
+//	for it.Next() {
+//		bill := it.Value()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// Stopping early is fine: no request is made beyond the current page.
+type Iterator[T any] struct {
+	c        *Client
+	entity   string
+	query    string
+	where    string
+	err      error
+	page     []T
+	pos      int
+	start    int
+	lastPage bool
+	total    *int
+	// pageErr is the Failure that came with the current page; Err reports it once the
+	// page's rows have been yielded.
+	pageErr error
+}
+
+// Iterate returns an Iterator over the results of baseQuery, a query without STARTPOSITION
+// and MAXRESULTS, e.g. "SELECT * FROM Invoice WHERE TxnDate >= '2024-01-01'". Results are
+// ordered like ListOptions.OrderBy: by the query's ORDERBY clause with Id appended as a
+// tie-breaker, or by Id if it has none. T is the entity struct, e.g. Invoice; use
+// json.RawMessage to get the objects as QuickBooks returns them.
+//
+// An invalid query is reported by the first call to Next, through Err.
+func Iterate[T any](c *Client, baseQuery string) *Iterator[T] {
+	it := &Iterator[T]{c: c, start: 1}
+
+	m := baseQueryPattern.FindStringSubmatchIndex(baseQuery)
+	switch {
+	case m == nil:
+		it.err = errors.New("query must have the form SELECT ... FROM <entity> [WHERE ...] [ORDERBY ...]")
+	case pagingClausePattern.MatchString(baseQuery + " "):
+		it.err = errors.New("query must not set STARTPOSITION or MAXRESULTS")
+	default:
+		it.entity, it.where = baseQuery[m[2]:m[3]], baseQuery[m[4]:m[5]]
+		orderBy := ""
+		if m[6] >= 0 {
+			orderBy = baseQuery[m[6]:m[7]]
+		}
+		// The query up to the end of the WHERE clause, with the ORDERBY clause rebuilt.
+		it.query = baseQuery[:m[5]] + " ORDERBY " + withIDTieBreak(orderBy)
+	}
+
+	return it
+}
+
+// Next advances to the next object, fetching the next page when needed. It returns false
+// at the end of the results or on an error; see Err. When QuickBooks returns a page together
+// with a Fault, Next still yields the rows of that page, then stops with the Failure.
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	if it.pos+1 < len(it.page) {
+		it.pos++
+		return true
+	}
+
+	if it.lastPage {
+		it.err = it.pageErr
+		return false
+	}
+
+	query := it.query + " STARTPOSITION " + strconv.Itoa(it.start) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
+	page, err := queryEntities[T](it.c, it.entity, query)
+	if err != nil && len(page) == 0 {
+		it.err = err
+		return false
+	}
+
+	it.page, it.pos = page, 0
+	it.start += queryPageSize
+	it.lastPage = len(page) < queryPageSize || err != nil
+	it.pageErr = err
+
+	return len(page) > 0
+}
+
+// Value returns the current object. It is only valid after Next returned true.
+func (it *Iterator[T]) Value() T {
+	return it.page[it.pos]
+}
+
+// Err returns the error that stopped the iteration, or nil if it reached the end.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// TotalCount returns the number of objects matching the query, as reported by a COUNT query
+// that is made on the first call only. Objects created or deleted while iterating can make it
+// differ from the number Next yields.
+func (it *Iterator[T]) TotalCount() (int, error) {
+	if it.entity == "" {
+		return 0, it.err
+	}
+
+	if it.total == nil {
+		total, err := countEntities(it.c, it.entity, it.where)
+		if err != nil {
+			return 0, err
+		}
+		it.total = &total
+	}
+
+	return *it.total, nil
+}
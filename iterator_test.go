@@ -0,0 +1,80 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIterator(t *testing.T) {
+	const total = 2500
+	var queries []string
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		queries = append(queries, query)
+		if strings.HasPrefix(query, "SELECT COUNT(*)") {
+			w.Write([]byte(`{"QueryResponse": {"totalCount": 2500}}`))
+			return
+		}
+
+		m := pagePattern.FindStringSubmatch(query)
+		require.NotNil(t, m, query)
+		start, _ := strconv.Atoi(m[1])
+
+		var bills []map[string]string
+		for id := start; id < start+queryPageSize && id <= total; id++ {
+			bills = append(bills, map[string]string{"Id": strconv.Itoa(id)})
+		}
+		body, err := json.Marshal(map[string]any{"QueryResponse": map[string]any{"Bill": bills}})
+		require.NoError(t, err)
+		w.Write(body)
+	})
+
+	it := Iterate[Bill](client, "SELECT * FROM Bill WHERE Balance > '0' ORDERBY DueDate")
+	n := 0
+	for it.Next() {
+		n++
+		require.Equal(t, strconv.Itoa(n), it.Value().ID)
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, total, n)
+	assert.Equal(t, []string{
+		"SELECT * FROM Bill WHERE Balance > '0' ORDERBY DueDate, Id STARTPOSITION 1 MAXRESULTS 1000",
+		"SELECT * FROM Bill WHERE Balance > '0' ORDERBY DueDate, Id STARTPOSITION 1001 MAXRESULTS 1000",
+		"SELECT * FROM Bill WHERE Balance > '0' ORDERBY DueDate, Id STARTPOSITION 2001 MAXRESULTS 1000",
+	}, queries)
+
+	count, err := it.TotalCount()
+	require.NoError(t, err)
+	assert.Equal(t, total, count)
+	assert.Equal(t, "SELECT COUNT(*) FROM Bill WHERE Balance > '0'", queries[3])
+
+	// Breaking early makes no further requests.
+	queries = nil
+	it = Iterate[Bill](client, "SELECT Id FROM Bill")
+	for it.Next() {
+		if it.Value().ID == "3" {
+			break
+		}
+	}
+	assert.Equal(t, []string{"SELECT Id FROM Bill ORDERBY Id STARTPOSITION 1 MAXRESULTS 1000"}, queries)
+}
+
+func TestIteratorInvalidQuery(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("no request expected")
+	})
+
+	for _, query := range []string{"DELETE FROM Bill", "SELECT * FROM Bill MAXRESULTS 10"} {
+		it := Iterate[Bill](client, query)
+		assert.False(t, it.Next(), query)
+		assert.Error(t, it.Err(), query)
+		_, err := it.TotalCount()
+		assert.Error(t, err, query)
+	}
+}
@@ -4,12 +4,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"strconv"
+	"math/big"
 )
 
 // JournalEntry represents a QuickBooks JournalEntry object as returned by the API.
 // Read-only fields (Id, SyncToken, MetaData, TotalAmt, HomeTotalAmt) are populated by the service.
 type JournalEntry struct {
+	EntityMeta
+
 	ID           string        `json:"Id,omitempty"`
 	SyncToken    string        `json:",omitempty"`
 	MetaData     *MetaData     `json:",omitempty"`
@@ -36,141 +38,194 @@ type JournalEntryCreateInput struct {
 	ExchangeRate json.Number    `json:",omitempty"`
 	TxnTaxDetail *TxnTaxDetail  `json:",omitempty"`
 	Adjustment   *bool          `json:",omitempty"`
+	// Extra holds fields the struct does not model yet; see ExtraFields.
+	Extra ExtraFields `json:"-"`
 }
 
-// CreateJournalEntry creates the given JournalEntry on the QuickBooks server, returning
-// the resulting JournalEntry object.
-func (c *Client) CreateJournalEntry(input *JournalEntryCreateInput) (*JournalEntry, error) {
-	var resp struct {
-		JournalEntry JournalEntry
-		Time         Date
+// Posting types of a JournalEntryLineDetail.
+const (
+	PostingTypeDebit  = "Debit"
+	PostingTypeCredit = "Credit"
+)
+
+// UnbalancedJournalEntryError is returned by JournalEntryCreateInput.Validate when the debit
+// and credit lines do not add up to the same amount.
+type UnbalancedJournalEntryError struct {
+	Debits  json.Number
+	Credits json.Number
+	// Imbalance is Debits - Credits.
+	Imbalance json.Number
+}
+
+// Error implements the error interface.
+func (e *UnbalancedJournalEntryError) Error() string {
+	return fmt.Sprintf("journal entry is unbalanced: debits %s, credits %s (off by %s)", e.Debits, e.Credits, e.Imbalance)
+}
+
+// Validate checks that the entry has lines and that its debit and credit lines balance,
+// returning an *UnbalancedJournalEntryError when they don't. QuickBooks rejects unbalanced
+// entries with a much vaguer message.
+func (input *JournalEntryCreateInput) Validate() error {
+	if len(input.Line) == 0 {
+		return errors.New("journal entry has no lines")
+	}
+
+	var debits, credits []json.Number
+	for i, line := range input.Line {
+		switch line.JournalEntryLineDetail.PostingType {
+		case PostingTypeDebit:
+			debits = append(debits, line.Amount)
+		case PostingTypeCredit:
+			credits = append(credits, line.Amount)
+		default:
+			return fmt.Errorf("line %d: invalid PostingType %q", i, line.JournalEntryLineDetail.PostingType)
+		}
 	}
 
-	if err := c.post("journalentry", input, &resp, nil); err != nil {
-		return nil, err
+	totalDebits, err := sumAmounts(debits...)
+	if err != nil {
+		return err
 	}
 
-	return &resp.JournalEntry, nil
-}
+	totalCredits, err := sumAmounts(credits...)
+	if err != nil {
+		return err
+	}
 
-// DeleteJournalEntry deletes the journal entry.
-func (c *Client) DeleteJournalEntry(journalEntry *JournalEntry) error {
-	if journalEntry.ID == "" || journalEntry.SyncToken == "" {
-		return errors.New("missing id/sync token")
+	imbalance, err := subtractAmounts(totalDebits, totalCredits)
+	if err != nil {
+		return err
 	}
 
-	return c.post("journalentry", journalEntry, nil, map[string]string{"operation": "delete"})
+	if r, _ := new(big.Rat).SetString(imbalance.String()); r.Sign() != 0 {
+		return &UnbalancedJournalEntryError{Debits: totalDebits, Credits: totalCredits, Imbalance: imbalance}
+	}
+
+	return nil
 }
 
-// FindJournalEntries gets the full list of JournalEntries in the QuickBooks account.
-func (c *Client) FindJournalEntries() ([]JournalEntry, error) {
-	var resp struct {
-		QueryResponse struct {
-			JournalEntries []JournalEntry `json:"JournalEntry"`
-			MaxResults     int
-			StartPosition  int
-			TotalCount     int
-		}
+// ValidateJournalEntryCurrency checks a multicurrency journal entry against the company's
+// currency settings before it is posted.
+//
+// QuickBooks has no per-line currency on journal entries: every line amount is in the
+// entry's CurrencyRef (the home currency when unset), converted with the header ExchangeRate.
+// Lines can therefore only post to accounts in the home currency or in that transaction
+// currency, which is what this checks, along with the exchange rate being set for foreign
+// currency entries. It fetches the preferences and every account the lines reference.
+func (c *Client) ValidateJournalEntryCurrency(input *JournalEntryCreateInput) error {
+	preferences, err := c.FindPreferences()
+	if err != nil {
+		return err
 	}
 
-	if err := c.query("SELECT COUNT(*) FROM JournalEntry", &resp); err != nil {
-		return nil, err
+	var homeCurrency string
+	multiCurrency := false
+	if prefs := preferences.CurrencyPrefs; prefs != nil {
+		if prefs.HomeCurrency != nil {
+			homeCurrency = prefs.HomeCurrency.Value
+		}
+		multiCurrency = prefs.MultiCurrencyEnabled != nil && *prefs.MultiCurrencyEnabled
 	}
 
-	if resp.QueryResponse.TotalCount == 0 {
-		return nil, errors.New("no journal entries could be found")
+	txnCurrency := homeCurrency
+	if input.CurrencyRef != nil && input.CurrencyRef.Value != "" {
+		txnCurrency = input.CurrencyRef.Value
 	}
 
-	journalEntries := make([]JournalEntry, 0, resp.QueryResponse.TotalCount)
+	if txnCurrency != homeCurrency {
+		if !multiCurrency {
+			return fmt.Errorf("journal entry is in %s but multicurrency is disabled for the company", txnCurrency)
+		}
 
-	for i := 0; i < resp.QueryResponse.TotalCount; i += queryPageSize {
-		query := "SELECT * FROM JournalEntry ORDERBY Id STARTPOSITION " + strconv.Itoa(i+1) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
+		rate, ok := new(big.Rat).SetString(input.ExchangeRate.String())
+		if !ok || rate.Sign() <= 0 {
+			return fmt.Errorf("journal entry in %s needs a positive ExchangeRate to %s", txnCurrency, homeCurrency)
+		}
+	}
 
-		if err := c.query(query, &resp); err != nil {
-			return nil, err
+	accountCurrencies := map[string]string{}
+	for i, line := range input.Line {
+		accountID := line.JournalEntryLineDetail.AccountRef.Value
+		if accountID == "" {
+			return fmt.Errorf("line %d: missing AccountRef", i)
 		}
 
-		if resp.QueryResponse.JournalEntries == nil {
-			return nil, errors.New("no journal entries could be found")
+		currency, ok := accountCurrencies[accountID]
+		if !ok {
+			account, err := c.FindAccountByID(accountID)
+			if err != nil {
+				return err
+			}
+
+			currency = homeCurrency
+			if account.CurrencyRef != nil && account.CurrencyRef.Value != "" {
+				currency = account.CurrencyRef.Value
+			}
+			accountCurrencies[accountID] = currency
 		}
 
-		journalEntries = append(journalEntries, resp.QueryResponse.JournalEntries...)
+		if currency != homeCurrency && currency != txnCurrency {
+			return fmt.Errorf("line %d: account %s is in %s but the journal entry is in %s", i, accountID, currency, txnCurrency)
+		}
 	}
 
-	return journalEntries, nil
+	return nil
 }
 
-// FindJournalEntryByID finds the journal entry by the given id.
-func (c *Client) FindJournalEntryByID(id string) (*JournalEntry, error) {
-	var resp struct {
-		JournalEntry JournalEntry
-		Time         Date
+// CreateJournalEntry creates the given JournalEntry on the QuickBooks server, returning
+// the resulting JournalEntry object.
+func (c *Client) CreateJournalEntry(input *JournalEntryCreateInput) (*JournalEntry, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
 	}
 
-	if err := c.get("journalentry/"+id, &resp, nil); err != nil {
-		return nil, err
+	return postSingle[JournalEntry](c, "journalentry", input, nil)
+}
+
+// DeleteJournalEntry deletes the journal entry.
+func (c *Client) DeleteJournalEntry(journalEntry *JournalEntry) error {
+	if journalEntry.ID == "" || journalEntry.SyncToken == "" {
+		return errors.New("missing id/sync token")
 	}
 
-	return &resp.JournalEntry, nil
+	return c.postRetryingStale("journalentry", "journalentry/"+journalEntry.ID, &journalEntry.SyncToken, journalEntry, nil, map[string]string{"operation": "delete"})
+}
+
+// FindJournalEntries gets the full list of JournalEntries in the QuickBooks account.
+func (c *Client) FindJournalEntries() ([]JournalEntry, error) {
+	return findAllPages[JournalEntry](c, "JournalEntry", "no journal entries could be found")
+}
+
+// FindJournalEntriesWithOptions returns the journal entries matching opts, ordered by opts.OrderBy.
+// Pass nil for opts to list them all.
+func (c *Client) FindJournalEntriesWithOptions(opts *ListOptions) ([]JournalEntry, error) {
+	return findAllWithOptions[JournalEntry](c, "JournalEntry", false, opts)
+}
+
+// FindJournalEntryByID finds the journal entry by the given id.
+func (c *Client) FindJournalEntryByID(id string) (*JournalEntry, error) {
+	return getSingle[JournalEntry](c, "journalentry/"+id, nil)
 }
 
 // QueryJournalEntries accepts an SQL query and returns all journal entries found using it.
 func (c *Client) QueryJournalEntries(query string) ([]JournalEntry, error) {
-	var resp struct {
-		QueryResponse struct {
-			JournalEntries []JournalEntry `json:"JournalEntry"`
-			StartPosition  int
-			MaxResults     int
-		}
-	}
-
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
+	journalEntries, err := queryEntities[JournalEntry](c, "JournalEntry", query)
+	if err != nil {
+		return journalEntries, err
 	}
 
-	if resp.QueryResponse.JournalEntries == nil {
+	if journalEntries == nil {
 		return nil, errors.New("could not find any journal entries")
 	}
 
-	return resp.QueryResponse.JournalEntries, nil
+	return journalEntries, nil
 }
 
 // ListJournalEntries returns one page of JournalEntries ordered by Id.
 // Pass an empty pageToken to start from the beginning.
 // The returned nextPageToken is empty when there are no more results.
 func (c *Client) ListJournalEntries(pageToken string, pageSize int) (*ListResponse[JournalEntry], error) {
-	if pageSize <= 0 || pageSize > queryPageSize {
-		pageSize = queryPageSize
-	}
-
-	startPosition := 1
-	if pageToken != "" {
-		var err error
-		startPosition, err = strconv.Atoi(pageToken)
-		if err != nil {
-			return nil, fmt.Errorf("invalid page token: %v", err)
-		}
-	}
-
-	var resp struct {
-		QueryResponse struct {
-			JournalEntries []JournalEntry `json:"JournalEntry"`
-			StartPosition  int
-			MaxResults     int
-		}
-	}
-
-	query := "SELECT * FROM JournalEntry ORDERBY Id STARTPOSITION " + strconv.Itoa(startPosition) + " MAXRESULTS " + strconv.Itoa(pageSize)
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
-	}
-
-	result := &ListResponse[JournalEntry]{Items: resp.QueryResponse.JournalEntries}
-	if len(result.Items) == pageSize {
-		result.NextPageToken = strconv.Itoa(startPosition + pageSize)
-	}
-
-	return result, nil
+	return listPage[JournalEntry](c, "JournalEntry", pageToken, pageSize)
 }
 
 // UpdateJournalEntry updates the journal entry.
@@ -194,14 +249,5 @@ func (c *Client) UpdateJournalEntry(journalEntry *JournalEntry) (*JournalEntry,
 		Sparse:       true,
 	}
 
-	var journalEntryData struct {
-		JournalEntry JournalEntry
-		Time         Date
-	}
-
-	if err = c.post("journalentry", payload, &journalEntryData, nil); err != nil {
-		return nil, err
-	}
-
-	return &journalEntryData.JournalEntry, err
+	return updateSingle[JournalEntry](c, "journalentry", "journalentry/"+journalEntry.ID, &journalEntry.SyncToken, payload)
 }
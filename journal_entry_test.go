@@ -0,0 +1,84 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func journalEntryLine(postingType string, amount json.Number) Line {
+	return Line{
+		Amount:                 amount,
+		DetailType:             "JournalEntryLineDetail",
+		JournalEntryLineDetail: JournalEntryLineDetail{PostingType: postingType},
+	}
+}
+
+func TestJournalEntryCreateInputValidate(t *testing.T) {
+	balanced := &JournalEntryCreateInput{Line: []Line{
+		journalEntryLine(PostingTypeDebit, "100.10"),
+		journalEntryLine(PostingTypeCredit, "60.05"),
+		journalEntryLine(PostingTypeCredit, "40.05"),
+	}}
+	assert.NoError(t, balanced.Validate())
+
+	unbalanced := &JournalEntryCreateInput{Line: []Line{
+		journalEntryLine(PostingTypeDebit, "100.00"),
+		journalEntryLine(PostingTypeCredit, "99.99"),
+	}}
+	var unbalancedErr *UnbalancedJournalEntryError
+	require.ErrorAs(t, unbalanced.Validate(), &unbalancedErr)
+	assert.Equal(t, json.Number("0.01"), unbalancedErr.Imbalance)
+	assert.EqualError(t, unbalancedErr, "journal entry is unbalanced: debits 100.00, credits 99.99 (off by 0.01)")
+
+	assert.Error(t, (&JournalEntryCreateInput{}).Validate())
+	assert.Error(t, (&JournalEntryCreateInput{Line: []Line{journalEntryLine("debit", "1")}}).Validate())
+
+	_, err := (&Client{}).CreateJournalEntry(unbalanced)
+	assert.ErrorAs(t, err, &unbalancedErr)
+}
+
+func TestValidateJournalEntryCurrency(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/company/test-realm/preferences":
+			w.Write([]byte(`{"Preferences": {"CurrencyPrefs": {"MultiCurrencyEnabled": true, "HomeCurrency": {"value": "USD"}}}}`))
+		case "/v3/company/test-realm/account/1":
+			w.Write([]byte(`{"Account": {"Id": "1", "Name": "Checking"}}`))
+		case "/v3/company/test-realm/account/2":
+			w.Write([]byte(`{"Account": {"Id": "2", "Name": "EUR Receivable", "CurrencyRef": {"value": "EUR"}}}`))
+		case "/v3/company/test-realm/account/3":
+			w.Write([]byte(`{"Account": {"Id": "3", "Name": "GBP Payable", "CurrencyRef": {"value": "GBP"}}}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	})
+
+	line := func(postingType, accountID string) Line {
+		l := journalEntryLine(postingType, "100")
+		l.JournalEntryLineDetail.AccountRef = ReferenceType{NameValue: NameValue{Value: accountID}}
+		return l
+	}
+	eur := &ReferenceType{NameValue: NameValue{Value: "EUR"}}
+
+	input := &JournalEntryCreateInput{
+		CurrencyRef:  eur,
+		ExchangeRate: "1.08",
+		Line:         []Line{line(PostingTypeDebit, "2"), line(PostingTypeCredit, "1")},
+	}
+	assert.NoError(t, client.ValidateJournalEntryCurrency(input))
+
+	input.ExchangeRate = ""
+	assert.ErrorContains(t, client.ValidateJournalEntryCurrency(input), "ExchangeRate")
+
+	input.ExchangeRate = "1.08"
+	input.Line[1] = line(PostingTypeCredit, "3")
+	assert.EqualError(t, client.ValidateJournalEntryCurrency(input), "line 1: account 3 is in GBP but the journal entry is in EUR")
+
+	assert.Error(t, client.ValidateJournalEntryCurrency(&JournalEntryCreateInput{
+		Line: []Line{line(PostingTypeDebit, "2"), line(PostingTypeCredit, "3")},
+	}))
+}
@@ -0,0 +1,149 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// JournalQueryParams holds the optional query parameters for the Journal report.
+type JournalQueryParams struct {
+	StartDate *string
+	EndDate   *string
+	// Predefined date range, e.g. "This Month", "Last Fiscal Year". Ignored when StartDate/EndDate are set.
+	DateMacro *string
+	// Comma separated list of transaction types, e.g. "Invoice,JournalEntry".
+	TransactionType *string
+	// Comma separated list of column keys, e.g. "tx_date,txn_type,account_name,debt_amt,credit_amt".
+	Columns *string
+	// Column key to sort by, e.g. "tx_date".
+	SortBy *string
+	// ascend or descend
+	SortOrder *string
+}
+
+func (p *JournalQueryParams) toMap() map[string]string {
+	m := map[string]string{}
+	if p.StartDate != nil {
+		m["start_date"] = *p.StartDate
+	}
+	if p.EndDate != nil {
+		m["end_date"] = *p.EndDate
+	}
+	if p.DateMacro != nil {
+		m["date_macro"] = *p.DateMacro
+	}
+	if p.TransactionType != nil {
+		m["transaction_type"] = *p.TransactionType
+	}
+	if p.Columns != nil {
+		m["columns"] = *p.Columns
+	}
+	if p.SortBy != nil {
+		m["sort_by"] = *p.SortBy
+	}
+	if p.SortOrder != nil {
+		m["sort_order"] = *p.SortOrder
+	}
+	return m
+}
+
+// JournalLine is one posting line of the Journal report.
+// QuickBooks only prints the date, type, number and name on the first line of each
+// transaction; they are copied onto the transaction's other lines here.
+type JournalLine struct {
+	Date      string
+	TxnType   string
+	TxnID     string
+	DocNumber string
+	Name      string
+	Memo      string
+	Account   string
+	AccountID string
+	// Debit and Credit are empty on the side the line does not post to.
+	Debit  json.Number
+	Credit json.Number
+}
+
+// Journal is the Journal report: every transaction of the period in journal form.
+type Journal struct {
+	Report *Report
+	Lines  []JournalLine
+	// TotalDebit and TotalCredit are summed from Lines, so they can be tied out against the
+	// report's own TOTAL row.
+	TotalDebit  json.Number
+	TotalCredit json.Number
+}
+
+// GetJournal fetches the Journal report. Pass nil for params to use the API defaults.
+func (c *Client) GetJournal(params *JournalQueryParams) (*Journal, error) {
+	var queryParams map[string]string
+	if params != nil {
+		queryParams = params.toMap()
+	}
+
+	report, err := c.getReport("JournalReport", queryParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return newJournal(report)
+}
+
+func newJournal(report *Report) (*Journal, error) {
+	cols := map[string]int{}
+	for _, key := range []string{"tx_date", "txn_type", "doc_num", "name", "memo", "account_name", "debt_amt", "credit_amt"} {
+		cols[key] = report.ColumnIndex(key)
+	}
+
+	journal := &Journal{Report: report}
+	var debits, credits []json.Number
+
+	var walk func(rows []ReportRow)
+	walk = func(rows []ReportRow) {
+		var txn JournalLine
+		for _, row := range rows {
+			if row.IsSection() {
+				walk(row.Rows)
+				continue
+			}
+
+			if strings.EqualFold(row.cell(0).Value, "TOTAL") {
+				continue
+			}
+
+			if date := row.cell(cols["tx_date"]).Value; date != "" {
+				txnType := row.cell(cols["txn_type"])
+				txn = JournalLine{
+					Date:      date,
+					TxnType:   txnType.Value,
+					TxnID:     txnType.ID,
+					DocNumber: row.cell(cols["doc_num"]).Value,
+					Name:      row.cell(cols["name"]).Value,
+				}
+			}
+
+			account := row.cell(cols["account_name"])
+			line := txn
+			line.Memo = row.cell(cols["memo"]).Value
+			line.Account = account.Value
+			line.AccountID = account.ID
+			line.Debit = glAmount(row.cell(cols["debt_amt"]).Value)
+			line.Credit = glAmount(row.cell(cols["credit_amt"]).Value)
+
+			journal.Lines = append(journal.Lines, line)
+			debits = append(debits, line.Debit)
+			credits = append(credits, line.Credit)
+		}
+	}
+	walk(report.Rows)
+
+	var err error
+	if journal.TotalDebit, err = sumAmounts(debits...); err != nil {
+		return nil, err
+	}
+	if journal.TotalCredit, err = sumAmounts(credits...); err != nil {
+		return nil, err
+	}
+
+	return journal, nil
+}
@@ -0,0 +1,63 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetJournal(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v3/company/test-realm/reports/JournalReport", r.URL.Path)
+		assert.Equal(t, "Invoice,Payment", r.URL.Query().Get("transaction_type"))
+		assert.Equal(t, "2024-01-01", r.URL.Query().Get("start_date"))
+		w.Write([]byte(`{
+  "Header": {"ReportName": "JournalReport"},
+  "Columns": {"Column": [
+    {"ColType": "Date", "MetaData": [{"Name": "ColKey", "Value": "tx_date"}]},
+    {"ColType": "String", "MetaData": [{"Name": "ColKey", "Value": "txn_type"}]},
+    {"ColType": "String", "MetaData": [{"Name": "ColKey", "Value": "doc_num"}]},
+    {"ColType": "String", "MetaData": [{"Name": "ColKey", "Value": "name"}]},
+    {"ColType": "String", "MetaData": [{"Name": "ColKey", "Value": "account_name"}]},
+    {"ColType": "Money", "MetaData": [{"Name": "ColKey", "Value": "debt_amt"}]},
+    {"ColType": "Money", "MetaData": [{"Name": "ColKey", "Value": "credit_amt"}]}
+  ]},
+  "Rows": {"Row": [
+    {"type": "Section", "Rows": {"Row": [
+      {"type": "Data", "ColData": [{"value": "2024-01-05"}, {"value": "Invoice", "id": "130"}, {"value": "1037"}, {"value": "Cool Cars"}, {"value": "Accounts Receivable (A/R)", "id": "84"}, {"value": "1,100.00"}, {"value": ""}]},
+      {"type": "Data", "ColData": [{"value": ""}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": "Services", "id": "1"}, {"value": ""}, {"value": "1,000.00"}]},
+      {"type": "Data", "ColData": [{"value": ""}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": "Board of Equalization Payable", "id": "89"}, {"value": ""}, {"value": "100.00"}]}
+    ]}, "Summary": {"ColData": [{"value": ""}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": "1,100.00"}, {"value": "1,100.00"}]}},
+    {"type": "Section", "Rows": {"Row": [
+      {"type": "Data", "ColData": [{"value": "2024-01-09"}, {"value": "Payment", "id": "131"}, {"value": ""}, {"value": "Cool Cars"}, {"value": "Undeposited Funds", "id": "4"}, {"value": "500.00"}, {"value": ""}]},
+      {"type": "Data", "ColData": [{"value": ""}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": "Accounts Receivable (A/R)", "id": "84"}, {"value": ""}, {"value": "500.00"}]}
+    ]}, "Summary": {"ColData": [{"value": ""}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": "500.00"}, {"value": "500.00"}]}},
+    {"type": "Data", "ColData": [{"value": "TOTAL"}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": ""}, {"value": "1,600.00"}, {"value": "1,600.00"}]}
+  ]}
+}`))
+	})
+
+	start, types := "2024-01-01", "Invoice,Payment"
+	journal, err := client.GetJournal(&JournalQueryParams{StartDate: &start, TransactionType: &types})
+	require.NoError(t, err)
+	require.Len(t, journal.Lines, 5)
+
+	assert.Equal(t, JournalLine{
+		Date:      "2024-01-05",
+		TxnType:   "Invoice",
+		TxnID:     "130",
+		DocNumber: "1037",
+		Name:      "Cool Cars",
+		Account:   "Services",
+		AccountID: "1",
+		Credit:    "1000.00",
+	}, journal.Lines[1])
+	assert.Equal(t, "131", journal.Lines[4].TxnID)
+	assert.Empty(t, journal.Lines[4].Debit)
+
+	assert.Equal(t, json.Number("1600.00"), journal.TotalDebit)
+	assert.Equal(t, json.Number("1600.00"), journal.TotalCredit)
+}
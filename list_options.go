@@ -0,0 +1,335 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ListOptions controls the FindXWithOptions methods.
+// The zero value lists every active object ordered by Id, one page at a time.
+type ListOptions struct {
+	// Filter is a query condition without the WHERE keyword, e.g. "Balance > '0'".
+	// FilterCondition and AllOf build conditions with the right quoting.
+	Filter string
+	// OrderBy is the ORDERBY clause without the keyword, e.g. "MetaData.LastUpdatedTime DESC".
+	// Defaults to "Id". Pages are fetched with STARTPOSITION, which only works when the order
+	// is total: rows that tie can come back in a different order on each request and be
+	// skipped or returned twice across page boundaries. So unless the clause already sorts
+	// on Id, ", Id" is appended as a tie-breaker.
+	OrderBy string
+	// Currency restricts the results to transactions in the given currency, e.g. "EUR". It
+	// only applies to transaction entities of multicurrency companies, whose CurrencyRef
+	// holds the ISO 4217 code.
+	Currency string
+	// IncludeInactive also returns inactive objects. QuickBooks hides them by default.
+	// It only applies to entities that have an Active field.
+	IncludeInactive bool
+	// MaxResults caps the number of objects returned; 0 means no limit.
+	MaxResults int
+	// Concurrency is the number of pages fetched in parallel. Values above 1 cost an extra
+	// COUNT query up front. Defaults to 1. Client.MaxConcurrentPages takes precedence: the
+	// pages are fetched at most that many at a time whatever Concurrency asks for.
+	Concurrency int
+	// Fields selects the top-level fields to return, e.g. []string{"Id", "DocNumber", "Balance"}.
+	// The objects come back sparsely populated: every other field, including Line, is left
+	// at its zero value. Don't pass such objects to the UpdateX methods, which would overwrite
+	// those fields with empty values; fetch the full object first. Defaults to all fields.
+	Fields []string
+}
+
+var (
+	fieldNamePattern    = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+	currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+// selection returns the SELECT list for the options.
+func (o *ListOptions) selection() (string, error) {
+	if len(o.Fields) == 0 {
+		return "*", nil
+	}
+
+	for _, field := range o.Fields {
+		if !fieldNamePattern.MatchString(field) {
+			return "", fmt.Errorf("invalid field name %q", field)
+		}
+	}
+
+	return strings.Join(o.Fields, ", "), nil
+}
+
+// refEquals returns a filter matching objects whose reference field points at id, e.g.
+// DepositToAccountRef = '35'. QuickBooks compares reference fields by their value (the Id)
+// and wants it quoted like a string.
+func refEquals(field string, id string) string {
+	return field + " = '" + strings.Replace(id, "'", "''", -1) + "'"
+}
+
+// withIDTieBreak returns the ORDERBY clause orderBy with Id appended, unless one of its terms
+// already sorts on Id. An empty clause becomes "Id".
+func withIDTieBreak(orderBy string) string {
+	if strings.TrimSpace(orderBy) == "" {
+		return "Id"
+	}
+
+	for _, term := range strings.Split(orderBy, ",") {
+		if fields := strings.Fields(term); len(fields) > 0 && strings.EqualFold(fields[0], "Id") {
+			return orderBy
+		}
+	}
+
+	return orderBy + ", Id"
+}
+
+// where returns the WHERE clause for the options, including the leading space, or "".
+func (o *ListOptions) where(hasActive bool) string {
+	filter := o.Filter
+	if o.Currency != "" {
+		if filter != "" {
+			filter += " AND "
+		}
+		filter += refEquals("CurrencyRef", o.Currency)
+	}
+	if o.IncludeInactive && hasActive {
+		if filter != "" {
+			filter += " AND "
+		}
+		filter += "Active IN (true, false)"
+	}
+
+	if filter == "" {
+		return ""
+	}
+	return " WHERE " + filter
+}
+
+// findAllWithOptions pages through every entity matching opts and returns them in order.
+// hasActive tells whether the entity has an Active field, which IncludeInactive needs.
+func findAllWithOptions[T any](c *Client, entity string, hasActive bool, opts *ListOptions) ([]T, error) {
+	if opts == nil {
+		opts = &ListOptions{}
+	}
+
+	if opts.MaxResults < 0 {
+		return nil, errors.New("MaxResults cannot be negative")
+	}
+
+	if opts.Currency != "" && !currencyCodePattern.MatchString(opts.Currency) {
+		return nil, fmt.Errorf("invalid currency code %q", opts.Currency)
+	}
+
+	selection, err := opts.selection()
+	if err != nil {
+		return nil, err
+	}
+
+	where := opts.where(hasActive)
+	orderBy := withIDTieBreak(opts.OrderBy)
+
+	fetch := func(startPosition, maxResults int) ([]T, error) {
+		query := "SELECT " + selection + " FROM " + entity + where + " ORDERBY " + orderBy +
+			" STARTPOSITION " + strconv.Itoa(startPosition) + " MAXRESULTS " + strconv.Itoa(maxResults)
+		return queryEntities[T](c, entity, query)
+	}
+
+	concurrency := min(opts.Concurrency, c.maxConcurrentPages())
+	if concurrency <= 1 {
+		var items []T
+		for start := 1; ; start += queryPageSize {
+			pageSize := queryPageSize
+			if opts.MaxResults > 0 {
+				pageSize = min(pageSize, opts.MaxResults-len(items))
+			}
+
+			page, err := fetch(start, pageSize)
+			items = append(items, page...)
+			if err != nil {
+				return partialItems(items, err)
+			}
+
+			if len(page) < pageSize || (opts.MaxResults > 0 && len(items) >= opts.MaxResults) {
+				return items, nil
+			}
+		}
+	}
+
+	total, err := countEntities(c, entity, where)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.MaxResults > 0 && opts.MaxResults < total {
+		total = opts.MaxResults
+	}
+
+	pages, err := fetchPages((total+queryPageSize-1)/queryPageSize, concurrency, func(i int) ([]T, error) {
+		return fetch(i*queryPageSize+1, min(queryPageSize, total-i*queryPageSize))
+	})
+
+	return partialItems(joinPages(pages, total), err)
+}
+
+// DefaultMaxConcurrentPages is the number of pages the FindX methods fetch in parallel when
+// Client.MaxConcurrentPages is 0.
+const DefaultMaxConcurrentPages = 4
+
+// maxConcurrentPages returns the MaxConcurrentPages setting, or its default.
+func (c *Client) maxConcurrentPages() int {
+	if c.MaxConcurrentPages <= 0 {
+		return DefaultMaxConcurrentPages
+	}
+	return c.MaxConcurrentPages
+}
+
+// findAllPages returns every entity ordered by Id for the FindX methods: a COUNT query, then
+// its pages, up to MaxConcurrentPages at a time. It fails with notFound when the count is
+// zero or a page comes back empty.
+func findAllPages[T any](c *Client, entity string, notFound string) ([]T, error) {
+	total, err := countEntities(c, entity, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if total == 0 {
+		return nil, errors.New(notFound)
+	}
+
+	pages, err := fetchPages((total+queryPageSize-1)/queryPageSize, c.maxConcurrentPages(), func(i int) ([]T, error) {
+		query := "SELECT * FROM " + entity + " ORDERBY Id STARTPOSITION " + strconv.Itoa(i*queryPageSize+1) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
+
+		page, err := queryEntities[T](c, entity, query)
+		if err == nil && page == nil {
+			err = errors.New(notFound)
+		}
+		return page, err
+	})
+
+	return partialItems(joinPages(pages, total), err)
+}
+
+// joinPages concatenates the pages in order.
+func joinPages[T any](pages [][]T, total int) []T {
+	items := make([]T, 0, total)
+	for _, page := range pages {
+		items = append(items, page...)
+	}
+	return items
+}
+
+// partialItems returns items together with err when err is a Failure, which QuickBooks
+// sends next to the rows it could return, and drops them for any other error.
+func partialItems[T any](items []T, err error) ([]T, error) {
+	var failure Failure
+	if err != nil && !errors.As(err, &failure) {
+		return nil, err
+	}
+	return items, err
+}
+
+// fetchPages calls fetch for pages 0 to n-1, at most concurrency at a time, and returns the
+// pages in order. After a failure no further pages are started and the error of the first
+// failed page is returned, along with the pages fetched so far, including whatever the failed
+// page returned.
+func fetchPages[T any](n, concurrency int, fetch func(page int) ([]T, error)) ([][]T, error) {
+	pages := make([][]T, n)
+	errs := make([]error, n)
+	sem := make(chan struct{}, max(concurrency, 1))
+
+	var failed atomic.Bool
+	var wg sync.WaitGroup
+	for i := range pages {
+		sem <- struct{}{}
+		if failed.Load() {
+			<-sem
+			break
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			if pages[i], errs[i] = fetch(i); errs[i] != nil {
+				failed.Store(true)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return pages, err
+		}
+	}
+	return pages, nil
+}
+
+// listPage returns one page of the entities ordered by Id for the ListX methods. pageToken is
+// the STARTPOSITION of the page, or "" for the first one.
+func listPage[T any](c *Client, entity string, pageToken string, pageSize int) (*ListResponse[T], error) {
+	if pageSize <= 0 || pageSize > queryPageSize {
+		pageSize = queryPageSize
+	}
+
+	startPosition := 1
+	if pageToken != "" {
+		var err error
+		startPosition, err = strconv.Atoi(pageToken)
+		if err != nil {
+			return nil, fmt.Errorf("invalid page token: %v", err)
+		}
+	}
+
+	query := "SELECT * FROM " + entity + " ORDERBY Id STARTPOSITION " + strconv.Itoa(startPosition) + " MAXRESULTS " + strconv.Itoa(pageSize)
+	items, err := queryEntities[T](c, entity, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ListResponse[T]{Items: items}
+	if len(result.Items) == pageSize {
+		result.NextPageToken = strconv.Itoa(startPosition + pageSize)
+	}
+
+	return result, nil
+}
+
+// queryEntities runs query and decodes the entities QuickBooks returns under the entity's name.
+// When QuickBooks answers 200 with a Fault next to the rows, it returns both the rows and the
+// Failure.
+func queryEntities[T any](c *Client, entity string, query string) ([]T, error) {
+	var body json.RawMessage
+	err := c.query(query, &body)
+	if err != nil && len(body) == 0 {
+		return nil, err
+	}
+
+	items, decodeErr := decodeList[T](body, entity)
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+
+	return partialItems(items, err)
+}
+
+// countEntities returns the number of entities matching the WHERE clause.
+func countEntities(c *Client, entity string, where string) (int, error) {
+	var resp struct {
+		QueryResponse struct {
+			TotalCount int
+		}
+	}
+
+	if err := c.query("SELECT COUNT(*) FROM "+entity+where, &resp); err != nil {
+		return 0, err
+	}
+
+	return resp.QueryResponse.TotalCount, nil
+}
@@ -0,0 +1,332 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var pagePattern = regexp.MustCompile(`STARTPOSITION (\d+) MAXRESULTS (\d+)$`)
+
+// newPagingTestClient serves total customers with Ids 1..total, paging like QuickBooks does.
+func newPagingTestClient(t *testing.T, total int, queries *[]string) *Client {
+	var mu sync.Mutex
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		mu.Lock()
+		*queries = append(*queries, query)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if strings.HasPrefix(query, "SELECT COUNT(*)") {
+			w.Write([]byte(`{"QueryResponse":{"totalCount":` + strconv.Itoa(total) + `}}`))
+			return
+		}
+
+		m := pagePattern.FindStringSubmatch(query)
+		require.NotNil(t, m, query)
+		start, _ := strconv.Atoi(m[1])
+		max, _ := strconv.Atoi(m[2])
+
+		var customers []map[string]string
+		for id := start; id < start+max && id <= total; id++ {
+			customers = append(customers, map[string]string{"Id": strconv.Itoa(id)})
+		}
+
+		body, err := json.Marshal(map[string]any{"QueryResponse": map[string]any{"Customer": customers}})
+		require.NoError(t, err)
+		w.Write(body)
+	})
+	return client
+}
+
+func TestFindCustomersWithOptions(t *testing.T) {
+	var queries []string
+	client := newPagingTestClient(t, 1500, &queries)
+
+	customers, err := client.FindCustomersWithOptions(&ListOptions{
+		Filter:          "Balance > '0'",
+		OrderBy:         "DisplayName",
+		IncludeInactive: true,
+		MaxResults:      1200,
+	})
+	require.NoError(t, err)
+	require.Len(t, customers, 1200)
+	assert.Equal(t, "1200", customers[1199].ID)
+
+	assert.Equal(t, []string{
+		"SELECT * FROM Customer WHERE Balance > '0' AND Active IN (true, false) ORDERBY DisplayName, Id STARTPOSITION 1 MAXRESULTS 1000",
+		"SELECT * FROM Customer WHERE Balance > '0' AND Active IN (true, false) ORDERBY DisplayName, Id STARTPOSITION 1001 MAXRESULTS 200",
+	}, queries)
+}
+
+func TestFindWithOptionsTieBreak(t *testing.T) {
+	assert.Equal(t, "Id", withIDTieBreak(""))
+	assert.Equal(t, "Id DESC", withIDTieBreak("Id DESC"))
+	assert.Equal(t, "TxnDate DESC, id", withIDTieBreak("TxnDate DESC, id"))
+	assert.Equal(t, "TxnDate DESC, Id", withIDTieBreak("TxnDate DESC"))
+
+	// 1500 invoices share three dates. Like QuickBooks, the server orders rows that tie
+	// differently from one request to the next unless the query sorts on Id as well.
+	const total = 1500
+	requests := 0
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		m := pagePattern.FindStringSubmatch(query)
+		require.NotNil(t, m, query)
+		start, _ := strconv.Atoi(m[1])
+		max, _ := strconv.Atoi(m[2])
+		requests++
+
+		ids := make([]int, total)
+		for i := range ids {
+			ids[i] = i + 1
+		}
+		date := func(id int) int { return id % 3 }
+		tieBroken := strings.Contains(query, "ORDERBY TxnDate, Id ")
+		sort.SliceStable(ids, func(i, j int) bool {
+			if date(ids[i]) != date(ids[j]) {
+				return date(ids[i]) < date(ids[j])
+			}
+			if tieBroken {
+				return ids[i] < ids[j]
+			}
+			return (ids[i] < ids[j]) == (requests%2 == 0)
+		})
+
+		var invoices []map[string]string
+		for i := start - 1; i < start-1+max && i < total; i++ {
+			invoices = append(invoices, map[string]string{"Id": strconv.Itoa(ids[i])})
+		}
+		body, err := json.Marshal(map[string]any{"QueryResponse": map[string]any{"Invoice": invoices}})
+		require.NoError(t, err)
+		w.Write(body)
+	})
+
+	invoices, err := client.FindInvoicesWithOptions(&ListOptions{OrderBy: "TxnDate"})
+	require.NoError(t, err)
+	require.Len(t, invoices, total)
+
+	seen := map[string]bool{}
+	for _, invoice := range invoices {
+		require.False(t, seen[invoice.ID], "invoice %s returned twice", invoice.ID)
+		seen[invoice.ID] = true
+	}
+}
+
+func TestFindCustomersWithOptionsConcurrent(t *testing.T) {
+	var queries []string
+	client := newPagingTestClient(t, 2500, &queries)
+
+	customers, err := client.FindCustomersWithOptions(&ListOptions{Concurrency: 3})
+	require.NoError(t, err)
+	require.Len(t, customers, 2500)
+	for i, c := range customers {
+		require.Equal(t, strconv.Itoa(i+1), c.ID)
+	}
+	assert.Len(t, queries, 4)
+
+	// MaxConcurrentPages caps Concurrency: one page at a time needs no COUNT query.
+	queries = nil
+	client.MaxConcurrentPages = 1
+	customers, err = client.FindCustomersWithOptions(&ListOptions{Concurrency: 3})
+	require.NoError(t, err)
+	require.Len(t, customers, 2500)
+	require.Len(t, queries, 3)
+	assert.NotContains(t, queries[0], "COUNT(*)")
+}
+
+func TestFindInvoicesWithOptionsFields(t *testing.T) {
+	var queries []string
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("query"))
+		w.Write([]byte(`{"QueryResponse": {"Invoice": [{"Id": "130", "DocNumber": "1037", "Balance": 362.07, "CustomerRef": {"value": "24", "name": "Sonnenschein Family Store"}}]}}`))
+	})
+
+	invoices, err := client.FindInvoicesWithOptions(&ListOptions{Fields: []string{"Id", "DocNumber", "Balance", "CustomerRef"}})
+	require.NoError(t, err)
+	require.Len(t, invoices, 1)
+	assert.Equal(t, "24", invoices[0].CustomerRef.Value)
+	assert.Empty(t, invoices[0].Line)
+	assert.Equal(t, []string{"SELECT Id, DocNumber, Balance, CustomerRef FROM Invoice ORDERBY Id STARTPOSITION 1 MAXRESULTS 1000"}, queries)
+
+	_, err = client.FindInvoicesWithOptions(&ListOptions{Fields: []string{"Id FROM Customer --"}})
+	assert.Error(t, err)
+}
+
+// BenchmarkInvoiceProjection compares decoding a page of 50-line invoices with decoding the
+// same page restricted to header fields, as returned when ListOptions.Fields is set.
+func BenchmarkInvoiceProjection(b *testing.B) {
+	header := map[string]any{"Id": "130", "DocNumber": "1037", "Balance": "362.07", "CustomerRef": map[string]string{"value": "24"}}
+
+	full := map[string]any{}
+	for k, v := range header {
+		full[k] = v
+	}
+	var lines []map[string]any
+	for i := 0; i < 50; i++ {
+		lines = append(lines, map[string]any{
+			"Id": strconv.Itoa(i + 1), "LineNum": i + 1, "Amount": "10.00", "Description": "Rock Fountain",
+			"DetailType":          "SalesItemLineDetail",
+			"SalesItemLineDetail": map[string]any{"ItemRef": map[string]string{"value": "5", "name": "Rock Fountain"}, "UnitPrice": "10", "Qty": 1, "TaxCodeRef": map[string]string{"value": "TAX"}},
+		})
+	}
+	full["Line"] = lines
+
+	page := func(invoice map[string]any) []byte {
+		invoices := make([]map[string]any, 100)
+		for i := range invoices {
+			invoices[i] = invoice
+		}
+		body, err := json.Marshal(map[string]any{"QueryResponse": map[string]any{"Invoice": invoices}})
+		require.NoError(b, err)
+		return body
+	}
+
+	for name, body := range map[string][]byte{"all fields": page(full), "header fields": page(header)} {
+		b.Run(name, func(b *testing.B) {
+			b.ReportMetric(float64(len(body)), "payload-bytes")
+			for i := 0; i < b.N; i++ {
+				if _, err := decodeList[Invoice](body, "Invoice"); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestQueryTransactionsForAccount(t *testing.T) {
+	var queries []string
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		queries = append(queries, query)
+
+		switch {
+		case strings.Contains(query, "FROM Deposit"):
+			w.Write([]byte(`{"QueryResponse":{"Deposit":[{"Id":"1","DepositToAccountRef":{"value":"35"},"TotalAmt":100}]}}`))
+		default:
+			w.Write([]byte(`{"QueryResponse":{"Purchase":[{"Id":"9","AccountRef":{"value":"41"},"PaymentType":"Check"}]}}`))
+		}
+	})
+
+	deposits, err := client.QueryDepositsForAccount("35")
+	require.NoError(t, err)
+	require.Len(t, deposits, 1)
+	assert.Equal(t, "35", deposits[0].DepositToAccountRef.Value)
+
+	purchases, err := client.QueryPurchasesForAccount("4'1")
+	require.NoError(t, err)
+	require.Len(t, purchases, 1)
+
+	assert.Equal(t, []string{
+		"SELECT * FROM Deposit WHERE DepositToAccountRef = '35' ORDERBY Id STARTPOSITION 1 MAXRESULTS 1000",
+		"SELECT * FROM Purchase WHERE AccountRef = '4''1' ORDERBY Id STARTPOSITION 1 MAXRESULTS 1000",
+	}, queries)
+
+	_, err = client.QueryDepositsForAccount("")
+	assert.Error(t, err)
+}
+
+func TestFindWithOptionsCurrency(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "SELECT * FROM Bill WHERE Balance > '0' AND CurrencyRef = 'EUR' ORDERBY Id STARTPOSITION 1 MAXRESULTS 1000", r.URL.Query().Get("query"))
+		w.Write([]byte(`{"QueryResponse":{"Bill":[{"Id":"3","CurrencyRef":{"value":"EUR","name":"Euro"},"ExchangeRate":1.08}]}}`))
+	})
+
+	bills, err := client.FindBillsWithOptions(&ListOptions{Filter: "Balance > '0'", Currency: "EUR"})
+	require.NoError(t, err)
+	require.Len(t, bills, 1)
+	assert.Equal(t, "EUR", bills[0].CurrencyRef.Value)
+
+	_, err = client.FindBillsWithOptions(&ListOptions{Currency: "eur' OR ''='"})
+	assert.Error(t, err)
+
+	var memo CreditMemo
+	require.NoError(t, json.Unmarshal([]byte(`{"Id":"9","CurrencyRef":{"value":"CAD"},"ExchangeRate":0.74}`), &memo))
+	b, err := json.Marshal(memo)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"CurrencyRef":{"value":"CAD"}`)
+	assert.Contains(t, string(b), `"ExchangeRate":0.74`)
+}
+
+func TestFindAllPagesConcurrent(t *testing.T) {
+	const total = 3500
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		if strings.HasPrefix(query, "SELECT COUNT(*) FROM Vendor") {
+			w.Write([]byte(`{"QueryResponse": {"totalCount": 3500}}`))
+			return
+		}
+
+		mu.Lock()
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+		time.Sleep(20 * time.Millisecond)
+
+		m := pagePattern.FindStringSubmatch(query)
+		require.NotNil(t, m, query)
+		assert.Equal(t, "1000", m[2])
+		start, _ := strconv.Atoi(m[1])
+
+		var vendors []map[string]string
+		for id := start; id < start+queryPageSize && id <= total; id++ {
+			vendors = append(vendors, map[string]string{"Id": strconv.Itoa(id)})
+		}
+		body, err := json.Marshal(map[string]any{"QueryResponse": map[string]any{"Vendor": vendors}})
+		require.NoError(t, err)
+		w.Write(body)
+	})
+	client.MaxConcurrentPages = 2
+
+	vendors, err := client.FindVendors()
+	require.NoError(t, err)
+	require.Len(t, vendors, total)
+	for i, vendor := range vendors {
+		require.Equal(t, strconv.Itoa(i+1), vendor.ID)
+	}
+	assert.LessOrEqual(t, maxInFlight, 2)
+}
+
+func TestListVendors(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("query") {
+		case "SELECT * FROM Vendor ORDERBY Id STARTPOSITION 1 MAXRESULTS 2":
+			w.Write([]byte(`{"QueryResponse": {"Vendor": [{"Id": "1"}, {"Id": "2"}], "startPosition": 1, "maxResults": 2}}`))
+		case "SELECT * FROM Vendor ORDERBY Id STARTPOSITION 3 MAXRESULTS 2":
+			w.Write([]byte(`{"QueryResponse": {"Vendor": [{"Id": "3"}], "startPosition": 3, "maxResults": 1}}`))
+		default:
+			t.Errorf("unexpected query %q", r.URL.Query().Get("query"))
+		}
+	})
+
+	page, err := client.ListVendors("", 2)
+	require.NoError(t, err)
+	assert.Len(t, page.Items, 2)
+	assert.Equal(t, "3", page.NextPageToken)
+
+	page, err = client.ListVendors(page.NextPageToken, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "3", page.Items[0].ID)
+	assert.Empty(t, page.NextPageToken)
+
+	_, err = client.ListVendors("next", 2)
+	assert.EqualError(t, err, `invalid page token: strconv.Atoi: parsing "next": invalid syntax`)
+}
@@ -4,24 +4,82 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"strconv"
+	"math/big"
 )
 
 // Payment represents a QuickBooks Payment object as returned by the API.
 // Read-only fields (Id, SyncToken, MetaData, UnappliedAmt) are populated by the service.
+// PaymentRefNum holds the check number or card transaction reference of the payment.
 type Payment struct {
+	EntityMeta
+
 	ID                  string         `json:"Id,omitempty"`
 	SyncToken           string         `json:",omitempty"`
 	MetaData            *MetaData      `json:",omitempty"`
 	CustomerRef         ReferenceType  `json:",omitempty"`
 	TotalAmt            json.Number    `json:",omitempty"`
+	CurrencyRef         *ReferenceType `json:",omitempty"`
+	ExchangeRate        json.Number    `json:",omitempty"`
 	UnappliedAmt        json.Number    `json:",omitempty"`
 	TxnDate             *Date          `json:",omitempty"`
 	DepositToAccountRef *ReferenceType `json:",omitempty"`
+	PaymentMethodRef    *ReferenceType `json:",omitempty"`
+	PaymentRefNum       *string        `json:",omitempty"`
+	PrivateNote         *string        `json:",omitempty"`
 	ProcessPayment      *bool          `json:",omitempty"`
 	Line                []PaymentLine  `json:",omitempty"`
 }
 
+// IsFullyApplied reports whether the whole payment has been applied to invoices or other
+// transactions. Payments with a non-zero UnappliedAmt are sitting as a customer credit.
+func (p *Payment) IsFullyApplied() bool {
+	if p.UnappliedAmt == "" {
+		return true
+	}
+
+	unapplied, ok := new(big.Rat).SetString(p.UnappliedAmt.String())
+	return ok && unapplied.Sign() == 0
+}
+
+// IsVoided reports whether the payment has been voided. Like Invoice.IsVoided it relies on
+// the zero TotalAmt and "Voided" PrivateNote QuickBooks leaves, so a user editing the note
+// after the void defeats it.
+func (p *Payment) IsVoided() bool {
+	return isVoided(p.PrivateNote, p.TotalAmt)
+}
+
+// PaymentApplication is the part of a payment applied to one transaction.
+type PaymentApplication struct {
+	// TxnType is "Invoice" for invoice payments; credit memos and journal entries applied
+	// through the payment show up as "CreditMemo" and "JournalEntry".
+	TxnType string
+	TxnID   string
+	Amount  json.Number
+}
+
+// Applications returns how the payment is split across the transactions it pays, one entry
+// per linked transaction, in line order. The amount not applied to anything is UnappliedAmt.
+func (p *Payment) Applications() []PaymentApplication {
+	var applications []PaymentApplication
+	for _, line := range p.Line {
+		for _, linked := range line.LinkedTxn {
+			applications = append(applications, PaymentApplication{TxnType: linked.TxnType, TxnID: linked.TxnID, Amount: line.Amount})
+		}
+	}
+	return applications
+}
+
+// InvoiceIDs returns the Ids of the invoices the payment is applied to.
+func (p *Payment) InvoiceIDs() []string {
+	var ids []string
+	for _, application := range p.Applications() {
+		if application.TxnType == "Invoice" {
+			ids = append(ids, application.TxnID)
+		}
+	}
+	return ids
+}
+
 // PaymentLine represents a line item within a Payment.
 type PaymentLine struct {
 	Amount    json.Number `json:",omitempty"`
@@ -33,144 +91,167 @@ type PaymentLine struct {
 type PaymentCreateInput struct {
 	CustomerRef         ReferenceType  `json:",omitempty"`
 	TotalAmt            json.Number    `json:",omitempty"`
+	CurrencyRef         *ReferenceType `json:",omitempty"`
+	ExchangeRate        json.Number    `json:",omitempty"`
 	TxnDate             *Date          `json:",omitempty"`
 	DepositToAccountRef *ReferenceType `json:",omitempty"`
+	PaymentMethodRef    *ReferenceType `json:",omitempty"`
+	PaymentRefNum       *string        `json:",omitempty"`
+	PrivateNote         *string        `json:",omitempty"`
 	ProcessPayment      *bool          `json:",omitempty"`
 	Line                []PaymentLine  `json:",omitempty"`
+	// Extra holds fields the struct does not model yet; see ExtraFields.
+	Extra ExtraFields `json:"-"`
 }
 
-// CreatePayment creates the given payment within QuickBooks.
-func (c *Client) CreatePayment(input *PaymentCreateInput) (*Payment, error) {
-	var resp struct {
-		Payment Payment
-		Time    Date
+// ApplyCreditMemo applies amount of the given credit memo against the given invoice without
+// receiving any cash. QuickBooks models this as a zero-dollar Payment with one line linked to
+// the Invoice and one line linked to the CreditMemo.
+func (c *Client) ApplyCreditMemo(creditMemoID, invoiceID string, amount json.Number) (*Payment, error) {
+	if creditMemoID == "" || invoiceID == "" {
+		return nil, errors.New("missing credit memo id/invoice id")
 	}
 
-	if err := c.post("payment", input, &resp, nil); err != nil {
+	if amount == "" {
+		return nil, errors.New("missing amount")
+	}
+
+	invoice, err := c.FindInvoiceByID(invoiceID)
+	if err != nil {
 		return nil, err
 	}
 
-	return &resp.Payment, nil
-}
+	creditMemo, err := c.FindCreditMemoByID(creditMemoID)
+	if err != nil {
+		return nil, err
+	}
 
-// DeletePayment deletes the given payment from QuickBooks.
-func (c *Client) DeletePayment(payment *Payment) error {
-	if payment.ID == "" || payment.SyncToken == "" {
-		return errors.New("missing id/sync token")
+	if invoice.CustomerRef.Value != creditMemo.CustomerRef.Value {
+		return nil, fmt.Errorf("credit memo %s belongs to customer %s, but invoice %s belongs to customer %s",
+			creditMemoID, creditMemo.CustomerRef.Value, invoiceID, invoice.CustomerRef.Value)
 	}
 
-	return c.post("payment", payment, nil, map[string]string{"operation": "delete"})
+	return c.CreatePayment(&PaymentCreateInput{
+		CustomerRef: invoice.CustomerRef,
+		TotalAmt:    "0",
+		Line: []PaymentLine{
+			{Amount: amount, LinkedTxn: []LinkedTxn{{TxnID: invoiceID, TxnType: "Invoice"}}},
+			{Amount: amount, LinkedTxn: []LinkedTxn{{TxnID: creditMemoID, TxnType: "CreditMemo"}}},
+		},
+	})
 }
 
-// FindPayments gets the full list of Payments in the QuickBooks account.
-func (c *Client) FindPayments() ([]Payment, error) {
-	var resp struct {
-		QueryResponse struct {
-			Payments      []Payment `json:"Payment"`
-			MaxResults    int
-			StartPosition int
-			TotalCount    int
-		}
-	}
+// InvoicePaymentError is returned by CreateInvoiceAndReceivePayment when the invoice was
+// created but recording the payment failed. The invoice is left open in QuickBooks; callers
+// can retry the payment against Invoice or void it.
+type InvoicePaymentError struct {
+	Invoice *Invoice
+	Err     error
+}
 
-	if err := c.query("SELECT COUNT(*) FROM Payment", &resp); err != nil {
-		return nil, err
+// Error implements the error interface.
+func (e *InvoicePaymentError) Error() string {
+	return fmt.Sprintf("invoice %s was created but the payment failed: %v", e.Invoice.ID, e.Err)
+}
+
+// Unwrap returns the error of the payment request.
+func (e *InvoicePaymentError) Unwrap() error {
+	return e.Err
+}
+
+// CreateInvoiceAndReceivePayment creates the invoice and then a payment of its full balance,
+// linked to it and deposited to the given account, for point-of-sale flows that invoice and
+// collect in one step. Use a SalesReceipt instead when no open invoice is needed at all.
+//
+// The payment is recorded in the currency of the invoice, at its exchange rate.
+//
+// These are two separate requests and QuickBooks cannot roll back the first: if the payment
+// fails, the created invoice is returned inside an *InvoicePaymentError.
+func (c *Client) CreateInvoiceAndReceivePayment(input *InvoiceCreateInput, depositToAccountID string) (*Invoice, *Payment, error) {
+	if depositToAccountID == "" {
+		return nil, nil, errors.New("missing deposit to account id")
 	}
 
-	if resp.QueryResponse.TotalCount == 0 {
-		return nil, errors.New("no payments could be found")
+	invoice, err := c.CreateInvoice(input)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	payments := make([]Payment, 0, resp.QueryResponse.TotalCount)
+	amount := invoice.Balance
+	if amount == "" {
+		amount = invoice.TotalAmt
+	}
 
-	for i := 0; i < resp.QueryResponse.TotalCount; i += queryPageSize {
-		query := "SELECT * FROM Payment ORDERBY Id STARTPOSITION " + strconv.Itoa(i+1) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
+	payment, err := c.CreatePayment(&PaymentCreateInput{
+		CustomerRef:         invoice.CustomerRef,
+		TotalAmt:            amount,
+		TxnDate:             invoice.TxnDate,
+		CurrencyRef:         invoice.CurrencyRef,
+		ExchangeRate:        invoice.ExchangeRate,
+		DepositToAccountRef: &ReferenceType{NameValue: NameValue{Value: depositToAccountID}},
+		Line:                []PaymentLine{{Amount: amount, LinkedTxn: []LinkedTxn{{TxnID: invoice.ID, TxnType: "Invoice"}}}},
+	})
+	if err != nil {
+		return invoice, nil, &InvoicePaymentError{Invoice: invoice, Err: err}
+	}
 
-		if err := c.query(query, &resp); err != nil {
-			return nil, err
-		}
+	return invoice, payment, nil
+}
 
-		if resp.QueryResponse.Payments == nil {
-			return nil, errors.New("no payments could be found")
-		}
+// CreatePayment creates the given payment within QuickBooks.
+func (c *Client) CreatePayment(input *PaymentCreateInput) (*Payment, error) {
+	return postSingle[Payment](c, "payment", input, nil)
+}
 
-		payments = append(payments, resp.QueryResponse.Payments...)
+// DeletePayment deletes the given payment from QuickBooks.
+func (c *Client) DeletePayment(payment *Payment) error {
+	if payment.ID == "" || payment.SyncToken == "" {
+		return errors.New("missing id/sync token")
 	}
 
-	return payments, nil
+	return c.postRetryingStale("payment", "payment/"+payment.ID, &payment.SyncToken, payment, nil, map[string]string{"operation": "delete"})
+}
+
+// FindPayments gets the full list of Payments in the QuickBooks account.
+func (c *Client) FindPayments() ([]Payment, error) {
+	return findAllPages[Payment](c, "Payment", "no payments could be found")
+}
+
+// FindPaymentsWithOptions returns the payments matching opts, ordered by opts.OrderBy.
+// Pass nil for opts to list them all.
+func (c *Client) FindPaymentsWithOptions(opts *ListOptions) ([]Payment, error) {
+	return findAllWithOptions[Payment](c, "Payment", false, opts)
 }
 
 // FindPaymentByID returns a payment with a given Id.
 func (c *Client) FindPaymentByID(id string) (*Payment, error) {
-	var resp struct {
-		Payment Payment
-		Time    Date
-	}
-
-	if err := c.get("payment/"+id, &resp, nil); err != nil {
-		return nil, err
-	}
+	return getSingle[Payment](c, "payment/"+id, nil)
+}
 
-	return &resp.Payment, nil
+// FindPaymentsByIDs returns the payments with the given Ids. Ids that match nothing are skipped.
+func (c *Client) FindPaymentsByIDs(ids []string) ([]Payment, error) {
+	return findByIDs[Payment](c, "Payment", false, ids)
 }
 
 // QueryPayments accepts a SQL query and returns all payments found using it.
 func (c *Client) QueryPayments(query string) ([]Payment, error) {
-	var resp struct {
-		QueryResponse struct {
-			Payments      []Payment `json:"Payment"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
+	payments, err := queryEntities[Payment](c, "Payment", query)
+	if err != nil {
+		return payments, err
 	}
 
-	if resp.QueryResponse.Payments == nil {
+	if payments == nil {
 		return nil, errors.New("could not find any payments")
 	}
 
-	return resp.QueryResponse.Payments, nil
+	return payments, nil
 }
 
 // ListPayments returns one page of Payments ordered by Id.
 // Pass an empty pageToken to start from the beginning.
 // The returned nextPageToken is empty when there are no more results.
 func (c *Client) ListPayments(pageToken string, pageSize int) (*ListResponse[Payment], error) {
-	if pageSize <= 0 || pageSize > queryPageSize {
-		pageSize = queryPageSize
-	}
-
-	startPosition := 1
-	if pageToken != "" {
-		var err error
-		startPosition, err = strconv.Atoi(pageToken)
-		if err != nil {
-			return nil, fmt.Errorf("invalid page token: %v", err)
-		}
-	}
-
-	var resp struct {
-		QueryResponse struct {
-			Payments      []Payment `json:"Payment"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	query := "SELECT * FROM Payment ORDERBY Id STARTPOSITION " + strconv.Itoa(startPosition) + " MAXRESULTS " + strconv.Itoa(pageSize)
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
-	}
-
-	result := &ListResponse[Payment]{Items: resp.QueryResponse.Payments}
-	if len(result.Items) == pageSize {
-		result.NextPageToken = strconv.Itoa(startPosition + pageSize)
-	}
-
-	return result, nil
+	return listPage[Payment](c, "Payment", pageToken, pageSize)
 }
 
 // UpdatePayment updates the given payment in QuickBooks.
@@ -194,30 +275,36 @@ func (c *Client) UpdatePayment(payment *Payment) (*Payment, error) {
 		Sparse:  true,
 	}
 
-	var paymentData struct {
-		Payment Payment
-		Time    Date
-	}
-
-	if err = c.post("payment", payload, &paymentData, nil); err != nil {
-		return nil, err
-	}
-
-	return &paymentData.Payment, err
+	return updateSingle[Payment](c, "payment", "payment/"+payment.ID, &payment.SyncToken, payload)
 }
 
-// VoidPayment voids the given payment in QuickBooks.
+// VoidPayment voids the given payment in QuickBooks. The payment stays on file with its
+// TotalAmt zeroed and "Voided" as its PrivateNote, and the invoices it paid are open again.
+// Only the Id and SyncToken of payment are sent; on success *payment is replaced by the voided
+// payment QuickBooks returned.
 func (c *Client) VoidPayment(payment *Payment) error {
 	if payment.ID == "" {
 		return errors.New("missing payment id")
 	}
 
-	existingPayment, err := c.FindPaymentByID(payment.ID)
+	syncToken, err := c.currentSyncToken("payment/" + payment.ID)
 	if err != nil {
 		return err
 	}
 
-	payment.SyncToken = existingPayment.SyncToken
+	// Only Id and SyncToken are sent, so local changes to *payment are not saved by the void.
+	payload := &sparseFields{ID: payment.ID, SyncToken: syncToken}
+
+	var body json.RawMessage
+	if err := c.postRetryingStale("payment", "payment/"+payment.ID, &payload.SyncToken, payload, &body, map[string]string{"operation": "update", "include": "void"}); err != nil {
+		return err
+	}
+
+	voided, err := decodeSingle[Payment](body)
+	if err != nil {
+		return err
+	}
 
-	return c.post("payment", payment, nil, map[string]string{"operation": "update", "include": "void"})
+	*payment = *voided
+	return nil
 }
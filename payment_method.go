@@ -2,13 +2,13 @@ package quickbooks
 
 import (
 	"errors"
-	"fmt"
-	"strconv"
 )
 
 // PaymentMethod represents a QuickBooks PaymentMethod object as returned by the API.
 // Read-only fields (Id, SyncToken, MetaData) are populated by the service.
 type PaymentMethod struct {
+	EntityMeta
+
 	ID        string    `json:"Id,omitempty"`
 	SyncToken string    `json:",omitempty"`
 	MetaData  *MetaData `json:",omitempty"`
@@ -23,21 +23,14 @@ type PaymentMethodCreateInput struct {
 	Name   string  `json:",omitempty"`
 	Type   *string `json:",omitempty"`
 	Active *bool   `json:",omitempty"`
+	// Extra holds fields the struct does not model yet; see ExtraFields.
+	Extra ExtraFields `json:"-"`
 }
 
 // CreatePaymentMethod creates the given PaymentMethod on the QuickBooks server, returning
 // the resulting PaymentMethod object.
 func (c *Client) CreatePaymentMethod(input *PaymentMethodCreateInput) (*PaymentMethod, error) {
-	var resp struct {
-		PaymentMethod PaymentMethod
-		Time          Date
-	}
-
-	if err := c.post("paymentmethod", input, &resp, nil); err != nil {
-		return nil, err
-	}
-
-	return &resp.PaymentMethod, nil
+	return postSingle[PaymentMethod](c, "paymentmethod", input, nil)
 }
 
 // DeletePaymentMethod deletes the payment method.
@@ -46,118 +39,44 @@ func (c *Client) DeletePaymentMethod(paymentMethod *PaymentMethod) error {
 		return errors.New("missing id/sync token")
 	}
 
-	return c.post("paymentmethod", paymentMethod, nil, map[string]string{"operation": "delete"})
+	return c.postRetryingStale("paymentmethod", "paymentmethod/"+paymentMethod.ID, &paymentMethod.SyncToken, paymentMethod, nil, map[string]string{"operation": "delete"})
 }
 
 // FindPaymentMethods gets the full list of PaymentMethods in the QuickBooks account.
 func (c *Client) FindPaymentMethods() ([]PaymentMethod, error) {
-	var resp struct {
-		QueryResponse struct {
-			PaymentMethods []PaymentMethod `json:"PaymentMethod"`
-			MaxResults     int
-			StartPosition  int
-			TotalCount     int
-		}
-	}
-
-	if err := c.query("SELECT COUNT(*) FROM PaymentMethod", &resp); err != nil {
-		return nil, err
-	}
-
-	if resp.QueryResponse.TotalCount == 0 {
-		return nil, errors.New("no payment methods could be found")
-	}
-
-	paymentMethods := make([]PaymentMethod, 0, resp.QueryResponse.TotalCount)
-
-	for i := 0; i < resp.QueryResponse.TotalCount; i += queryPageSize {
-		query := "SELECT * FROM PaymentMethod ORDERBY Id STARTPOSITION " + strconv.Itoa(i+1) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
-
-		if err := c.query(query, &resp); err != nil {
-			return nil, err
-		}
-
-		if resp.QueryResponse.PaymentMethods == nil {
-			return nil, errors.New("no payment methods could be found")
-		}
-
-		paymentMethods = append(paymentMethods, resp.QueryResponse.PaymentMethods...)
-	}
+	return findAllPages[PaymentMethod](c, "PaymentMethod", "no payment methods could be found")
+}
 
-	return paymentMethods, nil
+// FindPaymentMethodsWithOptions returns the payment methods matching opts, ordered by opts.OrderBy.
+// Pass nil for opts to list them all.
+func (c *Client) FindPaymentMethodsWithOptions(opts *ListOptions) ([]PaymentMethod, error) {
+	return findAllWithOptions[PaymentMethod](c, "PaymentMethod", true, opts)
 }
 
 // FindPaymentMethodByID returns a payment method with a given Id.
 func (c *Client) FindPaymentMethodByID(id string) (*PaymentMethod, error) {
-	var resp struct {
-		PaymentMethod PaymentMethod
-		Time          Date
-	}
-
-	if err := c.get("paymentmethod/"+id, &resp, nil); err != nil {
-		return nil, err
-	}
-
-	return &resp.PaymentMethod, nil
+	return getSingle[PaymentMethod](c, "paymentmethod/"+id, nil)
 }
 
 // QueryPaymentMethods accepts an SQL query and returns all payment methods found using it.
 func (c *Client) QueryPaymentMethods(query string) ([]PaymentMethod, error) {
-	var resp struct {
-		QueryResponse struct {
-			PaymentMethods []PaymentMethod `json:"PaymentMethod"`
-			StartPosition  int
-			MaxResults     int
-		}
-	}
-
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
+	paymentMethods, err := queryEntities[PaymentMethod](c, "PaymentMethod", query)
+	if err != nil {
+		return paymentMethods, err
 	}
 
-	if resp.QueryResponse.PaymentMethods == nil {
+	if paymentMethods == nil {
 		return nil, errors.New("could not find any payment methods")
 	}
 
-	return resp.QueryResponse.PaymentMethods, nil
+	return paymentMethods, nil
 }
 
 // ListPaymentMethods returns one page of PaymentMethods ordered by Id.
 // Pass an empty pageToken to start from the beginning.
 // The returned nextPageToken is empty when there are no more results.
 func (c *Client) ListPaymentMethods(pageToken string, pageSize int) (*ListResponse[PaymentMethod], error) {
-	if pageSize <= 0 || pageSize > queryPageSize {
-		pageSize = queryPageSize
-	}
-
-	startPosition := 1
-	if pageToken != "" {
-		var err error
-		startPosition, err = strconv.Atoi(pageToken)
-		if err != nil {
-			return nil, fmt.Errorf("invalid page token: %v", err)
-		}
-	}
-
-	var resp struct {
-		QueryResponse struct {
-			PaymentMethods []PaymentMethod `json:"PaymentMethod"`
-			StartPosition  int
-			MaxResults     int
-		}
-	}
-
-	query := "SELECT * FROM PaymentMethod ORDERBY Id STARTPOSITION " + strconv.Itoa(startPosition) + " MAXRESULTS " + strconv.Itoa(pageSize)
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
-	}
-
-	result := &ListResponse[PaymentMethod]{Items: resp.QueryResponse.PaymentMethods}
-	if len(result.Items) == pageSize {
-		result.NextPageToken = strconv.Itoa(startPosition + pageSize)
-	}
-
-	return result, nil
+	return listPage[PaymentMethod](c, "PaymentMethod", pageToken, pageSize)
 }
 
 // UpdatePaymentMethod updates the payment method.
@@ -181,14 +100,5 @@ func (c *Client) UpdatePaymentMethod(paymentMethod *PaymentMethod) (*PaymentMeth
 		Sparse:        true,
 	}
 
-	var paymentMethodData struct {
-		PaymentMethod PaymentMethod
-		Time          Date
-	}
-
-	if err = c.post("paymentmethod", payload, &paymentMethodData, nil); err != nil {
-		return nil, err
-	}
-
-	return &paymentMethodData.PaymentMethod, err
+	return updateSingle[PaymentMethod](c, "paymentmethod", "paymentmethod/"+paymentMethod.ID, &paymentMethod.SyncToken, payload)
 }
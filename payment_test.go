@@ -0,0 +1,203 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyCreditMemo(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v3/company/test-realm/invoice/130":
+			w.Write([]byte(`{"Invoice":{"Id":"130","SyncToken":"0","CustomerRef":{"value":"1"},"Line":[]}}`))
+		case "/v3/company/test-realm/creditmemo/73":
+			w.Write([]byte(`{"CreditMemo":{"Id":"73","SyncToken":"0","CustomerRef":{"value":"1"}}}`))
+		case "/v3/company/test-realm/payment":
+			require.Equal(t, http.MethodPost, r.Method)
+
+			var body struct {
+				CustomerRef ReferenceType
+				TotalAmt    json.Number
+				Line        []PaymentLine
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+			assert.Equal(t, "1", body.CustomerRef.Value)
+			assert.Equal(t, json.Number("0"), body.TotalAmt)
+			require.Len(t, body.Line, 2)
+			assert.Equal(t, json.Number("25.00"), body.Line[0].Amount)
+			assert.Equal(t, LinkedTxn{TxnID: "130", TxnType: "Invoice"}, body.Line[0].LinkedTxn[0])
+			assert.Equal(t, json.Number("25.00"), body.Line[1].Amount)
+			assert.Equal(t, LinkedTxn{TxnID: "73", TxnType: "CreditMemo"}, body.Line[1].LinkedTxn[0])
+
+			w.Write([]byte(`{"Payment":{"Id":"200","SyncToken":"0","TotalAmt":0}}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	payment, err := client.ApplyCreditMemo("73", "130", "25.00")
+	require.NoError(t, err)
+	assert.Equal(t, "200", payment.ID)
+}
+
+func TestApplyCreditMemoCustomerMismatch(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v3/company/test-realm/invoice/130":
+			w.Write([]byte(`{"Invoice":{"Id":"130","CustomerRef":{"value":"1"},"Line":[]}}`))
+		case "/v3/company/test-realm/creditmemo/73":
+			w.Write([]byte(`{"CreditMemo":{"Id":"73","CustomerRef":{"value":"2"}}}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	})
+
+	_, err := client.ApplyCreditMemo("73", "130", "25.00")
+	assert.Error(t, err)
+}
+
+func TestPaymentIsFullyApplied(t *testing.T) {
+	var p Payment
+	require.NoError(t, json.Unmarshal([]byte(`{"Id":"190","TotalAmt":300,"UnappliedAmt":25.5,"ProcessPayment":false}`), &p))
+	assert.Equal(t, json.Number("25.5"), p.UnappliedAmt)
+	require.NotNil(t, p.ProcessPayment)
+	assert.False(t, *p.ProcessPayment)
+	assert.False(t, p.IsFullyApplied())
+
+	assert.True(t, (&Payment{UnappliedAmt: "0.00"}).IsFullyApplied())
+	assert.True(t, (&Payment{}).IsFullyApplied())
+}
+
+func TestCreateInvoiceAndReceivePayment(t *testing.T) {
+	failPayment := false
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/company/test-realm/invoice":
+			w.Write([]byte(`{"Invoice":{"Id":"130","SyncToken":"0","CustomerRef":{"value":"1"},"TotalAmt":60,"Balance":50,"Deposit":10,"Line":[]}}`))
+		case "/v3/company/test-realm/payment":
+			if failPayment {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"Fault":{"Error":[{"Message":"Invalid account","code":"2500"}],"type":"ValidationFault"}}`))
+				return
+			}
+
+			var body PaymentCreateInput
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, json.Number("50"), body.TotalAmt)
+			assert.Equal(t, "35", body.DepositToAccountRef.Value)
+			require.Len(t, body.Line, 1)
+			assert.Equal(t, LinkedTxn{TxnID: "130", TxnType: "Invoice"}, body.Line[0].LinkedTxn[0])
+
+			w.Write([]byte(`{"Payment":{"Id":"200","SyncToken":"0","TotalAmt":50}}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	})
+
+	input := &InvoiceCreateInput{
+		CustomerRef: ReferenceType{NameValue: NameValue{Value: "1"}},
+		Line:        []Line{{Amount: "60", DetailType: SalesItemLineDetailType}},
+	}
+
+	invoice, payment, err := client.CreateInvoiceAndReceivePayment(input, "35")
+	require.NoError(t, err)
+	assert.Equal(t, "130", invoice.ID)
+	assert.Equal(t, "200", payment.ID)
+
+	failPayment = true
+	invoice, payment, err = client.CreateInvoiceAndReceivePayment(input, "35")
+	var paymentErr *InvoicePaymentError
+	require.ErrorAs(t, err, &paymentErr)
+	assert.Equal(t, "130", paymentErr.Invoice.ID)
+	assert.Equal(t, "130", invoice.ID)
+	assert.Nil(t, payment)
+}
+
+func TestCreateInvoiceAndReceivePaymentForeignCurrency(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/company/test-realm/invoice":
+			w.Write([]byte(`{"Invoice":{"Id":"131","SyncToken":"0","CustomerRef":{"value":"7"},"CurrencyRef":{"value":"EUR","name":"Euro"},"ExchangeRate":1.08,"TotalAmt":100,"Balance":100,"Line":[]}}`))
+		case "/v3/company/test-realm/payment":
+			var body PaymentCreateInput
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.NotNil(t, body.CurrencyRef)
+			assert.Equal(t, "EUR", body.CurrencyRef.Value)
+			assert.Equal(t, json.Number("1.08"), body.ExchangeRate)
+			assert.Equal(t, json.Number("100"), body.TotalAmt)
+
+			w.Write([]byte(`{"Payment":{"Id":"201","SyncToken":"0","TotalAmt":100,"CurrencyRef":{"value":"EUR"},"ExchangeRate":1.08}}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	})
+
+	_, payment, err := client.CreateInvoiceAndReceivePayment(&InvoiceCreateInput{
+		CustomerRef: ReferenceType{NameValue: NameValue{Value: "7"}},
+		CurrencyRef: &ReferenceType{NameValue: NameValue{Value: "EUR"}},
+		Line:        []Line{{Amount: "100", DetailType: SalesItemLineDetailType}},
+	}, "35")
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", payment.CurrencyRef.Value)
+}
+
+func TestPaymentApplications(t *testing.T) {
+	var p Payment
+	require.NoError(t, json.Unmarshal([]byte(`{
+  "Id": "190",
+  "TotalAmt": 300,
+  "Line": [
+    {"Amount": 200, "LinkedTxn": [{"TxnId": "130", "TxnType": "Invoice"}]},
+    {"Amount": 75.5, "LinkedTxn": [{"TxnId": "131", "TxnType": "Invoice"}]},
+    {"Amount": 24.5, "LinkedTxn": [{"TxnId": "73", "TxnType": "CreditMemo"}]}
+  ]
+}`), &p))
+
+	assert.Equal(t, []PaymentApplication{
+		{TxnType: "Invoice", TxnID: "130", Amount: "200"},
+		{TxnType: "Invoice", TxnID: "131", Amount: "75.5"},
+		{TxnType: "CreditMemo", TxnID: "73", Amount: "24.5"},
+	}, p.Applications())
+	assert.Equal(t, []string{"130", "131"}, p.InvoiceIDs())
+	assert.Nil(t, (&Payment{}).Applications())
+}
+
+func TestPaymentRefNumRoundTrip(t *testing.T) {
+	refNum := "4471"
+
+	for _, v := range []any{
+		&Payment{PaymentRefNum: &refNum},
+		&PaymentCreateInput{PaymentRefNum: &refNum},
+		&SalesReceipt{PaymentRefNum: &refNum},
+		&SalesReceiptCreateInput{PaymentRefNum: &refNum},
+		&RefundReceipt{PaymentRefNum: &refNum},
+		&RefundReceiptCreateInput{PaymentRefNum: &refNum},
+	} {
+		b, err := json.Marshal(v)
+		require.NoError(t, err)
+		assert.Contains(t, string(b), `"PaymentRefNum":"4471"`)
+	}
+
+	var receipt SalesReceipt
+	require.NoError(t, json.Unmarshal([]byte(`{"Id":"12","PaymentRefNum":"4471"}`), &receipt))
+	require.NotNil(t, receipt.PaymentRefNum)
+	assert.Equal(t, refNum, *receipt.PaymentRefNum)
+
+	var refund RefundReceipt
+	require.NoError(t, json.Unmarshal([]byte(`{"Id":"13","PaymentRefNum":"4471"}`), &refund))
+	assert.Equal(t, refNum, *refund.PaymentRefNum)
+
+	var payment Payment
+	require.NoError(t, json.Unmarshal([]byte(`{"Id":"14","PaymentRefNum":"4471"}`), &payment))
+	assert.Equal(t, refNum, *payment.PaymentRefNum)
+}
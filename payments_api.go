@@ -0,0 +1,234 @@
+package quickbooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// The Payments API (api.intuit.com/quickbooks/v4/payments) processes card payments and is
+// separate from the Accounting API the rest of this package targets. It shares the OAuth
+// transport of the Client but needs the com.intuit.quickbooks.payment scope.
+//
+// Every write takes a requestID which QuickBooks uses as an idempotency key: retrying a call
+// with the same requestID does not charge or refund twice. Use a new one for each operation.
+
+// Charge statuses returned by the Payments API.
+const (
+	ChargeStatusAuthorized = "AUTHORIZED"
+	ChargeStatusCaptured   = "CAPTURED"
+	ChargeStatusDeclined   = "DECLINED"
+	ChargeStatusRefunded   = "REFUNDED"
+	ChargeStatusSettled    = "SETTLED"
+	ChargeStatusVoided     = "VOIDED"
+)
+
+// CardAddress is the billing address of a card.
+type CardAddress struct {
+	StreetAddress string `json:"streetAddress,omitempty"`
+	City          string `json:"city,omitempty"`
+	Region        string `json:"region,omitempty"`
+	Country       string `json:"country,omitempty"`
+	PostalCode    string `json:"postalCode,omitempty"`
+}
+
+// Card holds the card details of a charge or token request.
+// Responses only carry the masked number.
+type Card struct {
+	Number   string       `json:"number,omitempty"`
+	ExpMonth string       `json:"expMonth,omitempty"`
+	ExpYear  string       `json:"expYear,omitempty"`
+	CVC      string       `json:"cvc,omitempty"`
+	Name     string       `json:"name,omitempty"`
+	Address  *CardAddress `json:"address,omitempty"`
+	CardType string       `json:"cardType,omitempty"`
+}
+
+// PaymentContext describes the circumstances of a charge.
+type PaymentContext struct {
+	Mobile      bool        `json:"mobile"`
+	IsEcommerce bool        `json:"isEcommerce"`
+	Tax         json.Number `json:"tax,string,omitempty"`
+}
+
+// ChargeRefund is a refund issued against a charge.
+type ChargeRefund struct {
+	ID          string          `json:"id,omitempty"`
+	Amount      json.Number     `json:"amount,string"`
+	Description string          `json:"description,omitempty"`
+	Context     *PaymentContext `json:"context,omitempty"`
+	Status      string          `json:"status,omitempty"`
+	Created     *time.Time      `json:"created,omitempty"`
+}
+
+// Charge is a card charge of the Payments API.
+type Charge struct {
+	ID          string          `json:"id,omitempty"`
+	Status      string          `json:"status,omitempty"`
+	Amount      json.Number     `json:"amount,string"`
+	Currency    string          `json:"currency"`
+	Token       string          `json:"token,omitempty"`
+	Card        *Card           `json:"card,omitempty"`
+	Capture     bool            `json:"capture"`
+	Context     *PaymentContext `json:"context,omitempty"`
+	Description string          `json:"description,omitempty"`
+	AuthCode    string          `json:"authCode,omitempty"`
+	Created     *time.Time      `json:"created,omitempty"`
+	Refunds     []ChargeRefund  `json:"refundDetail,omitempty"`
+}
+
+// PaymentsError is a single error of a Payments API error response.
+type PaymentsError struct {
+	Code     string `json:"code"`
+	Type     string `json:"type"`
+	Message  string `json:"message"`
+	Detail   string `json:"detail"`
+	MoreInfo string `json:"moreInfo"`
+}
+
+// PaymentsFailure is returned when the Payments API rejects a request.
+// Its shape differs from the Accounting API's Failure.
+type PaymentsFailure struct {
+	StatusCode int
+	Errors     []PaymentsError `json:"errors"`
+}
+
+// Error implements the error interface.
+func (f PaymentsFailure) Error() string {
+	if len(f.Errors) == 0 {
+		return "payments API error: " + strconv.Itoa(f.StatusCode)
+	}
+
+	e := f.Errors[0]
+	return fmt.Sprintf("payments API error: %d %s %s: %s", f.StatusCode, e.Code, e.Message, e.Detail)
+}
+
+// CreateToken exchanges card details for a single-use token that can be charged with
+// CreateCharge, so raw card numbers never need to reach the caller's servers again.
+func (c *Client) CreateToken(card *Card) (string, error) {
+	if card == nil {
+		return "", errors.New("missing card")
+	}
+
+	var resp struct {
+		Value string `json:"value"`
+	}
+
+	if err := c.paymentsReq("tokens", "", struct {
+		Card *Card `json:"card"`
+	}{card}, &resp); err != nil {
+		return "", err
+	}
+
+	return resp.Value, nil
+}
+
+// CreateCharge creates a charge. Set Capture to false to only authorize the amount and
+// capture it later with CaptureCharge.
+func (c *Client) CreateCharge(requestID string, charge *Charge) (*Charge, error) {
+	if charge.Token == "" && charge.Card == nil {
+		return nil, errors.New("a charge needs either a token or a card")
+	}
+
+	var resp Charge
+	if err := c.paymentsReq("charges", requestID, charge, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// CaptureCharge captures a charge previously created with Capture set to false.
+// amount may be lower than the authorized amount but not higher.
+func (c *Client) CaptureCharge(requestID string, chargeID string, amount json.Number, context *PaymentContext) (*Charge, error) {
+	if chargeID == "" {
+		return nil, errors.New("missing charge id")
+	}
+
+	payload := struct {
+		Amount  json.Number     `json:"amount,string"`
+		Context *PaymentContext `json:"context,omitempty"`
+	}{amount, context}
+
+	var resp Charge
+	if err := c.paymentsReq("charges/"+chargeID+"/capture", requestID, payload, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// RefundCharge refunds amount of a captured charge.
+func (c *Client) RefundCharge(requestID string, chargeID string, refund *ChargeRefund) (*ChargeRefund, error) {
+	if chargeID == "" {
+		return nil, errors.New("missing charge id")
+	}
+
+	var resp ChargeRefund
+	if err := c.paymentsReq("charges/"+chargeID+"/refunds", requestID, refund, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// paymentsReq POSTs payloadData to the Payments API endpoint and decodes the response into responseObject.
+func (c *Client) paymentsReq(endpoint string, requestID string, payloadData any, responseObject any) (e error) {
+	if c.paymentsEndpoint == nil {
+		return errors.New("payments endpoint is not configured")
+	}
+
+	endpointURL := *c.paymentsEndpoint
+	endpointURL.Path += endpoint
+
+	marshalledJson, err := json.Marshal(payloadData)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(c.context(), "POST", endpointURL.String(), bytes.NewBuffer(marshalledJson))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Content-Type", "application/json")
+	if requestID != "" {
+		req.Header.Set("Request-Id", requestID)
+	}
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil && e == nil {
+			e = closeErr
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		msg, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return errors.New("When reading response body:" + err.Error())
+		}
+
+		failure := PaymentsFailure{StatusCode: resp.StatusCode}
+		if err = json.Unmarshal(msg, &failure); err != nil {
+			return errors.New(strconv.Itoa(resp.StatusCode) + " " + string(msg))
+		}
+		return failure
+	}
+
+	if err = json.NewDecoder(resp.Body).Decode(responseObject); err != nil {
+		return fmt.Errorf("failed to unmarshal response into object: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateCharge(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/quickbooks/v4/payments/charges", r.URL.Path)
+		assert.Equal(t, "req-1", r.Header.Get("Request-Id"))
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"amount":"10.55","currency":"USD","token":"tok-123","capture":false}`, string(body))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"EMU891",  "status":"AUTHORIZED","amount":"10.55","currency":"USD","capture":false,"authCode":"664472","created":"2014-11-04T23:24:58Z"}`))
+	})
+
+	charge, err := client.CreateCharge("req-1", &Charge{Amount: "10.55", Currency: "USD", Token: "tok-123"})
+	require.NoError(t, err)
+	assert.Equal(t, "EMU891", charge.ID)
+	assert.Equal(t, ChargeStatusAuthorized, charge.Status)
+	assert.Equal(t, json.Number("10.55"), charge.Amount)
+	assert.Equal(t, "664472", charge.AuthCode)
+}
+
+func TestCaptureChargeFailure(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/quickbooks/v4/payments/charges/EMU891/capture", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"errors":[{"code":"PMT-4000","type":"invalid_request","message":"amount is invalid.","detail":"amount","moreInfo":"Amount exceeds authorized amount"}]}`))
+	})
+
+	_, err := client.CaptureCharge("req-2", "EMU891", "20.00", nil)
+	require.Error(t, err)
+
+	var failure PaymentsFailure
+	require.True(t, errors.As(err, &failure))
+	assert.Equal(t, http.StatusBadRequest, failure.StatusCode)
+	require.Len(t, failure.Errors, 1)
+	assert.Equal(t, "PMT-4000", failure.Errors[0].Code)
+}
@@ -0,0 +1,235 @@
+package quickbooks
+
+import (
+	"errors"
+)
+
+// ErrCustomTxnNumbersDisabled is returned when a DocNumber is supplied but the company has
+// custom transaction numbers turned off, in which case QuickBooks ignores or rejects it.
+var ErrCustomTxnNumbersDisabled = errors.New("custom transaction numbers are disabled in the company preferences")
+
+// AccountingInfoPrefs holds the accounting preferences of the company.
+type AccountingInfoPrefs struct {
+	FirstMonthOfFiscalYear  *string `json:",omitempty"`
+	UseAccountNumbers       *bool   `json:",omitempty"`
+	TaxYearMonth            *string `json:",omitempty"`
+	ClassTrackingPerTxn     *bool   `json:",omitempty"`
+	ClassTrackingPerTxnLine *bool   `json:",omitempty"`
+	TrackDepartments        *bool   `json:",omitempty"`
+	DepartmentTerminology   *string `json:",omitempty"`
+	CustomerTerminology     *string `json:",omitempty"`
+	BookCloseDate           *Date   `json:",omitempty"`
+}
+
+// ProductAndServicesPrefs holds the products and services preferences of the company.
+type ProductAndServicesPrefs struct {
+	ForSales                 *bool `json:",omitempty"`
+	ForPurchase              *bool `json:",omitempty"`
+	QuantityWithPriceAndRate *bool `json:",omitempty"`
+	QuantityOnHand           *bool `json:",omitempty"`
+}
+
+// SalesFormsPrefs holds the sales form preferences of the company.
+type SalesFormsPrefs struct {
+	CustomTxnNumbers           *bool          `json:",omitempty"`
+	AllowDeposit               *bool          `json:",omitempty"`
+	AllowDiscount              *bool          `json:",omitempty"`
+	AllowEstimates             *bool          `json:",omitempty"`
+	AllowServiceDate           *bool          `json:",omitempty"`
+	AllowShipping              *bool          `json:",omitempty"`
+	AutoApplyCredit            *bool          `json:",omitempty"`
+	AutoApplyPayments          *bool          `json:",omitempty"`
+	DefaultDiscountAccount     *string        `json:",omitempty"`
+	DefaultTerms               *ReferenceType `json:",omitempty"`
+	DefaultCustomerMessage     *string        `json:",omitempty"`
+	EmailCopyToCompany         *bool          `json:",omitempty"`
+	IPNSupportEnabled          *bool          `json:",omitempty"`
+	ETransactionEnabledStatus  *string        `json:",omitempty"`
+	ETransactionAttachPDF      *bool          `json:",omitempty"`
+	ETransactionPaymentEnabled *bool          `json:",omitempty"`
+
+	// CustomField holds the custom field definitions of the sales forms; see
+	// Preferences.CustomFieldDefinitions.
+	CustomField []CustomFieldPrefs `json:",omitempty"`
+}
+
+// VendorAndPurchasesPrefs holds the vendor and purchase preferences of the company.
+type VendorAndPurchasesPrefs struct {
+	TrackingByCustomer      *bool          `json:",omitempty"`
+	BillableExpenseTracking *bool          `json:",omitempty"`
+	DefaultTerms            *ReferenceType `json:",omitempty"`
+	DefaultMarkup           *string        `json:",omitempty"`
+}
+
+// TaxPrefs holds the sales tax preferences of the company.
+type TaxPrefs struct {
+	UsingSalesTax *bool `json:",omitempty"`
+	// PartnerTaxEnabled is true for US companies on Automated Sales Tax.
+	PartnerTaxEnabled *bool          `json:",omitempty"`
+	TaxGroupCodeRef   *ReferenceType `json:",omitempty"`
+}
+
+// CurrencyPrefs holds the currency preferences of the company.
+type CurrencyPrefs struct {
+	MultiCurrencyEnabled *bool          `json:",omitempty"`
+	HomeCurrency         *ReferenceType `json:",omitempty"`
+}
+
+// ReportPrefs holds the report preferences of the company.
+type ReportPrefs struct {
+	ReportBasis                *string `json:",omitempty"`
+	CalcAgingReportFromTxnDate *bool   `json:",omitempty"`
+}
+
+// OtherPrefs holds the preferences QuickBooks exposes as free-form name/value pairs.
+type OtherPrefs struct {
+	NameValue []NameValue `json:",omitempty"`
+}
+
+// Preferences represents the QuickBooks Preferences object of the company.
+// Read-only fields (Id, SyncToken, MetaData) are populated by the service.
+type Preferences struct {
+	EntityMeta
+
+	ID                      string                   `json:"Id,omitempty"`
+	SyncToken               string                   `json:",omitempty"`
+	MetaData                *MetaData                `json:",omitempty"`
+	AccountingInfoPrefs     *AccountingInfoPrefs     `json:",omitempty"`
+	ProductAndServicesPrefs *ProductAndServicesPrefs `json:",omitempty"`
+	SalesFormsPrefs         *SalesFormsPrefs         `json:",omitempty"`
+	VendorAndPurchasesPrefs *VendorAndPurchasesPrefs `json:",omitempty"`
+	TaxPrefs                *TaxPrefs                `json:",omitempty"`
+	CurrencyPrefs           *CurrencyPrefs           `json:",omitempty"`
+	ReportPrefs             *ReportPrefs             `json:",omitempty"`
+	OtherPrefs              *OtherPrefs              `json:",omitempty"`
+}
+
+// CustomTxnNumbersEnabled reports whether the company lets callers choose their own
+// transaction numbers (DocNumber). QuickBooks treats a missing setting as disabled.
+func (p *Preferences) CustomTxnNumbersEnabled() bool {
+	return p.SalesFormsPrefs != nil && p.SalesFormsPrefs.CustomTxnNumbers != nil && *p.SalesFormsPrefs.CustomTxnNumbers
+}
+
+// CheckDocNumber returns ErrCustomTxnNumbersDisabled if docNumber is set while custom
+// transaction numbers are disabled. Set Client.DocNumbers to have the Create methods of the
+// sales forms run this check, or clear the DocNumber instead of failing.
+func (p *Preferences) CheckDocNumber(docNumber *string) error {
+	if docNumber != nil && *docNumber != "" && !p.CustomTxnNumbersEnabled() {
+		return ErrCustomTxnNumbersDisabled
+	}
+
+	return nil
+}
+
+// DocNumberPolicy tells the Create methods of the sales forms (invoices, estimates, sales
+// receipts, credit memos and refund receipts) what to do with a DocNumber when the company
+// has custom transaction numbers turned off; see Client.DocNumbers.
+type DocNumberPolicy int
+
+const (
+	// DocNumberSend sends the DocNumber as given. QuickBooks ignores or rejects it, depending
+	// on the edition.
+	DocNumberSend DocNumberPolicy = iota
+	// DocNumberCheck fails the create with ErrCustomTxnNumbersDisabled before sending it.
+	DocNumberCheck
+	// DocNumberStrip clears the DocNumber and lets QuickBooks number the transaction.
+	DocNumberStrip
+)
+
+// applyDocNumberPolicy applies c.DocNumbers to the DocNumber of a sales form about to be
+// created. It only reads the preferences when a DocNumber is set and the policy is not
+// DocNumberSend.
+func (c *Client) applyDocNumberPolicy(docNumber **string) error {
+	if c.DocNumbers == DocNumberSend || *docNumber == nil || **docNumber == "" {
+		return nil
+	}
+
+	preferences, err := c.FindPreferences()
+	if err != nil {
+		return err
+	}
+
+	err = preferences.CheckDocNumber(*docNumber)
+	if err != nil && c.DocNumbers == DocNumberStrip {
+		*docNumber = nil
+		return nil
+	}
+	return err
+}
+
+// HomeCurrency returns the ISO 4217 code of the company's home currency (e.g. "USD"), the
+// currency of the HomeTotalAmt and HomeBalance fields, or "" if QuickBooks did not report it.
+func (p *Preferences) HomeCurrency() string {
+	if p.CurrencyPrefs == nil || p.CurrencyPrefs.HomeCurrency == nil {
+		return ""
+	}
+	return p.CurrencyPrefs.HomeCurrency.Value
+}
+
+// MultiCurrencyEnabled reports whether the company has multicurrency turned on, in which case
+// transactions carry a CurrencyRef and an ExchangeRate.
+func (p *Preferences) MultiCurrencyEnabled() bool {
+	return p.CurrencyPrefs != nil && boolValue(p.CurrencyPrefs.MultiCurrencyEnabled, false)
+}
+
+// FindPreferences returns the QuickBooks Preferences object of the company.
+func (c *Client) FindPreferences() (*Preferences, error) {
+	return getSingle[Preferences](c, "preferences", nil)
+}
+
+// UpdatePreferences updates the company preferences.
+func (c *Client) UpdatePreferences(preferences *Preferences) (*Preferences, error) {
+	existingPreferences, err := c.FindPreferences()
+	if err != nil {
+		return nil, err
+	}
+
+	preferences.ID = existingPreferences.ID
+	preferences.SyncToken = existingPreferences.SyncToken
+
+	payload := struct {
+		*Preferences
+		Sparse bool `json:"sparse"`
+	}{
+		Preferences: preferences,
+		Sparse:      true,
+	}
+
+	return updateSingle[Preferences](c, "preferences", "preferences", &preferences.SyncToken, payload)
+}
+
+// HomeCurrency returns the home currency code of the company from its preferences.
+func (c *Client) HomeCurrency() (string, error) {
+	preferences, err := c.FindPreferences()
+	if err != nil {
+		return "", err
+	}
+
+	currency := preferences.HomeCurrency()
+	if currency == "" {
+		return "", errors.New("the company preferences have no home currency")
+	}
+	return currency, nil
+}
+
+// EnableMultiCurrency turns on multicurrency for the company.
+//
+// WARNING: this cannot be undone. QuickBooks offers no way to turn multicurrency off again,
+// neither through the API nor in the UI, and it changes how every later transaction, report
+// and accounts receivable/payable balance is handled. The home currency can no longer be
+// changed either. Only call it after the company owner has explicitly agreed.
+//
+// It does nothing if multicurrency is already on.
+func (c *Client) EnableMultiCurrency() (*Preferences, error) {
+	existing, err := c.FindPreferences()
+	if err != nil {
+		return nil, err
+	}
+
+	if existing.MultiCurrencyEnabled() {
+		return existing, nil
+	}
+
+	enabled := true
+	return c.UpdatePreferences(&Preferences{CurrencyPrefs: &CurrencyPrefs{MultiCurrencyEnabled: &enabled}})
+}
@@ -0,0 +1,161 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreferences(t *testing.T) {
+	byteValue := json.RawMessage(`
+{
+  "Preferences": {
+    "SalesFormsPrefs": {
+      "CustomTxnNumbers": false,
+      "AllowDeposit": true,
+      "AllowDiscount": true,
+      "DefaultTerms": {"value": "3"},
+      "ETransactionEnabledStatus": "NotApplicable"
+    },
+    "TaxPrefs": {
+      "TaxGroupCodeRef": {"value": "5"},
+      "UsingSalesTax": true
+    },
+    "CurrencyPrefs": {
+      "MultiCurrencyEnabled": false,
+      "HomeCurrency": {"value": "USD"}
+    },
+    "AccountingInfoPrefs": {
+      "BookCloseDate": "2014-09-30",
+      "TrackDepartments": false
+    },
+    "domain": "QBO",
+    "SyncToken": "6",
+    "Id": "1",
+    "sparse": false,
+    "MetaData": {
+      "CreateTime": "2014-09-16T14:59:48-07:00",
+      "LastUpdatedTime": "2015-06-18T11:21:40-07:00"
+    }
+  },
+  "time": "2015-07-22T13:57:27.84-07:00"
+}`)
+
+	var r struct {
+		Preferences Preferences
+		Time        Date
+	}
+	require.NoError(t, json.Unmarshal(byteValue, &r))
+
+	prefs := r.Preferences
+	assert.Equal(t, "1", prefs.ID)
+	assert.Equal(t, "6", prefs.SyncToken)
+	require.NotNil(t, prefs.SalesFormsPrefs)
+	assert.Equal(t, "3", prefs.SalesFormsPrefs.DefaultTerms.Value)
+	require.NotNil(t, prefs.TaxPrefs)
+	assert.True(t, *prefs.TaxPrefs.UsingSalesTax)
+	require.NotNil(t, prefs.CurrencyPrefs)
+	assert.Equal(t, "USD", prefs.CurrencyPrefs.HomeCurrency.Value)
+	assert.Equal(t, "2014-09-30", prefs.AccountingInfoPrefs.BookCloseDate.Format("2006-01-02"))
+	assert.Equal(t, "USD", prefs.HomeCurrency())
+	assert.False(t, prefs.MultiCurrencyEnabled())
+	assert.Equal(t, "", (&Preferences{}).HomeCurrency())
+
+	docNumber := "INV-1001"
+	assert.False(t, prefs.CustomTxnNumbersEnabled())
+	assert.ErrorIs(t, prefs.CheckDocNumber(&docNumber), ErrCustomTxnNumbersDisabled)
+	assert.NoError(t, prefs.CheckDocNumber(nil))
+
+	enabled := true
+	prefs.SalesFormsPrefs.CustomTxnNumbers = &enabled
+	assert.NoError(t, prefs.CheckDocNumber(&docNumber))
+}
+
+func TestEnableMultiCurrency(t *testing.T) {
+	enabled := false
+	var posted map[string]any
+
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&posted))
+			enabled = true
+		}
+		w.Write([]byte(`{"Preferences":{"Id":"1","SyncToken":"6","CurrencyPrefs":{"MultiCurrencyEnabled":` + strconv.FormatBool(enabled) + `,"HomeCurrency":{"value":"CAD"}}}}`))
+	})
+
+	currency, err := client.HomeCurrency()
+	require.NoError(t, err)
+	assert.Equal(t, "CAD", currency)
+
+	prefs, err := client.EnableMultiCurrency()
+	require.NoError(t, err)
+	assert.True(t, prefs.MultiCurrencyEnabled())
+	assert.Equal(t, map[string]any{"MultiCurrencyEnabled": true}, posted["CurrencyPrefs"])
+	assert.Equal(t, true, posted["sparse"])
+
+	// Already enabled: nothing is posted.
+	posted = nil
+	_, err = client.EnableMultiCurrency()
+	require.NoError(t, err)
+	assert.Nil(t, posted)
+}
+
+func TestCreateInvoiceDocNumberPolicy(t *testing.T) {
+	var posted []map[string]any
+	preferenceReads := 0
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/company/test-realm/preferences":
+			preferenceReads++
+			w.Write([]byte(`{"Preferences": {"Id": "1", "SalesFormsPrefs": {"CustomTxnNumbers": false}}}`))
+		case "/v3/company/test-realm/invoice":
+			var body map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			posted = append(posted, body)
+			w.Write([]byte(`{"Invoice": {"Id": "130", "DocNumber": "1038"}}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	})
+
+	newInput := func() *InvoiceCreateInput {
+		docNumber := "INV-7"
+		return &InvoiceCreateInput{
+			CustomerRef: ReferenceType{NameValue: NameValue{Value: "1"}},
+			DocNumber:   &docNumber,
+			Line: []Line{{Amount: "10", DetailType: SalesItemLineDetailType,
+				SalesItemLineDetail: SalesItemLineDetail{ItemRef: &ReferenceType{NameValue: NameValue{Value: "1"}}}}},
+		}
+	}
+
+	// DocNumberSend leaves the DocNumber alone without reading the preferences.
+	_, err := client.CreateInvoice(newInput())
+	require.NoError(t, err)
+	assert.Equal(t, "INV-7", posted[0]["DocNumber"])
+	assert.Equal(t, 0, preferenceReads)
+
+	client.DocNumbers = DocNumberCheck
+	_, err = client.CreateInvoice(newInput())
+	assert.ErrorIs(t, err, ErrCustomTxnNumbersDisabled)
+	assert.Len(t, posted, 1)
+
+	client.DocNumbers = DocNumberStrip
+	input := newInput()
+	_, err = client.CreateInvoice(input)
+	require.NoError(t, err)
+	require.Len(t, posted, 2)
+	assert.NotContains(t, posted[1], "DocNumber")
+	assert.Nil(t, input.DocNumber)
+
+	// Without a DocNumber there is nothing to check.
+	input = newInput()
+	input.DocNumber = nil
+	_, err = client.CreateInvoice(input)
+	require.NoError(t, err)
+	assert.Equal(t, 2, preferenceReads)
+}
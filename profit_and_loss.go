@@ -0,0 +1,206 @@
+package quickbooks
+
+import (
+	"encoding/json"
+)
+
+// ProfitAndLossQueryParams are the query parameters of the ProfitAndLoss report.
+type ProfitAndLossQueryParams struct {
+	// Cash or Accrual
+	AccountingMethod *string
+	StartDate        *string
+	EndDate          *string
+	// Predefined date range, e.g. "This Month", "Last Fiscal Year". Ignored when StartDate/EndDate are set.
+	DateMacro *string
+	// Total, Month, Week, Days, Quarter, Year, Customers, Vendors, Classes, Departments, Employees, ProductsAndServices
+	SummarizeColumnBy *string
+	// Comma separated lists of ids to filter on.
+	Customer   *string
+	Vendor     *string
+	Item       *string
+	Class      *string
+	Department *string
+	// ascend or descend
+	SortOrder *string
+}
+
+func (p *ProfitAndLossQueryParams) toMap() map[string]string {
+	m := map[string]string{}
+	if p.AccountingMethod != nil {
+		m["accounting_method"] = *p.AccountingMethod
+	}
+	if p.StartDate != nil {
+		m["start_date"] = *p.StartDate
+	}
+	if p.EndDate != nil {
+		m["end_date"] = *p.EndDate
+	}
+	if p.DateMacro != nil {
+		m["date_macro"] = *p.DateMacro
+	}
+	if p.SummarizeColumnBy != nil {
+		m["summarize_column_by"] = *p.SummarizeColumnBy
+	}
+	if p.Customer != nil {
+		m["customer"] = *p.Customer
+	}
+	if p.Vendor != nil {
+		m["vendor"] = *p.Vendor
+	}
+	if p.Item != nil {
+		m["item"] = *p.Item
+	}
+	if p.Class != nil {
+		m["class"] = *p.Class
+	}
+	if p.Department != nil {
+		m["department"] = *p.Department
+	}
+	if p.SortOrder != nil {
+		m["sort_order"] = *p.SortOrder
+	}
+	return m
+}
+
+// Groups of the top-level sections of a ProfitAndLoss report. GrossProfit,
+// NetOperatingIncome, NetOtherIncome and NetIncome only carry a total.
+const (
+	ProfitAndLossIncome             = "Income"
+	ProfitAndLossCOGS               = "COGS"
+	ProfitAndLossGrossProfit        = "GrossProfit"
+	ProfitAndLossExpenses           = "Expenses"
+	ProfitAndLossNetOperatingIncome = "NetOperatingIncome"
+	ProfitAndLossOtherIncome        = "OtherIncome"
+	ProfitAndLossOtherExpenses      = "OtherExpenses"
+	ProfitAndLossNetOtherIncome     = "NetOtherIncome"
+	ProfitAndLossNetIncome          = "NetIncome"
+)
+
+// ProfitAndLossLine is an account of a ProfitAndLoss report. Amounts holds its value in each
+// column after the account name, in column order. An account with sub-accounts has them in
+// Lines, nested as deep as the chart of accounts goes, and its Amounts are the totals
+// including them.
+type ProfitAndLossLine struct {
+	Account   string
+	AccountID string
+	Amounts   []json.Number
+	Lines     []ProfitAndLossLine
+}
+
+// ProfitAndLossSection is a top-level section of a ProfitAndLoss report, such as Income or
+// Expenses. Total holds the section total in each column after the account name.
+type ProfitAndLossSection struct {
+	Group string
+	Title string
+	Lines []ProfitAndLossLine
+	Total []json.Number
+}
+
+// ProfitAndLoss is a ProfitAndLoss report read into its sections. The embedded Report
+// gives access to the raw rows, Flatten and FlattenByID.
+type ProfitAndLoss struct {
+	*Report
+	Sections []ProfitAndLossSection
+}
+
+// Section returns the top-level section with the given group, e.g. ProfitAndLossIncome,
+// or nil if the report has none, as happens when no transaction falls into it.
+func (p *ProfitAndLoss) Section(group string) *ProfitAndLossSection {
+	for i := range p.Sections {
+		if p.Sections[i].Group == group {
+			return &p.Sections[i]
+		}
+	}
+	return nil
+}
+
+// NetIncome returns the net income in the last column, the total column when the report is
+// summarized by period, or "" if the report has no NetIncome section.
+func (p *ProfitAndLoss) NetIncome() json.Number {
+	section := p.Section(ProfitAndLossNetIncome)
+	if section == nil || len(section.Total) == 0 {
+		return ""
+	}
+	return section.Total[len(section.Total)-1]
+}
+
+// newProfitAndLoss reads the sections of the report.
+func newProfitAndLoss(report *Report) *ProfitAndLoss {
+	pl := &ProfitAndLoss{Report: report}
+
+	for _, row := range report.Rows {
+		if !row.IsSection() {
+			continue
+		}
+
+		section := ProfitAndLossSection{
+			Group: row.Group,
+			Lines: profitAndLossLines(row.Rows),
+			Total: reportAmounts(row.Summary),
+		}
+		if len(row.Header) > 0 {
+			section.Title = row.Header[0].Value
+		} else if len(row.Summary) > 0 {
+			section.Title = row.Summary[0].Value
+		}
+
+		pl.Sections = append(pl.Sections, section)
+	}
+
+	return pl
+}
+
+// profitAndLossLines reads the account rows of a section, recursing into the sections of
+// parent accounts.
+func profitAndLossLines(rows []ReportRow) []ProfitAndLossLine {
+	var lines []ProfitAndLossLine
+	for _, row := range rows {
+		if !row.IsSection() {
+			name := row.cell(0)
+			lines = append(lines, ProfitAndLossLine{Account: name.Value, AccountID: name.ID, Amounts: reportAmounts(row.ColData)})
+			continue
+		}
+
+		var name ReportColData
+		if len(row.Header) > 0 {
+			name = row.Header[0]
+		}
+		lines = append(lines, ProfitAndLossLine{
+			Account:   name.Value,
+			AccountID: name.ID,
+			Amounts:   reportAmounts(row.Summary),
+			Lines:     profitAndLossLines(row.Rows),
+		})
+	}
+	return lines
+}
+
+// reportAmounts returns the amounts of the cells after the first, which holds the row name.
+func reportAmounts(cells []ReportColData) []json.Number {
+	if len(cells) < 2 {
+		return nil
+	}
+
+	amounts := make([]json.Number, len(cells)-1)
+	for i, cell := range cells[1:] {
+		amounts[i] = glAmount(cell.Value)
+	}
+	return amounts
+}
+
+// GetProfitAndLoss fetches a ProfitAndLoss (income statement) report from the QBO API and
+// reads it into its sections. Pass nil for params to use the API defaults. Flatten turns the
+// result into account → amount lookups.
+func (c *Client) GetProfitAndLoss(params *ProfitAndLossQueryParams) (*ProfitAndLoss, error) {
+	var queryParams map[string]string
+	if params != nil {
+		queryParams = params.toMap()
+	}
+
+	report, err := c.getReport("ProfitAndLoss", queryParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return newProfitAndLoss(report), nil
+}
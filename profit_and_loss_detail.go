@@ -0,0 +1,143 @@
+package quickbooks
+
+// ProfitAndLossDetailQueryParams holds the optional query parameters for the ProfitAndLossDetail report.
+type ProfitAndLossDetailQueryParams struct {
+	// Cash or Accrual
+	AccountingMethod *string
+	StartDate        *string
+	EndDate          *string
+	// Predefined date range, e.g. "This Month", "Last Fiscal Year". Ignored when StartDate/EndDate are set.
+	DateMacro *string
+	// Classes, Customers, Vendors, Employees, Departments or ProductsAndServices groups the
+	// transactions by that dimension; see GetProfitAndLossDetailGrouped.
+	SummarizeColumnBy *string
+	// Comma separated lists of ids to filter on.
+	Customer   *string
+	Vendor     *string
+	Employee   *string
+	Item       *string
+	Class      *string
+	Department *string
+	Account    *string
+	// Comma separated list of column keys, e.g. "tx_date,txn_type,doc_num,subt_nat_amount".
+	Columns *string
+	// Column key to sort by, e.g. "tx_date".
+	SortBy *string
+	// ascend or descend
+	SortOrder *string
+}
+
+func (p *ProfitAndLossDetailQueryParams) toMap() map[string]string {
+	m := map[string]string{}
+	if p.AccountingMethod != nil {
+		m["accounting_method"] = *p.AccountingMethod
+	}
+	if p.StartDate != nil {
+		m["start_date"] = *p.StartDate
+	}
+	if p.EndDate != nil {
+		m["end_date"] = *p.EndDate
+	}
+	if p.DateMacro != nil {
+		m["date_macro"] = *p.DateMacro
+	}
+	if p.SummarizeColumnBy != nil {
+		m["summarize_column_by"] = *p.SummarizeColumnBy
+	}
+	if p.Customer != nil {
+		m["customer"] = *p.Customer
+	}
+	if p.Vendor != nil {
+		m["vendor"] = *p.Vendor
+	}
+	if p.Employee != nil {
+		m["employee"] = *p.Employee
+	}
+	if p.Item != nil {
+		m["item"] = *p.Item
+	}
+	if p.Class != nil {
+		m["class"] = *p.Class
+	}
+	if p.Department != nil {
+		m["department"] = *p.Department
+	}
+	if p.Account != nil {
+		m["account"] = *p.Account
+	}
+	if p.Columns != nil {
+		m["columns"] = *p.Columns
+	}
+	if p.SortBy != nil {
+		m["sort_by"] = *p.SortBy
+	}
+	if p.SortOrder != nil {
+		m["sort_order"] = *p.SortOrder
+	}
+	return m
+}
+
+// GetProfitAndLossDetail fetches a ProfitAndLossDetail report from the QBO API.
+// Pass nil for params to use the API defaults. For long ranges see StreamProfitAndLossDetail.
+func (c *Client) GetProfitAndLossDetail(params *ProfitAndLossDetailQueryParams) (*Report, error) {
+	var queryParams map[string]string
+	if params != nil {
+		queryParams = params.toMap()
+	}
+	return c.getReport("ProfitAndLossDetail", queryParams)
+}
+
+// ProfitAndLossDetailRow is a transaction row of the ProfitAndLossDetail report together with
+// the group and account it is reported under.
+type ProfitAndLossDetailRow struct {
+	// Group is the header of the class, customer or other group the row belongs to when the
+	// report is grouped with SummarizeColumnBy; its ID is the Id of that entity. It is zero
+	// for ungrouped reports.
+	Group ReportColData
+	// Account is the header of the innermost section holding the row, normally the account.
+	Account ReportColData
+	Row     ReportRow
+}
+
+// ProfitAndLossDetail is a ProfitAndLossDetail report with every transaction row attributed
+// to its group and account.
+type ProfitAndLossDetail struct {
+	Report *Report
+	Rows   []ProfitAndLossDetailRow
+}
+
+// newProfitAndLossDetail attributes the data rows of report. When grouped, the outermost
+// section of each row is its group and the levels below it are the account hierarchy
+// (e.g. Income, then Services).
+func newProfitAndLossDetail(report *Report, grouped bool) *ProfitAndLossDetail {
+	detail := &ProfitAndLossDetail{Report: report}
+
+	for _, row := range report.GroupedDataRows() {
+		line := ProfitAndLossDetailRow{Row: row.ReportRow}
+		sections := row.Sections
+		if grouped && len(sections) > 0 {
+			line.Group = sections[0]
+			sections = sections[1:]
+		}
+		if len(sections) > 0 {
+			line.Account = sections[len(sections)-1]
+		}
+		detail.Rows = append(detail.Rows, line)
+	}
+
+	return detail
+}
+
+// GetProfitAndLossDetailGrouped fetches the ProfitAndLossDetail report and attributes each
+// transaction row to its account and, when params.SummarizeColumnBy groups the report by
+// class, customer or another dimension, to its group. Pass nil for params to use the API
+// defaults.
+func (c *Client) GetProfitAndLossDetailGrouped(params *ProfitAndLossDetailQueryParams) (*ProfitAndLossDetail, error) {
+	report, err := c.GetProfitAndLossDetail(params)
+	if err != nil {
+		return nil, err
+	}
+
+	grouped := params != nil && params.SummarizeColumnBy != nil && *params.SummarizeColumnBy != "Total"
+	return newProfitAndLossDetail(report, grouped), nil
+}
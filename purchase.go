@@ -3,13 +3,13 @@ package quickbooks
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
-	"strconv"
 )
 
 // Purchase represents a QuickBooks Purchase object as returned by the API.
 // Read-only fields (Id, SyncToken, MetaData, TotalAmt) are populated by the service.
 type Purchase struct {
+	EntityMeta
+
 	ID            string         `json:"Id,omitempty"`
 	SyncToken     string         `json:",omitempty"`
 	MetaData      *MetaData      `json:",omitempty"`
@@ -25,6 +25,7 @@ type Purchase struct {
 	CurrencyRef   *ReferenceType `json:",omitempty"`
 	ExchangeRate  json.Number    `json:",omitempty"`
 	TxnTaxDetail  *TxnTaxDetail  `json:",omitempty"`
+	GlobalTaxCalculation GlobalTaxCalculation `json:",omitempty"`
 	Credit        *bool          `json:",omitempty"`
 	PaymentMethodRef *ReferenceType `json:",omitempty"`
 }
@@ -43,23 +44,25 @@ type PurchaseCreateInput struct {
 	CurrencyRef   *ReferenceType `json:",omitempty"`
 	ExchangeRate  json.Number    `json:",omitempty"`
 	TxnTaxDetail  *TxnTaxDetail  `json:",omitempty"`
+	GlobalTaxCalculation GlobalTaxCalculation `json:",omitempty"`
 	Credit        *bool          `json:",omitempty"`
 	PaymentMethodRef *ReferenceType `json:",omitempty"`
+	// Extra holds fields the struct does not model yet; see ExtraFields.
+	Extra ExtraFields `json:"-"`
 }
 
 // CreatePurchase creates the given Purchase on the QuickBooks server, returning
 // the resulting Purchase object.
 func (c *Client) CreatePurchase(input *PurchaseCreateInput) (*Purchase, error) {
-	var resp struct {
-		Purchase Purchase
-		Time     Date
+	if err := validateGlobalTaxCalculation(input.GlobalTaxCalculation); err != nil {
+		return nil, err
 	}
 
-	if err := c.post("purchase", input, &resp, nil); err != nil {
+	if err := ValidateExpenseLines(input.Line); err != nil {
 		return nil, err
 	}
 
-	return &resp.Purchase, nil
+	return postSingle[Purchase](c, "purchase", input, nil)
 }
 
 // DeletePurchase deletes the purchase.
@@ -68,118 +71,54 @@ func (c *Client) DeletePurchase(purchase *Purchase) error {
 		return errors.New("missing id/sync token")
 	}
 
-	return c.post("purchase", purchase, nil, map[string]string{"operation": "delete"})
+	return c.postRetryingStale("purchase", "purchase/"+purchase.ID, &purchase.SyncToken, purchase, nil, map[string]string{"operation": "delete"})
 }
 
 // FindPurchases gets the full list of Purchases in the QuickBooks account.
 func (c *Client) FindPurchases() ([]Purchase, error) {
-	var resp struct {
-		QueryResponse struct {
-			Purchases     []Purchase `json:"Purchase"`
-			MaxResults    int
-			StartPosition int
-			TotalCount    int
-		}
-	}
-
-	if err := c.query("SELECT COUNT(*) FROM Purchase", &resp); err != nil {
-		return nil, err
-	}
-
-	if resp.QueryResponse.TotalCount == 0 {
-		return nil, errors.New("no purchases could be found")
-	}
-
-	purchases := make([]Purchase, 0, resp.QueryResponse.TotalCount)
-
-	for i := 0; i < resp.QueryResponse.TotalCount; i += queryPageSize {
-		query := "SELECT * FROM Purchase ORDERBY Id STARTPOSITION " + strconv.Itoa(i+1) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
-
-		if err := c.query(query, &resp); err != nil {
-			return nil, err
-		}
+	return findAllPages[Purchase](c, "Purchase", "no purchases could be found")
+}
 
-		if resp.QueryResponse.Purchases == nil {
-			return nil, errors.New("no purchases could be found")
-		}
+// FindPurchasesWithOptions returns the purchases matching opts, ordered by opts.OrderBy.
+// Pass nil for opts to list them all.
+func (c *Client) FindPurchasesWithOptions(opts *ListOptions) ([]Purchase, error) {
+	return findAllWithOptions[Purchase](c, "Purchase", false, opts)
+}
 
-		purchases = append(purchases, resp.QueryResponse.Purchases...)
+// QueryPurchasesForAccount returns every purchase (check, cash or credit card expense) paid
+// from the given bank or credit card account, paging through all of them, ordered by Id.
+func (c *Client) QueryPurchasesForAccount(accountID string) ([]Purchase, error) {
+	if accountID == "" {
+		return nil, errors.New("missing account id")
 	}
 
-	return purchases, nil
+	return findAllWithOptions[Purchase](c, "Purchase", false, &ListOptions{Filter: refEquals("AccountRef", accountID)})
 }
 
 // FindPurchaseByID finds the purchase by the given id.
 func (c *Client) FindPurchaseByID(id string) (*Purchase, error) {
-	var resp struct {
-		Purchase Purchase
-		Time     Date
-	}
-
-	if err := c.get("purchase/"+id, &resp, nil); err != nil {
-		return nil, err
-	}
-
-	return &resp.Purchase, nil
+	return getSingle[Purchase](c, "purchase/"+id, nil)
 }
 
 // QueryPurchases accepts an SQL query and returns all purchases found using it.
 func (c *Client) QueryPurchases(query string) ([]Purchase, error) {
-	var resp struct {
-		QueryResponse struct {
-			Purchases     []Purchase `json:"Purchase"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
+	purchases, err := queryEntities[Purchase](c, "Purchase", query)
+	if err != nil {
+		return purchases, err
 	}
 
-	if resp.QueryResponse.Purchases == nil {
+	if purchases == nil {
 		return nil, errors.New("could not find any purchases")
 	}
 
-	return resp.QueryResponse.Purchases, nil
+	return purchases, nil
 }
 
 // ListPurchases returns one page of Purchases ordered by Id.
 // Pass an empty pageToken to start from the beginning.
 // The returned nextPageToken is empty when there are no more results.
 func (c *Client) ListPurchases(pageToken string, pageSize int) (*ListResponse[Purchase], error) {
-	if pageSize <= 0 || pageSize > queryPageSize {
-		pageSize = queryPageSize
-	}
-
-	startPosition := 1
-	if pageToken != "" {
-		var err error
-		startPosition, err = strconv.Atoi(pageToken)
-		if err != nil {
-			return nil, fmt.Errorf("invalid page token: %v", err)
-		}
-	}
-
-	var resp struct {
-		QueryResponse struct {
-			Purchases     []Purchase `json:"Purchase"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	query := "SELECT * FROM Purchase ORDERBY Id STARTPOSITION " + strconv.Itoa(startPosition) + " MAXRESULTS " + strconv.Itoa(pageSize)
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
-	}
-
-	result := &ListResponse[Purchase]{Items: resp.QueryResponse.Purchases}
-	if len(result.Items) == pageSize {
-		result.NextPageToken = strconv.Itoa(startPosition + pageSize)
-	}
-
-	return result, nil
+	return listPage[Purchase](c, "Purchase", pageToken, pageSize)
 }
 
 // UpdatePurchase updates the purchase.
@@ -203,14 +142,5 @@ func (c *Client) UpdatePurchase(purchase *Purchase) (*Purchase, error) {
 		Sparse:   true,
 	}
 
-	var purchaseData struct {
-		Purchase Purchase
-		Time     Date
-	}
-
-	if err = c.post("purchase", payload, &purchaseData, nil); err != nil {
-		return nil, err
-	}
-
-	return &purchaseData.Purchase, err
+	return updateSingle[Purchase](c, "purchase", "purchase/"+purchase.ID, &purchase.SyncToken, payload)
 }
@@ -3,12 +3,13 @@ package quickbooks
 import (
 	"encoding/json"
 	"errors"
-	"strconv"
 )
 
 // PurchaseOrder represents a QuickBooks PurchaseOrder object as returned by the API.
 // Read-only fields (Id, SyncToken, MetaData, TotalAmt) are populated by the service.
 type PurchaseOrder struct {
+	EntityMeta
+
 	ID            string         `json:"Id,omitempty"`
 	SyncToken     string         `json:",omitempty"`
 	MetaData      *MetaData      `json:",omitempty"`
@@ -30,6 +31,8 @@ type PurchaseOrder struct {
 	TxnTaxDetail  *TxnTaxDetail  `json:",omitempty"`
 	EmailStatus   *string        `json:",omitempty"`
 	POEmail       *EmailAddress  `json:",omitempty"`
+	// GlobalTaxCalculation says whether the line amounts of the order include tax.
+	GlobalTaxCalculation GlobalTaxCalculation `json:",omitempty"`
 }
 
 // PurchaseOrderCreateInput contains the writable fields accepted when creating a PurchaseOrder.
@@ -52,21 +55,24 @@ type PurchaseOrderCreateInput struct {
 	TxnTaxDetail  *TxnTaxDetail  `json:",omitempty"`
 	EmailStatus   *string        `json:",omitempty"`
 	POEmail       *EmailAddress  `json:",omitempty"`
+	// GlobalTaxCalculation must be empty or one of TaxExcluded, TaxInclusive and TaxNotApplicable.
+	GlobalTaxCalculation GlobalTaxCalculation `json:",omitempty"`
+	// Extra holds fields the struct does not model yet; see ExtraFields.
+	Extra ExtraFields `json:"-"`
 }
 
 // CreatePurchaseOrder creates the given PurchaseOrder on the QuickBooks server, returning
 // the resulting PurchaseOrder object.
 func (c *Client) CreatePurchaseOrder(input *PurchaseOrderCreateInput) (*PurchaseOrder, error) {
-	var resp struct {
-		PurchaseOrder PurchaseOrder
-		Time          Date
+	if err := validateGlobalTaxCalculation(input.GlobalTaxCalculation); err != nil {
+		return nil, err
 	}
 
-	if err := c.post("purchaseorder", input, &resp, nil); err != nil {
+	if err := ValidateExpenseLines(input.Line); err != nil {
 		return nil, err
 	}
 
-	return &resp.PurchaseOrder, nil
+	return postSingle[PurchaseOrder](c, "purchaseorder", input, nil)
 }
 
 // DeletePurchaseOrder deletes the purchase order.
@@ -75,80 +81,37 @@ func (c *Client) DeletePurchaseOrder(purchaseOrder *PurchaseOrder) error {
 		return errors.New("missing id/sync token")
 	}
 
-	return c.post("purchaseorder", purchaseOrder, nil, map[string]string{"operation": "delete"})
+	return c.postRetryingStale("purchaseorder", "purchaseorder/"+purchaseOrder.ID, &purchaseOrder.SyncToken, purchaseOrder, nil, map[string]string{"operation": "delete"})
 }
 
 // FindPurchaseOrders gets the full list of PurchaseOrders in the QuickBooks account.
 func (c *Client) FindPurchaseOrders() ([]PurchaseOrder, error) {
-	var resp struct {
-		QueryResponse struct {
-			PurchaseOrders []PurchaseOrder `json:"PurchaseOrder"`
-			MaxResults     int
-			StartPosition  int
-			TotalCount     int
-		}
-	}
-
-	if err := c.query("SELECT COUNT(*) FROM PurchaseOrder", &resp); err != nil {
-		return nil, err
-	}
-
-	if resp.QueryResponse.TotalCount == 0 {
-		return nil, errors.New("no purchase orders could be found")
-	}
-
-	purchaseOrders := make([]PurchaseOrder, 0, resp.QueryResponse.TotalCount)
-
-	for i := 0; i < resp.QueryResponse.TotalCount; i += queryPageSize {
-		query := "SELECT * FROM PurchaseOrder ORDERBY Id STARTPOSITION " + strconv.Itoa(i+1) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
-
-		if err := c.query(query, &resp); err != nil {
-			return nil, err
-		}
-
-		if resp.QueryResponse.PurchaseOrders == nil {
-			return nil, errors.New("no purchase orders could be found")
-		}
-
-		purchaseOrders = append(purchaseOrders, resp.QueryResponse.PurchaseOrders...)
-	}
+	return findAllPages[PurchaseOrder](c, "PurchaseOrder", "no purchase orders could be found")
+}
 
-	return purchaseOrders, nil
+// FindPurchaseOrdersWithOptions returns the purchase orders matching opts, ordered by opts.OrderBy.
+// Pass nil for opts to list them all.
+func (c *Client) FindPurchaseOrdersWithOptions(opts *ListOptions) ([]PurchaseOrder, error) {
+	return findAllWithOptions[PurchaseOrder](c, "PurchaseOrder", false, opts)
 }
 
 // FindPurchaseOrderByID finds the purchase order by the given id.
 func (c *Client) FindPurchaseOrderByID(id string) (*PurchaseOrder, error) {
-	var resp struct {
-		PurchaseOrder PurchaseOrder
-		Time          Date
-	}
-
-	if err := c.get("purchaseorder/"+id, &resp, nil); err != nil {
-		return nil, err
-	}
-
-	return &resp.PurchaseOrder, nil
+	return getSingle[PurchaseOrder](c, "purchaseorder/"+id, nil)
 }
 
 // QueryPurchaseOrders accepts an SQL query and returns all purchase orders found using it.
 func (c *Client) QueryPurchaseOrders(query string) ([]PurchaseOrder, error) {
-	var resp struct {
-		QueryResponse struct {
-			PurchaseOrders []PurchaseOrder `json:"PurchaseOrder"`
-			StartPosition  int
-			MaxResults     int
-		}
-	}
-
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
+	purchaseOrders, err := queryEntities[PurchaseOrder](c, "PurchaseOrder", query)
+	if err != nil {
+		return purchaseOrders, err
 	}
 
-	if resp.QueryResponse.PurchaseOrders == nil {
+	if purchaseOrders == nil {
 		return nil, errors.New("could not find any purchase orders")
 	}
 
-	return resp.QueryResponse.PurchaseOrders, nil
+	return purchaseOrders, nil
 }
 
 // UpdatePurchaseOrder updates the purchase order.
@@ -172,14 +135,5 @@ func (c *Client) UpdatePurchaseOrder(purchaseOrder *PurchaseOrder) (*PurchaseOrd
 		Sparse:        true,
 	}
 
-	var purchaseOrderData struct {
-		PurchaseOrder PurchaseOrder
-		Time          Date
-	}
-
-	if err = c.post("purchaseorder", payload, &purchaseOrderData, nil); err != nil {
-		return nil, err
-	}
-
-	return &purchaseOrderData.PurchaseOrder, err
+	return updateSingle[PurchaseOrder](c, "purchaseorder", "purchaseorder/"+purchaseOrder.ID, &purchaseOrder.SyncToken, payload)
 }
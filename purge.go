@@ -0,0 +1,126 @@
+package quickbooks
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrProductionPurge is returned by PurgeEntity when the client targets production and
+// Client.AllowProduction is not set.
+var ErrProductionPurge = errors.New("refusing to purge a production company; set Client.AllowProduction to override")
+
+// purgeDeletable lists the entities QuickBooks lets you delete.
+var purgeDeletable = map[string]bool{
+	"Attachable":    true,
+	"Bill":          true,
+	"BillPayment":   true,
+	"CreditMemo":    true,
+	"Deposit":       true,
+	"Estimate":      true,
+	"Invoice":       true,
+	"JournalEntry":  true,
+	"Payment":       true,
+	"Purchase":      true,
+	"PurchaseOrder": true,
+	"RefundReceipt": true,
+	"SalesReceipt":  true,
+	"TimeActivity":  true,
+	"Transfer":      true,
+	"VendorCredit":  true,
+}
+
+// purgeDeactivatable lists the name list entities, which QuickBooks never deletes but
+// makes inactive instead.
+var purgeDeactivatable = map[string]bool{
+	"Account":       true,
+	"Class":         true,
+	"Customer":      true,
+	"Department":    true,
+	"Employee":      true,
+	"Item":          true,
+	"PaymentMethod": true,
+	"Term":          true,
+	"Vendor":        true,
+}
+
+// PurgeFailure is a record PurgeEntity could not delete.
+type PurgeFailure struct {
+	ID  string
+	Err error
+}
+
+// PurgeResult reports what PurgeEntity did.
+type PurgeResult struct {
+	Entity string
+	// Purged counts the records deleted, or made inactive for name list entities.
+	Purged   int
+	Failures []PurgeFailure
+}
+
+// PurgeEntity deletes every record of the named entity (e.g. "Invoice"), typically to reset
+// a sandbox company. Name list entities such as Customer or Account cannot be deleted, so
+// their active records are made inactive instead; read-only entities are rejected.
+//
+// Records are deleted in batches of 30. A record that fails does not stop the purge: it is
+// reported in PurgeResult.Failures. Deleting a record can fail because others depend on it
+// (e.g. a payment applied to an invoice), so purging transactions that others link to last,
+// or running the purge twice, may be needed.
+//
+// It refuses to run against the production endpoint unless Client.AllowProduction is set.
+func (c *Client) PurgeEntity(entityName string) (*PurgeResult, error) {
+	if strings.HasPrefix(c.endpoint.String(), ProductionEndpoint.String()) && !c.AllowProduction {
+		return nil, ErrProductionPurge
+	}
+
+	var name string
+	for _, known := range FindableEntities() {
+		if strings.EqualFold(known, entityName) {
+			name = known
+		}
+	}
+
+	deactivate := purgeDeactivatable[name]
+	if !deactivate && !purgeDeletable[name] {
+		return nil, fmt.Errorf("%s cannot be purged", entityName)
+	}
+
+	records, err := findAllWithOptions[struct {
+		ID        string `json:"Id"`
+		SyncToken string
+	}](c, name, false, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PurgeResult{Entity: name}
+	for start := 0; start < len(records); start += batchMaxItems {
+		chunk := records[start:min(start+batchMaxItems, len(records))]
+
+		items := make([]BatchItem, len(chunk))
+		for i, record := range chunk {
+			items[i] = BatchItem{BID: record.ID, Operation: BatchDelete, Entity: name}
+			if deactivate {
+				items[i].Operation = BatchUpdate
+				items[i].Payload = map[string]any{"Id": record.ID, "SyncToken": record.SyncToken, "Active": false, "sparse": true}
+			} else {
+				items[i].Payload = map[string]any{"Id": record.ID, "SyncToken": record.SyncToken}
+			}
+		}
+
+		responses, err := c.Batch(items)
+		if err != nil {
+			return result, err
+		}
+
+		for _, r := range responses {
+			if r.Err != nil {
+				result.Failures = append(result.Failures, PurgeFailure{ID: r.BID, Err: r.Err})
+				continue
+			}
+			result.Purged++
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,75 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurgeEntity(t *testing.T) {
+	var batchSizes []int
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/company/test-realm/query":
+			assert.Equal(t, "SELECT * FROM Invoice ORDERBY Id STARTPOSITION 1 MAXRESULTS 1000", r.URL.Query().Get("query"))
+			var invoices []map[string]string
+			for id := 1; id <= 35; id++ {
+				invoices = append(invoices, map[string]string{"Id": strconv.Itoa(id), "SyncToken": "0"})
+			}
+			require.NoError(t, json.NewEncoder(w).Encode(map[string]any{"QueryResponse": map[string]any{"Invoice": invoices}}))
+		case "/v3/company/test-realm/batch":
+			var body struct {
+				BatchItemRequest []struct {
+					BID       string `json:"bId"`
+					Operation string `json:"operation"`
+					Invoice   struct {
+						ID string `json:"Id"`
+					}
+				}
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			batchSizes = append(batchSizes, len(body.BatchItemRequest))
+
+			var responses []string
+			for _, item := range body.BatchItemRequest {
+				assert.Equal(t, BatchDelete, item.Operation)
+				assert.Equal(t, item.BID, item.Invoice.ID)
+				if item.BID == "7" {
+					responses = append(responses, `{"bId": "7", "Fault": {"type": "ValidationFault", "Error": [{"Message": "Object Not Found", "code": "610"}]}}`)
+					continue
+				}
+				responses = append(responses, fmt.Sprintf(`{"bId": %q, "Invoice": {"Id": %q, "status": "Deleted"}}`, item.BID, item.BID))
+			}
+			w.Write([]byte(`{"BatchItemResponse": [` + strings.Join(responses, ",") + `]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	})
+
+	result, err := client.PurgeEntity("invoice")
+	require.NoError(t, err)
+	assert.Equal(t, "Invoice", result.Entity)
+	assert.Equal(t, 34, result.Purged)
+	require.Len(t, result.Failures, 1)
+	assert.Equal(t, "7", result.Failures[0].ID)
+	assert.Equal(t, []int{30, 5}, batchSizes)
+
+	_, err = client.PurgeEntity("TaxRate")
+	assert.EqualError(t, err, "TaxRate cannot be purged")
+}
+
+func TestPurgeEntityRefusesProduction(t *testing.T) {
+	endpoint, err := url.Parse(ProductionEndpoint.String() + "/v3/company/123/")
+	require.NoError(t, err)
+
+	client := &Client{endpoint: endpoint, realm: "123"}
+	_, err = client.PurgeEntity("Invoice")
+	assert.ErrorIs(t, err, ErrProductionPurge)
+}
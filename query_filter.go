@@ -0,0 +1,187 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Helpers for building ListOptions.Filter conditions. The query grammar is picky about
+// literals: strings and dates must be quoted ('Paid', '2024-01-31'), while numbers and
+// booleans must not be (TotalAmt > 10000, Active = false). A quoted number compares as a
+// string and an unquoted date is a syntax error, so let these helpers render the values.
+
+var (
+	filterFieldPattern  = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*(\.[A-Za-z][A-Za-z0-9]*)*$`)
+	filterNumberPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+	filterOperators     = []string{"=", "<", ">", "<=", ">=", "LIKE"}
+)
+
+// FilterCondition returns the condition "field op value" with value rendered the way
+// QuickBooks expects for its Go type:
+//   - string is quoted, with apostrophes escaped;
+//   - Date is quoted as YYYY-MM-DD and time.Time as an RFC 3339 timestamp;
+//   - json.Number, integers and floats are written bare, as are booleans.
+//
+// op is one of =, <, >, <=, >= and LIKE.
+func FilterCondition(field string, op string, value any) (string, error) {
+	if !filterFieldPattern.MatchString(field) {
+		return "", fmt.Errorf("invalid field name %q", field)
+	}
+
+	op = strings.ToUpper(op)
+	if !slices.Contains(filterOperators, op) {
+		return "", fmt.Errorf("unsupported operator %q", op)
+	}
+
+	literal, err := filterLiteral(value)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", field, err)
+	}
+
+	return field + " " + op + " " + literal, nil
+}
+
+// filterLiteral renders value as a query literal.
+func filterLiteral(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return "'" + strings.Replace(v, "'", "''", -1) + "'", nil
+	case Date:
+		return "'" + v.Format(secondFormat) + "'", nil
+	case time.Time:
+		return "'" + v.Format(time.RFC3339) + "'", nil
+	case json.Number:
+		if !filterNumberPattern.MatchString(string(v)) {
+			return "", fmt.Errorf("invalid number %q", string(v))
+		}
+		return string(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	}
+
+	return "", fmt.Errorf("unsupported filter value type %T", value)
+}
+
+// AllOf joins conditions with AND, skipping empty ones. QuickBooks has no OR.
+func AllOf(conditions ...string) string {
+	var nonEmpty []string
+	for _, condition := range conditions {
+		if condition != "" {
+			nonEmpty = append(nonEmpty, condition)
+		}
+	}
+	return strings.Join(nonEmpty, " AND ")
+}
+
+// FilterIn returns the condition "field IN (values)", rendering each value like FilterCondition.
+func FilterIn(field string, values ...any) (string, error) {
+	if !filterFieldPattern.MatchString(field) {
+		return "", fmt.Errorf("invalid field name %q", field)
+	}
+	if len(values) == 0 {
+		return "", fmt.Errorf("%s IN needs at least one value", field)
+	}
+
+	literals := make([]string, len(values))
+	for i, value := range values {
+		literal, err := filterLiteral(value)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", field, err)
+		}
+		literals[i] = literal
+	}
+
+	return field + " IN (" + strings.Join(literals, ", ") + ")", nil
+}
+
+// AmountRange returns a filter matching min <= field <= max. Either bound may be empty to
+// leave that side open, e.g. AmountRange("TotalAmt", "10000", "") for amounts of at least 10000.
+func AmountRange(field string, min, max json.Number) (string, error) {
+	if min == "" && max == "" {
+		return "", fmt.Errorf("%s range needs at least one bound", field)
+	}
+
+	var lower, upper string
+	var err error
+	if min != "" {
+		if lower, err = FilterCondition(field, ">=", min); err != nil {
+			return "", err
+		}
+	}
+	if max != "" {
+		if upper, err = FilterCondition(field, "<=", max); err != nil {
+			return "", err
+		}
+	}
+
+	if min != "" && max != "" {
+		lo, _ := min.Float64()
+		hi, _ := max.Float64()
+		if lo > hi {
+			return "", fmt.Errorf("%s range minimum %s is above maximum %s", field, min, max)
+		}
+	}
+
+	return AllOf(lower, upper), nil
+}
+
+// findByAmount lists the entities whose TotalAmt lies in [min, max], on top of any filter
+// already in opts.
+func findByAmount[T any](c *Client, entity string, min, max json.Number, opts *ListOptions) ([]T, error) {
+	amountFilter, err := AmountRange("TotalAmt", min, max)
+	if err != nil {
+		return nil, err
+	}
+
+	var o ListOptions
+	if opts != nil {
+		o = *opts
+	}
+	o.Filter = AllOf(o.Filter, amountFilter)
+
+	return findAllWithOptions[T](c, entity, false, &o)
+}
+
+// idsPerQuery caps the number of Ids in one IN list, keeping the query URL short.
+const idsPerQuery = 100
+
+// findByIDs fetches the entities with the given Ids, idsPerQuery at a time, ordered by Id
+// within each chunk. Duplicate Ids are fetched once and Ids that match nothing are skipped.
+// hasActive tells whether the entity has an Active field, so inactive objects are included.
+func findByIDs[T any](c *Client, entity string, hasActive bool, ids []string) ([]T, error) {
+	seen := map[string]bool{}
+	var values []any
+	for _, id := range ids {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			values = append(values, id)
+		}
+	}
+
+	var all []T
+	for start := 0; start < len(values); start += idsPerQuery {
+		filter, err := FilterIn("Id", values[start:min(start+idsPerQuery, len(values))]...)
+		if err != nil {
+			return nil, err
+		}
+
+		items, err := findAllWithOptions[T](c, entity, hasActive, &ListOptions{Filter: filter, IncludeInactive: hasActive})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+	}
+
+	return all, nil
+}
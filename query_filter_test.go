@@ -0,0 +1,86 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterCondition(t *testing.T) {
+	tests := []struct {
+		field string
+		op    string
+		value any
+		want  string
+	}{
+		{"TotalAmt", ">", json.Number("10000"), "TotalAmt > 10000"},
+		{"Balance", ">=", 0.5, "Balance >= 0.5"},
+		{"Active", "=", false, "Active = false"},
+		{"DocNumber", "=", "O'Brien-1", "DocNumber = 'O''Brien-1'"},
+		{"TxnDate", "<", Date{time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)}, "TxnDate < '2024-01-31'"},
+		{"MetaData.LastUpdatedTime", ">", time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC), "MetaData.LastUpdatedTime > '2024-01-31T12:00:00Z'"},
+		{"DisplayName", "like", "Acme%", "DisplayName LIKE 'Acme%'"},
+	}
+
+	for _, tt := range tests {
+		got, err := FilterCondition(tt.field, tt.op, tt.value)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, got)
+	}
+
+	_, err := FilterCondition("TotalAmt", ">", json.Number("1 OR 1=1"))
+	assert.Error(t, err)
+	_, err = FilterCondition("TotalAmt = 1 --", ">", 1)
+	assert.Error(t, err)
+	_, err = FilterCondition("TotalAmt", "!=", 1)
+	assert.Error(t, err)
+	_, err = FilterCondition("TotalAmt", ">", struct{}{})
+	assert.Error(t, err)
+}
+
+func TestFilterIn(t *testing.T) {
+	filter, err := FilterIn("Id", "1", "O'2")
+	require.NoError(t, err)
+	assert.Equal(t, "Id IN ('1', 'O''2')", filter)
+
+	filter, err = FilterIn("TotalAmt", 5, json.Number("7.5"))
+	require.NoError(t, err)
+	assert.Equal(t, "TotalAmt IN (5, 7.5)", filter)
+
+	_, err = FilterIn("Id")
+	assert.Error(t, err)
+}
+
+func TestAmountRange(t *testing.T) {
+	filter, err := AmountRange("TotalAmt", "500", "1000")
+	require.NoError(t, err)
+	assert.Equal(t, "TotalAmt >= 500 AND TotalAmt <= 1000", filter)
+
+	filter, err = AmountRange("TotalAmt", "", "1000")
+	require.NoError(t, err)
+	assert.Equal(t, "TotalAmt <= 1000", filter)
+
+	_, err = AmountRange("TotalAmt", "", "")
+	assert.Error(t, err)
+	_, err = AmountRange("TotalAmt", "1000", "500")
+	assert.Error(t, err)
+}
+
+func TestFindBillsByAmount(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "SELECT * FROM Bill WHERE Balance > '0' AND TotalAmt >= 500 AND TotalAmt <= 1000 ORDERBY Id STARTPOSITION 1 MAXRESULTS 1000", r.URL.Query().Get("query"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"QueryResponse":{"Bill":[{"Id":"3","TotalAmt":750}]}}`))
+	})
+
+	opts := &ListOptions{Filter: "Balance > '0'"}
+	bills, err := client.FindBillsByAmount("500", "1000", opts)
+	require.NoError(t, err)
+	require.Len(t, bills, 1)
+	assert.Equal(t, "3", bills[0].ID)
+	assert.Equal(t, "Balance > '0'", opts.Filter)
+}
@@ -0,0 +1,167 @@
+package quickbooks
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimitStatus is the request budget QuickBooks last reported for a company.
+//
+// Intuit does not send budget headers on every response or from every environment; Observed
+// is false until a response carried one. Limit and Remaining are -1 when the matching
+// header was missing, and Reset is zero when QuickBooks did not say when the budget resets.
+type RateLimitStatus struct {
+	Observed  bool
+	Limit     int
+	Remaining int
+	Reset     time.Time
+	// ObservedAt is when the response carrying these values was received.
+	ObservedAt time.Time
+}
+
+// Headers carrying the request budget. Retry-After is sent with 429 responses.
+const (
+	rateLimitLimitHeader     = "X-RateLimit-Limit"
+	rateLimitRemainingHeader = "X-RateLimit-Remaining"
+	rateLimitResetHeader     = "X-RateLimit-Reset"
+	retryAfterHeader         = "Retry-After"
+)
+
+// RateLimit returns the request budget last reported for the client's company. It is safe to
+// call concurrently with requests, including from clients returned by ForRealm.
+func (c *Client) RateLimit() RateLimitStatus {
+	return c.limiter.status(c.realm)
+}
+
+// realmLimiter records until when each realm is throttled and the budget it last reported.
+// A nil *realmLimiter never throttles.
+type realmLimiter struct {
+	mu       sync.Mutex
+	until    map[string]time.Time
+	statuses map[string]RateLimitStatus
+}
+
+func newRealmLimiter() *realmLimiter {
+	return &realmLimiter{until: map[string]time.Time{}, statuses: map[string]RateLimitStatus{}}
+}
+
+// throttled reports whether requests to realm should be held back.
+func (l *realmLimiter) throttled(realm string) bool {
+	return l.throttledFor(realm) > 0
+}
+
+// throttledFor returns how much longer requests to realm should be held back, or 0.
+func (l *realmLimiter) throttledFor(realm string) time.Duration {
+	if l == nil {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	until, ok := l.until[realm]
+	if !ok {
+		return 0
+	}
+	left := time.Until(until)
+	if left <= 0 {
+		delete(l.until, realm)
+		return 0
+	}
+	return left
+}
+
+// throttle holds back requests to realm for d.
+func (l *realmLimiter) throttle(realm string, d time.Duration) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.until[realm] = time.Now().Add(d)
+}
+
+// observe records the budget headers of a response from realm, if it has any.
+func (l *realmLimiter) observe(realm string, h http.Header, now time.Time) {
+	if l == nil {
+		return
+	}
+
+	status := RateLimitStatus{
+		Limit:      headerInt(h, rateLimitLimitHeader),
+		Remaining:  headerInt(h, rateLimitRemainingHeader),
+		ObservedAt: now,
+	}
+
+	if reset, err := strconv.ParseInt(h.Get(rateLimitResetHeader), 10, 64); err == nil {
+		// The reset is either a Unix time or a number of seconds from now.
+		if reset > 1e9 {
+			status.Reset = time.Unix(reset, 0)
+		} else {
+			status.Reset = now.Add(time.Duration(reset) * time.Second)
+		}
+	}
+
+	if h.Get(retryAfterHeader) != "" {
+		status.Remaining = 0
+		status.Reset = now.Add(retryAfter(h, now, 0))
+	}
+
+	if status.Limit < 0 && status.Remaining < 0 && status.Reset.IsZero() {
+		return
+	}
+	status.Observed = true
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.statuses[realm] = status
+}
+
+// status returns the budget last observed for realm.
+func (l *realmLimiter) status(realm string) RateLimitStatus {
+	if l == nil {
+		return RateLimitStatus{Limit: -1, Remaining: -1}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	status, ok := l.statuses[realm]
+	if !ok {
+		return RateLimitStatus{Limit: -1, Remaining: -1}
+	}
+	return status
+}
+
+// retryAfter returns how long the Retry-After header asks to wait, or fallback when the
+// header is missing or invalid.
+func retryAfter(h http.Header, now time.Time, fallback time.Duration) time.Duration {
+	value := h.Get(retryAfterHeader)
+	if value == "" {
+		return fallback
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		return max(t.Sub(now), 0)
+	}
+
+	return fallback
+}
+
+// headerInt returns the integer value of header key, or -1.
+func headerInt(h http.Header, key string) int {
+	n, err := strconv.Atoi(h.Get(key))
+	if err != nil {
+		return -1
+	}
+	return n
+}
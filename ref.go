@@ -0,0 +1,69 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ResolveRef fills in ref.Name by looking up the referenced object when QuickBooks only
+// returned its Id. entityType is the QuickBooks entity name, e.g. "Customer", "Account" or "Item".
+// Names are cached per client, so resolving the same entity twice costs one request.
+// A ref that already has a Name is left untouched.
+func (c *Client) ResolveRef(ref *ReferenceType, entityType string) error {
+	if ref == nil || ref.Value == "" {
+		return errors.New("missing ref value")
+	}
+
+	if ref.Name != "" {
+		return nil
+	}
+
+	if entityType == "" {
+		entityType = ref.Type
+	}
+	if entityType == "" {
+		return errors.New("missing entity type")
+	}
+
+	key := entityType + "/" + ref.Value
+	if name, ok := c.caches().refNames.Load(key); ok {
+		ref.Name = name.(string)
+		return nil
+	}
+
+	var resp map[string]json.RawMessage
+	if err := c.get(strings.ToLower(entityType)+"/"+ref.Value, &resp, nil); err != nil {
+		return err
+	}
+
+	raw, ok := resp[entityType]
+	if !ok {
+		return fmt.Errorf("no %s in response", entityType)
+	}
+
+	// Person-like entities are referenced by DisplayName, hierarchical ones (accounts, items,
+	// sub-customers) by their FullyQualifiedName.
+	var names struct {
+		DisplayName        string
+		FullyQualifiedName string
+		Name               string
+	}
+	if err := json.Unmarshal(raw, &names); err != nil {
+		return fmt.Errorf("failed to unmarshal %s: %v", entityType, err)
+	}
+
+	name := names.DisplayName
+	if name == "" {
+		name = names.FullyQualifiedName
+	}
+	if name == "" {
+		name = names.Name
+	}
+
+	c.caches().refNames.Store(key, name)
+	ref.Name = name
+
+	return nil
+}
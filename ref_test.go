@@ -0,0 +1,29 @@
+package quickbooks
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveRef(t *testing.T) {
+	requests := 0
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/v3/company/test-realm/account/64", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Account":{"Id":"64","Name":"Decks and Patios","FullyQualifiedName":"Job Expenses:Job Materials:Decks and Patios"},"time":"2015-07-24T10:48:27.082-07:00"}`))
+	})
+
+	ref := &ReferenceType{NameValue: NameValue{Value: "64"}}
+	require.NoError(t, client.ResolveRef(ref, "Account"))
+	assert.Equal(t, "Job Expenses:Job Materials:Decks and Patios", ref.Name)
+
+	again := &ReferenceType{NameValue: NameValue{Value: "64"}}
+	require.NoError(t, client.ResolveRef(again, "Account"))
+	assert.Equal(t, ref.Name, again.Name)
+	assert.Equal(t, 1, requests)
+}
@@ -3,19 +3,21 @@ package quickbooks
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
-	"strconv"
 )
 
 // RefundReceipt represents a QuickBooks RefundReceipt object as returned by the API.
 // Read-only fields (Id, SyncToken, MetaData, TotalAmt, Balance) are populated by the service.
+// PaymentRefNum is the number of the check written or the card transaction that refunded it.
 type RefundReceipt struct {
+	EntityMeta
+
 	ID                    string         `json:"Id,omitempty"`
 	SyncToken             string         `json:",omitempty"`
 	MetaData              *MetaData      `json:",omitempty"`
 	CustomerRef           *ReferenceType `json:",omitempty"`
 	DepositToAccountRef   *ReferenceType `json:",omitempty"`
 	PaymentMethodRef      *ReferenceType `json:",omitempty"`
+	PaymentRefNum         *string        `json:",omitempty"`
 	Line                  []Line         `json:",omitempty"`
 	TxnDate               *Date          `json:",omitempty"`
 	DocNumber             *string        `json:",omitempty"`
@@ -35,6 +37,8 @@ type RefundReceipt struct {
 	CustomField           []CustomField  `json:",omitempty"`
 	TotalAmt              json.Number    `json:",omitempty"`
 	Balance               json.Number    `json:",omitempty"`
+	// GlobalTaxCalculation says whether the line amounts include tax.
+	GlobalTaxCalculation GlobalTaxCalculation `json:",omitempty"`
 }
 
 // RefundReceiptCreateInput contains the writable fields accepted when creating a RefundReceipt.
@@ -44,6 +48,7 @@ type RefundReceiptCreateInput struct {
 	CustomerRef           *ReferenceType `json:",omitempty"`
 	DepositToAccountRef   *ReferenceType `json:",omitempty"`
 	PaymentMethodRef      *ReferenceType `json:",omitempty"`
+	PaymentRefNum         *string        `json:",omitempty"`
 	TxnDate               *Date          `json:",omitempty"`
 	DocNumber             *string        `json:",omitempty"`
 	PrivateNote           *string        `json:",omitempty"`
@@ -60,21 +65,28 @@ type RefundReceiptCreateInput struct {
 	BillEmail             *EmailAddress  `json:",omitempty"`
 	TxnTaxDetail          *TxnTaxDetail  `json:",omitempty"`
 	CustomField           []CustomField  `json:",omitempty"`
+	// GlobalTaxCalculation is left empty by US companies.
+	GlobalTaxCalculation GlobalTaxCalculation `json:",omitempty"`
+	// Extra holds fields the struct does not model yet; see ExtraFields.
+	Extra ExtraFields `json:"-"`
 }
 
 // CreateRefundReceipt creates the given RefundReceipt on the QuickBooks server, returning
 // the resulting RefundReceipt object.
 func (c *Client) CreateRefundReceipt(input *RefundReceiptCreateInput) (*RefundReceipt, error) {
-	var resp struct {
-		RefundReceipt RefundReceipt
-		Time          Date
+	if err := validateGlobalTaxCalculation(input.GlobalTaxCalculation); err != nil {
+		return nil, err
+	}
+
+	if err := c.applyDocNumberPolicy(&input.DocNumber); err != nil {
+		return nil, err
 	}
 
-	if err := c.post("refundreceipt", input, &resp, nil); err != nil {
+	if err := c.resolveCustomFields(input.CustomField); err != nil {
 		return nil, err
 	}
 
-	return &resp.RefundReceipt, nil
+	return postSingle[RefundReceipt](c, "refundreceipt", input, nil)
 }
 
 // DeleteRefundReceipt deletes the refund receipt.
@@ -83,118 +95,65 @@ func (c *Client) DeleteRefundReceipt(refundReceipt *RefundReceipt) error {
 		return errors.New("missing id/sync token")
 	}
 
-	return c.post("refundreceipt", refundReceipt, nil, map[string]string{"operation": "delete"})
+	return c.postRetryingStale("refundreceipt", "refundreceipt/"+refundReceipt.ID, &refundReceipt.SyncToken, refundReceipt, nil, map[string]string{"operation": "delete"})
 }
 
 // FindRefundReceipts gets the full list of RefundReceipts in the QuickBooks account.
 func (c *Client) FindRefundReceipts() ([]RefundReceipt, error) {
-	var resp struct {
-		QueryResponse struct {
-			RefundReceipts []RefundReceipt `json:"RefundReceipt"`
-			MaxResults     int
-			StartPosition  int
-			TotalCount     int
-		}
-	}
-
-	if err := c.query("SELECT COUNT(*) FROM RefundReceipt", &resp); err != nil {
-		return nil, err
-	}
-
-	if resp.QueryResponse.TotalCount == 0 {
-		return nil, errors.New("no refund receipts could be found")
-	}
-
-	refundReceipts := make([]RefundReceipt, 0, resp.QueryResponse.TotalCount)
-
-	for i := 0; i < resp.QueryResponse.TotalCount; i += queryPageSize {
-		query := "SELECT * FROM RefundReceipt ORDERBY Id STARTPOSITION " + strconv.Itoa(i+1) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
-
-		if err := c.query(query, &resp); err != nil {
-			return nil, err
-		}
-
-		if resp.QueryResponse.RefundReceipts == nil {
-			return nil, errors.New("no refund receipts could be found")
-		}
-
-		refundReceipts = append(refundReceipts, resp.QueryResponse.RefundReceipts...)
-	}
+	return findAllPages[RefundReceipt](c, "RefundReceipt", "no refund receipts could be found")
+}
 
-	return refundReceipts, nil
+// FindRefundReceiptsWithOptions returns the refund receipts matching opts, ordered by opts.OrderBy.
+// Pass nil for opts to list them all.
+func (c *Client) FindRefundReceiptsWithOptions(opts *ListOptions) ([]RefundReceipt, error) {
+	return findAllWithOptions[RefundReceipt](c, "RefundReceipt", false, opts)
 }
 
 // FindRefundReceiptByID finds the refund receipt by the given id.
 func (c *Client) FindRefundReceiptByID(id string) (*RefundReceipt, error) {
-	var resp struct {
-		RefundReceipt RefundReceipt
-		Time          Date
-	}
+	return getSingle[RefundReceipt](c, "refundreceipt/"+id, nil)
+}
 
-	if err := c.get("refundreceipt/"+id, &resp, nil); err != nil {
-		return nil, err
+// FindRefundReceiptPDF returns the refund receipt with the given Id rendered as a PDF.
+func (c *Client) FindRefundReceiptPDF(id string) ([]byte, error) {
+	if id == "" {
+		return nil, errors.New("missing refund receipt id")
 	}
 
-	return &resp.RefundReceipt, nil
+	return c.getPDF("refundreceipt/" + id + "/pdf")
 }
 
 // QueryRefundReceipts accepts an SQL query and returns all refund receipts found using it.
 func (c *Client) QueryRefundReceipts(query string) ([]RefundReceipt, error) {
-	var resp struct {
-		QueryResponse struct {
-			RefundReceipts []RefundReceipt `json:"RefundReceipt"`
-			StartPosition  int
-			MaxResults     int
-		}
-	}
-
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
+	refundReceipts, err := queryEntities[RefundReceipt](c, "RefundReceipt", query)
+	if err != nil {
+		return refundReceipts, err
 	}
 
-	if resp.QueryResponse.RefundReceipts == nil {
+	if refundReceipts == nil {
 		return nil, errors.New("could not find any refund receipts")
 	}
 
-	return resp.QueryResponse.RefundReceipts, nil
+	return refundReceipts, nil
 }
 
 // ListRefundReceipts returns one page of RefundReceipts ordered by Id.
 // Pass an empty pageToken to start from the beginning.
 // The returned nextPageToken is empty when there are no more results.
 func (c *Client) ListRefundReceipts(pageToken string, pageSize int) (*ListResponse[RefundReceipt], error) {
-	if pageSize <= 0 || pageSize > queryPageSize {
-		pageSize = queryPageSize
-	}
-
-	startPosition := 1
-	if pageToken != "" {
-		var err error
-		startPosition, err = strconv.Atoi(pageToken)
-		if err != nil {
-			return nil, fmt.Errorf("invalid page token: %v", err)
-		}
-	}
-
-	var resp struct {
-		QueryResponse struct {
-			RefundReceipts []RefundReceipt `json:"RefundReceipt"`
-			StartPosition  int
-			MaxResults     int
-		}
-	}
+	return listPage[RefundReceipt](c, "RefundReceipt", pageToken, pageSize)
+}
 
-	query := "SELECT * FROM RefundReceipt ORDERBY Id STARTPOSITION " + strconv.Itoa(startPosition) + " MAXRESULTS " + strconv.Itoa(pageSize)
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
-	}
+// SendRefundReceipt emails the refund receipt to its BillEmail, or to emailAddress if it is
+// not empty; the address then becomes its BillEmail. See SendInvoice.
+func (c *Client) SendRefundReceipt(refundReceiptId string, emailAddress string) error {
+	queryParameters := make(map[string]string)
 
-	result := &ListResponse[RefundReceipt]{Items: resp.QueryResponse.RefundReceipts}
-	if len(result.Items) == pageSize {
-		result.NextPageToken = strconv.Itoa(startPosition + pageSize)
+	if emailAddress != "" {
+		queryParameters["sendTo"] = emailAddress
 	}
 
-	return result, nil
+	return c.post("refundreceipt/"+refundReceiptId+"/send", nil, nil, queryParameters)
 }
 
 // UpdateRefundReceipt updates the refund receipt.
@@ -218,16 +177,7 @@ func (c *Client) UpdateRefundReceipt(refundReceipt *RefundReceipt) (*RefundRecei
 		Sparse:        true,
 	}
 
-	var refundReceiptData struct {
-		RefundReceipt RefundReceipt
-		Time          Date
-	}
-
-	if err = c.post("refundreceipt", payload, &refundReceiptData, nil); err != nil {
-		return nil, err
-	}
-
-	return &refundReceiptData.RefundReceipt, err
+	return updateSingle[RefundReceipt](c, "refundreceipt", "refundreceipt/"+refundReceipt.ID, &refundReceipt.SyncToken, payload)
 }
 
 // VoidRefundReceipt voids the refund receipt.
@@ -243,5 +193,5 @@ func (c *Client) VoidRefundReceipt(refundReceipt *RefundReceipt) error {
 
 	refundReceipt.SyncToken = existingRefundReceipt.SyncToken
 
-	return c.post("refundreceipt", refundReceipt, nil, map[string]string{"operation": "void"})
+	return c.postRetryingStale("refundreceipt", "refundreceipt/"+refundReceipt.ID, &refundReceipt.SyncToken, refundReceipt, nil, map[string]string{"operation": "void"})
 }
@@ -0,0 +1,152 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ReimburseCharge represents a QuickBooks ReimburseCharge object: a billable expense from a
+// bill, purchase or other expense that can be passed on to a customer on an invoice.
+// QuickBooks creates and updates these itself; all fields are read-only.
+type ReimburseCharge struct {
+	EntityMeta
+
+	ID              string         `json:"Id,omitempty"`
+	SyncToken       string         `json:",omitempty"`
+	MetaData        *MetaData      `json:",omitempty"`
+	CustomerRef     ReferenceType  `json:",omitempty"`
+	Amount          json.Number    `json:",omitempty"`
+	HasBeenInvoiced *bool          `json:",omitempty"`
+	TxnDate         *Date          `json:",omitempty"`
+	PrivateNote     *string        `json:",omitempty"`
+	CurrencyRef     *ReferenceType `json:",omitempty"`
+	ExchangeRate    json.Number    `json:",omitempty"`
+	// LinkedTxn points at the expense transaction the charge comes from.
+	LinkedTxn []LinkedTxn `json:",omitempty"`
+	Line      []Line      `json:",omitempty"`
+}
+
+// FindReimburseChargeByID finds the reimburse charge by the given id.
+func (c *Client) FindReimburseChargeByID(id string) (*ReimburseCharge, error) {
+	return getSingle[ReimburseCharge](c, "reimbursecharge/"+id, nil)
+}
+
+// FindUnbilledReimburseCharges returns the billable expenses of the given customer that have
+// not been added to an invoice yet.
+func (c *Client) FindUnbilledReimburseCharges(customerID string) ([]ReimburseCharge, error) {
+	if customerID == "" {
+		return nil, errors.New("missing customer id")
+	}
+
+	charges, err := findAllWithOptions[ReimburseCharge](c, "ReimburseCharge", false, &ListOptions{
+		Filter: refEquals("CustomerRef", customerID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	unbilled := charges[:0]
+	for _, charge := range charges {
+		if charge.HasBeenInvoiced == nil || !*charge.HasBeenInvoiced {
+			unbilled = append(unbilled, charge)
+		}
+	}
+
+	return unbilled, nil
+}
+
+// MarkupInfo describes the markup applied to a billable expense when it is billed to the
+// customer, for firms that bill cost-plus.
+type MarkupInfo struct {
+	PercentBased *bool `json:",omitempty"`
+	// Percent is the markup in percent, e.g. "15" for cost plus 15%.
+	Percent json.Number `json:",omitempty"`
+	// Value is the markup amount when the markup is not percent based.
+	Value         json.Number    `json:",omitempty"`
+	PriceLevelRef *ReferenceType `json:",omitempty"`
+	// MarkUpIncomeAccountRef is the income account the markup is posted to.
+	MarkUpIncomeAccountRef *ReferenceType `json:",omitempty"`
+}
+
+// ReimburseLineDetail is the detail of an invoice line billing a ReimburseCharge.
+type ReimburseLineDetail struct {
+	ItemAccountRef *ReferenceType `json:",omitempty"`
+	TaxCodeRef     *ReferenceType `json:",omitempty"`
+	MarkupInfo     *MarkupInfo    `json:",omitempty"`
+}
+
+// AddReimburseCharges appends one invoice line per billable expense, linked to its
+// ReimburseCharge so QuickBooks marks the expense as billed when the invoice is saved.
+func (input *InvoiceCreateInput) AddReimburseCharges(charges ...ReimburseCharge) error {
+	return input.addReimburseCharges(nil, charges)
+}
+
+// AddReimburseChargesWithMarkup is AddReimburseCharges for cost-plus billing: each line is
+// billed at the charge's amount plus percent (e.g. "15"), rounded to cents, and records the
+// markup so QuickBooks posts the difference to incomeAccountID. Pass an empty incomeAccountID
+// to use the company's default markup account.
+func (input *InvoiceCreateInput) AddReimburseChargesWithMarkup(percent json.Number, incomeAccountID string, charges ...ReimburseCharge) error {
+	pct, ok := new(big.Rat).SetString(percent.String())
+	if !ok || pct.Sign() < 0 {
+		return fmt.Errorf("invalid markup percent %q", percent)
+	}
+
+	percentBased := true
+	markup := &MarkupInfo{PercentBased: &percentBased, Percent: percent}
+	if incomeAccountID != "" {
+		markup.MarkUpIncomeAccountRef = &ReferenceType{NameValue: NameValue{Value: incomeAccountID}}
+	}
+
+	return input.addReimburseCharges(markup, charges)
+}
+
+func (input *InvoiceCreateInput) addReimburseCharges(markup *MarkupInfo, charges []ReimburseCharge) error {
+	for _, charge := range charges {
+		if charge.ID == "" {
+			return errors.New("missing reimburse charge id")
+		}
+
+		if charge.CustomerRef.Value != "" && input.CustomerRef.Value != "" && charge.CustomerRef.Value != input.CustomerRef.Value {
+			return errors.New("reimburse charge " + charge.ID + " belongs to another customer")
+		}
+
+		line := Line{
+			Amount:     charge.Amount,
+			DetailType: "ReimburseLineDetail",
+			LinkedTxn:  []LinkedTxn{{TxnID: charge.ID, TxnType: "ReimburseCharge"}},
+		}
+		if charge.PrivateNote != nil {
+			line.Description = *charge.PrivateNote
+		}
+
+		if markup != nil {
+			amount, err := markedUpAmount(charge.Amount, markup.Percent)
+			if err != nil {
+				return err
+			}
+			line.Amount = amount
+			line.ReimburseLineDetail = &ReimburseLineDetail{MarkupInfo: markup}
+		}
+
+		input.Line = append(input.Line, line)
+	}
+
+	return nil
+}
+
+// markedUpAmount returns amount plus percent of it, rounded to cents.
+func markedUpAmount(amount json.Number, percent json.Number) (json.Number, error) {
+	a, ok := new(big.Rat).SetString(amount.String())
+	if !ok {
+		return "", fmt.Errorf("invalid amount %q", amount)
+	}
+	p, ok := new(big.Rat).SetString(percent.String())
+	if !ok {
+		return "", fmt.Errorf("invalid markup percent %q", percent)
+	}
+
+	factor := new(big.Rat).Add(big.NewRat(1, 1), new(big.Rat).Quo(p, big.NewRat(100, 1)))
+	return json.Number(new(big.Rat).Mul(a, factor).FloatString(2)), nil
+}
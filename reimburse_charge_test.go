@@ -0,0 +1,60 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindUnbilledReimburseCharges(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "SELECT * FROM ReimburseCharge WHERE CustomerRef = '58' ORDERBY Id STARTPOSITION 1 MAXRESULTS 1000", r.URL.Query().Get("query"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"QueryResponse":{"ReimburseCharge":[
+  {"Id":"1","CustomerRef":{"value":"58"},"Amount":110.5,"HasBeenInvoiced":false,"PrivateNote":"Lumber","LinkedTxn":[{"TxnId":"151","TxnType":"Bill"}]},
+  {"Id":"2","CustomerRef":{"value":"58"},"Amount":20,"HasBeenInvoiced":true}
+]}}`))
+	})
+
+	charges, err := client.FindUnbilledReimburseCharges("58")
+	require.NoError(t, err)
+	require.Len(t, charges, 1)
+	assert.Equal(t, "1", charges[0].ID)
+	assert.Equal(t, []LinkedTxn{{TxnID: "151", TxnType: "Bill"}}, charges[0].LinkedTxn)
+
+	input := &InvoiceCreateInput{CustomerRef: ReferenceType{NameValue: NameValue{Value: "58"}}}
+	require.NoError(t, input.AddReimburseCharges(charges...))
+	require.Len(t, input.Line, 1)
+	assert.Equal(t, json.Number("110.5"), input.Line[0].Amount)
+	assert.Equal(t, "ReimburseLineDetail", input.Line[0].DetailType)
+	assert.Equal(t, "Lumber", input.Line[0].Description)
+	assert.Equal(t, []LinkedTxn{{TxnID: "1", TxnType: "ReimburseCharge"}}, input.Line[0].LinkedTxn)
+
+	other := &InvoiceCreateInput{CustomerRef: ReferenceType{NameValue: NameValue{Value: "59"}}}
+	assert.Error(t, other.AddReimburseCharges(charges...))
+}
+
+func TestAddReimburseChargesWithMarkup(t *testing.T) {
+	input := &InvoiceCreateInput{CustomerRef: ReferenceType{NameValue: NameValue{Value: "58"}}}
+	charges := []ReimburseCharge{{ID: "1", Amount: "110.50"}, {ID: "2", Amount: "20"}}
+
+	require.NoError(t, input.AddReimburseChargesWithMarkup("15", "90", charges...))
+	require.Len(t, input.Line, 2)
+	assert.Equal(t, json.Number("127.08"), input.Line[0].Amount)
+	assert.Equal(t, json.Number("23.00"), input.Line[1].Amount)
+
+	markup := input.Line[0].ReimburseLineDetail.MarkupInfo
+	assert.True(t, *markup.PercentBased)
+	assert.Equal(t, json.Number("15"), markup.Percent)
+	assert.Equal(t, "90", markup.MarkUpIncomeAccountRef.Value)
+
+	b, err := json.Marshal(input.Line[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"ReimburseLineDetail":{"MarkupInfo":{"PercentBased":true,"Percent":15,"MarkUpIncomeAccountRef":{"value":"90"}}}`)
+
+	assert.Error(t, input.AddReimburseChargesWithMarkup("-5", "", charges...))
+}
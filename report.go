@@ -0,0 +1,360 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ReportHeader holds the header fields common to all QuickBooks reports.
+type ReportHeader struct {
+	ReportName string
+	Option     []NameValue
+	DateMacro  string
+	// ReportBasis is ReportBasisCash or ReportBasisAccrual.
+	ReportBasis string
+	// StartPeriod and EndPeriod are the report's date range as YYYY-MM-DD; see Period.
+	StartPeriod        string
+	EndPeriod          string
+	SummarizeColumnsBy string
+	// Currency is the ISO 4217 code the amounts are reported in, normally the home currency.
+	Currency string
+	// Time is when QuickBooks generated the report.
+	Time time.Time
+}
+
+// Report bases.
+const (
+	ReportBasisCash    = "Cash"
+	ReportBasisAccrual = "Accrual"
+)
+
+// Period parses StartPeriod and EndPeriod. Reports as of a single date, like the balance
+// sheet, may only have an end.
+func (h *ReportHeader) Period() (start, end time.Time, err error) {
+	if h.StartPeriod != "" {
+		if start, err = time.Parse(secondFormat, h.StartPeriod); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid report start period %q", h.StartPeriod)
+		}
+	}
+	if h.EndPeriod != "" {
+		if end, err = time.Parse(secondFormat, h.EndPeriod); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid report end period %q", h.EndPeriod)
+		}
+	}
+	return start, end, nil
+}
+
+// CheckCompatible returns an error if the reports were run on a different basis or in a
+// different currency, in which case their amounts cannot be added together, e.g. when
+// consolidating the reports of several companies.
+func (h *ReportHeader) CheckCompatible(other *ReportHeader) error {
+	if h.ReportBasis != other.ReportBasis {
+		return fmt.Errorf("report basis differs: %s vs %s", h.ReportBasis, other.ReportBasis)
+	}
+	if h.Currency != other.Currency {
+		return fmt.Errorf("report currency differs: %s vs %s", h.Currency, other.Currency)
+	}
+	return nil
+}
+
+// ReportColumn describes one column of a report.
+// MetaData usually carries a "ColKey" entry naming the column (e.g. "tx_date", "subt_nat_amount").
+type ReportColumn struct {
+	ColType  string
+	ColTitle string
+	MetaData []NameValue `json:",omitempty"`
+}
+
+// Key returns the column's ColKey metadata value, or "" if it has none.
+func (rc ReportColumn) Key() string {
+	for _, md := range rc.MetaData {
+		if md.Name == "ColKey" {
+			return md.Value
+		}
+	}
+	return ""
+}
+
+// ReportColData is a single cell of a report row.
+// ID is set when the cell refers to a QuickBooks entity (an account, a transaction, a customer, ...).
+type ReportColData struct {
+	ID    string `json:"id,omitempty"`
+	Value string `json:"value"`
+}
+
+func (cd *ReportColData) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ID    json.RawMessage `json:"id"`
+		Value json.RawMessage `json:"value"`
+	}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var err error
+	if cd.ID, err = lenientString(raw.ID); err != nil {
+		return err
+	}
+
+	cd.Value, err = lenientString(raw.Value)
+	return err
+}
+
+// ReportRow is one node of a report's row tree.
+//
+// Data rows carry ColData. Section rows carry a Header, nested Rows and a Summary
+// holding the section subtotals.
+type ReportRow struct {
+	Type    string
+	Group   string
+	Header  []ReportColData
+	ColData []ReportColData
+	Rows    []ReportRow
+	Summary []ReportColData
+}
+
+// IsSection reports whether the row is a section (as opposed to a data row).
+func (r *ReportRow) IsSection() bool {
+	return r.Type == "Section" || len(r.ColData) == 0
+}
+
+func (r *ReportRow) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Type    string `json:"type"`
+		Group   string `json:"group"`
+		ColData []ReportColData
+		Header  struct {
+			ColData []ReportColData
+		}
+		Rows struct {
+			Row []ReportRow
+		}
+		Summary struct {
+			ColData []ReportColData
+		}
+	}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	r.Type = raw.Type
+	r.Group = raw.Group
+	r.ColData = raw.ColData
+	r.Header = raw.Header.ColData
+	r.Rows = raw.Rows.Row
+	r.Summary = raw.Summary.ColData
+
+	return nil
+}
+
+// Report is the generic shape shared by the QuickBooks report endpoints.
+type Report struct {
+	Header  ReportHeader
+	Columns []ReportColumn
+	Rows    []ReportRow
+}
+
+func (rp *Report) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Header  ReportHeader
+		Columns struct {
+			Column []ReportColumn
+		}
+		Rows struct {
+			Row []ReportRow
+		}
+	}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	rp.Header = raw.Header
+	rp.Columns = raw.Columns.Column
+	rp.Rows = raw.Rows.Row
+
+	return nil
+}
+
+// reportParams lists the query parameters each report accepts. QuickBooks answers an
+// unsupported filter with a generic validation fault (or silently ignores it), so getReport
+// rejects them before sending the request.
+var reportParams = map[string][]string{
+	"BalanceSheet": {"accounting_method", "start_date", "end_date", "date_macro", "summarize_column_by",
+		"customer", "vendor", "item", "class", "department", "sort_order"},
+	"BudgetVsActuals": {"budget", "accounting_method", "start_date", "end_date", "date_macro",
+		"summarize_column_by", "customer", "class", "department"},
+	"CustomerIncome": {"accounting_method", "start_date", "end_date", "date_macro", "summarize_column_by",
+		"customer", "vendor", "class", "department", "term", "sort_order"},
+	"GeneralLedger": {"accounting_method", "start_date", "end_date", "date_macro", "account", "customer",
+		"vendor", "class", "department", "columns", "sort_by", "sort_order"},
+	"JournalReport": {"start_date", "end_date", "date_macro", "transaction_type", "columns", "sort_by",
+		"sort_order"},
+	"ProfitAndLoss": {"accounting_method", "start_date", "end_date", "date_macro", "summarize_column_by",
+		"customer", "vendor", "item", "class", "department", "sort_order"},
+	"ProfitAndLossDetail": {"accounting_method", "start_date", "end_date", "date_macro", "summarize_column_by",
+		"customer", "vendor", "employee", "item", "class", "department", "account", "columns", "sort_by", "sort_order"},
+	"TaxSummary": {"agency_id", "accounting_method", "start_date", "end_date", "date_macro", "sort_order"},
+	"TransactionList": {"accounting_method", "start_date", "end_date", "date_macro", "source_account",
+		"customer", "vendor", "item", "class", "department", "columns", "sort_by", "sort_order"},
+	"TrialBalance": {"accounting_method", "start_date", "end_date", "date_macro", "sort_order",
+		"summarize_column_by"},
+	"VendorExpenses": {"accounting_method", "start_date", "end_date", "date_macro", "summarize_column_by",
+		"customer", "vendor", "class", "department", "sort_order"},
+}
+
+var summarizeColumnByValues = []string{"Total", "Month", "Week", "Days", "Quarter", "Year", "Customers",
+	"Vendors", "Classes", "Departments", "Employees", "ProductsAndServices"}
+
+// validateReportParams checks queryParams against what the named report accepts, so a
+// mistake fails with a clear message instead of a server side fault.
+func validateReportParams(name string, queryParams map[string]string) error {
+	supported, ok := reportParams[name]
+	if !ok {
+		return nil
+	}
+
+	for key := range queryParams {
+		if !slices.Contains(supported, key) {
+			return fmt.Errorf("the %s report does not support the %s filter", name, key)
+		}
+	}
+
+	if method, ok := queryParams["accounting_method"]; ok && method != "Cash" && method != "Accrual" {
+		return fmt.Errorf("accounting_method must be Cash or Accrual, got %q", method)
+	}
+	if order, ok := queryParams["sort_order"]; ok && order != "ascend" && order != "descend" {
+		return fmt.Errorf("sort_order must be ascend or descend, got %q", order)
+	}
+	if by, ok := queryParams["summarize_column_by"]; ok && !slices.Contains(summarizeColumnByValues, by) {
+		return fmt.Errorf("unsupported summarize_column_by %q", by)
+	}
+
+	var dates [2]time.Time
+	for i, key := range []string{"start_date", "end_date"} {
+		value, ok := queryParams[key]
+		if !ok {
+			continue
+		}
+		date, err := time.Parse(secondFormat, value)
+		if err != nil {
+			return fmt.Errorf("%s must be formatted as YYYY-MM-DD, got %q", key, value)
+		}
+		dates[i] = date
+	}
+	if !dates[0].IsZero() && !dates[1].IsZero() && dates[1].Before(dates[0]) {
+		return fmt.Errorf("end_date %s is before start_date %s", queryParams["end_date"], queryParams["start_date"])
+	}
+
+	return nil
+}
+
+// getReport fetches the named report (e.g. "BalanceSheet") with the given query parameters.
+func (c *Client) getReport(name string, queryParams map[string]string) (*Report, error) {
+	if err := validateReportParams(name, queryParams); err != nil {
+		return nil, err
+	}
+
+	var report Report
+	if err := c.get("reports/"+name, &report, queryParams); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// ColumnIndex returns the index of the column with the given ColKey, or -1 if the report has no such column.
+func (rp *Report) ColumnIndex(key string) int {
+	for i, col := range rp.Columns {
+		if col.Key() == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// DataRows returns every data row of the report in document order, descending into sections.
+func (rp *Report) DataRows() []ReportRow {
+	var rows []ReportRow
+	var walk func([]ReportRow)
+	walk = func(rs []ReportRow) {
+		for _, r := range rs {
+			if len(r.ColData) > 0 {
+				rows = append(rows, r)
+			}
+			walk(r.Rows)
+		}
+	}
+	walk(rp.Rows)
+	return rows
+}
+
+// GroupedRow is a data row together with the headers of the sections it is nested in,
+// outermost first, e.g. the class, then "Income", then the account.
+type GroupedRow struct {
+	Sections []ReportColData
+	ReportRow
+}
+
+// GroupedDataRows is DataRows with the section path of each row, so detail rows can be
+// attributed to the groups and accounts they are listed under. Sections without a header
+// are not part of the path.
+func (rp *Report) GroupedDataRows() []GroupedRow {
+	var rows []GroupedRow
+	var walk func(rs []ReportRow, path []ReportColData)
+	walk = func(rs []ReportRow, path []ReportColData) {
+		for _, r := range rs {
+			if len(r.ColData) > 0 {
+				rows = append(rows, GroupedRow{Sections: slices.Clip(path), ReportRow: r})
+			}
+
+			inner := path
+			if len(r.Header) > 0 {
+				inner = append(slices.Clip(path), r.Header[0])
+			}
+			walk(r.Rows, inner)
+		}
+	}
+	walk(rp.Rows, nil)
+	return rows
+}
+
+// Flatten collapses the data rows of a summary report, such as ProfitAndLoss or BalanceSheet,
+// into a map from the row's name (e.g. an account name) to its amount. Section subtotals are
+// left out. The amount is taken from the last column, which is the total column when the
+// report is summarized by period or by another dimension.
+//
+// Names are only unique within their section, so sub-accounts with the same name overwrite
+// each other; use FlattenByID when that matters.
+func (rp *Report) Flatten() map[string]string {
+	m := map[string]string{}
+	for _, row := range rp.DataRows() {
+		if name := row.cell(0).Value; name != "" {
+			m[name] = row.cell(len(row.ColData) - 1).Value
+		}
+	}
+	return m
+}
+
+// FlattenByID is Flatten keyed by the id of the entity each row refers to (e.g. the account
+// Id). Rows that do not refer to an entity are left out.
+func (rp *Report) FlattenByID() map[string]string {
+	m := map[string]string{}
+	for _, row := range rp.DataRows() {
+		if id := row.cell(0).ID; id != "" {
+			m[id] = row.cell(len(row.ColData) - 1).Value
+		}
+	}
+	return m
+}
+
+// cell returns the cell at index i of the row, or an empty cell if the row is too short.
+func (r *ReportRow) cell(i int) ReportColData {
+	if i < 0 || i >= len(r.ColData) {
+		return ReportColData{}
+	}
+	return r.ColData[i]
+}
@@ -0,0 +1,78 @@
+package quickbooks
+
+import (
+	"errors"
+	"time"
+)
+
+// QuickBooks reports have no paging parameters (no start position or page size like queries
+// have) and are cut off once a response reaches the report cell limit. The Stream methods work
+// around this for detail reports by requesting the range one calendar month at a time and
+// passing rows on as each month arrives, so a year of transactions is never held in memory as
+// a single response.
+
+// ReportRowFunc receives the rows of a streamed report in order, together with the report
+// (header and columns) of the window the row belongs to. Returning an error stops the stream
+// and that error is returned to the caller.
+type ReportRowFunc func(report *Report, row ReportRow) error
+
+// streamReportByMonth fetches the named report for each calendar month between from and to
+// (inclusive, by date) and calls fn for every data row.
+func (c *Client) streamReportByMonth(name string, queryParams map[string]string, from, to time.Time, fn ReportRowFunc) error {
+	if to.Before(from) {
+		return errors.New("end of range is before its start")
+	}
+
+	if queryParams == nil {
+		queryParams = map[string]string{}
+	}
+	delete(queryParams, "date_macro")
+
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	last := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+
+	for !start.After(last) {
+		end := time.Date(start.Year(), start.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+		if end.After(last) {
+			end = last
+		}
+
+		queryParams["start_date"] = start.Format(secondFormat)
+		queryParams["end_date"] = end.Format(secondFormat)
+
+		report, err := c.getReport(name, queryParams)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range report.DataRows() {
+			if err = fn(report, row); err != nil {
+				return err
+			}
+		}
+
+		start = end.AddDate(0, 0, 1)
+	}
+
+	return nil
+}
+
+// StreamTransactionList streams the TransactionList report between from and to, one month per
+// request. The date fields of params are ignored. Pass nil for params to use the API defaults.
+func (c *Client) StreamTransactionList(params *TransactionListQueryParams, from, to time.Time, fn ReportRowFunc) error {
+	var queryParams map[string]string
+	if params != nil {
+		queryParams = params.toMap()
+	}
+	return c.streamReportByMonth("TransactionList", queryParams, from, to, fn)
+}
+
+// StreamProfitAndLossDetail streams the ProfitAndLossDetail report between from and to, one
+// month per request. The date fields of params are ignored. Pass nil for params to use the API defaults.
+func (c *Client) StreamProfitAndLossDetail(params *ProfitAndLossDetailQueryParams, from, to time.Time, fn ReportRowFunc) error {
+	var queryParams map[string]string
+	if params != nil {
+		queryParams = params.toMap()
+	}
+	return c.streamReportByMonth("ProfitAndLossDetail", queryParams, from, to, fn)
+}
@@ -0,0 +1,261 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportColDataLenient(t *testing.T) {
+	var cells []ReportColData
+	require.NoError(t, json.Unmarshal([]byte(`[
+  {"value": "1201.00", "id": "35"},
+  {"value": 1201.00, "id": 35},
+  {"value": -0.5},
+  {"value": null},
+  {"value": true},
+  {}
+]`), &cells))
+
+	require.Len(t, cells, 6)
+	assert.Equal(t, ReportColData{ID: "35", Value: "1201.00"}, cells[0])
+	assert.Equal(t, ReportColData{ID: "35", Value: "1201.00"}, cells[1])
+	assert.Equal(t, "-0.5", cells[2].Value)
+	assert.Equal(t, "", cells[3].Value)
+	assert.Equal(t, "true", cells[4].Value)
+	assert.Equal(t, ReportColData{}, cells[5])
+
+	assert.Error(t, json.Unmarshal([]byte(`{"value": {"nested": 1}}`), &ReportColData{}))
+}
+
+func TestTrialBalanceValueRowLenient(t *testing.T) {
+	var header TrialBalanceRowHeader
+	require.NoError(t, json.Unmarshal([]byte(`{"id": 35, "value": "Checking"}`), &header))
+	assert.Equal(t, TrialBalanceRowHeader{ID: "35", Value: "Checking"}, header)
+
+	var row TrialBalanceValueRow
+	require.NoError(t, json.Unmarshal([]byte(`{"value": 1201.00}`), &row))
+	assert.Equal(t, "1201.00", row.Value)
+}
+
+func TestValidateReportParams(t *testing.T) {
+	assert.NoError(t, validateReportParams("ProfitAndLossDetail", map[string]string{
+		"item": "12", "accounting_method": "Accrual", "start_date": "2024-01-01", "end_date": "2024-03-31",
+	}))
+	assert.NoError(t, validateReportParams("SomeFutureReport", map[string]string{"anything": "x"}))
+
+	err := validateReportParams("TrialBalance", map[string]string{"item": "12"})
+	assert.EqualError(t, err, "the TrialBalance report does not support the item filter")
+
+	assert.Error(t, validateReportParams("BalanceSheet", map[string]string{"accounting_method": "cash"}))
+	assert.Error(t, validateReportParams("BalanceSheet", map[string]string{"sort_order": "desc"}))
+	assert.Error(t, validateReportParams("BalanceSheet", map[string]string{"summarize_column_by": "Items"}))
+	assert.Error(t, validateReportParams("GeneralLedger", map[string]string{"start_date": "01/01/2024"}))
+	assert.Error(t, validateReportParams("GeneralLedger", map[string]string{"start_date": "2024-02-01", "end_date": "2024-01-31"}))
+}
+
+func TestGetTransactionListItemFilter(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "12,14", r.URL.Query().Get("item"))
+		assert.Equal(t, "3", r.URL.Query().Get("class"))
+		_, _ = w.Write([]byte(`{"Header": {"ReportName": "TransactionList"}}`))
+	})
+
+	items, class := "12,14", "3"
+	_, err := client.GetTransactionList(&TransactionListQueryParams{Item: &items, Class: &class})
+	require.NoError(t, err)
+
+	method := "Modified Cash"
+	_, err = client.GetTransactionList(&TransactionListQueryParams{AccountingMethod: &method})
+	assert.Error(t, err)
+}
+
+func TestGetProfitAndLossFlatten(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v3/company/test-realm/reports/ProfitAndLoss", r.URL.Path)
+		assert.Equal(t, "12", r.URL.Query().Get("item"))
+		_, _ = w.Write([]byte(`{
+  "Header": {"ReportName": "ProfitAndLoss"},
+  "Columns": {"Column": [{"ColTitle": "", "ColType": "Account"}, {"ColTitle": "Total", "ColType": "Money"}]},
+  "Rows": {"Row": [
+    {"type": "Section", "group": "Income",
+     "Header": {"ColData": [{"value": "Income"}, {"value": ""}]},
+     "Rows": {"Row": [
+       {"type": "Data", "ColData": [{"value": "Design income", "id": "82"}, {"value": "2250.00"}]},
+       {"type": "Data", "ColData": [{"value": "Sales of Product Income", "id": "79"}, {"value": "912.75"}]}
+     ]},
+     "Summary": {"ColData": [{"value": "Total Income"}, {"value": "3162.75"}]}},
+    {"type": "Section", "group": "NetIncome",
+     "Summary": {"ColData": [{"value": "Net Income"}, {"value": "1642.46"}]}}
+  ]}
+}`))
+	})
+
+	item := "12"
+	report, err := client.GetProfitAndLoss(&ProfitAndLossQueryParams{Item: &item})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"Design income": "2250.00", "Sales of Product Income": "912.75"}, report.Flatten())
+	assert.Equal(t, map[string]string{"82": "2250.00", "79": "912.75"}, report.FlattenByID())
+}
+
+func TestReportHeader(t *testing.T) {
+	var report Report
+	require.NoError(t, json.Unmarshal([]byte(`{"Header": {
+  "Time": "2024-04-02T09:41:12-07:00",
+  "ReportName": "ProfitAndLoss",
+  "ReportBasis": "Accrual",
+  "StartPeriod": "2024-01-01",
+  "EndPeriod": "2024-03-31",
+  "SummarizeColumnsBy": "Total",
+  "Currency": "USD",
+  "Option": [{"Name": "NoReportData", "Value": "false"}]
+}}`), &report))
+
+	h := report.Header
+	assert.Equal(t, ReportBasisAccrual, h.ReportBasis)
+	assert.Equal(t, "USD", h.Currency)
+	assert.Equal(t, 2024, h.Time.Year())
+	assert.Equal(t, []NameValue{{Name: "NoReportData", Value: "false"}}, h.Option)
+
+	start, end, err := h.Period()
+	require.NoError(t, err)
+	assert.Equal(t, "2024-01-01", start.Format(secondFormat))
+	assert.Equal(t, "2024-03-31", end.Format(secondFormat))
+
+	assert.NoError(t, h.CheckCompatible(&ReportHeader{ReportBasis: "Accrual", Currency: "USD"}))
+	assert.EqualError(t, h.CheckCompatible(&ReportHeader{ReportBasis: "Cash", Currency: "USD"}), "report basis differs: Accrual vs Cash")
+	assert.Error(t, h.CheckCompatible(&ReportHeader{ReportBasis: "Accrual", Currency: "CAD"}))
+}
+
+func TestGetProfitAndLossDetailGrouped(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v3/company/test-realm/reports/ProfitAndLossDetail", r.URL.Path)
+		assert.Equal(t, "Classes", r.URL.Query().Get("summarize_column_by"))
+		w.Write([]byte(`{
+  "Header": {"ReportName": "ProfitAndLossDetail", "SummarizeColumnsBy": "Classes"},
+  "Columns": {"Column": [
+    {"ColTitle": "Date", "ColType": "Date", "MetaData": [{"Name": "ColKey", "Value": "tx_date"}]},
+    {"ColTitle": "Transaction Type", "ColType": "String", "MetaData": [{"Name": "ColKey", "Value": "txn_type"}]},
+    {"ColTitle": "Amount", "ColType": "Money", "MetaData": [{"Name": "ColKey", "Value": "subt_nat_amount"}]}
+  ]},
+  "Rows": {"Row": [
+    {"type": "Section",
+     "Header": {"ColData": [{"value": "Landscaping", "id": "5000000000000007"}, {"value": ""}, {"value": ""}]},
+     "Rows": {"Row": [
+       {"type": "Section",
+        "Header": {"ColData": [{"value": "Income"}, {"value": ""}, {"value": ""}]},
+        "Rows": {"Row": [
+          {"type": "Section",
+           "Header": {"ColData": [{"value": "Services", "id": "1"}, {"value": ""}, {"value": ""}]},
+           "Rows": {"Row": [
+             {"type": "Data", "ColData": [{"value": "2024-01-05"}, {"value": "Invoice", "id": "130"}, {"value": "250.00"}]},
+             {"type": "Data", "ColData": [{"value": "2024-01-09"}, {"value": "Sales Receipt", "id": "131"}, {"value": "75.00"}]}
+           ]},
+           "Summary": {"ColData": [{"value": "Total for Services"}, {"value": ""}, {"value": "325.00"}]}}
+        ]},
+        "Summary": {"ColData": [{"value": "Total Income"}, {"value": ""}, {"value": "325.00"}]}}
+     ]},
+     "Summary": {"ColData": [{"value": "Total Landscaping"}, {"value": ""}, {"value": "325.00"}]}},
+    {"type": "Section",
+     "Header": {"ColData": [{"value": "Not Specified"}, {"value": ""}, {"value": ""}]},
+     "Rows": {"Row": [
+       {"type": "Section",
+        "Header": {"ColData": [{"value": "Expenses"}, {"value": ""}, {"value": ""}]},
+        "Rows": {"Row": [
+          {"type": "Data", "ColData": [{"value": "2024-01-12"}, {"value": "Expense", "id": "140"}, {"value": "40.00"}]}
+        ]}}
+     ]}},
+    {"type": "Section", "group": "NetIncome",
+     "Summary": {"ColData": [{"value": "Net Income"}, {"value": ""}, {"value": "285.00"}]}}
+  ]}
+}`))
+	})
+
+	by := "Classes"
+	detail, err := client.GetProfitAndLossDetailGrouped(&ProfitAndLossDetailQueryParams{SummarizeColumnBy: &by})
+	require.NoError(t, err)
+	require.Len(t, detail.Rows, 3)
+
+	assert.Equal(t, ReportColData{ID: "5000000000000007", Value: "Landscaping"}, detail.Rows[0].Group)
+	assert.Equal(t, ReportColData{ID: "1", Value: "Services"}, detail.Rows[0].Account)
+	assert.Equal(t, "130", detail.Rows[0].Row.cell(1).ID)
+	assert.Equal(t, "Landscaping", detail.Rows[1].Group.Value)
+	assert.Equal(t, "131", detail.Rows[1].Row.cell(1).ID)
+	assert.Equal(t, "Not Specified", detail.Rows[2].Group.Value)
+	assert.Equal(t, "Expenses", detail.Rows[2].Account.Value)
+
+	grouped := detail.Report.GroupedDataRows()
+	assert.Equal(t, []ReportColData{{ID: "5000000000000007", Value: "Landscaping"}, {Value: "Income"}, {ID: "1", Value: "Services"}},
+		grouped[1].Sections)
+
+	// Without a grouping the outermost section is part of the account path.
+	ungrouped := newProfitAndLossDetail(detail.Report, false)
+	assert.Empty(t, ungrouped.Rows[0].Group)
+	assert.Equal(t, "Services", ungrouped.Rows[0].Account.Value)
+}
+
+func TestGetProfitAndLoss(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v3/company/test-realm/reports/ProfitAndLoss", r.URL.Path)
+		assert.Equal(t, "Month", r.URL.Query().Get("summarize_column_by"))
+		w.Write([]byte(`{
+  "Header": {"ReportName": "ProfitAndLoss", "SummarizeColumnsBy": "Month"},
+  "Columns": {"Column": [{"ColTitle": "", "ColType": "Account"}, {"ColTitle": "Jan 2024", "ColType": "Money"},
+    {"ColTitle": "Feb 2024", "ColType": "Money"}, {"ColTitle": "Total", "ColType": "Money"}]},
+  "Rows": {"Row": [
+    {"type": "Section", "group": "Income",
+     "Header": {"ColData": [{"value": "Income"}, {"value": ""}, {"value": ""}, {"value": ""}]},
+     "Rows": {"Row": [
+       {"type": "Data", "ColData": [{"value": "Design income", "id": "82"}, {"value": "1,000.00"}, {"value": "1250.00"}, {"value": "2,250.00"}]},
+       {"type": "Section",
+        "Header": {"ColData": [{"value": "Landscaping Services", "id": "45"}, {"value": ""}, {"value": ""}, {"value": ""}]},
+        "Rows": {"Row": [
+          {"type": "Section",
+           "Header": {"ColData": [{"value": "Job Materials", "id": "46"}, {"value": ""}, {"value": ""}, {"value": ""}]},
+           "Rows": {"Row": [
+             {"type": "Data", "ColData": [{"value": "Plants and Soil", "id": "49"}, {"value": "300.00"}, {"value": ""}, {"value": "300.00"}]}
+           ]},
+           "Summary": {"ColData": [{"value": "Total Job Materials"}, {"value": "300.00"}, {"value": ""}, {"value": "300.00"}]}},
+          {"type": "Data", "ColData": [{"value": "Labor", "id": "51"}, {"value": ""}, {"value": "250.00"}, {"value": "250.00"}]}
+        ]},
+        "Summary": {"ColData": [{"value": "Total Landscaping Services"}, {"value": "300.00"}, {"value": "250.00"}, {"value": "550.00"}]}}
+     ]},
+     "Summary": {"ColData": [{"value": "Total Income"}, {"value": "1300.00"}, {"value": "1500.00"}, {"value": "2800.00"}]}},
+    {"type": "Section", "group": "GrossProfit",
+     "Summary": {"ColData": [{"value": "Gross Profit"}, {"value": "1300.00"}, {"value": "1500.00"}, {"value": "2800.00"}]}},
+    {"type": "Section", "group": "NetIncome",
+     "Summary": {"ColData": [{"value": "Net Income"}, {"value": "1300.00"}, {"value": "1500.00"}, {"value": "2800.00"}]}}
+  ]}
+}`))
+	})
+
+	by := "Month"
+	pl, err := client.GetProfitAndLoss(&ProfitAndLossQueryParams{SummarizeColumnBy: &by})
+	require.NoError(t, err)
+
+	require.Len(t, pl.Sections, 3)
+	income := pl.Section(ProfitAndLossIncome)
+	require.NotNil(t, income)
+	assert.Equal(t, "Income", income.Title)
+	assert.Equal(t, []json.Number{"1300.00", "1500.00", "2800.00"}, income.Total)
+
+	require.Len(t, income.Lines, 2)
+	assert.Equal(t, ProfitAndLossLine{Account: "Design income", AccountID: "82", Amounts: []json.Number{"1000.00", "1250.00", "2250.00"}}, income.Lines[0])
+
+	landscaping := income.Lines[1]
+	assert.Equal(t, "45", landscaping.AccountID)
+	assert.Equal(t, []json.Number{"300.00", "250.00", "550.00"}, landscaping.Amounts)
+	require.Len(t, landscaping.Lines, 2)
+	assert.Equal(t, "Job Materials", landscaping.Lines[0].Account)
+	assert.Equal(t, []ProfitAndLossLine{{Account: "Plants and Soil", AccountID: "49", Amounts: []json.Number{"300.00", "", "300.00"}}}, landscaping.Lines[0].Lines)
+	assert.Equal(t, "Labor", landscaping.Lines[1].Account)
+
+	assert.Equal(t, "Gross Profit", pl.Section(ProfitAndLossGrossProfit).Title)
+	assert.Nil(t, pl.Section(ProfitAndLossExpenses))
+	assert.Equal(t, json.Number("2800.00"), pl.NetIncome())
+	assert.Equal(t, "300.00", pl.Flatten()["Plants and Soil"])
+}
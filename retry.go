@@ -0,0 +1,87 @@
+package quickbooks
+
+import (
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Delays between retries when QuickBooks does not say how long to wait. The n-th retry waits
+// a random duration between half and all of retryBaseDelay*2^n, capped at retryMaxDelay, so
+// clients that failed together don't retry together.
+var (
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 30 * time.Second
+)
+
+// retryDelay returns how long to wait before sending a failed request again, and whether it
+// should be sent again at all. attempt counts the retries already made.
+//
+// Throttled requests (429, a throttle fault or ErrThrottled) were not processed, so they are
+// retried whatever the method, once the throttle set from the Retry-After header lifts. A 5xx
+// is only retried for a GET: a POST may have been applied before the server failed, and
+// sending it again could create a second invoice. Connection errors are retried for every
+// method.
+func (c *Client) retryDelay(method string, err error, attempt int) (time.Duration, bool) {
+	if attempt >= c.MaxRetries || c.context().Err() != nil {
+		return 0, false
+	}
+
+	if IsThrottled(err) {
+		if d := c.limiter.throttledFor(c.realm); d > 0 {
+			return d, true
+		}
+		return backoff(attempt), true
+	}
+
+	status := responseStatus(err)
+	if status >= 500 && method == http.MethodGet {
+		return backoff(attempt), true
+	}
+
+	var urlErr *url.Error
+	if status == 0 && errors.As(err, &urlErr) {
+		return backoff(attempt), true
+	}
+
+	return 0, false
+}
+
+// backoff returns the jittered exponential delay before retry attempt.
+func backoff(attempt int) time.Duration {
+	d := retryMaxDelay
+	if attempt < 16 {
+		d = min(retryBaseDelay<<attempt, retryMaxDelay)
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// responseStatus returns the HTTP status carried by err, or 0 when the request got no response.
+func responseStatus(err error) int {
+	var failure Failure
+	if errors.As(err, &failure) {
+		return failure.StatusCode
+	}
+
+	var unexpected *UnexpectedResponseError
+	if errors.As(err, &unexpected) {
+		return unexpected.StatusCode
+	}
+
+	return 0
+}
+
+// sleep waits for d, or until the context of the client is done.
+func (c *Client) sleep(d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-c.context().Done():
+		return c.context().Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,78 @@
+package quickbooks
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetry(t *testing.T) {
+	defer func(base time.Duration) { retryBaseDelay = base }(retryBaseDelay)
+	retryBaseDelay = time.Millisecond
+
+	var calls map[string]int
+	client, server := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + strings.TrimPrefix(r.URL.Path, "/v3/company/test-realm/")
+		calls[key]++
+
+		switch {
+		case strings.HasSuffix(key, "throttled") && calls[key] == 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"Fault": {"Error": [{"Message": "ThrottleExceeded", "code": "3001"}], "type": "SERVICE"}}`))
+		case strings.HasSuffix(key, "unavailable") && calls[key] < 3:
+			w.Header().Set("Content-Type", "text/html")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`<html>Service Unavailable</html>`))
+		case strings.HasSuffix(key, "invalid"):
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"Fault": {"Error": [{"Message": "Required param missing", "code": "2020"}], "type": "ValidationFault"}}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	})
+
+	// Without MaxRetries every failure is returned as is.
+	calls = map[string]int{}
+	assert.True(t, IsThrottled(client.get("throttled", nil, nil)))
+	assert.Equal(t, 1, calls["GET throttled"])
+
+	client.MaxRetries = 3
+	calls = map[string]int{}
+	require.NoError(t, client.get("throttled", nil, nil))
+	require.NoError(t, client.post("throttled", map[string]string{"Name": "A"}, nil, nil))
+	require.NoError(t, client.get("unavailable", nil, nil))
+	assert.Equal(t, map[string]int{"GET throttled": 2, "POST throttled": 2, "GET unavailable": 3}, calls)
+
+	// A 5xx to a POST is ambiguous and returned at once, as is a validation fault.
+	calls = map[string]int{}
+	var unexpected *UnexpectedResponseError
+	assert.ErrorAs(t, client.post("unavailable", nil, nil, nil), &unexpected)
+	assert.True(t, IsValidation(client.get("invalid", nil, nil)))
+	assert.Equal(t, map[string]int{"POST unavailable": 1, "GET invalid": 1}, calls)
+
+	client.MaxRetries = 1
+	calls = map[string]int{}
+	assert.ErrorAs(t, client.get("unavailable", nil, nil), &unexpected)
+	assert.Equal(t, 2, calls["GET unavailable"])
+
+	// Connection errors are retried whatever the method.
+	server.Close()
+	start := time.Now()
+	assert.ErrorContains(t, client.post("closed", nil, nil, nil), "failed to make request")
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt, want := range []time.Duration{retryBaseDelay, 2 * retryBaseDelay, 4 * retryBaseDelay} {
+		d := backoff(attempt)
+		assert.GreaterOrEqual(t, d, want/2)
+		assert.LessOrEqual(t, d, want)
+	}
+
+	assert.LessOrEqual(t, backoff(100), retryMaxDelay)
+}
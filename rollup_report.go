@@ -0,0 +1,151 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// RollupQueryParams are the query parameters of the CustomerIncome and VendorExpenses reports.
+type RollupQueryParams struct {
+	// Cash or Accrual
+	AccountingMethod *string
+	StartDate        *string
+	EndDate          *string
+	// Predefined date range, e.g. "This Month", "Last Fiscal Year". Ignored when StartDate/EndDate are set.
+	DateMacro *string
+	// Total, Month, Week, Days, Quarter, Year, Customers, Vendors, Classes, Departments, Employees, ProductsAndServices
+	SummarizeColumnBy *string
+	// Comma separated lists of ids to filter on.
+	Customer   *string
+	Vendor     *string
+	Class      *string
+	Department *string
+	// Term filters on the sales term; only the CustomerIncome report accepts it.
+	Term *string
+	// ascend or descend
+	SortOrder *string
+}
+
+func (p *RollupQueryParams) toMap() map[string]string {
+	m := map[string]string{}
+	if p.AccountingMethod != nil {
+		m["accounting_method"] = *p.AccountingMethod
+	}
+	if p.StartDate != nil {
+		m["start_date"] = *p.StartDate
+	}
+	if p.EndDate != nil {
+		m["end_date"] = *p.EndDate
+	}
+	if p.DateMacro != nil {
+		m["date_macro"] = *p.DateMacro
+	}
+	if p.SummarizeColumnBy != nil {
+		m["summarize_column_by"] = *p.SummarizeColumnBy
+	}
+	if p.Customer != nil {
+		m["customer"] = *p.Customer
+	}
+	if p.Vendor != nil {
+		m["vendor"] = *p.Vendor
+	}
+	if p.Class != nil {
+		m["class"] = *p.Class
+	}
+	if p.Department != nil {
+		m["department"] = *p.Department
+	}
+	if p.Term != nil {
+		m["term"] = *p.Term
+	}
+	if p.SortOrder != nil {
+		m["sort_order"] = *p.SortOrder
+	}
+	return m
+}
+
+// RollupRow is the line of one customer or vendor in a rollup report.
+type RollupRow struct {
+	// Name is the customer or vendor name; ID is its Id, empty for rows such as "Not Specified".
+	Name string
+	ID   string
+	// Amounts holds the row's value in each column after the name, in column order.
+	Amounts []json.Number
+}
+
+// Total returns the amount in the last column: the total when the report is summarized by
+// period, the net income of a CustomerIncome report, or the expenses of a VendorExpenses report.
+func (r RollupRow) Total() json.Number {
+	if len(r.Amounts) == 0 {
+		return ""
+	}
+	return r.Amounts[len(r.Amounts)-1]
+}
+
+// Rollup is a CustomerIncome or VendorExpenses report grouped by customer or vendor.
+// Sub-customers appear as rows of their own; section subtotals and the grand total are left
+// out and can be read from Report.
+type Rollup struct {
+	Report *Report
+	Rows   []RollupRow
+}
+
+// SortByAmount orders the rows by the given column of Amounts, largest first, as "top
+// customers" lists want. A negative column sorts by Total. Rows keep their report order on ties.
+func (r *Rollup) SortByAmount(column int) {
+	amount := func(row RollupRow) float64 {
+		if column < 0 {
+			return numberFloat(row.Total())
+		}
+		if column >= len(row.Amounts) {
+			return 0
+		}
+		return numberFloat(row.Amounts[column])
+	}
+
+	sort.SliceStable(r.Rows, func(i, j int) bool {
+		return amount(r.Rows[i]) > amount(r.Rows[j])
+	})
+}
+
+// newRollup reads the customer or vendor rows of the report.
+func newRollup(report *Report) *Rollup {
+	rollup := &Rollup{Report: report}
+
+	for _, row := range report.DataRows() {
+		name := row.cell(0)
+		line := RollupRow{Name: name.Value, ID: name.ID}
+		for _, cell := range row.ColData[1:] {
+			line.Amounts = append(line.Amounts, glAmount(cell.Value))
+		}
+		rollup.Rows = append(rollup.Rows, line)
+	}
+
+	return rollup
+}
+
+func (c *Client) getRollup(name string, params *RollupQueryParams) (*Rollup, error) {
+	var queryParams map[string]string
+	if params != nil {
+		queryParams = params.toMap()
+	}
+
+	report, err := c.getReport(name, queryParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return newRollup(report), nil
+}
+
+// GetCustomerIncome fetches the CustomerIncome report: income, expenses and net income per
+// customer over the period. Pass nil for params to use the API defaults.
+func (c *Client) GetCustomerIncome(params *RollupQueryParams) (*Rollup, error) {
+	return c.getRollup("CustomerIncome", params)
+}
+
+// GetVendorExpenses fetches the VendorExpenses report: the spend per vendor over the period.
+// Pass nil for params to use the API defaults.
+func (c *Client) GetVendorExpenses(params *RollupQueryParams) (*Rollup, error) {
+	return c.getRollup("VendorExpenses", params)
+}
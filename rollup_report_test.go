@@ -0,0 +1,57 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCustomerIncome(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v3/company/test-realm/reports/CustomerIncome", r.URL.Path)
+		assert.Equal(t, "This Fiscal Year", r.URL.Query().Get("date_macro"))
+		w.Write([]byte(`{
+  "Header": {"ReportName": "CustomerIncome"},
+  "Columns": {"Column": [
+    {"ColTitle": "", "ColType": "String"},
+    {"ColTitle": "Income", "ColType": "Money"},
+    {"ColTitle": "Expenses", "ColType": "Money"},
+    {"ColTitle": "Net Income", "ColType": "Money"}
+  ]},
+  "Rows": {"Row": [
+    {"type": "Data", "ColData": [{"value": "Amy's Bird Sanctuary", "id": "1"}, {"value": "239.00"}, {"value": ""}, {"value": "239.00"}]},
+    {"type": "Section",
+     "Header": {"ColData": [{"value": "Freeman Sporting Goods", "id": "5"}, {"value": ""}, {"value": ""}, {"value": ""}]},
+     "Rows": {"Row": [
+       {"type": "Data", "ColData": [{"value": "55 Twin Lane", "id": "6"}, {"value": "1,085.00"}, {"value": "35.00"}, {"value": "1,050.00"}]}
+     ]},
+     "Summary": {"ColData": [{"value": "Total Freeman Sporting Goods"}, {"value": "1,085.00"}, {"value": "35.00"}, {"value": "1,050.00"}]}},
+    {"type": "Data", "ColData": [{"value": "Not Specified"}, {"value": ""}, {"value": "-12.50"}, {"value": "-12.50"}]},
+    {"type": "Section", "group": "GrandTotal",
+     "Summary": {"ColData": [{"value": "TOTAL"}, {"value": "1,324.00"}, {"value": "22.50"}, {"value": "1,276.50"}]}}
+  ]}
+}`))
+	})
+
+	macro := "This Fiscal Year"
+	rollup, err := client.GetCustomerIncome(&RollupQueryParams{DateMacro: &macro})
+	require.NoError(t, err)
+	require.Len(t, rollup.Rows, 3)
+	assert.Equal(t, RollupRow{Name: "55 Twin Lane", ID: "6", Amounts: []json.Number{"1085.00", "35.00", "1050.00"}}, rollup.Rows[1])
+	assert.Equal(t, json.Number("-12.50"), rollup.Rows[2].Total())
+
+	rollup.SortByAmount(-1)
+	assert.Equal(t, []string{"6", "1", ""}, []string{rollup.Rows[0].ID, rollup.Rows[1].ID, rollup.Rows[2].ID})
+
+	// Column 1 is Expenses; an empty cell counts as zero.
+	rollup.SortByAmount(1)
+	assert.Equal(t, "6", rollup.Rows[0].ID)
+	assert.Equal(t, "1", rollup.Rows[1].ID)
+
+	term := "3"
+	_, err = client.GetVendorExpenses(&RollupQueryParams{Term: &term})
+	assert.EqualError(t, err, "the VendorExpenses report does not support the term filter")
+}
@@ -0,0 +1,120 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// Line detail types of sales forms (invoices, estimates, sales receipts, credit memos).
+const (
+	SalesItemLineDetailType = "SalesItemLineDetail"
+	GroupLineDetailType     = "GroupLineDetail"
+	DescriptionOnlyLineType = "DescriptionOnly"
+	DiscountLineDetailType  = "DiscountLineDetail"
+	SubTotalLineDetailType  = "SubTotalLineDetail"
+)
+
+// NewDiscountLine returns a discount line for a sales form. With percent set, value is the
+// discount rate (e.g. "10" for 10%) and QuickBooks computes the amount; otherwise value is
+// the discount amount.
+func NewDiscountLine(percent bool, value json.Number) Line {
+	line := Line{
+		DetailType:         DiscountLineDetailType,
+		DiscountLineDetail: DiscountLineDetail{PercentBased: &percent},
+	}
+
+	if percent {
+		line.DiscountLineDetail.DiscountPercent = value
+	} else {
+		line.Amount = value
+	}
+
+	return line
+}
+
+// NewSubTotalLine returns a subtotal line. QuickBooks computes its amount from the lines above it.
+func NewSubTotalLine() Line {
+	return Line{
+		DetailType:         SubTotalLineDetailType,
+		SubTotalLineDetail: &SubTotalLineDetail{},
+	}
+}
+
+// ValidateSalesLines checks the ordering rules QuickBooks applies to discount and subtotal
+// lines on sales forms:
+//   - there is at most one discount line,
+//   - it comes after at least one item line, since it discounts the lines above it,
+//   - when the form has a subtotal line, the discount line directly follows it.
+func ValidateSalesLines(lines []Line) error {
+	discountAt, subTotalAt := -1, -1
+	itemLines := 0
+
+	for i, line := range lines {
+		switch line.DetailType {
+		case DiscountLineDetailType:
+			if discountAt >= 0 {
+				return errors.New("a sales form can have only one discount line")
+			}
+			if itemLines == 0 {
+				return errors.New("discount line must follow the item lines it discounts")
+			}
+			discountAt = i
+		case SubTotalLineDetailType:
+			subTotalAt = i
+		case SalesItemLineDetailType, GroupLineDetailType:
+			itemLines++
+		}
+	}
+
+	if discountAt >= 0 && subTotalAt >= 0 && discountAt != subTotalAt+1 {
+		return errors.New("discount line must directly follow the subtotal line")
+	}
+
+	return nil
+}
+
+// SalesLineTotal is the amount QuickBooks computed for one item line of a sales form.
+type SalesLineTotal struct {
+	ID     string
+	Amount json.Number
+	// DiscountAmt is the line-level discount, which only some locales support.
+	DiscountAmt json.Number
+}
+
+// TransactionTotals holds the totals QuickBooks computed for a sales form. Read them from
+// the response rather than re-adding the lines: QuickBooks rounds per line and per tax rate,
+// so a client-side sum can be off by a cent.
+type TransactionTotals struct {
+	// SubTotal is the amount of the subtotal line QuickBooks adds to every sales form.
+	SubTotal json.Number
+	// Discount is the amount of the document-level discount line, or empty without one.
+	Discount json.Number
+	TotalTax json.Number
+	TotalAmt json.Number
+	Lines    []SalesLineTotal
+}
+
+// salesTotals collects the computed totals from the lines and tax detail of a sales form.
+func salesTotals(lines []Line, taxDetail *TxnTaxDetail, totalAmt json.Number) TransactionTotals {
+	totals := TransactionTotals{TotalAmt: totalAmt}
+	if taxDetail != nil {
+		totals.TotalTax = taxDetail.TotalTax
+	}
+
+	for _, line := range lines {
+		switch line.DetailType {
+		case SubTotalLineDetailType:
+			totals.SubTotal = line.Amount
+		case DiscountLineDetailType:
+			totals.Discount = line.Amount
+		case SalesItemLineDetailType, GroupLineDetailType:
+			totals.Lines = append(totals.Lines, SalesLineTotal{
+				ID:          line.ID,
+				Amount:      line.Amount,
+				DiscountAmt: line.SalesItemLineDetail.DiscountAmt,
+			})
+		}
+	}
+
+	return totals
+}
@@ -0,0 +1,78 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDiscountLine(t *testing.T) {
+	percent := NewDiscountLine(true, "10")
+	assert.Equal(t, DiscountLineDetailType, percent.DetailType)
+	assert.True(t, *percent.DiscountLineDetail.PercentBased)
+	assert.Equal(t, json.Number("10"), percent.DiscountLineDetail.DiscountPercent)
+
+	amount := NewDiscountLine(false, "25.00")
+	assert.False(t, *amount.DiscountLineDetail.PercentBased)
+	assert.Equal(t, json.Number("25.00"), amount.Amount)
+
+	b, err := json.Marshal(NewSubTotalLine())
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"DetailType":"SubTotalLineDetail"`)
+	assert.Contains(t, string(b), `"SubTotalLineDetail":{}`)
+}
+
+func TestValidateSalesLines(t *testing.T) {
+	item := Line{Amount: "100", DetailType: SalesItemLineDetailType}
+	discount := NewDiscountLine(true, "10")
+	subTotal := NewSubTotalLine()
+
+	assert.NoError(t, ValidateSalesLines([]Line{item, discount}))
+	assert.NoError(t, ValidateSalesLines([]Line{item, item, subTotal, discount}))
+
+	assert.Error(t, ValidateSalesLines([]Line{discount, item}))
+	assert.Error(t, ValidateSalesLines([]Line{item, discount, discount}))
+	assert.Error(t, ValidateSalesLines([]Line{item, subTotal, item, discount}))
+
+	_, err := (&Client{}).BuildCreateInvoicePayload(&InvoiceCreateInput{Line: []Line{discount, item}})
+	assert.Error(t, err)
+
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL.Path)
+	})
+	_, err = client.CreateSalesReceipt(&SalesReceiptCreateInput{Line: []Line{discount, item}})
+	assert.Error(t, err)
+	_, err = client.CreateCreditMemo(&CreditMemoCreateInput{Line: []Line{item, discount, discount}})
+	assert.Error(t, err)
+}
+
+func TestTransactionTotals(t *testing.T) {
+	var invoice Invoice
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"Id": "130",
+		"Line": [
+			{"Id": "1", "LineNum": 1, "Amount": 33.33, "DetailType": "SalesItemLineDetail", "SalesItemLineDetail": {"Qty": 3, "UnitPrice": 11.11}},
+			{"Id": "2", "LineNum": 2, "Amount": 66.67, "DetailType": "SalesItemLineDetail", "SalesItemLineDetail": {"DiscountAmt": 5}},
+			{"Amount": 100.00, "DetailType": "SubTotalLineDetail", "SubTotalLineDetail": {}},
+			{"Amount": 10.00, "DetailType": "DiscountLineDetail", "DiscountLineDetail": {"PercentBased": true, "DiscountPercent": 10}}
+		],
+		"TxnTaxDetail": {"TotalTax": 7.43},
+		"TotalAmt": 97.43
+	}`), &invoice))
+
+	totals := invoice.TransactionTotals()
+	assert.Equal(t, json.Number("100.00"), totals.SubTotal)
+	assert.Equal(t, json.Number("10.00"), totals.Discount)
+	assert.Equal(t, json.Number("7.43"), totals.TotalTax)
+	assert.Equal(t, json.Number("97.43"), totals.TotalAmt)
+	assert.Equal(t, []SalesLineTotal{
+		{ID: "1", Amount: "33.33"},
+		{ID: "2", Amount: "66.67", DiscountAmt: "5"},
+	}, totals.Lines)
+
+	estimate := Estimate{TotalAmt: "5"}
+	assert.Equal(t, TransactionTotals{TotalAmt: "5"}, estimate.TransactionTotals())
+}
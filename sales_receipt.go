@@ -3,90 +3,126 @@ package quickbooks
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
-	"strconv"
 )
 
 // SalesReceipt represents a QuickBooks SalesReceipt object as returned by the API.
 // Read-only fields (Id, SyncToken, MetaData, TotalAmt, Balance, TxnSource) are populated by the service.
+// PaymentRefNum records the check number or card reference the customer paid with.
 type SalesReceipt struct {
-	ID                           string         `json:"Id,omitempty"`
-	SyncToken                    string         `json:",omitempty"`
-	MetaData                     *MetaData      `json:",omitempty"`
-	CustomerRef                  *ReferenceType `json:",omitempty"`
-	CustomerMemo                 *MemoRef       `json:",omitempty"`
-	DocNumber                    *string        `json:",omitempty"`
-	TxnDate                      *Date          `json:",omitempty"`
-	DepartmentRef                *ReferenceType `json:",omitempty"`
-	PrivateNote                  *string        `json:",omitempty"`
-	Line                         []Line         `json:",omitempty"`
-	TxnTaxDetail                 *TxnTaxDetail  `json:",omitempty"`
-	BillAddr                     *Address       `json:",omitempty"`
-	ShipAddr                     *Address       `json:",omitempty"`
-	ClassRef                     *ReferenceType `json:",omitempty"`
-	ShipMethodRef                *ReferenceType `json:",omitempty"`
-	ShipDate                     *Date          `json:",omitempty"`
-	TrackingNum                  *string        `json:",omitempty"`
-	TotalAmt                     json.Number    `json:",omitempty"`
-	CurrencyRef                  *ReferenceType `json:",omitempty"`
-	ExchangeRate                 json.Number    `json:",omitempty"`
-	DepositToAccountRef          *ReferenceType `json:",omitempty"`
-	ApplyTaxAfterDiscount        *bool          `json:",omitempty"`
-	PrintStatus                  *string        `json:",omitempty"`
-	EmailStatus                  *string        `json:",omitempty"`
-	BillEmail                    *EmailAddress  `json:",omitempty"`
-	BillEmailCC                  *EmailAddress  `json:"BillEmailCc,omitempty"`
-	BillEmailBCC                 *EmailAddress  `json:"BillEmailBcc,omitempty"`
-	DeliveryInfo                 *DeliveryInfo  `json:",omitempty"`
-	Balance                      json.Number    `json:",omitempty"`
-	TxnSource                    *string        `json:",omitempty"`
-	PaymentMethodRef             *ReferenceType `json:",omitempty"`
-	CustomField                  []CustomField  `json:",omitempty"`
+	EntityMeta
+
+	ID                   string               `json:"Id,omitempty"`
+	SyncToken            string               `json:",omitempty"`
+	MetaData             *MetaData            `json:",omitempty"`
+	CustomerRef          *ReferenceType       `json:",omitempty"`
+	CustomerMemo         *MemoRef             `json:",omitempty"`
+	DocNumber            *string              `json:",omitempty"`
+	TxnDate              *Date                `json:",omitempty"`
+	DepartmentRef        *ReferenceType       `json:",omitempty"`
+	PrivateNote          *string              `json:",omitempty"`
+	Line                 []Line               `json:",omitempty"`
+	TxnTaxDetail         *TxnTaxDetail        `json:",omitempty"`
+	GlobalTaxCalculation GlobalTaxCalculation `json:",omitempty"`
+	BillAddr             *Address             `json:",omitempty"`
+	ShipAddr             *Address             `json:",omitempty"`
+	// ShipFromAddr is where the goods ship from; Automated Sales Tax uses it for origin-based rates.
+	ShipFromAddr *Address `json:",omitempty"`
+	// FreeFormAddress is true when ShipAddr is stored exactly as entered rather than as a
+	// formatted address. See Address.
+	FreeFormAddress       *bool          `json:",omitempty"`
+	ClassRef              *ReferenceType `json:",omitempty"`
+	ShipMethodRef         *ReferenceType `json:",omitempty"`
+	ShipDate              *Date          `json:",omitempty"`
+	TrackingNum           *string        `json:",omitempty"`
+	TotalAmt              json.Number    `json:",omitempty"`
+	CurrencyRef           *ReferenceType `json:",omitempty"`
+	ExchangeRate          json.Number    `json:",omitempty"`
+	DepositToAccountRef   *ReferenceType `json:",omitempty"`
+	ApplyTaxAfterDiscount *bool          `json:",omitempty"`
+	PrintStatus           *string        `json:",omitempty"`
+	EmailStatus           *string        `json:",omitempty"`
+	BillEmail             *EmailAddress  `json:",omitempty"`
+	BillEmailCC           *EmailAddress  `json:"BillEmailCc,omitempty"`
+	BillEmailBCC          *EmailAddress  `json:"BillEmailBcc,omitempty"`
+	DeliveryInfo          *DeliveryInfo  `json:",omitempty"`
+	Balance               json.Number    `json:",omitempty"`
+	TxnSource             *string        `json:",omitempty"`
+	PaymentMethodRef      *ReferenceType `json:",omitempty"`
+	PaymentRefNum         *string        `json:",omitempty"`
+	CustomField           []CustomField  `json:",omitempty"`
+}
+
+// TransactionTotals returns the subtotal, discount, tax and total QuickBooks computed for the sales receipt.
+func (s *SalesReceipt) TransactionTotals() TransactionTotals {
+	return salesTotals(s.Line, s.TxnTaxDetail, s.TotalAmt)
 }
 
 // SalesReceiptCreateInput contains the writable fields accepted when creating a SalesReceipt.
 // Line is required; all other fields are optional.
 type SalesReceiptCreateInput struct {
-	Line                         []Line         `json:",omitempty"`
-	CustomerRef                  *ReferenceType `json:",omitempty"`
-	CustomerMemo                 *MemoRef       `json:",omitempty"`
-	DocNumber                    *string        `json:",omitempty"`
-	TxnDate                      *Date          `json:",omitempty"`
-	DepartmentRef                *ReferenceType `json:",omitempty"`
-	PrivateNote                  *string        `json:",omitempty"`
-	TxnTaxDetail                 *TxnTaxDetail  `json:",omitempty"`
-	BillAddr                     *Address       `json:",omitempty"`
-	ShipAddr                     *Address       `json:",omitempty"`
-	ClassRef                     *ReferenceType `json:",omitempty"`
-	ShipMethodRef                *ReferenceType `json:",omitempty"`
-	ShipDate                     *Date          `json:",omitempty"`
-	TrackingNum                  *string        `json:",omitempty"`
-	CurrencyRef                  *ReferenceType `json:",omitempty"`
-	ExchangeRate                 json.Number    `json:",omitempty"`
-	DepositToAccountRef          *ReferenceType `json:",omitempty"`
-	ApplyTaxAfterDiscount        *bool          `json:",omitempty"`
-	PrintStatus                  *string        `json:",omitempty"`
-	EmailStatus                  *string        `json:",omitempty"`
-	BillEmail                    *EmailAddress  `json:",omitempty"`
-	BillEmailCC                  *EmailAddress  `json:"BillEmailCc,omitempty"`
-	BillEmailBCC                 *EmailAddress  `json:"BillEmailBcc,omitempty"`
-	PaymentMethodRef             *ReferenceType `json:",omitempty"`
-	CustomField                  []CustomField  `json:",omitempty"`
+	Line                 []Line               `json:",omitempty"`
+	CustomerRef          *ReferenceType       `json:",omitempty"`
+	CustomerMemo         *MemoRef             `json:",omitempty"`
+	DocNumber            *string              `json:",omitempty"`
+	TxnDate              *Date                `json:",omitempty"`
+	DepartmentRef        *ReferenceType       `json:",omitempty"`
+	PrivateNote          *string              `json:",omitempty"`
+	TxnTaxDetail         *TxnTaxDetail        `json:",omitempty"`
+	GlobalTaxCalculation GlobalTaxCalculation `json:",omitempty"`
+	BillAddr             *Address             `json:",omitempty"`
+	ShipAddr             *Address             `json:",omitempty"`
+	ShipFromAddr         *Address             `json:",omitempty"`
+	FreeFormAddress      *bool                `json:",omitempty"`
+	ClassRef             *ReferenceType       `json:",omitempty"`
+	ShipMethodRef        *ReferenceType       `json:",omitempty"`
+	ShipDate             *Date                `json:",omitempty"`
+	TrackingNum          *string              `json:",omitempty"`
+	CurrencyRef          *ReferenceType       `json:",omitempty"`
+	ExchangeRate         json.Number          `json:",omitempty"`
+	DepositToAccountRef  *ReferenceType       `json:",omitempty"`
+	// ApplyTaxAfterDiscount is ignored for Automated Sales Tax and non-US companies;
+	// see Preferences.CheckApplyTaxAfterDiscount.
+	ApplyTaxAfterDiscount *bool          `json:",omitempty"`
+	PrintStatus           *string        `json:",omitempty"`
+	EmailStatus           *string        `json:",omitempty"`
+	BillEmail             *EmailAddress  `json:",omitempty"`
+	BillEmailCC           *EmailAddress  `json:"BillEmailCc,omitempty"`
+	BillEmailBCC          *EmailAddress  `json:"BillEmailBcc,omitempty"`
+	PaymentMethodRef      *ReferenceType `json:",omitempty"`
+	PaymentRefNum         *string        `json:",omitempty"`
+	CustomField           []CustomField  `json:",omitempty"`
+	// Extra holds fields the struct does not model yet; see ExtraFields.
+	Extra ExtraFields `json:"-"`
 }
 
 // CreateSalesReceipt creates the given SalesReceipt on the QuickBooks server, returning
 // the resulting SalesReceipt object.
 func (c *Client) CreateSalesReceipt(input *SalesReceiptCreateInput) (*SalesReceipt, error) {
-	var resp struct {
-		SalesReceipt SalesReceipt
-		Time         Date
+	if err := input.validate(); err != nil {
+		return nil, err
 	}
 
-	if err := c.post("salesreceipt", input, &resp, nil); err != nil {
+	if err := c.applyDocNumberPolicy(&input.DocNumber); err != nil {
 		return nil, err
 	}
 
-	return &resp.SalesReceipt, nil
+	if err := c.resolveCustomFields(input.CustomField); err != nil {
+		return nil, err
+	}
+
+	return postSingle[SalesReceipt](c, "salesreceipt", input, nil)
+}
+
+func (input *SalesReceiptCreateInput) validate() error {
+	if err := validateGlobalTaxCalculation(input.GlobalTaxCalculation); err != nil {
+		return err
+	}
+
+	if err := ValidateSalesLines(input.Line); err != nil {
+		return err
+	}
+
+	return validateMemos(input.CustomerMemo, input.PrivateNote)
 }
 
 // DeleteSalesReceipt deletes the sales receipt.
@@ -95,121 +131,59 @@ func (c *Client) DeleteSalesReceipt(salesReceipt *SalesReceipt) error {
 		return errors.New("missing id/sync token")
 	}
 
-	return c.post("salesreceipt", salesReceipt, nil, map[string]string{"operation": "delete"})
+	return c.postRetryingStale("salesreceipt", "salesreceipt/"+salesReceipt.ID, &salesReceipt.SyncToken, salesReceipt, nil, map[string]string{"operation": "delete"})
 }
 
 // FindSalesReceipts gets the full list of SalesReceipts in the QuickBooks account.
 func (c *Client) FindSalesReceipts() ([]SalesReceipt, error) {
-	var resp struct {
-		QueryResponse struct {
-			SalesReceipts []SalesReceipt `json:"SalesReceipt"`
-			MaxResults    int
-			StartPosition int
-			TotalCount    int
-		}
-	}
-
-	if err := c.query("SELECT COUNT(*) FROM SalesReceipt", &resp); err != nil {
-		return nil, err
-	}
-
-	if resp.QueryResponse.TotalCount == 0 {
-		return nil, errors.New("no sales receipts could be found")
-	}
-
-	salesReceipts := make([]SalesReceipt, 0, resp.QueryResponse.TotalCount)
-
-	for i := 0; i < resp.QueryResponse.TotalCount; i += queryPageSize {
-		query := "SELECT * FROM SalesReceipt ORDERBY Id STARTPOSITION " + strconv.Itoa(i+1) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
-
-		if err := c.query(query, &resp); err != nil {
-			return nil, err
-		}
-
-		if resp.QueryResponse.SalesReceipts == nil {
-			return nil, errors.New("no sales receipts could be found")
-		}
-
-		salesReceipts = append(salesReceipts, resp.QueryResponse.SalesReceipts...)
-	}
+	return findAllPages[SalesReceipt](c, "SalesReceipt", "no sales receipts could be found")
+}
 
-	return salesReceipts, nil
+// FindSalesReceiptsWithOptions returns the sales receipts matching opts, ordered by opts.OrderBy.
+// Pass nil for opts to list them all.
+func (c *Client) FindSalesReceiptsWithOptions(opts *ListOptions) ([]SalesReceipt, error) {
+	return findAllWithOptions[SalesReceipt](c, "SalesReceipt", false, opts)
 }
 
 // FindSalesReceiptByID finds the sales receipt by the given id.
 func (c *Client) FindSalesReceiptByID(id string) (*SalesReceipt, error) {
-	var resp struct {
-		SalesReceipt SalesReceipt
-		Time         Date
-	}
+	return getSingle[SalesReceipt](c, "salesreceipt/"+id, nil)
+}
 
-	if err := c.get("salesreceipt/"+id, &resp, nil); err != nil {
-		return nil, err
+// FindSalesReceiptPDF returns the PDF of the sales receipt with the given Id, e.g. to hand
+// the customer a printed receipt.
+func (c *Client) FindSalesReceiptPDF(id string) ([]byte, error) {
+	if id == "" {
+		return nil, errors.New("missing sales receipt id")
 	}
 
-	return &resp.SalesReceipt, nil
+	return c.getPDF("salesreceipt/" + id + "/pdf")
 }
 
 // QuerySalesReceipts accepts an SQL query and returns all sales receipts found using it.
 func (c *Client) QuerySalesReceipts(query string) ([]SalesReceipt, error) {
-	var resp struct {
-		QueryResponse struct {
-			SalesReceipts []SalesReceipt `json:"SalesReceipt"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
+	salesReceipts, err := queryEntities[SalesReceipt](c, "SalesReceipt", query)
+	if err != nil {
+		return salesReceipts, err
 	}
 
-	if resp.QueryResponse.SalesReceipts == nil {
+	if salesReceipts == nil {
 		return nil, errors.New("could not find any sales receipts")
 	}
 
-	return resp.QueryResponse.SalesReceipts, nil
+	return salesReceipts, nil
 }
 
 // ListSalesReceipts returns one page of SalesReceipts ordered by Id.
 // Pass an empty pageToken to start from the beginning.
 // The returned nextPageToken is empty when there are no more results.
 func (c *Client) ListSalesReceipts(pageToken string, pageSize int) (*ListResponse[SalesReceipt], error) {
-	if pageSize <= 0 || pageSize > queryPageSize {
-		pageSize = queryPageSize
-	}
-
-	startPosition := 1
-	if pageToken != "" {
-		var err error
-		startPosition, err = strconv.Atoi(pageToken)
-		if err != nil {
-			return nil, fmt.Errorf("invalid page token: %v", err)
-		}
-	}
-
-	var resp struct {
-		QueryResponse struct {
-			SalesReceipts []SalesReceipt `json:"SalesReceipt"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	query := "SELECT * FROM SalesReceipt ORDERBY Id STARTPOSITION " + strconv.Itoa(startPosition) + " MAXRESULTS " + strconv.Itoa(pageSize)
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
-	}
-
-	result := &ListResponse[SalesReceipt]{Items: resp.QueryResponse.SalesReceipts}
-	if len(result.Items) == pageSize {
-		result.NextPageToken = strconv.Itoa(startPosition + pageSize)
-	}
-
-	return result, nil
+	return listPage[SalesReceipt](c, "SalesReceipt", pageToken, pageSize)
 }
 
-// SendSalesReceipt sends the sales receipt to the SalesReceipt.BillEmail if emailAddress is left empty.
+// SendSalesReceipt emails the sales receipt to its BillEmail, or to emailAddress if it is
+// not empty. Like SendInvoice, it overwrites BillEmail with emailAddress, updates the email
+// status, and has no CC/BCC override.
 func (c *Client) SendSalesReceipt(salesReceiptId string, emailAddress string) error {
 	queryParameters := make(map[string]string)
 
@@ -241,16 +215,7 @@ func (c *Client) UpdateSalesReceipt(salesReceipt *SalesReceipt) (*SalesReceipt,
 		Sparse:       true,
 	}
 
-	var salesReceiptData struct {
-		SalesReceipt SalesReceipt
-		Time         Date
-	}
-
-	if err = c.post("salesreceipt", payload, &salesReceiptData, nil); err != nil {
-		return nil, err
-	}
-
-	return &salesReceiptData.SalesReceipt, err
+	return updateSingle[SalesReceipt](c, "salesreceipt", "salesreceipt/"+salesReceipt.ID, &salesReceipt.SyncToken, payload)
 }
 
 // VoidSalesReceipt voids the sales receipt.
@@ -266,5 +231,5 @@ func (c *Client) VoidSalesReceipt(salesReceipt *SalesReceipt) error {
 
 	salesReceipt.SyncToken = existingSalesReceipt.SyncToken
 
-	return c.post("salesreceipt", salesReceipt, nil, map[string]string{"operation": "void"})
+	return c.postRetryingStale("salesreceipt", "salesreceipt/"+salesReceipt.ID, &salesReceipt.SyncToken, salesReceipt, nil, map[string]string{"operation": "void"})
 }
@@ -0,0 +1,191 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// US companies mark each sales line as taxable or not with one of these pseudo tax codes,
+// whether or not they use Automated Sales Tax. Companies outside the US reference a real
+// TaxCode Id on each line instead.
+const (
+	TaxableTaxCode    = "TAX"
+	NonTaxableTaxCode = "NON"
+)
+
+// UsesAutomatedSalesTax reports whether the company is a US company on Automated Sales Tax (AST).
+//
+// AST companies let QuickBooks pick the rate from the shipping address: lines only say whether
+// they are taxable, and TxnTaxDetail.TxnTaxCodeRef must not be sent (TxnTaxDetail.TotalTax may
+// still be set to override the computed tax). Manual sales tax companies choose the rate by
+// setting TxnTaxDetail.TxnTaxCodeRef.
+func (p *Preferences) UsesAutomatedSalesTax() bool {
+	return p.TaxPrefs != nil && p.TaxPrefs.PartnerTaxEnabled != nil && *p.TaxPrefs.PartnerTaxEnabled
+}
+
+// CheckTxnTaxDetail returns an error if the given transaction tax detail is not valid for the
+// company's sales tax mode.
+//
+// The Create methods do not call it, since that would cost a preferences request on every
+// create; callers that hold the result of FindPreferences run it on TxnTaxDetail before
+// creating the transaction.
+func (p *Preferences) CheckTxnTaxDetail(detail *TxnTaxDetail) error {
+	if detail == nil {
+		return nil
+	}
+
+	if p.UsesAutomatedSalesTax() && detail.TxnTaxCodeRef.Value != "" {
+		return errors.New("TxnTaxDetail.TxnTaxCodeRef cannot be set for companies using Automated Sales Tax")
+	}
+
+	return nil
+}
+
+// SetLineTaxable marks a sales item line as taxable or non-taxable, the way US companies
+// (with or without Automated Sales Tax) expect.
+func SetLineTaxable(line *Line, taxable bool) {
+	code := NonTaxableTaxCode
+	if taxable {
+		code = TaxableTaxCode
+	}
+
+	line.SalesItemLineDetail.TaxCodeRef = &ReferenceType{NameValue: NameValue{Value: code}}
+}
+
+// SetLineTaxCode sets the tax code of a sales item line, the way companies outside the US expect.
+func SetLineTaxCode(line *Line, taxCodeID string) {
+	line.SalesItemLineDetail.TaxCodeRef = &ReferenceType{NameValue: NameValue{Value: taxCodeID}}
+}
+
+// SetManualSalesTax sets the tax code (rate or group) applied to the whole transaction, for
+// companies using manual sales tax. It returns an error for Automated Sales Tax companies.
+func (p *Preferences) SetManualSalesTax(detail *TxnTaxDetail, taxCodeID string) error {
+	if p.UsesAutomatedSalesTax() {
+		return errors.New("the transaction tax code is computed by QuickBooks for companies using Automated Sales Tax")
+	}
+
+	detail.TxnTaxCodeRef = ReferenceType{NameValue: NameValue{Value: taxCodeID}}
+
+	return nil
+}
+
+// GlobalTaxCalculation tells QuickBooks whether line amounts of a transaction include tax.
+// Companies outside the US need it on transactions with tax; US companies leave it empty.
+type GlobalTaxCalculation string
+
+const (
+	TaxExcluded      GlobalTaxCalculation = "TaxExcluded"
+	TaxInclusive     GlobalTaxCalculation = "TaxInclusive"
+	TaxNotApplicable GlobalTaxCalculation = "NotApplicable"
+)
+
+// IsValid reports whether g is empty or one of the values QuickBooks accepts.
+func (g GlobalTaxCalculation) IsValid() bool {
+	switch g {
+	case "", TaxExcluded, TaxInclusive, TaxNotApplicable:
+		return true
+	}
+	return false
+}
+
+// validateGlobalTaxCalculation rejects a GlobalTaxCalculation QuickBooks would not accept, so
+// the create methods fail before sending the request.
+func validateGlobalTaxCalculation(g GlobalTaxCalculation) error {
+	if !g.IsValid() {
+		return fmt.Errorf("invalid GlobalTaxCalculation %q", g)
+	}
+	return nil
+}
+
+// TaxLineDetailType is the DetailType of the TxnTaxDetail.TaxLine entries.
+const TaxLineDetailType = "TaxLineDetail"
+
+// AppliedTaxRate is the tax one rate contributed to a transaction.
+type AppliedTaxRate struct {
+	TaxRateRef   ReferenceType
+	PercentBased bool
+	// Percent is the rate in percent, e.g. "8.5". It is empty for rates that are not percent based.
+	Percent json.Number
+	// NetAmountTaxable is the amount the rate was applied to.
+	NetAmountTaxable json.Number
+	// Amount is the tax charged at this rate.
+	Amount json.Number
+}
+
+// RatesApplied returns the per-rate breakdown of the transaction tax, in the order QuickBooks
+// reports it. The amounts add up to TotalTax unless the tax was overridden.
+func (d *TxnTaxDetail) RatesApplied() []AppliedTaxRate {
+	if d == nil {
+		return nil
+	}
+
+	var rates []AppliedTaxRate
+	for _, line := range d.TaxLine {
+		if line.DetailType != "" && line.DetailType != TaxLineDetailType {
+			continue
+		}
+
+		detail := line.TaxLineDetail
+		rates = append(rates, AppliedTaxRate{
+			TaxRateRef:       detail.TaxRateRef,
+			PercentBased:     detail.PercentBased != nil && *detail.PercentBased,
+			Percent:          detail.TaxPercent,
+			NetAmountTaxable: detail.NetAmountTaxable,
+			Amount:           line.Amount,
+		})
+	}
+
+	return rates
+}
+
+// ErrApplyTaxAfterDiscountIgnored is returned when ApplyTaxAfterDiscount is set for a company
+// whose tax setup makes QuickBooks ignore it.
+var ErrApplyTaxAfterDiscountIgnored = errors.New("ApplyTaxAfterDiscount has no effect for this company")
+
+// CheckApplyTaxAfterDiscount returns ErrApplyTaxAfterDiscountIgnored if value is set although
+// QuickBooks would ignore it. country is the company country, see Client.CompanyCountry.
+//
+// ApplyTaxAfterDiscount picks whether sales tax is computed on the amount before (false, the
+// default) or after (true) the discount line. Only US companies on manual sales tax get to
+// choose: Automated Sales Tax always taxes the discounted amount, and companies outside the US
+// tax each line with its own tax code, so the flag is dropped and the totals don't change.
+func (p *Preferences) CheckApplyTaxAfterDiscount(country string, value *bool) error {
+	if value == nil {
+		return nil
+	}
+
+	if country != "US" || p.UsesAutomatedSalesTax() {
+		return ErrApplyTaxAfterDiscountIgnored
+	}
+
+	return nil
+}
+
+// SetApplyTaxAfterDiscount sets *field, the ApplyTaxAfterDiscount field of a sales form, to
+// value. It leaves *field alone and returns ErrApplyTaxAfterDiscountIgnored when the setting
+// would have no effect; callers who don't mind can ignore that error.
+func (p *Preferences) SetApplyTaxAfterDiscount(country string, field **bool, value bool) error {
+	if err := p.CheckApplyTaxAfterDiscount(country, &value); err != nil {
+		return err
+	}
+
+	*field = &value
+	return nil
+}
+
+// SetApplyTaxAfterDiscount is Preferences.SetApplyTaxAfterDiscount with the preferences and
+// country of the company fetched for the caller.
+func (c *Client) SetApplyTaxAfterDiscount(field **bool, value bool) error {
+	country, err := c.CompanyCountry()
+	if err != nil {
+		return err
+	}
+
+	preferences, err := c.FindPreferences()
+	if err != nil {
+		return err
+	}
+
+	return preferences.SetApplyTaxAfterDiscount(country, field, value)
+}
@@ -0,0 +1,140 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxnTaxDetailRatesApplied(t *testing.T) {
+	var detail TxnTaxDetail
+	require.NoError(t, json.Unmarshal([]byte(`{
+  "TxnTaxCodeRef": {"value": "3"},
+  "TotalTax": 9.5,
+  "TaxLine": [
+    {
+      "Amount": 8,
+      "DetailType": "TaxLineDetail",
+      "TaxLineDetail": {"TaxRateRef": {"value": "1", "name": "State"}, "PercentBased": true, "TaxPercent": 8, "NetAmountTaxable": 100}
+    },
+    {
+      "Amount": 1.5,
+      "DetailType": "TaxLineDetail",
+      "TaxLineDetail": {"TaxRateRef": {"value": "2"}, "PercentBased": false, "NetAmountTaxable": 100}
+    }
+  ]
+}`), &detail))
+
+	rates := detail.RatesApplied()
+	require.Len(t, rates, 2)
+	assert.Equal(t, AppliedTaxRate{
+		TaxRateRef:       ReferenceType{NameValue: NameValue{Value: "1", Name: "State"}},
+		PercentBased:     true,
+		Percent:          "8",
+		NetAmountTaxable: "100",
+		Amount:           "8",
+	}, rates[0])
+	assert.False(t, rates[1].PercentBased)
+	assert.Equal(t, json.Number("1.5"), rates[1].Amount)
+
+	var none *TxnTaxDetail
+	assert.Nil(t, none.RatesApplied())
+}
+
+func TestSetApplyTaxAfterDiscount(t *testing.T) {
+	enabled := true
+	manual := &Preferences{}
+	ast := &Preferences{TaxPrefs: &TaxPrefs{PartnerTaxEnabled: &enabled}}
+
+	var input InvoiceCreateInput
+	require.NoError(t, manual.SetApplyTaxAfterDiscount("US", &input.ApplyTaxAfterDiscount, true))
+	require.NotNil(t, input.ApplyTaxAfterDiscount)
+	assert.True(t, *input.ApplyTaxAfterDiscount)
+
+	var receipt SalesReceiptCreateInput
+	assert.ErrorIs(t, ast.SetApplyTaxAfterDiscount("US", &receipt.ApplyTaxAfterDiscount, true), ErrApplyTaxAfterDiscountIgnored)
+	assert.ErrorIs(t, manual.SetApplyTaxAfterDiscount("GB", &receipt.ApplyTaxAfterDiscount, false), ErrApplyTaxAfterDiscountIgnored)
+	assert.Nil(t, receipt.ApplyTaxAfterDiscount)
+
+	assert.NoError(t, ast.CheckApplyTaxAfterDiscount("US", nil))
+}
+
+func TestCheckTxnTaxDetail(t *testing.T) {
+	enabled := true
+	ast := &Preferences{TaxPrefs: &TaxPrefs{PartnerTaxEnabled: &enabled}}
+	manual := &Preferences{}
+
+	withCode := &TxnTaxDetail{TxnTaxCodeRef: ReferenceType{NameValue: NameValue{Value: "3"}}}
+	totalOnly := &TxnTaxDetail{TotalTax: "7.50"}
+
+	tests := []struct {
+		name        string
+		preferences *Preferences
+		detail      *TxnTaxDetail
+		wantErr     bool
+	}{
+		{"ast without detail", ast, nil, false},
+		{"ast with tax code", ast, withCode, true},
+		{"ast overriding the total", ast, totalOnly, false},
+		{"manual without detail", manual, nil, false},
+		{"manual with tax code", manual, withCode, false},
+		{"manual overriding the total", manual, totalOnly, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.preferences.CheckTxnTaxDetail(tt.detail)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCreateRejectsInvalidGlobalTaxCalculation(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL.Path)
+	})
+
+	bad := GlobalTaxCalculation("TaxIncluded")
+	vendor := ReferenceType{NameValue: NameValue{Value: "56"}}
+	line := Line{Amount: "10", DetailType: SalesItemLineDetailType}
+
+	creates := map[string]func() error{
+		"estimate": func() error {
+			_, err := client.CreateEstimate(&EstimateCreateInput{GlobalTaxCalculation: bad})
+			return err
+		},
+		"sales receipt": func() error {
+			_, err := client.CreateSalesReceipt(&SalesReceiptCreateInput{GlobalTaxCalculation: bad})
+			return err
+		},
+		"credit memo": func() error {
+			_, err := client.CreateCreditMemo(&CreditMemoCreateInput{GlobalTaxCalculation: bad})
+			return err
+		},
+		"refund receipt": func() error {
+			_, err := client.CreateRefundReceipt(&RefundReceiptCreateInput{GlobalTaxCalculation: bad})
+			return err
+		},
+		"bill": func() error {
+			_, err := client.CreateBill(&BillCreateInput{VendorRef: vendor, Line: []Line{line}, GlobalTaxCalculation: bad})
+			return err
+		},
+		"purchase": func() error {
+			_, err := client.CreatePurchase(&PurchaseCreateInput{GlobalTaxCalculation: bad})
+			return err
+		},
+		"purchase order": func() error {
+			_, err := client.CreatePurchaseOrder(&PurchaseOrderCreateInput{GlobalTaxCalculation: bad})
+			return err
+		},
+	}
+	for name, create := range creates {
+		assert.EqualError(t, create(), `invalid GlobalTaxCalculation "TaxIncluded"`, name)
+	}
+}
@@ -2,12 +2,14 @@ package quickbooks
 
 import (
 	"errors"
-	"strconv"
+	"time"
 )
 
 // TaxAgency represents a QuickBooks TaxAgency object as returned by the API.
 // Read-only fields (Id, SyncToken, MetaData) are populated by the service.
 type TaxAgency struct {
+	EntityMeta
+
 	ID                      string         `json:"Id,omitempty"`
 	SyncToken               string         `json:",omitempty"`
 	MetaData                *MetaData      `json:",omitempty"`
@@ -29,92 +31,51 @@ type TaxAgencyCreateInput struct {
 	TaxOnPurchasesAccountRef *ReferenceType `json:",omitempty"`
 	TaxTrackedOnSales       *bool          `json:",omitempty"`
 	TaxOnSalesAccountRef    *ReferenceType `json:",omitempty"`
+	// Extra holds fields the struct does not model yet; see ExtraFields.
+	Extra ExtraFields `json:"-"`
 }
 
 // CreateTaxAgency creates the given TaxAgency on the QuickBooks server, returning
 // the resulting TaxAgency object.
 func (c *Client) CreateTaxAgency(input *TaxAgencyCreateInput) (*TaxAgency, error) {
-	var resp struct {
-		TaxAgency TaxAgency
-		Time      Date
-	}
+	return postSingle[TaxAgency](c, "taxagency", input, nil)
+}
 
-	if err := c.post("taxagency", input, &resp, nil); err != nil {
-		return nil, err
+// SetTaxAgencyLastFileDate records that sales tax returns for the agency have been filed
+// through date, without changing anything else on the agency. Only the date part is sent.
+func (c *Client) SetTaxAgencyLastFileDate(id string, date time.Time) (*TaxAgency, error) {
+	if date.IsZero() {
+		return nil, errors.New("missing last file date")
 	}
-
-	return &resp.TaxAgency, nil
+	return updateFields[TaxAgency](c, "taxagency", id, map[string]any{"LastFileDate": date.Format(secondFormat)})
 }
 
 // FindTaxAgencies gets the full list of TaxAgencies in the QuickBooks account.
 func (c *Client) FindTaxAgencies() ([]TaxAgency, error) {
-	var resp struct {
-		QueryResponse struct {
-			TaxAgencies   []TaxAgency `json:"TaxAgency"`
-			MaxResults    int
-			StartPosition int
-			TotalCount    int
-		}
-	}
-
-	if err := c.query("SELECT COUNT(*) FROM TaxAgency", &resp); err != nil {
-		return nil, err
-	}
-
-	if resp.QueryResponse.TotalCount == 0 {
-		return nil, errors.New("no tax agencies could be found")
-	}
-
-	taxAgencies := make([]TaxAgency, 0, resp.QueryResponse.TotalCount)
-
-	for i := 0; i < resp.QueryResponse.TotalCount; i += queryPageSize {
-		query := "SELECT * FROM TaxAgency ORDERBY Id STARTPOSITION " + strconv.Itoa(i+1) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
-
-		if err := c.query(query, &resp); err != nil {
-			return nil, err
-		}
-
-		if resp.QueryResponse.TaxAgencies == nil {
-			return nil, errors.New("no tax agencies could be found")
-		}
-
-		taxAgencies = append(taxAgencies, resp.QueryResponse.TaxAgencies...)
-	}
+	return findAllPages[TaxAgency](c, "TaxAgency", "no tax agencies could be found")
+}
 
-	return taxAgencies, nil
+// FindTaxAgenciesWithOptions returns the tax agencies matching opts, ordered by opts.OrderBy.
+// Pass nil for opts to list them all.
+func (c *Client) FindTaxAgenciesWithOptions(opts *ListOptions) ([]TaxAgency, error) {
+	return findAllWithOptions[TaxAgency](c, "TaxAgency", false, opts)
 }
 
 // FindTaxAgencyByID returns a tax agency with a given Id.
 func (c *Client) FindTaxAgencyByID(id string) (*TaxAgency, error) {
-	var resp struct {
-		TaxAgency TaxAgency
-		Time      Date
-	}
-
-	if err := c.get("taxagency/"+id, &resp, nil); err != nil {
-		return nil, err
-	}
-
-	return &resp.TaxAgency, nil
+	return getSingle[TaxAgency](c, "taxagency/"+id, nil)
 }
 
 // QueryTaxAgencies accepts an SQL query and returns all tax agencies found using it.
 func (c *Client) QueryTaxAgencies(query string) ([]TaxAgency, error) {
-	var resp struct {
-		QueryResponse struct {
-			TaxAgencies   []TaxAgency `json:"TaxAgency"`
-			StartPosition int
-			MaxResults    int
-		}
+	taxAgencies, err := queryEntities[TaxAgency](c, "TaxAgency", query)
+	if err != nil {
+		return taxAgencies, err
 	}
 
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
-	}
-
-	if resp.QueryResponse.TaxAgencies == nil {
+	if taxAgencies == nil {
 		return nil, errors.New("could not find any tax agencies")
 	}
 
-	return resp.QueryResponse.TaxAgencies, nil
+	return taxAgencies, nil
 }
@@ -0,0 +1,34 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetTaxAgencyLastFileDate(t *testing.T) {
+	var posted map[string]any
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			assert.Equal(t, "/v3/company/test-realm/taxagency/2", r.URL.Path)
+			w.Write([]byte(`{"TaxAgency": {"Id": "2", "SyncToken": "5", "DisplayName": "State Board"}}`))
+			return
+		}
+
+		assert.Equal(t, "/v3/company/test-realm/taxagency", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&posted))
+		w.Write([]byte(`{"TaxAgency": {"Id": "2", "SyncToken": "6", "DisplayName": "State Board", "LastFileDate": "2024-03-31"}}`))
+	})
+
+	agency, err := client.SetTaxAgencyLastFileDate("2", time.Date(2024, 3, 31, 17, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, "2024-03-31", agency.LastFileDate.Format(secondFormat))
+	assert.Equal(t, map[string]any{"Id": "2", "SyncToken": "5", "sparse": true, "LastFileDate": "2024-03-31"}, posted)
+
+	_, err = client.SetTaxAgencyLastFileDate("2", time.Time{})
+	assert.Error(t, err)
+}
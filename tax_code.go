@@ -2,7 +2,6 @@ package quickbooks
 
 import (
 	"errors"
-	"strconv"
 )
 
 // TaxRateDetail holds the rate reference within a TaxRateList.
@@ -21,6 +20,8 @@ type TaxRateList struct {
 // TaxCodes are read-only — use the TaxService API to create them.
 // Read-only fields (Id, SyncToken, MetaData) are populated by the service.
 type TaxCode struct {
+	EntityMeta
+
 	ID                  string       `json:"Id,omitempty"`
 	SyncToken           string       `json:",omitempty"`
 	MetaData            *MetaData    `json:",omitempty"`
@@ -35,73 +36,30 @@ type TaxCode struct {
 
 // FindTaxCodes gets the full list of TaxCodes in the QuickBooks account.
 func (c *Client) FindTaxCodes() ([]TaxCode, error) {
-	var resp struct {
-		QueryResponse struct {
-			TaxCodes      []TaxCode `json:"TaxCode"`
-			MaxResults    int
-			StartPosition int
-			TotalCount    int
-		}
-	}
-
-	if err := c.query("SELECT COUNT(*) FROM TaxCode", &resp); err != nil {
-		return nil, err
-	}
-
-	if resp.QueryResponse.TotalCount == 0 {
-		return nil, errors.New("no tax codes could be found")
-	}
-
-	taxCodes := make([]TaxCode, 0, resp.QueryResponse.TotalCount)
-
-	for i := 0; i < resp.QueryResponse.TotalCount; i += queryPageSize {
-		query := "SELECT * FROM TaxCode ORDERBY Id STARTPOSITION " + strconv.Itoa(i+1) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
-
-		if err := c.query(query, &resp); err != nil {
-			return nil, err
-		}
-
-		if resp.QueryResponse.TaxCodes == nil {
-			return nil, errors.New("no tax codes could be found")
-		}
-
-		taxCodes = append(taxCodes, resp.QueryResponse.TaxCodes...)
-	}
+	return findAllPages[TaxCode](c, "TaxCode", "no tax codes could be found")
+}
 
-	return taxCodes, nil
+// FindTaxCodesWithOptions returns the tax codes matching opts, ordered by opts.OrderBy.
+// Pass nil for opts to list them all.
+func (c *Client) FindTaxCodesWithOptions(opts *ListOptions) ([]TaxCode, error) {
+	return findAllWithOptions[TaxCode](c, "TaxCode", true, opts)
 }
 
 // FindTaxCodeByID returns a tax code with a given Id.
 func (c *Client) FindTaxCodeByID(id string) (*TaxCode, error) {
-	var resp struct {
-		TaxCode TaxCode
-		Time    Date
-	}
-
-	if err := c.get("taxcode/"+id, &resp, nil); err != nil {
-		return nil, err
-	}
-
-	return &resp.TaxCode, nil
+	return getSingle[TaxCode](c, "taxcode/"+id, nil)
 }
 
 // QueryTaxCodes accepts an SQL query and returns all tax codes found using it.
 func (c *Client) QueryTaxCodes(query string) ([]TaxCode, error) {
-	var resp struct {
-		QueryResponse struct {
-			TaxCodes      []TaxCode `json:"TaxCode"`
-			StartPosition int
-			MaxResults    int
-		}
+	taxCodes, err := queryEntities[TaxCode](c, "TaxCode", query)
+	if err != nil {
+		return taxCodes, err
 	}
 
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
-	}
-
-	if resp.QueryResponse.TaxCodes == nil {
+	if taxCodes == nil {
 		return nil, errors.New("could not find any tax codes")
 	}
 
-	return resp.QueryResponse.TaxCodes, nil
+	return taxCodes, nil
 }
@@ -3,7 +3,6 @@ package quickbooks
 import (
 	"encoding/json"
 	"errors"
-	"strconv"
 )
 
 // EffectiveTaxRate holds a time-bounded tax rate value.
@@ -17,6 +16,8 @@ type EffectiveTaxRate struct {
 // TaxRates are read-only — use the TaxService API to create them.
 // Read-only fields (Id, SyncToken, MetaData) are populated by the service.
 type TaxRate struct {
+	EntityMeta
+
 	ID               string             `json:"Id,omitempty"`
 	SyncToken        string             `json:",omitempty"`
 	MetaData         *MetaData          `json:",omitempty"`
@@ -32,73 +33,30 @@ type TaxRate struct {
 
 // FindTaxRates gets the full list of TaxRates in the QuickBooks account.
 func (c *Client) FindTaxRates() ([]TaxRate, error) {
-	var resp struct {
-		QueryResponse struct {
-			TaxRates      []TaxRate `json:"TaxRate"`
-			MaxResults    int
-			StartPosition int
-			TotalCount    int
-		}
-	}
-
-	if err := c.query("SELECT COUNT(*) FROM TaxRate", &resp); err != nil {
-		return nil, err
-	}
-
-	if resp.QueryResponse.TotalCount == 0 {
-		return nil, errors.New("no tax rates could be found")
-	}
-
-	taxRates := make([]TaxRate, 0, resp.QueryResponse.TotalCount)
-
-	for i := 0; i < resp.QueryResponse.TotalCount; i += queryPageSize {
-		query := "SELECT * FROM TaxRate ORDERBY Id STARTPOSITION " + strconv.Itoa(i+1) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
-
-		if err := c.query(query, &resp); err != nil {
-			return nil, err
-		}
-
-		if resp.QueryResponse.TaxRates == nil {
-			return nil, errors.New("no tax rates could be found")
-		}
-
-		taxRates = append(taxRates, resp.QueryResponse.TaxRates...)
-	}
+	return findAllPages[TaxRate](c, "TaxRate", "no tax rates could be found")
+}
 
-	return taxRates, nil
+// FindTaxRatesWithOptions returns the tax rates matching opts, ordered by opts.OrderBy.
+// Pass nil for opts to list them all.
+func (c *Client) FindTaxRatesWithOptions(opts *ListOptions) ([]TaxRate, error) {
+	return findAllWithOptions[TaxRate](c, "TaxRate", true, opts)
 }
 
 // FindTaxRateByID returns a tax rate with a given Id.
 func (c *Client) FindTaxRateByID(id string) (*TaxRate, error) {
-	var resp struct {
-		TaxRate TaxRate
-		Time    Date
-	}
-
-	if err := c.get("taxrate/"+id, &resp, nil); err != nil {
-		return nil, err
-	}
-
-	return &resp.TaxRate, nil
+	return getSingle[TaxRate](c, "taxrate/"+id, nil)
 }
 
 // QueryTaxRates accepts an SQL query and returns all tax rates found using it.
 func (c *Client) QueryTaxRates(query string) ([]TaxRate, error) {
-	var resp struct {
-		QueryResponse struct {
-			TaxRates      []TaxRate `json:"TaxRate"`
-			StartPosition int
-			MaxResults    int
-		}
+	taxRates, err := queryEntities[TaxRate](c, "TaxRate", query)
+	if err != nil {
+		return taxRates, err
 	}
 
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
-	}
-
-	if resp.QueryResponse.TaxRates == nil {
+	if taxRates == nil {
 		return nil, errors.New("could not find any tax rates")
 	}
 
-	return resp.QueryResponse.TaxRates, nil
+	return taxRates, nil
 }
@@ -0,0 +1,121 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// TaxSummaryQueryParams are the query parameters of the TaxSummary report.
+type TaxSummaryQueryParams struct {
+	// AgencyID is the Id of the TaxAgency to report on. QuickBooks requires it.
+	AgencyID string
+	// Cash or Accrual
+	AccountingMethod *string
+	StartDate        *string
+	EndDate          *string
+	// Predefined date range, e.g. "This Month", "Last Fiscal Year". Ignored when StartDate/EndDate are set.
+	DateMacro *string
+	// ascend or descend
+	SortOrder *string
+}
+
+func (p *TaxSummaryQueryParams) toMap() map[string]string {
+	m := map[string]string{"agency_id": p.AgencyID}
+	if p.AccountingMethod != nil {
+		m["accounting_method"] = *p.AccountingMethod
+	}
+	if p.StartDate != nil {
+		m["start_date"] = *p.StartDate
+	}
+	if p.EndDate != nil {
+		m["end_date"] = *p.EndDate
+	}
+	if p.DateMacro != nil {
+		m["date_macro"] = *p.DateMacro
+	}
+	if p.SortOrder != nil {
+		m["sort_order"] = *p.SortOrder
+	}
+	return m
+}
+
+// TaxSummaryLine is one tax rate of the TaxSummary report.
+type TaxSummaryLine struct {
+	// Section is the heading the rate is listed under, e.g. sales or purchases.
+	Section string
+	// Name is the name of the tax rate; ID is its Id when the report links it.
+	Name string
+	ID   string
+	// TaxableAmount is the net amount the rate was applied to.
+	TaxableAmount json.Number
+	// Tax is the tax collected (or paid) at the rate.
+	Tax json.Number
+}
+
+// TaxSummary is the TaxSummary report of one tax agency: the taxable amount and tax per rate,
+// which is what a sales tax return is prepared from.
+type TaxSummary struct {
+	Report *Report
+	Lines  []TaxSummaryLine
+}
+
+// GetTaxSummary fetches the TaxSummary report of the agency in params.AgencyID.
+func (c *Client) GetTaxSummary(params *TaxSummaryQueryParams) (*TaxSummary, error) {
+	if params == nil || params.AgencyID == "" {
+		return nil, errors.New("missing tax agency id")
+	}
+
+	report, err := c.getReport("TaxSummary", params.toMap())
+	if err != nil {
+		return nil, err
+	}
+
+	return newTaxSummary(report), nil
+}
+
+// newTaxSummary reads the rate lines of the report. The report has no stable column keys, so
+// the last two money columns are taken as the taxable amount and the tax.
+func newTaxSummary(report *Report) *TaxSummary {
+	var money []int
+	for i, col := range report.Columns {
+		if col.ColType == "Money" {
+			money = append(money, i)
+		}
+	}
+
+	taxable, tax := -1, -1
+	if len(money) > 0 {
+		tax = money[len(money)-1]
+	}
+	if len(money) > 1 {
+		taxable = money[len(money)-2]
+	}
+
+	summary := &TaxSummary{Report: report}
+
+	var walk func(section string, rows []ReportRow)
+	walk = func(section string, rows []ReportRow) {
+		for _, row := range rows {
+			if row.IsSection() {
+				heading := section
+				if len(row.Header) > 0 && row.Header[0].Value != "" {
+					heading = row.Header[0].Value
+				}
+				walk(heading, row.Rows)
+				continue
+			}
+
+			name := row.cell(0)
+			summary.Lines = append(summary.Lines, TaxSummaryLine{
+				Section:       section,
+				Name:          name.Value,
+				ID:            name.ID,
+				TaxableAmount: glAmount(row.cell(taxable).Value),
+				Tax:           glAmount(row.cell(tax).Value),
+			})
+		}
+	}
+	walk("", report.Rows)
+
+	return summary
+}
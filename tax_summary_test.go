@@ -0,0 +1,45 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTaxSummary(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v3/company/test-realm/reports/TaxSummary", r.URL.Path)
+		assert.Equal(t, "3", r.URL.Query().Get("agency_id"))
+		assert.Equal(t, "2024-01-01", r.URL.Query().Get("start_date"))
+		w.Write([]byte(`{
+  "Header": {"ReportName": "TaxSummary"},
+  "Columns": {"Column": [
+    {"ColTitle": "", "ColType": "String"},
+    {"ColTitle": "NET AMOUNT", "ColType": "Money"},
+    {"ColTitle": "TAX AMOUNT", "ColType": "Money"}
+  ]},
+  "Rows": {"Row": [
+    {"type": "Section",
+     "Header": {"ColData": [{"value": "Sales"}, {"value": ""}, {"value": ""}]},
+     "Rows": {"Row": [
+       {"type": "Data", "ColData": [{"value": "GST 5%", "id": "7"}, {"value": "1,200.00"}, {"value": "60.00"}]},
+       {"type": "Data", "ColData": [{"value": "PST 7%", "id": "8"}, {"value": "800.00"}, {"value": "56.00"}]}
+     ]},
+     "Summary": {"ColData": [{"value": "Total Sales"}, {"value": "2,000.00"}, {"value": "116.00"}]}}
+  ]}
+}`))
+	})
+
+	_, err := client.GetTaxSummary(nil)
+	assert.Error(t, err)
+
+	start := "2024-01-01"
+	summary, err := client.GetTaxSummary(&TaxSummaryQueryParams{AgencyID: "3", StartDate: &start})
+	require.NoError(t, err)
+	require.Len(t, summary.Lines, 2)
+	assert.Equal(t, TaxSummaryLine{Section: "Sales", Name: "GST 5%", ID: "7", TaxableAmount: "1200.00", Tax: "60.00"}, summary.Lines[0])
+	assert.Equal(t, json.Number("56.00"), summary.Lines[1].Tax)
+}
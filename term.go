@@ -3,12 +3,23 @@ package quickbooks
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"strconv"
+	"time"
+)
+
+// Term types. Standard terms count days from the transaction date, date-driven terms
+// fall due on a fixed day of the month.
+const (
+	TermTypeStandard   = "STANDARD"
+	TermTypeDateDriven = "DATE_DRIVEN"
 )
 
 // Term represents a QuickBooks Term object as returned by the API.
 // Read-only fields (Id, SyncToken, MetaData) are populated by the service.
 type Term struct {
+	EntityMeta
+
 	ID                 string      `json:"Id,omitempty"`
 	SyncToken          string      `json:",omitempty"`
 	MetaData           *MetaData   `json:",omitempty"`
@@ -23,6 +34,11 @@ type Term struct {
 	DiscountDayOfMonth *int        `json:",omitempty"`
 }
 
+// IsActive reports whether the term is active, treating a missing Active field as active.
+func (t *Term) IsActive() bool {
+	return boolValue(t.Active, true)
+}
+
 // TermCreateInput contains the writable fields accepted when creating a Term.
 // Name is required; all other fields are optional.
 type TermCreateInput struct {
@@ -35,103 +51,202 @@ type TermCreateInput struct {
 	DayOfMonthDue      *int        `json:",omitempty"`
 	DueNextMonthDays   *int        `json:",omitempty"`
 	DiscountDayOfMonth *int        `json:",omitempty"`
+	// Extra holds fields the struct does not model yet; see ExtraFields.
+	Extra ExtraFields `json:"-"`
 }
 
-// CreateTerm creates the given Term on the QuickBooks server, returning
-// the resulting Term object.
-func (c *Client) CreateTerm(input *TermCreateInput) (*Term, error) {
-	var resp struct {
-		Term Term
-		Time Date
-	}
+// Validate checks that the term only uses the fields of its Type; QuickBooks rejects terms mixing both sets.
+func (t *Term) Validate() error {
+	return validateTerm(t.Type, t.DueDays, t.DiscountDays, t.DayOfMonthDue, t.DueNextMonthDays, t.DiscountDayOfMonth)
+}
 
-	if err := c.post("term", input, &resp, nil); err != nil {
-		return nil, err
+// Validate checks that the term only uses the fields of its Type; QuickBooks rejects terms mixing both sets.
+func (input *TermCreateInput) Validate() error {
+	return validateTerm(input.Type, input.DueDays, input.DiscountDays, input.DayOfMonthDue, input.DueNextMonthDays, input.DiscountDayOfMonth)
+}
+
+// validateTerm enforces the field set of the term type. When termType is nil the type is
+// inferred from the fields that are set.
+func validateTerm(termType *string, dueDays, discountDays, dayOfMonthDue, dueNextMonthDays, discountDayOfMonth *int) error {
+	standard := dueDays != nil || discountDays != nil
+	dateDriven := dayOfMonthDue != nil || dueNextMonthDays != nil || discountDayOfMonth != nil
+
+	typ := ""
+	if termType != nil {
+		typ = *termType
 	}
 
-	return &resp.Term, nil
-}
+	switch {
+	case typ == "" && standard && dateDriven:
+		return errors.New("term mixes standard and date driven fields")
+	case typ == "" && standard:
+		typ = TermTypeStandard
+	case typ == "" && dateDriven:
+		typ = TermTypeDateDriven
+	}
 
-// DeleteTerm deletes the term.
-func (c *Client) DeleteTerm(term *Term) error {
-	if term.ID == "" || term.SyncToken == "" {
-		return errors.New("missing id/sync token")
+	switch typ {
+	case TermTypeStandard:
+		if dateDriven {
+			return errors.New("standard term cannot set DayOfMonthDue, DueNextMonthDays or DiscountDayOfMonth")
+		}
+		if dueDays == nil {
+			return errors.New("standard term requires DueDays")
+		}
+		if *dueDays < 0 || (discountDays != nil && *discountDays < 0) {
+			return errors.New("term days cannot be negative")
+		}
+	case TermTypeDateDriven:
+		if standard {
+			return errors.New("date driven term cannot set DueDays or DiscountDays")
+		}
+		if dayOfMonthDue == nil {
+			return errors.New("date driven term requires DayOfMonthDue")
+		}
+		if !validDayOfMonth(*dayOfMonthDue) || (discountDayOfMonth != nil && !validDayOfMonth(*discountDayOfMonth)) {
+			return errors.New("day of month must be between 1 and 31")
+		}
+		if dueNextMonthDays != nil && *dueNextMonthDays < 0 {
+			return errors.New("term days cannot be negative")
+		}
+	case "":
+		return errors.New("term requires either DueDays or DayOfMonthDue")
+	default:
+		return fmt.Errorf("unknown term type %q", typ)
 	}
 
-	return c.post("term", term, nil, map[string]string{"operation": "delete"})
+	return nil
 }
 
-// FindTerms gets the full list of Terms in the QuickBooks account.
-func (c *Client) FindTerms() ([]Term, error) {
-	var resp struct {
-		QueryResponse struct {
-			Terms         []Term `json:"Term"`
-			MaxResults    int
-			StartPosition int
-			TotalCount    int
+func validDayOfMonth(day int) bool {
+	return day >= 1 && day <= 31
+}
+
+// Describe renders a human label for the term, e.g. "Net 30", "2% 10 Net 30",
+// "Due on receipt" or "Due on the 15th". It returns Name if the term does not validate.
+func (t *Term) Describe() string {
+	if t.Validate() != nil {
+		return t.Name
+	}
+
+	if t.DayOfMonthDue != nil {
+		label := "Due on the " + ordinal(*t.DayOfMonthDue)
+		if t.DueNextMonthDays != nil && *t.DueNextMonthDays > 0 {
+			label += fmt.Sprintf(", next month if issued within %d days of the due date", *t.DueNextMonthDays)
 		}
+		return label
 	}
 
-	if err := c.query("SELECT COUNT(*) FROM Term", &resp); err != nil {
-		return nil, err
+	label := "Net " + strconv.Itoa(*t.DueDays)
+	if *t.DueDays == 0 {
+		label = "Due on receipt"
 	}
 
-	if resp.QueryResponse.TotalCount == 0 {
-		return nil, errors.New("no terms could be found")
+	if t.DiscountPercent != "" && t.DiscountDays != nil {
+		label = fmt.Sprintf("%s%% %d %s", t.DiscountPercent, *t.DiscountDays, label)
 	}
 
-	terms := make([]Term, 0, resp.QueryResponse.TotalCount)
+	return label
+}
 
-	for i := 0; i < resp.QueryResponse.TotalCount; i += queryPageSize {
-		query := "SELECT * FROM Term ORDERBY Id STARTPOSITION " + strconv.Itoa(i+1) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
+// DiscountDeadline returns the last day on which a transaction dated txnDate still earns the
+// early-payment discount of the term. ok is false if the term offers no discount.
+//
+// For standard terms that is DiscountDays after txnDate. For date-driven terms it is the
+// DiscountDayOfMonth of the month the transaction falls due in, which is the month after
+// txnDate's when txnDate is past DayOfMonthDue or within DueNextMonthDays of it.
+func (t *Term) DiscountDeadline(txnDate time.Time) (deadline time.Time, ok bool) {
+	if t.DiscountPercent == "" || numberFloat(t.DiscountPercent) == 0 {
+		return time.Time{}, false
+	}
 
-		if err := c.query(query, &resp); err != nil {
-			return nil, err
+	y, m, d := txnDate.Date()
+	switch {
+	case t.DiscountDays != nil:
+		return time.Date(y, m, d+*t.DiscountDays, 0, 0, 0, 0, txnDate.Location()), true
+	case t.DiscountDayOfMonth != nil && t.DayOfMonthDue != nil:
+		due := dayOfMonth(y, m, *t.DayOfMonthDue, txnDate.Location())
+		graceDays := 0
+		if t.DueNextMonthDays != nil {
+			graceDays = *t.DueNextMonthDays
 		}
-
-		if resp.QueryResponse.Terms == nil {
-			return nil, errors.New("no terms could be found")
+		if txnDate.AddDate(0, 0, graceDays).After(due) {
+			m++
 		}
-
-		terms = append(terms, resp.QueryResponse.Terms...)
+		return dayOfMonth(y, m, *t.DiscountDayOfMonth, txnDate.Location()), true
 	}
 
-	return terms, nil
+	return time.Time{}, false
 }
 
-// FindTermByID returns a term with a given Id.
-func (c *Client) FindTermByID(id string) (*Term, error) {
-	var resp struct {
-		Term Term
-		Time Date
+// dayOfMonth returns the given day of the month, or the last day of a month that is shorter.
+func dayOfMonth(year int, month time.Month, day int, loc *time.Location) time.Time {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, loc).Day()
+	return time.Date(year, month, min(day, last), 0, 0, 0, 0, loc)
+}
+
+// ordinal returns n with its English ordinal suffix, e.g. "1st", "22nd", "13th".
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
 	}
+	return strconv.Itoa(n) + suffix
+}
 
-	if err := c.get("term/"+id, &resp, nil); err != nil {
+// CreateTerm creates the given Term on the QuickBooks server, returning
+// the resulting Term object.
+func (c *Client) CreateTerm(input *TermCreateInput) (*Term, error) {
+	if err := input.Validate(); err != nil {
 		return nil, err
 	}
 
-	return &resp.Term, nil
+	return postSingle[Term](c, "term", input, nil)
 }
 
-// QueryTerms accepts an SQL query and returns all terms found using it.
-func (c *Client) QueryTerms(query string) ([]Term, error) {
-	var resp struct {
-		QueryResponse struct {
-			Terms         []Term `json:"Term"`
-			StartPosition int
-			MaxResults    int
-		}
+// DeleteTerm deletes the term.
+func (c *Client) DeleteTerm(term *Term) error {
+	if term.ID == "" || term.SyncToken == "" {
+		return errors.New("missing id/sync token")
 	}
 
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
+	return c.postRetryingStale("term", "term/"+term.ID, &term.SyncToken, term, nil, map[string]string{"operation": "delete"})
+}
+
+// FindTerms gets the full list of Terms in the QuickBooks account.
+func (c *Client) FindTerms() ([]Term, error) {
+	return findAllPages[Term](c, "Term", "no terms could be found")
+}
+
+// FindTermsWithOptions returns the terms matching opts, ordered by opts.OrderBy.
+// Pass nil for opts to list them all.
+func (c *Client) FindTermsWithOptions(opts *ListOptions) ([]Term, error) {
+	return findAllWithOptions[Term](c, "Term", true, opts)
+}
+
+// FindTermByID returns a term with a given Id.
+func (c *Client) FindTermByID(id string) (*Term, error) {
+	return getSingle[Term](c, "term/"+id, nil)
+}
+
+// QueryTerms accepts an SQL query and returns all terms found using it.
+func (c *Client) QueryTerms(query string) ([]Term, error) {
+	terms, err := queryEntities[Term](c, "Term", query)
+	if err != nil {
+		return terms, err
 	}
 
-	if resp.QueryResponse.Terms == nil {
+	if terms == nil {
 		return nil, errors.New("could not find any terms")
 	}
 
-	return resp.QueryResponse.Terms, nil
+	return terms, nil
 }
 
 // UpdateTerm updates the term.
@@ -155,14 +270,5 @@ func (c *Client) UpdateTerm(term *Term) (*Term, error) {
 		Sparse: true,
 	}
 
-	var termData struct {
-		Term Term
-		Time Date
-	}
-
-	if err = c.post("term", payload, &termData, nil); err != nil {
-		return nil, err
-	}
-
-	return &termData.Term, err
+	return updateSingle[Term](c, "term", "term/"+term.ID, &term.SyncToken, payload)
 }
@@ -0,0 +1,56 @@
+package quickbooks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTermValidate(t *testing.T) {
+	standard := TermTypeStandard
+	dateDriven := TermTypeDateDriven
+	days := func(n int) *int { return &n }
+
+	assert.NoError(t, (&Term{Type: &standard, DueDays: days(30)}).Validate())
+	assert.NoError(t, (&Term{DayOfMonthDue: days(15), DueNextMonthDays: days(5)}).Validate())
+
+	assert.Error(t, (&Term{Type: &standard, DueDays: days(30), DayOfMonthDue: days(15)}).Validate())
+	assert.Error(t, (&Term{Type: &dateDriven, DueDays: days(30)}).Validate())
+	assert.Error(t, (&Term{DueDays: days(30), DiscountDayOfMonth: days(1)}).Validate())
+	assert.Error(t, (&Term{Type: &dateDriven, DayOfMonthDue: days(32)}).Validate())
+	assert.Error(t, (&Term{}).Validate())
+}
+
+func TestTermDescribe(t *testing.T) {
+	days := func(n int) *int { return &n }
+
+	assert.Equal(t, "Net 30", (&Term{DueDays: days(30)}).Describe())
+	assert.Equal(t, "Due on receipt", (&Term{DueDays: days(0)}).Describe())
+	assert.Equal(t, "2% 10 Net 30", (&Term{DueDays: days(30), DiscountDays: days(10), DiscountPercent: "2"}).Describe())
+	assert.Equal(t, "Due on the 15th", (&Term{DayOfMonthDue: days(15)}).Describe())
+	assert.Equal(t, "Due on the 1st", (&Term{DayOfMonthDue: days(1)}).Describe())
+	assert.Equal(t, "Due on the 22nd", (&Term{DayOfMonthDue: days(22)}).Describe())
+	assert.Equal(t, "Custom", (&Term{Name: "Custom"}).Describe())
+}
+
+func TestTermDiscountDeadline(t *testing.T) {
+	days := func(n int) *int { return &n }
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	deadline, ok := (&Term{DueDays: days(30), DiscountDays: days(10), DiscountPercent: "2"}).DiscountDeadline(date(2024, 1, 25))
+	assert.True(t, ok)
+	assert.Equal(t, date(2024, 2, 4), deadline)
+
+	dateDriven := &Term{DayOfMonthDue: days(15), DueNextMonthDays: days(5), DiscountDayOfMonth: days(10), DiscountPercent: "1.5"}
+	deadline, _ = dateDriven.DiscountDeadline(date(2024, 1, 3))
+	assert.Equal(t, date(2024, 1, 10), deadline)
+	deadline, _ = dateDriven.DiscountDeadline(date(2024, 1, 12))
+	assert.Equal(t, date(2024, 2, 10), deadline)
+
+	deadline, _ = (&Term{DayOfMonthDue: days(31), DiscountDayOfMonth: days(31), DiscountPercent: "1"}).DiscountDeadline(date(2023, 2, 1))
+	assert.Equal(t, date(2023, 2, 28), deadline)
+
+	_, ok = (&Term{DueDays: days(30)}).DiscountDeadline(date(2024, 1, 25))
+	assert.False(t, ok)
+}
@@ -3,12 +3,13 @@ package quickbooks
 import (
 	"encoding/json"
 	"errors"
-	"strconv"
 )
 
 // TimeActivity represents a QuickBooks TimeActivity object as returned by the API.
 // Read-only fields (Id, SyncToken, MetaData) are populated by the service.
 type TimeActivity struct {
+	EntityMeta
+
 	ID             string         `json:"Id,omitempty"`
 	SyncToken      string         `json:",omitempty"`
 	MetaData       *MetaData      `json:",omitempty"`
@@ -55,21 +56,14 @@ type TimeActivityCreateInput struct {
 	BreakHours     *int           `json:",omitempty"`
 	BreakMinutes   *int           `json:",omitempty"`
 	Description    *string        `json:",omitempty"`
+	// Extra holds fields the struct does not model yet; see ExtraFields.
+	Extra ExtraFields `json:"-"`
 }
 
 // CreateTimeActivity creates the given TimeActivity on the QuickBooks server, returning
 // the resulting TimeActivity object.
 func (c *Client) CreateTimeActivity(input *TimeActivityCreateInput) (*TimeActivity, error) {
-	var resp struct {
-		TimeActivity TimeActivity
-		Time         Date
-	}
-
-	if err := c.post("timeactivity", input, &resp, nil); err != nil {
-		return nil, err
-	}
-
-	return &resp.TimeActivity, nil
+	return postSingle[TimeActivity](c, "timeactivity", input, nil)
 }
 
 // DeleteTimeActivity deletes the time activity.
@@ -78,80 +72,37 @@ func (c *Client) DeleteTimeActivity(timeActivity *TimeActivity) error {
 		return errors.New("missing id/sync token")
 	}
 
-	return c.post("timeactivity", timeActivity, nil, map[string]string{"operation": "delete"})
+	return c.postRetryingStale("timeactivity", "timeactivity/"+timeActivity.ID, &timeActivity.SyncToken, timeActivity, nil, map[string]string{"operation": "delete"})
 }
 
 // FindTimeActivities gets the full list of TimeActivities in the QuickBooks account.
 func (c *Client) FindTimeActivities() ([]TimeActivity, error) {
-	var resp struct {
-		QueryResponse struct {
-			TimeActivities []TimeActivity `json:"TimeActivity"`
-			MaxResults     int
-			StartPosition  int
-			TotalCount     int
-		}
-	}
-
-	if err := c.query("SELECT COUNT(*) FROM TimeActivity", &resp); err != nil {
-		return nil, err
-	}
-
-	if resp.QueryResponse.TotalCount == 0 {
-		return nil, errors.New("no time activities could be found")
-	}
-
-	timeActivities := make([]TimeActivity, 0, resp.QueryResponse.TotalCount)
-
-	for i := 0; i < resp.QueryResponse.TotalCount; i += queryPageSize {
-		query := "SELECT * FROM TimeActivity ORDERBY Id STARTPOSITION " + strconv.Itoa(i+1) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
-
-		if err := c.query(query, &resp); err != nil {
-			return nil, err
-		}
-
-		if resp.QueryResponse.TimeActivities == nil {
-			return nil, errors.New("no time activities could be found")
-		}
-
-		timeActivities = append(timeActivities, resp.QueryResponse.TimeActivities...)
-	}
+	return findAllPages[TimeActivity](c, "TimeActivity", "no time activities could be found")
+}
 
-	return timeActivities, nil
+// FindTimeActivitiesWithOptions returns the time activities matching opts, ordered by opts.OrderBy.
+// Pass nil for opts to list them all.
+func (c *Client) FindTimeActivitiesWithOptions(opts *ListOptions) ([]TimeActivity, error) {
+	return findAllWithOptions[TimeActivity](c, "TimeActivity", false, opts)
 }
 
 // FindTimeActivityByID returns a time activity with a given Id.
 func (c *Client) FindTimeActivityByID(id string) (*TimeActivity, error) {
-	var resp struct {
-		TimeActivity TimeActivity
-		Time         Date
-	}
-
-	if err := c.get("timeactivity/"+id, &resp, nil); err != nil {
-		return nil, err
-	}
-
-	return &resp.TimeActivity, nil
+	return getSingle[TimeActivity](c, "timeactivity/"+id, nil)
 }
 
 // QueryTimeActivities accepts an SQL query and returns all time activities found using it.
 func (c *Client) QueryTimeActivities(query string) ([]TimeActivity, error) {
-	var resp struct {
-		QueryResponse struct {
-			TimeActivities []TimeActivity `json:"TimeActivity"`
-			StartPosition  int
-			MaxResults     int
-		}
-	}
-
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
+	timeActivities, err := queryEntities[TimeActivity](c, "TimeActivity", query)
+	if err != nil {
+		return timeActivities, err
 	}
 
-	if resp.QueryResponse.TimeActivities == nil {
+	if timeActivities == nil {
 		return nil, errors.New("could not find any time activities")
 	}
 
-	return resp.QueryResponse.TimeActivities, nil
+	return timeActivities, nil
 }
 
 // UpdateTimeActivity updates the time activity.
@@ -175,14 +126,5 @@ func (c *Client) UpdateTimeActivity(timeActivity *TimeActivity) (*TimeActivity,
 		Sparse:       true,
 	}
 
-	var timeActivityData struct {
-		TimeActivity TimeActivity
-		Time         Date
-	}
-
-	if err = c.post("timeactivity", payload, &timeActivityData, nil); err != nil {
-		return nil, err
-	}
-
-	return &timeActivityData.TimeActivity, err
+	return updateSingle[TimeActivity](c, "timeactivity", "timeactivity/"+timeActivity.ID, &timeActivity.SyncToken, payload)
 }
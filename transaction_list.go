@@ -0,0 +1,163 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// TransactionListQueryParams holds the optional query parameters for the TransactionList report.
+type TransactionListQueryParams struct {
+	// Cash or Accrual
+	AccountingMethod *string
+	StartDate        *string
+	EndDate          *string
+	// Predefined date range, e.g. "This Month", "Last Fiscal Year". Ignored when StartDate/EndDate are set.
+	DateMacro *string
+	// Comma separated list of account ids the transactions must post to.
+	SourceAccount *string
+	// Comma separated lists of ids to filter on.
+	Customer   *string
+	Vendor     *string
+	Item       *string
+	Class      *string
+	Department *string
+	// Comma separated list of column keys, e.g. "tx_date,txn_type,doc_num,subt_nat_amount".
+	Columns *string
+	// Column key to sort by, e.g. "tx_date".
+	SortBy *string
+	// ascend or descend
+	SortOrder *string
+}
+
+func (p *TransactionListQueryParams) toMap() map[string]string {
+	m := map[string]string{}
+	if p.AccountingMethod != nil {
+		m["accounting_method"] = *p.AccountingMethod
+	}
+	if p.StartDate != nil {
+		m["start_date"] = *p.StartDate
+	}
+	if p.EndDate != nil {
+		m["end_date"] = *p.EndDate
+	}
+	if p.DateMacro != nil {
+		m["date_macro"] = *p.DateMacro
+	}
+	if p.SourceAccount != nil {
+		m["source_account"] = *p.SourceAccount
+	}
+	if p.Customer != nil {
+		m["customer"] = *p.Customer
+	}
+	if p.Vendor != nil {
+		m["vendor"] = *p.Vendor
+	}
+	if p.Item != nil {
+		m["item"] = *p.Item
+	}
+	if p.Class != nil {
+		m["class"] = *p.Class
+	}
+	if p.Department != nil {
+		m["department"] = *p.Department
+	}
+	if p.Columns != nil {
+		m["columns"] = *p.Columns
+	}
+	if p.SortBy != nil {
+		m["sort_by"] = *p.SortBy
+	}
+	if p.SortOrder != nil {
+		m["sort_order"] = *p.SortOrder
+	}
+	return m
+}
+
+// GetTransactionList fetches a TransactionList report from the QBO API.
+// Pass nil for params to use the API defaults.
+func (c *Client) GetTransactionList(params *TransactionListQueryParams) (*Report, error) {
+	var queryParams map[string]string
+	if params != nil {
+		queryParams = params.toMap()
+	}
+	return c.getReport("TransactionList", queryParams)
+}
+
+// RegisterEntry is a single line of an account register.
+type RegisterEntry struct {
+	TxnDate string
+	TxnType string
+	TxnID   string
+	DocNum  string
+	Name    string
+	Memo    string
+	Amount  json.Number
+	// Balance is the running total of Amount over the entries returned, starting from zero
+	// at the beginning of the requested range (it does not include the opening balance).
+	Balance json.Number
+}
+
+// registerColumns are the TransactionList columns GetTransactionListByAccount asks for.
+const registerColumns = "tx_date,txn_type,doc_num,name,memo,subt_nat_amount"
+
+// GetTransactionListByAccount returns the register entries posted to the given account between
+// from and to (both inclusive, compared by date only), ordered by transaction date, with a running balance.
+func (c *Client) GetTransactionListByAccount(accountID string, from, to time.Time) ([]RegisterEntry, error) {
+	if accountID == "" {
+		return nil, errors.New("missing account id")
+	}
+
+	if to.Before(from) {
+		return nil, errors.New("end of range is before its start")
+	}
+
+	startDate := from.Format(secondFormat)
+	endDate := to.Format(secondFormat)
+	columns := registerColumns
+	sortBy := "tx_date"
+	sortOrder := "ascend"
+
+	report, err := c.GetTransactionList(&TransactionListQueryParams{
+		StartDate:     &startDate,
+		EndDate:       &endDate,
+		SourceAccount: &accountID,
+		Columns:       &columns,
+		SortBy:        &sortBy,
+		SortOrder:     &sortOrder,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dateIdx := report.ColumnIndex("tx_date")
+	typeIdx := report.ColumnIndex("txn_type")
+	docIdx := report.ColumnIndex("doc_num")
+	nameIdx := report.ColumnIndex("name")
+	memoIdx := report.ColumnIndex("memo")
+	amountIdx := report.ColumnIndex("subt_nat_amount")
+
+	var entries []RegisterEntry
+	balance := json.Number("0")
+
+	for _, row := range report.DataRows() {
+		entry := RegisterEntry{
+			TxnDate: row.cell(dateIdx).Value,
+			TxnType: row.cell(typeIdx).Value,
+			TxnID:   row.cell(typeIdx).ID,
+			DocNum:  row.cell(docIdx).Value,
+			Name:    row.cell(nameIdx).Value,
+			Memo:    row.cell(memoIdx).Value,
+			Amount:  json.Number(row.cell(amountIdx).Value),
+		}
+
+		if balance, err = sumAmounts(balance, entry.Amount); err != nil {
+			return nil, err
+		}
+		entry.Balance = balance
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
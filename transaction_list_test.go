@@ -0,0 +1,154 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTransactionListByAccount(t *testing.T) {
+	const response = `{
+  "Header": {
+    "ReportName": "TransactionList",
+    "StartPeriod": "2016-03-01",
+    "EndPeriod": "2016-03-31",
+    "Currency": "USD",
+    "Time": "2016-03-31T10:11:07-07:00"
+  },
+  "Columns": {
+    "Column": [
+      {"ColType": "Date", "ColTitle": "Date", "MetaData": [{"Name": "ColKey", "Value": "tx_date"}]},
+      {"ColType": "String", "ColTitle": "Transaction Type", "MetaData": [{"Name": "ColKey", "Value": "txn_type"}]},
+      {"ColType": "String", "ColTitle": "Num", "MetaData": [{"Name": "ColKey", "Value": "doc_num"}]},
+      {"ColType": "String", "ColTitle": "Name", "MetaData": [{"Name": "ColKey", "Value": "name"}]},
+      {"ColType": "String", "ColTitle": "Memo/Description", "MetaData": [{"Name": "ColKey", "Value": "memo"}]},
+      {"ColType": "Money", "ColTitle": "Amount", "MetaData": [{"Name": "ColKey", "Value": "subt_nat_amount"}]}
+    ]
+  },
+  "Rows": {
+    "Row": [
+      {
+        "ColData": [
+          {"value": "2016-03-01"},
+          {"value": "Deposit", "id": "145"},
+          {"value": ""},
+          {"value": ""},
+          {"value": "Opening deposit"},
+          {"value": "1000.00"}
+        ],
+        "type": "Data"
+      },
+      {
+        "ColData": [
+          {"value": "2016-03-05"},
+          {"value": "Check", "id": "146"},
+          {"value": "1001"},
+          {"value": "Hicks Hardware", "id": "41"},
+          {"value": ""},
+          {"value": "-250.35"}
+        ],
+        "type": "Data"
+      }
+    ]
+  }
+}`
+
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v3/company/test-realm/reports/TransactionList", r.URL.Path)
+
+		q := r.URL.Query()
+		assert.Equal(t, "35", q.Get("source_account"))
+		assert.Equal(t, "2016-03-01", q.Get("start_date"))
+		assert.Equal(t, "2016-03-31", q.Get("end_date"))
+		assert.Equal(t, "tx_date", q.Get("sort_by"))
+		assert.Equal(t, registerColumns, q.Get("columns"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(response))
+	})
+
+	// Times late in the day must not push the boundaries onto the next date.
+	from := time.Date(2016, 3, 1, 23, 59, 0, 0, time.UTC)
+	to := time.Date(2016, 3, 31, 23, 59, 0, 0, time.UTC)
+
+	entries, err := client.GetTransactionListByAccount("35", from, to)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, "2016-03-01", entries[0].TxnDate)
+	assert.Equal(t, "Deposit", entries[0].TxnType)
+	assert.Equal(t, "145", entries[0].TxnID)
+	assert.Equal(t, json.Number("1000.00"), entries[0].Amount)
+	assert.Equal(t, json.Number("1000.00"), entries[0].Balance)
+
+	assert.Equal(t, "146", entries[1].TxnID)
+	assert.Equal(t, "1001", entries[1].DocNum)
+	assert.Equal(t, "Hicks Hardware", entries[1].Name)
+	assert.Equal(t, json.Number("-250.35"), entries[1].Amount)
+	assert.Equal(t, json.Number("749.65"), entries[1].Balance)
+}
+
+func TestGetTransactionListByAccountInvalidRange(t *testing.T) {
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL.Path)
+	})
+
+	_, err := client.GetTransactionListByAccount("35", time.Now(), time.Now().AddDate(0, 0, -1))
+	assert.Error(t, err)
+}
+
+func TestStreamTransactionList(t *testing.T) {
+	var windows []string
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v3/company/test-realm/reports/TransactionList", r.URL.Path)
+
+		q := r.URL.Query()
+		assert.Empty(t, q.Get("date_macro"))
+		windows = append(windows, q.Get("start_date")+"/"+q.Get("end_date"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+  "Header": {"ReportName": "TransactionList", "StartPeriod": "` + q.Get("start_date") + `"},
+  "Columns": {"Column": [{"ColType": "Date", "MetaData": [{"Name": "ColKey", "Value": "tx_date"}]}]},
+  "Rows": {"Row": [
+    {"type": "Data", "ColData": [{"value": "` + q.Get("start_date") + `"}]},
+    {"type": "Data", "ColData": [{"value": "` + q.Get("end_date") + `"}]}
+  ]}
+}`))
+	})
+
+	macro := "This Fiscal Year"
+	var dates []string
+	err := client.StreamTransactionList(&TransactionListQueryParams{DateMacro: &macro},
+		time.Date(2016, 1, 15, 0, 0, 0, 0, time.UTC), time.Date(2016, 3, 10, 0, 0, 0, 0, time.UTC),
+		func(report *Report, row ReportRow) error {
+			assert.Equal(t, 0, report.ColumnIndex("tx_date"))
+			dates = append(dates, row.ColData[0].Value)
+			return nil
+		})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"2016-01-15/2016-01-31", "2016-02-01/2016-02-29", "2016-03-01/2016-03-10"}, windows)
+	assert.Equal(t, []string{"2016-01-15", "2016-01-31", "2016-02-01", "2016-02-29", "2016-03-01", "2016-03-10"}, dates)
+}
+
+func TestStreamTransactionListStopsOnError(t *testing.T) {
+	requests := 0
+	client, _ := NewTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Rows": {"Row": [{"type": "Data", "ColData": [{"value": "x"}]}]}}`))
+	})
+
+	stop := errors.New("stop")
+	err := client.StreamTransactionList(nil,
+		time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2016, 12, 31, 0, 0, 0, 0, time.UTC),
+		func(*Report, ReportRow) error { return stop })
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, requests)
+}
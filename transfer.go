@@ -3,12 +3,13 @@ package quickbooks
 import (
 	"encoding/json"
 	"errors"
-	"strconv"
 )
 
 // Transfer represents a QuickBooks Transfer object as returned by the API.
 // Read-only fields (Id, SyncToken, MetaData, TotalAmt) are populated by the service.
 type Transfer struct {
+	EntityMeta
+
 	ID             string         `json:"Id,omitempty"`
 	SyncToken      string         `json:",omitempty"`
 	MetaData       *MetaData      `json:",omitempty"`
@@ -34,21 +35,14 @@ type TransferCreateInput struct {
 	TxnTaxDetail   *TxnTaxDetail  `json:",omitempty"`
 	CurrencyRef    *ReferenceType `json:",omitempty"`
 	ExchangeRate   json.Number    `json:",omitempty"`
+	// Extra holds fields the struct does not model yet; see ExtraFields.
+	Extra ExtraFields `json:"-"`
 }
 
 // CreateTransfer creates the given Transfer on the QuickBooks server, returning
 // the resulting Transfer object.
 func (c *Client) CreateTransfer(input *TransferCreateInput) (*Transfer, error) {
-	var resp struct {
-		Transfer Transfer
-		Time     Date
-	}
-
-	if err := c.post("transfer", input, &resp, nil); err != nil {
-		return nil, err
-	}
-
-	return &resp.Transfer, nil
+	return postSingle[Transfer](c, "transfer", input, nil)
 }
 
 // DeleteTransfer deletes the transfer.
@@ -57,80 +51,37 @@ func (c *Client) DeleteTransfer(transfer *Transfer) error {
 		return errors.New("missing id/sync token")
 	}
 
-	return c.post("transfer", transfer, nil, map[string]string{"operation": "delete"})
+	return c.postRetryingStale("transfer", "transfer/"+transfer.ID, &transfer.SyncToken, transfer, nil, map[string]string{"operation": "delete"})
 }
 
 // FindTransfers gets the full list of Transfers in the QuickBooks account.
 func (c *Client) FindTransfers() ([]Transfer, error) {
-	var resp struct {
-		QueryResponse struct {
-			Transfers     []Transfer `json:"Transfer"`
-			MaxResults    int
-			StartPosition int
-			TotalCount    int
-		}
-	}
-
-	if err := c.query("SELECT COUNT(*) FROM Transfer", &resp); err != nil {
-		return nil, err
-	}
-
-	if resp.QueryResponse.TotalCount == 0 {
-		return nil, errors.New("no transfers could be found")
-	}
-
-	transfers := make([]Transfer, 0, resp.QueryResponse.TotalCount)
-
-	for i := 0; i < resp.QueryResponse.TotalCount; i += queryPageSize {
-		query := "SELECT * FROM Transfer ORDERBY Id STARTPOSITION " + strconv.Itoa(i+1) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
-
-		if err := c.query(query, &resp); err != nil {
-			return nil, err
-		}
-
-		if resp.QueryResponse.Transfers == nil {
-			return nil, errors.New("no transfers could be found")
-		}
-
-		transfers = append(transfers, resp.QueryResponse.Transfers...)
-	}
+	return findAllPages[Transfer](c, "Transfer", "no transfers could be found")
+}
 
-	return transfers, nil
+// FindTransfersWithOptions returns the transfers matching opts, ordered by opts.OrderBy.
+// Pass nil for opts to list them all.
+func (c *Client) FindTransfersWithOptions(opts *ListOptions) ([]Transfer, error) {
+	return findAllWithOptions[Transfer](c, "Transfer", false, opts)
 }
 
 // FindTransferByID finds the transfer by the given id.
 func (c *Client) FindTransferByID(id string) (*Transfer, error) {
-	var resp struct {
-		Transfer Transfer
-		Time     Date
-	}
-
-	if err := c.get("transfer/"+id, &resp, nil); err != nil {
-		return nil, err
-	}
-
-	return &resp.Transfer, nil
+	return getSingle[Transfer](c, "transfer/"+id, nil)
 }
 
 // QueryTransfers accepts an SQL query and returns all transfers found using it.
 func (c *Client) QueryTransfers(query string) ([]Transfer, error) {
-	var resp struct {
-		QueryResponse struct {
-			Transfers     []Transfer `json:"Transfer"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
+	transfers, err := queryEntities[Transfer](c, "Transfer", query)
+	if err != nil {
+		return transfers, err
 	}
 
-	if resp.QueryResponse.Transfers == nil {
+	if transfers == nil {
 		return nil, errors.New("could not find any transfers")
 	}
 
-	return resp.QueryResponse.Transfers, nil
+	return transfers, nil
 }
 
 // UpdateTransfer updates the transfer.
@@ -154,14 +105,5 @@ func (c *Client) UpdateTransfer(transfer *Transfer) (*Transfer, error) {
 		Sparse:   true,
 	}
 
-	var transferData struct {
-		Transfer Transfer
-		Time     Date
-	}
-
-	if err = c.post("transfer", payload, &transferData, nil); err != nil {
-		return nil, err
-	}
-
-	return &transferData.Transfer, err
+	return updateSingle[Transfer](c, "transfer", "transfer/"+transfer.ID, &transfer.SyncToken, payload)
 }
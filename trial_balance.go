@@ -2,19 +2,11 @@ package quickbooks
 
 import (
 	"encoding/json"
-	"time"
 )
 
-type TrialBalanceHeader struct {
-	ReportName  string
-	Option      []NameValue
-	DateMacro   string
-	ReportBasis string
-	StartPeriod string
-	Currency    string
-	EndPeriod   string
-	Time        time.Time
-}
+// TrialBalanceHeader is the header of the TrialBalance report, which has the same fields as
+// every other report.
+type TrialBalanceHeader = ReportHeader
 
 type TrialBalanceColumn struct {
 	ColType  string
@@ -25,11 +17,32 @@ type TrialBalanceValueRow struct {
 	Value string `json:"value"`
 }
 
+func (v *TrialBalanceValueRow) UnmarshalJSON(data []byte) error {
+	var cd ReportColData
+	if err := json.Unmarshal(data, &cd); err != nil {
+		return err
+	}
+
+	v.Value = cd.Value
+	return nil
+}
+
 type TrialBalanceRowHeader struct {
 	ID    string `json:"id"`
 	Value string `json:"value"`
 }
 
+func (h *TrialBalanceRowHeader) UnmarshalJSON(data []byte) error {
+	var cd ReportColData
+	if err := json.Unmarshal(data, &cd); err != nil {
+		return err
+	}
+
+	h.ID = cd.ID
+	h.Value = cd.Value
+	return nil
+}
+
 type TrialBalanceRow struct {
 	RowHeader TrialBalanceRowHeader
 	Values    []TrialBalanceValueRow
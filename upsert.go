@@ -0,0 +1,84 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// duplicateNameCode is the fault code QuickBooks returns when a name-unique object such as
+// an item or account is created with a name that is already taken.
+const duplicateNameCode = "6240"
+
+// isDuplicateName reports whether err is a duplicate name fault.
+func isDuplicateName(err error) bool {
+	var failure Failure
+	if !errors.As(err, &failure) {
+		return false
+	}
+
+	for _, fe := range failure.Fault.Error {
+		if fe.Code == duplicateNameCode {
+			return true
+		}
+	}
+	return false
+}
+
+// inputFields returns the fields input marshals to, for use as a sparse update. Fields the
+// input leaves empty are omitted, so the update only touches what the caller set.
+func inputFields(input any) (map[string]any, error) {
+	b, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]json.RawMessage
+	if err = json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]any, len(raw))
+	for key, value := range raw {
+		fields[key] = value
+	}
+	return fields, nil
+}
+
+// upsert sparse-updates the object whose Id find returns with the fields of input, or
+// creates it when find returns "". If the create fails because a concurrent writer took the
+// name first, the object is looked up again and updated instead. The bool reports whether
+// the object was created.
+func upsert[T any](c *Client, endpoint string, input any, find func() (string, error), create func() (*T, error)) (*T, bool, error) {
+	fields, err := inputFields(input)
+	if err != nil {
+		return nil, false, err
+	}
+
+	id, err := find()
+	if err != nil {
+		return nil, false, err
+	}
+
+	if id == "" {
+		created, err := create()
+		if err == nil {
+			return created, true, nil
+		}
+		if !isDuplicateName(err) {
+			return nil, false, err
+		}
+
+		if id, err = find(); err != nil {
+			return nil, false, err
+		}
+		if id == "" {
+			return nil, false, errors.New("duplicate name reported but no matching object was found")
+		}
+	}
+
+	updated, err := updateFields[T](c, endpoint, id, fields)
+	if err != nil {
+		return nil, false, err
+	}
+	return updated, false, nil
+}
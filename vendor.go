@@ -3,13 +3,13 @@ package quickbooks
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
-	"strconv"
 )
 
 // Vendor represents a QuickBooks Vendor object as returned by the API.
 // Read-only fields (Id, SyncToken, MetaData, Balance) are populated by the service.
 type Vendor struct {
+	EntityMeta
+
 	ID               string           `json:"Id,omitempty"`
 	SyncToken        string           `json:",omitempty"`
 	MetaData         *MetaData        `json:",omitempty"`
@@ -44,6 +44,16 @@ type Vendor struct {
 	Balance          json.Number      `json:",omitempty"`
 }
 
+// IsActive reports whether the vendor is active, treating a missing Active field as active.
+func (v *Vendor) IsActive() bool {
+	return boolValue(v.Active, true)
+}
+
+// BalanceFloat returns Balance as a float64, or 0 if it is not set.
+func (v *Vendor) BalanceFloat() float64 {
+	return numberFloat(v.Balance)
+}
+
 // VendorCreateInput contains the writable fields accepted when creating a Vendor.
 // At least one of GivenName, FamilyName, DisplayName, or CompanyName is required.
 type VendorCreateInput struct {
@@ -75,132 +85,56 @@ type VendorCreateInput struct {
 	HasTPAR             *bool            `json:",omitempty"`
 	Vendor1099          *bool            `json:",omitempty"`
 	BillRate            json.Number      `json:",omitempty"`
+	// Extra holds fields the struct does not model yet; see ExtraFields.
+	Extra ExtraFields `json:"-"`
 }
 
 // CreateVendor creates the given Vendor on the QuickBooks server, returning
 // the resulting Vendor object.
 func (c *Client) CreateVendor(input *VendorCreateInput) (*Vendor, error) {
-	var resp struct {
-		Vendor Vendor
-		Time   Date
-	}
-
-	if err := c.post("vendor", input, &resp, nil); err != nil {
-		return nil, err
-	}
-
-	return &resp.Vendor, nil
+	return postSingle[Vendor](c, "vendor", input, nil)
 }
 
 // FindVendors gets the full list of Vendors in the QuickBooks account.
 func (c *Client) FindVendors() ([]Vendor, error) {
-	var resp struct {
-		QueryResponse struct {
-			Vendors       []Vendor `json:"Vendor"`
-			MaxResults    int
-			StartPosition int
-			TotalCount    int
-		}
-	}
-
-	if err := c.query("SELECT COUNT(*) FROM Vendor", &resp); err != nil {
-		return nil, err
-	}
-
-	if resp.QueryResponse.TotalCount == 0 {
-		return nil, errors.New("no vendors could be found")
-	}
-
-	vendors := make([]Vendor, 0, resp.QueryResponse.TotalCount)
-
-	for i := 0; i < resp.QueryResponse.TotalCount; i += queryPageSize {
-		query := "SELECT * FROM Vendor ORDERBY Id STARTPOSITION " + strconv.Itoa(i+1) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
-
-		if err := c.query(query, &resp); err != nil {
-			return nil, err
-		}
-
-		if resp.QueryResponse.Vendors == nil {
-			return nil, errors.New("no vendors could be found")
-		}
-
-		vendors = append(vendors, resp.QueryResponse.Vendors...)
-	}
+	return findAllPages[Vendor](c, "Vendor", "no vendors could be found")
+}
 
-	return vendors, nil
+// FindVendorsWithOptions returns the vendors matching opts, ordered by opts.OrderBy.
+// Pass nil for opts to list them all.
+func (c *Client) FindVendorsWithOptions(opts *ListOptions) ([]Vendor, error) {
+	return findAllWithOptions[Vendor](c, "Vendor", true, opts)
 }
 
 // FindVendorByID finds the vendor by the given id
 func (c *Client) FindVendorByID(id string) (*Vendor, error) {
-	var resp struct {
-		Vendor Vendor
-		Time   Date
-	}
-
-	if err := c.get("vendor/"+id, &resp, nil); err != nil {
-		return nil, err
-	}
+	return getSingle[Vendor](c, "vendor/"+id, nil)
+}
 
-	return &resp.Vendor, nil
+// FindVendorsByIDs returns the vendors with the given Ids, inactive ones included. Ids that match nothing are skipped.
+func (c *Client) FindVendorsByIDs(ids []string) ([]Vendor, error) {
+	return findByIDs[Vendor](c, "Vendor", true, ids)
 }
 
 // QueryVendors accepts an SQL query and returns all vendors found using it
 func (c *Client) QueryVendors(query string) ([]Vendor, error) {
-	var resp struct {
-		QueryResponse struct {
-			Vendors       []Vendor `json:"Vendor"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
+	vendors, err := queryEntities[Vendor](c, "Vendor", query)
+	if err != nil {
+		return vendors, err
 	}
 
-	if resp.QueryResponse.Vendors == nil {
+	if vendors == nil {
 		return nil, errors.New("could not find any vendors")
 	}
 
-	return resp.QueryResponse.Vendors, nil
+	return vendors, nil
 }
 
 // ListVendors returns one page of Vendors ordered by Id.
 // Pass an empty pageToken to start from the beginning.
 // The returned nextPageToken is empty when there are no more results.
 func (c *Client) ListVendors(pageToken string, pageSize int) (*ListResponse[Vendor], error) {
-	if pageSize <= 0 || pageSize > queryPageSize {
-		pageSize = queryPageSize
-	}
-
-	startPosition := 1
-	if pageToken != "" {
-		var err error
-		startPosition, err = strconv.Atoi(pageToken)
-		if err != nil {
-			return nil, fmt.Errorf("invalid page token: %v", err)
-		}
-	}
-
-	var resp struct {
-		QueryResponse struct {
-			Vendors       []Vendor `json:"Vendor"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	query := "SELECT * FROM Vendor ORDERBY Id STARTPOSITION " + strconv.Itoa(startPosition) + " MAXRESULTS " + strconv.Itoa(pageSize)
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
-	}
-
-	result := &ListResponse[Vendor]{Items: resp.QueryResponse.Vendors}
-	if len(result.Items) == pageSize {
-		result.NextPageToken = strconv.Itoa(startPosition + pageSize)
-	}
-
-	return result, nil
+	return listPage[Vendor](c, "Vendor", pageToken, pageSize)
 }
 
 // DeleteVendor deletes the vendor.
@@ -209,7 +143,7 @@ func (c *Client) DeleteVendor(vendor *Vendor) error {
 		return errors.New("missing id/sync token")
 	}
 
-	return c.post("vendor", vendor, nil, map[string]string{"operation": "delete"})
+	return c.postRetryingStale("vendor", "vendor/"+vendor.ID, &vendor.SyncToken, vendor, nil, map[string]string{"operation": "delete"})
 }
 
 // UpdateVendor updates the vendor
@@ -233,14 +167,5 @@ func (c *Client) UpdateVendor(vendor *Vendor) (*Vendor, error) {
 		Sparse: true,
 	}
 
-	var vendorData struct {
-		Vendor Vendor
-		Time   Date
-	}
-
-	if err = c.post("vendor", payload, &vendorData, nil); err != nil {
-		return nil, err
-	}
-
-	return &vendorData.Vendor, err
+	return updateSingle[Vendor](c, "vendor", "vendor/"+vendor.ID, &vendor.SyncToken, payload)
 }
@@ -3,12 +3,13 @@ package quickbooks
 import (
 	"encoding/json"
 	"errors"
-	"strconv"
 )
 
 // VendorCredit represents a QuickBooks VendorCredit object as returned by the API.
 // Read-only fields (Id, SyncToken, MetaData, TotalAmt, Balance) are populated by the service.
 type VendorCredit struct {
+	EntityMeta
+
 	ID                  string         `json:"Id,omitempty"`
 	SyncToken           string         `json:",omitempty"`
 	MetaData            *MetaData      `json:",omitempty"`
@@ -40,21 +41,18 @@ type VendorCreditCreateInput struct {
 	ExchangeRate        json.Number    `json:",omitempty"`
 	DepartmentRef       *ReferenceType `json:",omitempty"`
 	IncludeInAnnualTPAR *bool          `json:",omitempty"`
+	// Extra holds fields the struct does not model yet; see ExtraFields.
+	Extra ExtraFields `json:"-"`
 }
 
 // CreateVendorCredit creates the given VendorCredit on the QuickBooks server, returning
 // the resulting VendorCredit object.
 func (c *Client) CreateVendorCredit(input *VendorCreditCreateInput) (*VendorCredit, error) {
-	var resp struct {
-		VendorCredit VendorCredit
-		Time         Date
-	}
-
-	if err := c.post("vendorcredit", input, &resp, nil); err != nil {
+	if err := ValidateExpenseLines(input.Line); err != nil {
 		return nil, err
 	}
 
-	return &resp.VendorCredit, nil
+	return postSingle[VendorCredit](c, "vendorcredit", input, nil)
 }
 
 // DeleteVendorCredit deletes the vendor credit.
@@ -63,80 +61,37 @@ func (c *Client) DeleteVendorCredit(vendorCredit *VendorCredit) error {
 		return errors.New("missing id/sync token")
 	}
 
-	return c.post("vendorcredit", vendorCredit, nil, map[string]string{"operation": "delete"})
+	return c.postRetryingStale("vendorcredit", "vendorcredit/"+vendorCredit.ID, &vendorCredit.SyncToken, vendorCredit, nil, map[string]string{"operation": "delete"})
 }
 
 // FindVendorCredits gets the full list of VendorCredits in the QuickBooks account.
 func (c *Client) FindVendorCredits() ([]VendorCredit, error) {
-	var resp struct {
-		QueryResponse struct {
-			VendorCredits []VendorCredit `json:"VendorCredit"`
-			MaxResults    int
-			StartPosition int
-			TotalCount    int
-		}
-	}
-
-	if err := c.query("SELECT COUNT(*) FROM VendorCredit", &resp); err != nil {
-		return nil, err
-	}
-
-	if resp.QueryResponse.TotalCount == 0 {
-		return nil, errors.New("no vendor credits could be found")
-	}
-
-	vendorCredits := make([]VendorCredit, 0, resp.QueryResponse.TotalCount)
-
-	for i := 0; i < resp.QueryResponse.TotalCount; i += queryPageSize {
-		query := "SELECT * FROM VendorCredit ORDERBY Id STARTPOSITION " + strconv.Itoa(i+1) + " MAXRESULTS " + strconv.Itoa(queryPageSize)
-
-		if err := c.query(query, &resp); err != nil {
-			return nil, err
-		}
-
-		if resp.QueryResponse.VendorCredits == nil {
-			return nil, errors.New("no vendor credits could be found")
-		}
-
-		vendorCredits = append(vendorCredits, resp.QueryResponse.VendorCredits...)
-	}
+	return findAllPages[VendorCredit](c, "VendorCredit", "no vendor credits could be found")
+}
 
-	return vendorCredits, nil
+// FindVendorCreditsWithOptions returns the vendor credits matching opts, ordered by opts.OrderBy.
+// Pass nil for opts to list them all.
+func (c *Client) FindVendorCreditsWithOptions(opts *ListOptions) ([]VendorCredit, error) {
+	return findAllWithOptions[VendorCredit](c, "VendorCredit", false, opts)
 }
 
 // FindVendorCreditByID finds the vendor credit by the given id.
 func (c *Client) FindVendorCreditByID(id string) (*VendorCredit, error) {
-	var resp struct {
-		VendorCredit VendorCredit
-		Time         Date
-	}
-
-	if err := c.get("vendorcredit/"+id, &resp, nil); err != nil {
-		return nil, err
-	}
-
-	return &resp.VendorCredit, nil
+	return getSingle[VendorCredit](c, "vendorcredit/"+id, nil)
 }
 
 // QueryVendorCredits accepts an SQL query and returns all vendor credits found using it.
 func (c *Client) QueryVendorCredits(query string) ([]VendorCredit, error) {
-	var resp struct {
-		QueryResponse struct {
-			VendorCredits []VendorCredit `json:"VendorCredit"`
-			StartPosition int
-			MaxResults    int
-		}
-	}
-
-	if err := c.query(query, &resp); err != nil {
-		return nil, err
+	vendorCredits, err := queryEntities[VendorCredit](c, "VendorCredit", query)
+	if err != nil {
+		return vendorCredits, err
 	}
 
-	if resp.QueryResponse.VendorCredits == nil {
+	if vendorCredits == nil {
 		return nil, errors.New("could not find any vendor credits")
 	}
 
-	return resp.QueryResponse.VendorCredits, nil
+	return vendorCredits, nil
 }
 
 // UpdateVendorCredit updates the vendor credit.
@@ -160,14 +115,5 @@ func (c *Client) UpdateVendorCredit(vendorCredit *VendorCredit) (*VendorCredit,
 		Sparse:       true,
 	}
 
-	var vendorCreditData struct {
-		VendorCredit VendorCredit
-		Time         Date
-	}
-
-	if err = c.post("vendorcredit", payload, &vendorCreditData, nil); err != nil {
-		return nil, err
-	}
-
-	return &vendorCreditData.VendorCredit, err
+	return updateSingle[VendorCredit](c, "vendorcredit", "vendorcredit/"+vendorCredit.ID, &vendorCredit.SyncToken, payload)
 }
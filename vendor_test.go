@@ -80,3 +80,14 @@ func TestVendor(t *testing.T) {
 	assert.Equal(t, "2014-09-12T10:07:56-07:00", resp.Vendor.MetaData.CreateTime.String())
 	assert.Equal(t, "2014-09-17T11:13:46-07:00", resp.Vendor.MetaData.LastUpdatedTime.String())
 }
+
+func TestVendorAccessors(t *testing.T) {
+	var vendor Vendor
+	assert.True(t, vendor.IsActive())
+	assert.Equal(t, 0.0, vendor.BalanceFloat())
+
+	inactive := false
+	vendor = Vendor{Active: &inactive, Balance: "1250.75"}
+	assert.False(t, vendor.IsActive())
+	assert.Equal(t, 1250.75, vendor.BalanceFloat())
+}
@@ -0,0 +1,39 @@
+package quickbooks
+
+import (
+	"reflect"
+	"time"
+)
+
+var metaDataType = reflect.TypeOf(&MetaData{})
+
+// LastUpdatedTime returns MetaData.LastUpdatedTime of obj, any of the entity structs or a
+// pointer to one. ok is false if obj has no MetaData or QuickBooks did not return it.
+func LastUpdatedTime(obj any) (t time.Time, ok bool) {
+	v := reflect.ValueOf(obj)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return time.Time{}, false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return time.Time{}, false
+	}
+
+	field := v.FieldByName("MetaData")
+	if !field.IsValid() || field.Type() != metaDataType {
+		return time.Time{}, false
+	}
+
+	t = field.Interface().(*MetaData).LastUpdated()
+	return t, !t.IsZero()
+}
+
+// ShouldSkip reports whether obj has not changed since lastSeen, the LastUpdatedTime the
+// caller recorded when it last synced the object, so an incremental sync can leave it alone.
+// Objects without a LastUpdatedTime are never skipped.
+func ShouldSkip(obj any, lastSeen time.Time) bool {
+	updated, ok := LastUpdatedTime(obj)
+	return ok && !updated.After(lastSeen)
+}
@@ -0,0 +1,33 @@
+package quickbooks
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShouldSkip(t *testing.T) {
+	var invoice Invoice
+	require.NoError(t, json.Unmarshal([]byte(`{"Id": "1", "MetaData": {"CreateTime": "2024-03-01T09:00:00-08:00", "LastUpdatedTime": "2024-03-05T10:30:00-08:00"}}`), &invoice))
+
+	updated := time.Date(2024, 3, 5, 18, 30, 0, 0, time.UTC)
+	assert.True(t, invoice.MetaData.LastUpdated().Equal(updated))
+	assert.True(t, invoice.MetaData.Created().Before(updated))
+
+	got, ok := LastUpdatedTime(invoice)
+	assert.True(t, ok)
+	assert.True(t, got.Equal(updated))
+
+	assert.True(t, ShouldSkip(&invoice, updated))
+	assert.True(t, ShouldSkip(invoice, updated.Add(time.Hour)))
+	assert.False(t, ShouldSkip(&invoice, updated.Add(-time.Second)))
+
+	// Sparse objects queried without MetaData, and values that are not entities, always sync.
+	assert.False(t, ShouldSkip(&Invoice{ID: "2"}, updated))
+	assert.False(t, ShouldSkip((*Invoice)(nil), updated))
+	assert.False(t, ShouldSkip("Invoice", updated))
+	assert.True(t, (*MetaData)(nil).LastUpdated().IsZero())
+}